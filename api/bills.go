@@ -1,60 +1,105 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
-	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/gorilla/mux"
 )
 
-// @Summary Retrieve bills by public key
-// @Description Get bills associated with a specific public key
-// @Tags Bills
-// @Accept json
-// @Produce json
-// @Param pubKey path string true "Public Key"
-// @Success 200 {array} domain.Bill "List of bills"
-// @Failure 400 {object} ErrorResponse "Error: Missing 'pubKey' variable in the URL"
-// @Failure 404 {object} ErrorResponse "Error: Bills with specified public key not found"
-// @Router /address/{pubKey}/bills [get]
+// getBillsByPubKey gets bills associated with a specific public key.
 func (c *Controller) getBillsByPubKey(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	pubKeyStr, ok := vars[paramPubKey]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramPubKey)
+		c.rw.WriteMissingParamResponse(w, r, paramPubKey)
 		return
 	}
 
-	pubKeyHash, err := util.PubKeyHash(pubKeyStr)
+	address, err := predicate.ParseAddress(pubKeyStr)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramPubKey)
+		c.rw.WriteInvalidParamResponse(w, r, paramPubKey)
 		return
 	}
 
-	bills, err := c.MoneyService.GetBillsByPubKeyHash(r.Context(), pubKeyHash)
+	qp := r.URL.Query()
+	cursor, err := parseCursor(qp.Get(QueryParamCursor), listBills, 0)
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load bills with pubKey %s : %w", pubKeyStr, err))
+		c.rw.WriteInvalidParamResponse(w, r, QueryParamCursor)
+		return
+	}
+	limit, err := parseLimit(qp.Get(paramLimit), defaultBillsPageLimit)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+		return
+	}
+
+	bills, err := c.MoneyService.GetBillsByPubKeyHash(r.Context(), address.OwnerID)
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load bills with pubKey %s : %w", pubKeyStr, err))
 		return
 	}
 
 	if len(bills) == 0 {
-		c.rw.WriteErrorResponse(w, fmt.Errorf("bills with pubKey %s not found", pubKeyStr), http.StatusNotFound)
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("bills with pubKey %s not found", pubKeyStr), http.StatusNotFound)
 		return
 	}
 
+	bills, hasMore := billsPage(bills, pageRequest(cursor, limit, func(key []byte) types.UnitID { return key }))
+
 	var response = []domain.Bill{}
 	for _, bill := range bills {
-		response = append(response, domain.Bill{
-			NetworkID:   bill.NetworkID,
-			PartitionID: bill.PartitionID,
-			ID:          bill.ID,
-			Value:       bill.Value,
-			LockStatus:  bill.LockStatus,
-			Counter:     bill.Counter,
-		})
+		response = append(response, billResponse(bill, address.OwnerPredicate()))
 	}
 
-	c.rw.WriteResponse(w, response)
+	var firstKey, lastKey []byte
+	if len(bills) > 0 {
+		firstKey, lastKey = bills[0].ID, bills[len(bills)-1].ID
+	}
+	setPageLinks(w, r.URL, listBills, cursor, firstKey, lastKey, hasMore)
+	c.rw.WriteResponse(w, r, response)
+}
+
+// billsPage returns the page of the bills ordered by the bill ID, the money node returns all the bills at once.
+func billsPage(bills []*sdktypes.Bill, page domain.PageRequest[types.UnitID]) ([]*sdktypes.Bill, bool) {
+	slices.SortFunc(bills, func(a, b *sdktypes.Bill) int { return bytes.Compare(a.ID, b.ID) })
+
+	start, end := 0, len(bills)
+	if page.Key != nil {
+		idx, found := slices.BinarySearchFunc(bills, *page.Key, func(b *sdktypes.Bill, key types.UnitID) int { return bytes.Compare(b.ID, key) })
+		switch {
+		case page.Backward:
+			end = idx
+		case found:
+			start = idx + 1
+		default:
+			start = idx
+		}
+	}
+	hasMore := end-start > page.Limit
+	if page.Backward {
+		start = max(start, end-page.Limit)
+	} else {
+		end = min(end, start+page.Limit)
+	}
+	return bills[start:end], hasMore
+}
+
+// billResponse returns the bill of the owner, the money node returns the bills of the owner ID without the predicate.
+func billResponse(bill *sdktypes.Bill, owner *predicate.Predicate) domain.Bill {
+	return domain.Bill{
+		NetworkID:      bill.NetworkID,
+		PartitionID:    bill.PartitionID,
+		ID:             bill.ID,
+		Value:          bill.Value,
+		LockStatus:     bill.LockStatus,
+		Counter:        bill.Counter,
+		OwnerPredicate: owner,
+	}
 }
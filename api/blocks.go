@@ -1,35 +1,28 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/gorilla/mux"
 )
 
-// @Summary Retrieve a blockchain block by number, or the latest if unspecified
-// @Description Retrieves a block for all given partitions by using the provided block number as a path parameter, or retrieves the latest block if no number is specified.
-// @Tags Blocks
-// @Accept json
-// @Produce json
-// @Param blockNumber path string true "Block number ('latest' or a specific number)"
-// @Param partitionID query string false "List of partitions to get the blocks for. If not provided then get for all partitions"
-// @Success 200 {object} BlockResponse "Block information successfully retrieved"
-// @Failure 400 {object} string "invalid partitionID"
-// @Failure 400 {object} string "Missing or invalid block number"
-// @Failure 404 {object} string "No block found with the specified number"
-// @Failure 500 {object} string "Internal server error, such as a failure to retrieve the block"
-// @Router /blocks/{blockNumber} [get]
+// getBlock retrieves a block for all given partitions by using the provided block number as a path parameter, or
+// retrieves the latest block if no number is specified.
 func (c *Controller) getBlock(w http.ResponseWriter, r *http.Request) {
 	qp := r.URL.Query()
 	var partitionIDs []types.PartitionID
 	for _, pid := range qp[paramPartitionID] {
 		id, err := strconv.ParseUint(pid, 10, 64)
 		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramPartitionID)
+			c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
 			return
 		}
 		partitionIDs = append(partitionIDs, types.PartitionID(id))
@@ -40,7 +33,7 @@ func (c *Controller) getBlock(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	blockNumberStr, ok := vars[paramBlockNumber]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramBlockNumber)
+		c.rw.WriteMissingParamResponse(w, r, paramBlockNumber)
 		return
 	}
 
@@ -48,7 +41,7 @@ func (c *Controller) getBlock(w http.ResponseWriter, r *http.Request) {
 	if blockNumberStr == blockNumberLatest {
 		blockMap, err := c.StorageService.GetLastBlocks(r.Context(), partitionIDs, 1, true)
 		if err != nil {
-			c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to get latest blocks: %w", err))
+			c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to get latest blocks: %w", err))
 			return
 		}
 		for partitionID, blocks := range blockMap {
@@ -56,24 +49,28 @@ func (c *Controller) getBlock(w http.ResponseWriter, r *http.Request) {
 				result[partitionID] = blockInfoResponse(blocks[0])
 			}
 		}
-		c.rw.WriteResponse(w, result)
+		c.rw.WriteCacheableResponse(w, r, result, cacheControlLatest)
 		return
 	}
 
 	blockNumber, err := strconv.ParseUint(blockNumberStr, 10, 64)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramBlockNumber)
+		c.rw.WriteInvalidParamResponse(w, r, paramBlockNumber)
 		return
 	}
 
 	blockMap, err := c.StorageService.GetBlock(r.Context(), blockNumber, partitionIDs)
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load block with block number %d: %w", blockNumber, err))
+		if errors.Is(err, domain.ErrPruned) {
+			c.rw.WriteErrorResponse(w, r, err, http.StatusGone)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load block with block number %d: %w", blockNumber, err))
 		return
 	}
 
 	if len(blockMap) == 0 {
-		c.rw.WriteErrorResponse(w, fmt.Errorf("block with block number %d not found", blockNumber), http.StatusNotFound)
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("block with block number %d not found", blockNumber), http.StatusNotFound)
 		return
 	}
 
@@ -81,43 +78,56 @@ func (c *Controller) getBlock(w http.ResponseWriter, r *http.Request) {
 		result[partitionID] = blockInfoResponse(block)
 	}
 
-	c.rw.WriteResponse(w, result)
+	// without the partition filter the block of another partition may be added later
+	cacheControl := cacheControlRevalidate
+	if len(partitionIDs) > 0 && len(result) == len(slices.Compact(slices.Sorted(slices.Values(partitionIDs)))) {
+		cacheControl = cacheControlSynced
+	}
+	c.rw.WriteCacheableResponse(w, r, result, cacheControl)
+}
+
+// getBlockByHash retrieves the block of any partition with the given block hash.
+func (c *Controller) getBlockByHash(w http.ResponseWriter, r *http.Request) {
+	blockHashStr := mux.Vars(r)[paramBlockHash]
+	blockHash, err := util.DecodeHex(blockHashStr)
+	if err != nil || len(blockHash) == 0 {
+		c.rw.WriteInvalidParamResponse(w, r, paramBlockHash)
+		return
+	}
+	block, err := c.StorageService.GetBlockByHash(r.Context(), blockHash)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("block with block hash %s not found", blockHashStr), http.StatusNotFound)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load block with block hash %s: %w", blockHashStr, err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, blockInfoResponse(block), cacheControlSynced)
 }
 
-// @Summary Get blocks in a single partition, given a start block number and limit.
-// @Description Get blocks in a single partition, given a start block number and limit.
-// @produce	application/json
-// @Param partitionID path string true "Partition ID to get the blocks for"
-// @Param startBlock query string false "optionally specify the start block number"
-// @Param limit query string false "optionally specify the number of blocks to return, defaults to 10"
-// @Param includeEmpty query boolean false "whether to include blocks without transactions, defaults to true"
-// @Success 200 {array} BlockInfo
-// @Router /partitions/{partitionID}/blocks [get]
-// @Tags Blocks
+// getBlocksInRange gets a page of blocks in a single partition, latest first. Links to the next (older) and previous
+// (newer) pages are returned in the Link header.
 func (c *Controller) getBlocksInRange(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	partitionIDStr, ok := vars[paramPartitionID]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramPartitionID)
+		c.rw.WriteMissingParamResponse(w, r, paramPartitionID)
 		return
 	}
 	partitionIDUint, err := strconv.ParseUint(partitionIDStr, 10, 64)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramPartitionID)
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
 		return
 	}
 	partitionID := types.PartitionID(partitionIDUint)
 
 	qp := r.URL.Query()
 
-	limitStr := qp.Get(paramLimit)
-	limit := defaultBlocksPageLimit
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramLimit)
-			return
-		}
+	limit, err := parseLimit(qp.Get(paramLimit), defaultBlocksPageLimit)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+		return
 	}
 
 	includeEmptyStr := qp.Get(paramIncludeEmpty)
@@ -125,50 +135,96 @@ func (c *Controller) getBlocksInRange(w http.ResponseWriter, r *http.Request) {
 	if includeEmptyStr != "" {
 		includeEmpty, err = strconv.ParseBool(includeEmptyStr)
 		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramIncludeEmpty)
+			c.rw.WriteInvalidParamResponse(w, r, paramIncludeEmpty)
 			return
 		}
 	}
 
-	startBlockStr := qp.Get(paramStartBlock)
-	var startBlock uint64
-	if startBlockStr != "" {
-		startBlock, err = strconv.ParseUint(startBlockStr, 10, 64)
-		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramStartBlock)
-			return
-		}
-	} else {
-		lastBlocks, err := c.StorageService.GetLastBlocks(r.Context(), []types.PartitionID{partitionID}, limit, includeEmpty)
+	cursor, err := parseCursor(qp.Get(QueryParamCursor), listBlocks, 8)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, QueryParamCursor)
+		return
+	}
+	if startBlockStr := qp.Get(paramStartBlock); startBlockStr != "" && cursor == nil {
+		startBlock, err := strconv.ParseUint(startBlockStr, 10, 64)
 		if err != nil {
-			c.rw.WriteInternalErrorResponse(w, err)
+			c.rw.WriteInvalidParamResponse(w, r, paramStartBlock)
 			return
 		}
-		var response = []BlockInfo{}
-		for _, block := range lastBlocks[partitionID] {
-			response = append(response, blockInfoResponse(block))
+		// the page starts with the start block, ie it's the page after the next block
+		if startBlock < math.MaxUint64 {
+			cursor = &pageCursor{Version: cursorVersion, List: listBlocks, Key: encodeUint64(startBlock + 1)}
 		}
-		c.rw.WriteResponse(w, response)
-		return
 	}
 
-	blocks, prevBlockNumber, err := c.StorageService.GetBlocksInRange(r.Context(), partitionID, startBlock, limit, includeEmpty)
+	page := pageRequest(cursor, limit, decodeUint64)
+	blocks, hasMore, err := c.StorageService.GetBlocksPage(r.Context(), partitionID, page, includeEmpty)
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, err)
+		c.rw.WriteInternalErrorResponse(w, r, err)
 		return
 	}
-	prevBlockNumberStr := strconv.FormatUint(prevBlockNumber, 10)
-	setLinkHeader(r.URL, w, prevBlockNumberStr)
 
 	var response = []BlockInfo{}
 	for _, block := range blocks {
 		response = append(response, blockInfoResponse(block))
 	}
-	c.rw.WriteResponse(w, response)
+	var firstKey, lastKey []byte
+	if len(blocks) > 0 {
+		firstKey, lastKey = encodeUint64(blocks[0].BlockNumber), encodeUint64(blocks[len(blocks)-1].BlockNumber)
+	}
+	setPageLinks(w, r.URL, listBlocks, cursor, firstKey, lastKey, hasMore)
+	c.rw.WriteResponse(w, r, response)
+}
+
+// getRawBlock retrieves the block from the raw block archive exactly as it was received from the partition node.
+func (c *Controller) getRawBlock(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	partitionIDStr, ok := vars[paramPartitionID]
+	if !ok {
+		c.rw.WriteMissingParamResponse(w, r, paramPartitionID)
+		return
+	}
+	partitionID, err := strconv.ParseUint(partitionIDStr, 10, 64)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+		return
+	}
+	blockNumberStr, ok := vars[paramBlockNumber]
+	if !ok {
+		c.rw.WriteMissingParamResponse(w, r, paramBlockNumber)
+		return
+	}
+	blockNumber, err := strconv.ParseUint(blockNumberStr, 10, 64)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramBlockNumber)
+		return
+	}
+
+	if c.RawBlockService == nil {
+		c.rw.WriteErrorResponse(w, r, errors.New("raw block archive is not enabled"), http.StatusNotFound)
+		return
+	}
+
+	data, err := c.RawBlockService.GetRawBlock(r.Context(), types.PartitionID(partitionID), blockNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("raw block %d not found in partition %d", blockNumber, partitionID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrPruned) {
+			c.rw.WriteErrorResponse(w, r, err, http.StatusGone)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load raw block %d in partition %d: %w", blockNumber, partitionID, err))
+		return
+	}
+
+	c.rw.WriteCacheableRawResponse(w, r, ApplicationCbor, data, cacheControlSynced)
 }
 
 func blockInfoResponse(block *domain.BlockInfo) BlockInfo {
 	return BlockInfo{
+		BlockHash:          block.BlockHash,
 		PartitionID:        block.PartitionID,
 		PartitionTypeID:    block.PartitionTypeID,
 		ShardID:            block.ShardID,
@@ -150,12 +150,13 @@ func TestGetBlock_FailedToLoadBlock(t *testing.T) {
 func TestGetBlocks_Success(t *testing.T) {
 	r := mux.NewRouter()
 	mockStorage := mocks.NewStorageService(t)
-	mockStorage.EXPECT().GetBlocksInRange(mock.Anything, partitionID1, uint64(1), 10, true).
+	startKey := uint64(2)
+	mockStorage.EXPECT().GetBlocksPage(mock.Anything, partitionID1, domain.PageRequest[uint64]{Key: &startKey, Limit: 10}, true).
 		Return([]*domain.BlockInfo{
 			{}, // empty block
 			{TxHashes: []domain.TxHash{{0x02}}},
 			{TxHashes: []domain.TxHash{{0x03}}},
-		}, 0, nil)
+		}, true, nil)
 
 	restapi := &Controller{StorageService: mockStorage}
 	r.HandleFunc("/partitions/{partitionID}/blocks", restapi.getBlocksInRange)
@@ -176,17 +177,19 @@ func TestGetBlocks_Success(t *testing.T) {
 	require.Equal(t, BlockInfo{TxHashes: []domain.TxHash{{0x02}}}, result[1])
 	require.Equal(t, BlockInfo{TxHashes: []domain.TxHash{{0x03}}}, result[2])
 
-	require.Contains(t, res.Header.Get("Link"), "offsetKey=0")
+	require.Contains(t, res.Header.Get("Link"), `rel="next"`)
+	require.Contains(t, res.Header.Get("Link"), `rel="prev"`)
 }
 
 func TestGetBlocks_Success_ExcludeEmpty(t *testing.T) {
 	r := mux.NewRouter()
 	mockStorage := mocks.NewStorageService(t)
-	mockStorage.EXPECT().GetBlocksInRange(mock.Anything, partitionID1, uint64(1), 10, false).
+	startKey := uint64(2)
+	mockStorage.EXPECT().GetBlocksPage(mock.Anything, partitionID1, domain.PageRequest[uint64]{Key: &startKey, Limit: 10}, false).
 		Return([]*domain.BlockInfo{
 			{TxHashes: []domain.TxHash{{0x02}}},
 			{TxHashes: []domain.TxHash{{0x03}}},
-		}, 0, nil)
+		}, false, nil)
 
 	restapi := &Controller{StorageService: mockStorage}
 	r.HandleFunc("/partitions/{partitionID}/blocks", restapi.getBlocksInRange)
@@ -206,5 +209,79 @@ func TestGetBlocks_Success_ExcludeEmpty(t *testing.T) {
 	require.Equal(t, BlockInfo{TxHashes: []domain.TxHash{{0x02}}}, result[0])
 	require.Equal(t, BlockInfo{TxHashes: []domain.TxHash{{0x03}}}, result[1])
 
-	require.Contains(t, res.Header.Get("Link"), "offsetKey=0")
+	// no more blocks, only the link to the previous page
+	require.NotContains(t, res.Header.Get("Link"), `rel="next"`)
+	require.Contains(t, res.Header.Get("Link"), `rel="prev"`)
+}
+
+func TestGetRawBlock_Success(t *testing.T) {
+	r := mux.NewRouter()
+	rawBlock := []byte{0x83, 0x01, 0x02, 0x03}
+	mockRawBlocks := mocks.NewRawBlockService(t)
+	mockRawBlocks.EXPECT().GetRawBlock(mock.Anything, partitionID1, uint64(5)).Return(rawBlock, nil)
+
+	restapi := &Controller{RawBlockService: mockRawBlocks}
+	r.HandleFunc("/partitions/{partitionID}/blocks/{blockNumber}/raw", restapi.getRawBlock)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/partitions/%d/blocks/5/raw", ts.URL, partitionID1))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, ApplicationCbor, res.Header.Get(ContentType))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, rawBlock, body)
+}
+
+func TestGetRawBlock_NotFound(t *testing.T) {
+	r := mux.NewRouter()
+	mockRawBlocks := mocks.NewRawBlockService(t)
+	mockRawBlocks.EXPECT().GetRawBlock(mock.Anything, partitionID1, uint64(5)).Return(nil, domain.ErrNotFound)
+
+	restapi := &Controller{RawBlockService: mockRawBlocks}
+	r.HandleFunc("/partitions/{partitionID}/blocks/{blockNumber}/raw", restapi.getRawBlock)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/partitions/%d/blocks/5/raw", ts.URL, partitionID1))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestGetRawBlock_ArchiveDisabled(t *testing.T) {
+	r := mux.NewRouter()
+	restapi := &Controller{}
+	r.HandleFunc("/partitions/{partitionID}/blocks/{blockNumber}/raw", restapi.getRawBlock)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/partitions/%d/blocks/5/raw", ts.URL, partitionID1))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "raw block archive is not enabled")
+}
+
+func TestGetBlock_Pruned(t *testing.T) {
+	r := mux.NewRouter()
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetBlock(mock.Anything, uint64(1), []types.PartitionID{partitionID1}).
+		Return(nil, fmt.Errorf("block 1 of partition 1 has been %w, lowest available block is 100", domain.ErrPruned))
+
+	restapi := &Controller{StorageService: mockStorage}
+	r.HandleFunc("/blocks/{blockNumber}", restapi.getBlock)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/blocks/1?partitionID=%d", ts.URL, partitionID1))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusGone, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "lowest available block is 100")
 }
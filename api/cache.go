@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const (
+	HeaderCacheControl = "Cache-Control"
+	HeaderETag         = "ETag"
+	HeaderIfNoneMatch  = "If-None-Match"
+
+	// cacheControlSynced is used for the synced blocks and transactions, they change only when rolled back or
+	// pruned so the responses are cached for a minute and revalidated using the ETag after that
+	cacheControlSynced = "public, max-age=60, must-revalidate"
+	// cacheControlLatest is used for the data which changes with every block
+	cacheControlLatest = "public, max-age=2"
+	// cacheControlRevalidate allows caching the response but it must be revalidated using the ETag
+	cacheControlRevalidate = "no-cache"
+)
+
+/*
+WriteCacheableResponse writes the data like WriteResponse and adds the strong ETag of the response body
+and the Cache-Control header. When the ETag matches the If-None-Match header of the request 304 Not Modified
+is returned without the body.
+*/
+func (rw *ResponseWriter) WriteCacheableResponse(w http.ResponseWriter, r *http.Request, data any, cacheControl string) {
+	contentType, body, err := encodeResponse(r, data)
+	if err != nil {
+		rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	rw.WriteCacheableRawResponse(w, r, contentType, body, cacheControl)
+}
+
+// WriteCacheableRawResponse writes already encoded response data like WriteCacheableResponse.
+func (rw *ResponseWriter) WriteCacheableRawResponse(w http.ResponseWriter, r *http.Request, contentType string, data []byte, cacheControl string) {
+	etag := computeETag(data)
+	w.Header().Set(HeaderCacheControl, cacheControl)
+	w.Header().Set(HeaderETag, etag)
+	if etagMatches(r.Header.Values(HeaderIfNoneMatch), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	rw.WriteRawResponse(w, contentType, data)
+}
+
+// encodeResponse encodes the data in the negotiated content type.
+func encodeResponse(r *http.Request, data any) (string, []byte, error) {
+	if responseContentType(r) == ApplicationCbor {
+		buf, err := types.Cbor.Marshal(data)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode response data as cbor: %w", err)
+		}
+		return ApplicationCbor, buf, nil
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(data); err != nil {
+		return "", nil, fmt.Errorf("failed to encode response data as json: %w", err)
+	}
+	return ApplicationJson, buf.Bytes(), nil
+}
+
+func computeETag(data []byte) string {
+	h := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// etagMatches implements the weak comparison of the If-None-Match header values with the ETag.
+func etagMatches(ifNoneMatch []string, etag string) bool {
+	for _, value := range ifNoneMatch {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
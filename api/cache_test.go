@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtagMatches(t *testing.T) {
+	etag := `"abc"`
+	require.False(t, etagMatches(nil, etag))
+	require.True(t, etagMatches([]string{`"abc"`}, etag))
+	require.True(t, etagMatches([]string{`"x", W/"abc"`}, etag))
+	require.True(t, etagMatches([]string{`"x"`, `*`}, etag))
+	require.False(t, etagMatches([]string{`"abcd", abc`}, etag))
+}
+
+func TestCacheableResponses(t *testing.T) {
+	txHash := domain.TxHash{1, 2, 3}
+	block := &domain.BlockInfo{PartitionID: partitionID1, BlockNumber: 5}
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, txHash).Return(&domain.TxInfo{TxRecordHash: txHash}, nil)
+	mockStorage.EXPECT().GetBlock(mock.Anything, uint64(5), mock.Anything).
+		Return(map[types.PartitionID]*domain.BlockInfo{partitionID1: block}, nil)
+	restapi := &Controller{StorageService: mockStorage}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	get := func(path, accept, ifNoneMatch string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set(Accept, accept)
+		if ifNoneMatch != "" {
+			req.Header.Set(HeaderIfNoneMatch, ifNoneMatch)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	txPath := fmt.Sprintf("/api/v1/txs/0x%s", txHash)
+	res := get(txPath, ApplicationJson, "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, cacheControlSynced, res.Header.Get(HeaderCacheControl))
+	etag := res.Header.Get(HeaderETag)
+	require.NotEmpty(t, etag)
+
+	res = get(txPath, ApplicationJson, etag)
+	require.Equal(t, http.StatusNotModified, res.StatusCode)
+	require.Equal(t, etag, res.Header.Get(HeaderETag))
+
+	// CBOR response has a different ETag
+	res = get(txPath, ApplicationCbor, etag)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NotEqual(t, etag, res.Header.Get(HeaderETag))
+
+	// the block is cacheable only when all the requested partitions have it
+	res = get(fmt.Sprintf("/api/v1/blocks/5?partitionID=%d", partitionID1), ApplicationJson, "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, cacheControlSynced, res.Header.Get(HeaderCacheControl))
+	res = get("/api/v1/blocks/5", ApplicationJson, "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, cacheControlRevalidate, res.Header.Get(HeaderCacheControl))
+	require.Equal(t, http.StatusNotModified, get("/api/v1/blocks/5", ApplicationJson, res.Header.Get(HeaderETag)).StatusCode)
+}
@@ -1,28 +1,31 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/fxamacker/cbor/v2"
 )
 
 const (
 	ContentType     = "Content-Type"
+	Accept          = "Accept"
 	ApplicationJson = "application/json"
 	ApplicationCbor = "application/cbor"
 	UserAgent       = "User-Agent"
 
-	QueryParamOffsetKey   = "offsetKey"
-	QueryParamLimit       = "limit"
-	HeaderLink            = "Link"
-	HeaderLinkValueFormat = `<%s>; rel="next"`
+	QueryParamCursor = "cursor"
+	QueryParamLimit  = "limit"
+	HeaderLink       = "Link"
 )
 
 var (
@@ -43,44 +46,98 @@ type (
 	ResponseWriter struct {
 		//LogErr func(err error)
 	}
+
+	// contentTypeKey is the request context key of the content type negotiated by contentNegotiationMiddleware
+	contentTypeKey struct{}
 )
 
-func (rw *ResponseWriter) WriteResponse(w http.ResponseWriter, data any) {
+// WriteResponse writes the data encoded as CBOR or JSON depending on the negotiated content type,
+// JSON is used when the content type was not negotiated.
+func (rw *ResponseWriter) WriteResponse(w http.ResponseWriter, r *http.Request, data any) {
+	if responseContentType(r) == ApplicationCbor {
+		rw.WriteCborResponse(w, r, data)
+		return
+	}
+	rw.WriteJsonResponse(w, data)
+}
+
+func (rw *ResponseWriter) WriteJsonResponse(w http.ResponseWriter, data any) {
 	w.Header().Set(ContentType, ApplicationJson)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		//rw.logError(fmt.Errorf("failed to encode response data as json: %w", err))
 	}
 }
 
-func (rw *ResponseWriter) WriteCborResponse(w http.ResponseWriter, data any) {
+// WriteCborResponse writes the data using the Alphabill CBOR encoding, ie transaction records and
+// unicity certificates are encoded exactly as the partition nodes encode them.
+func (rw *ResponseWriter) WriteCborResponse(w http.ResponseWriter, r *http.Request, data any) {
+	buf, err := types.Cbor.Marshal(data)
+	if err != nil {
+		rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to encode response data as cbor: %w", err))
+		return
+	}
 	w.Header().Set(ContentType, ApplicationCbor)
-	if err := cbor.NewEncoder(w).Encode(data); err != nil {
-		//rw.logError(fmt.Errorf("failed to encode response data as cbor: %w", err))
+	if _, err := w.Write(buf); err != nil {
+		//rw.logError(fmt.Errorf("failed to write response data: %w", err))
+	}
+}
+
+// WriteResponseWithStatus writes the data like WriteResponse using the given status code.
+func (rw *ResponseWriter) WriteResponseWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	contentType, body, err := encodeResponse(r, data)
+	if err != nil {
+		rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	w.Header().Set(ContentType, contentType)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(body); err != nil {
+		//rw.logError(fmt.Errorf("failed to write response data: %w", err))
+	}
+}
+
+// WriteRawResponse writes already encoded response data with the given content type.
+func (rw *ResponseWriter) WriteRawResponse(w http.ResponseWriter, contentType string, data []byte) {
+	w.Header().Set(ContentType, contentType)
+	if _, err := w.Write(data); err != nil {
+		//rw.logError(fmt.Errorf("failed to write response data: %w", err))
 	}
 }
 
-func (rw *ResponseWriter) WriteInternalErrorResponse(w http.ResponseWriter, err error) {
+func (rw *ResponseWriter) WriteInternalErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	log.Error("Internal error", "err", err)
-	rw.ErrorResponse(w, http.StatusInternalServerError, errors.New("internal error"))
+	rw.ErrorResponse(w, r, http.StatusInternalServerError, errors.New("internal error"))
 }
 
-func (rw *ResponseWriter) WriteErrorResponse(w http.ResponseWriter, err error, statusCode ...int) {
+func (rw *ResponseWriter) WriteErrorResponse(w http.ResponseWriter, r *http.Request, err error, statusCode ...int) {
 	if len(statusCode) > 0 {
-		rw.ErrorResponse(w, statusCode[0], err)
+		rw.ErrorResponse(w, r, statusCode[0], err)
 		return
 	}
-	rw.ErrorResponse(w, http.StatusBadRequest, err)
+	rw.ErrorResponse(w, r, http.StatusBadRequest, err)
 }
 
-func (rw *ResponseWriter) WriteMissingParamResponse(w http.ResponseWriter, param string) {
-	rw.ErrorResponse(w, http.StatusBadRequest, fmt.Errorf("missing '%s' parameter", param))
+func (rw *ResponseWriter) WriteMissingParamResponse(w http.ResponseWriter, r *http.Request, param string) {
+	rw.ErrorResponse(w, r, http.StatusBadRequest, fmt.Errorf("missing '%s' parameter", param))
 }
 
-func (rw *ResponseWriter) WriteInvalidParamResponse(w http.ResponseWriter, param string) {
-	rw.ErrorResponse(w, http.StatusBadRequest, fmt.Errorf("invalid '%s' parameter", param))
+func (rw *ResponseWriter) WriteInvalidParamResponse(w http.ResponseWriter, r *http.Request, param string) {
+	rw.ErrorResponse(w, r, http.StatusBadRequest, fmt.Errorf("invalid '%s' parameter", param))
 }
 
-func (rw *ResponseWriter) ErrorResponse(w http.ResponseWriter, code int, err error) {
+func (rw *ResponseWriter) ErrorResponse(w http.ResponseWriter, r *http.Request, code int, err error) {
+	if responseContentType(r) == ApplicationCbor {
+		buf, err := types.Cbor.Marshal(ErrorResponse{Message: err.Error()})
+		if err != nil {
+			log.Error("failed to encode error response as cbor", "err", err)
+		}
+		w.Header().Set(ContentType, ApplicationCbor)
+		w.WriteHeader(code)
+		if _, err := w.Write(buf); err != nil {
+			//rw.logError(fmt.Errorf("failed to write error response: %w", err))
+		}
+		return
+	}
 	w.Header().Set(ContentType, ApplicationJson)
 	w.WriteHeader(code)
 	if err := json.NewEncoder(w).Encode(ErrorResponse{Message: err.Error()}); err != nil {
@@ -88,15 +145,79 @@ func (rw *ResponseWriter) ErrorResponse(w http.ResponseWriter, code int, err err
 	}
 }
 
-func setLinkHeader(u *url.URL, w http.ResponseWriter, next string) {
-	if next == "" {
-		w.Header().Del(HeaderLink)
-		return
+// contentNegotiationMiddleware selects the content type of the response (JSON or CBOR) based on the Accept
+// header of the request and responds with 406 Not Acceptable when the client accepts neither of them.
+func contentNegotiationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", Accept)
+		contentType, ok := negotiateContentType(r.Header.Values(Accept))
+		r = r.WithContext(context.WithValue(r.Context(), contentTypeKey{}, contentType))
+		if !ok {
+			(&ResponseWriter{}).ErrorResponse(w, r, http.StatusNotAcceptable,
+				fmt.Errorf("none of the accepted media types is supported, use %s or %s", ApplicationJson, ApplicationCbor))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseContentType returns the content type negotiated for the response of the request, JSON when the
+// content type was not negotiated.
+func responseContentType(r *http.Request) string {
+	if contentType, ok := r.Context().Value(contentTypeKey{}).(string); ok && contentType != "" {
+		return contentType
+	}
+	return ApplicationJson
+}
+
+/*
+negotiateContentType returns the supported media type the client prefers according to the Accept header values.
+Media type with higher quality wins, on equal quality exact media type wins over the wildcards and
+then the one listed first. JSON is returned when the header is not sent, false when nothing supported is accepted.
+*/
+func negotiateContentType(accept []string) (string, bool) {
+	var ranges []string
+	for _, v := range accept {
+		for _, mr := range strings.Split(v, ",") {
+			if mr = strings.TrimSpace(mr); mr != "" {
+				ranges = append(ranges, mr)
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return ApplicationJson, true
+	}
+
+	best, bestQ, bestExact := "", 0.0, false
+	for _, mr := range ranges {
+		mediaType, params, err := mime.ParseMediaType(mr)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue // "not acceptable"
+		}
+		var contentType string
+		exact := true
+		switch mediaType {
+		case ApplicationJson, ApplicationCbor:
+			contentType = mediaType
+		case "application/*", "*/*":
+			contentType, exact = ApplicationJson, false
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && exact && !bestExact) {
+			best, bestQ, bestExact = contentType, q, exact
+		}
 	}
-	qp := u.Query()
-	qp.Set(QueryParamOffsetKey, next)
-	u.RawQuery = qp.Encode()
-	w.Header().Set(HeaderLink, fmt.Sprintf(HeaderLinkValueFormat, u))
+	return best, best != ""
 }
 
 /*
@@ -146,7 +267,7 @@ func DecodeResponse(rsp *http.Response, successStatus int, data any, allowEmptyR
 	}
 
 	var errResponse ErrorResponse
-	if err := json.NewDecoder(rsp.Body).Decode(&errResponse); err != nil {
+	if err := dec.Decode(&errResponse); err != nil {
 		return fmt.Errorf("failed to decode error from the response body (%s): %w", rsp.Status, err)
 	}
 
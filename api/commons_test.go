@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	tests := []struct {
+		accept      []string
+		contentType string
+		ok          bool
+	}{
+		{nil, ApplicationJson, true},
+		{[]string{""}, ApplicationJson, true},
+		{[]string{"*/*"}, ApplicationJson, true},
+		{[]string{"application/*"}, ApplicationJson, true},
+		{[]string{"application/json"}, ApplicationJson, true},
+		{[]string{"application/cbor"}, ApplicationCbor, true},
+		{[]string{"application/cbor, application/json"}, ApplicationCbor, true},
+		{[]string{"application/json", "application/cbor"}, ApplicationJson, true},
+		{[]string{"application/json;q=0.5, application/cbor"}, ApplicationCbor, true},
+		{[]string{"*/*, application/cbor"}, ApplicationCbor, true},
+		{[]string{"text/html, application/cbor;q=0.1"}, ApplicationCbor, true},
+		{[]string{"application/cbor;q=0, */*;q=0.1"}, ApplicationJson, true},
+		{[]string{"text/html"}, "", false},
+		{[]string{"application/json;q=0"}, "", false},
+	}
+	for _, tc := range tests {
+		contentType, ok := negotiateContentType(tc.accept)
+		require.Equal(t, tc.ok, ok, tc.accept)
+		require.Equal(t, tc.contentType, contentType, tc.accept)
+	}
+}
+
+func getWithAccept(t *testing.T, url, accept string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if accept != "" {
+		req.Header.Set(Accept, accept)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+func TestContentNegotiation_Tx(t *testing.T) {
+	txHash := domain.TxHash{1, 2, 3, 4}
+	txRecord := &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: types.TaggedCBOR{0x80},
+		ServerMetadata:   &types.ServerMetadata{ActualFee: 1, TargetUnits: []types.UnitID{{0x0A}}, SuccessIndicator: types.TxStatusSuccessful},
+	}
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, txHash).
+		Return(&domain.TxInfo{TxRecordHash: txHash, Transaction: txRecord, PartitionID: partitionID1}, nil)
+	restapi := &Controller{StorageService: mockStorage}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+	url := fmt.Sprintf("%s/api/v1/txs/0x%s", ts.URL, txHash)
+
+	for _, accept := range []string{"", ApplicationJson, ApplicationCbor} {
+		res := getWithAccept(t, url, accept)
+		require.Equal(t, http.StatusOK, res.StatusCode, accept)
+		require.Contains(t, res.Header.Values("Vary"), Accept)
+
+		var result TxInfo
+		require.NoError(t, DecodeResponse(res, http.StatusOK, &result, false), accept)
+		require.Equal(t, partitionID1, result.PartitionID)
+		require.Equal(t, txHash, result.TxRecordHash)
+		require.Equal(t, txRecord.ServerMetadata, result.Transaction.ServerMetadata)
+		require.EqualValues(t, txRecord.TransactionOrder, result.Transaction.TransactionOrder)
+
+		if accept == ApplicationCbor {
+			require.Equal(t, ApplicationCbor, res.Header.Get(ContentType))
+			// the record is encoded exactly as the partition node encodes it
+			expected, err := types.Cbor.Marshal(txRecord)
+			require.NoError(t, err)
+			actual, err := types.Cbor.Marshal(result.Transaction)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		} else {
+			require.Equal(t, ApplicationJson, res.Header.Get(ContentType))
+		}
+	}
+}
+
+func TestContentNegotiation_Errors(t *testing.T) {
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetBlock(mock.Anything, uint64(7), []types.PartitionID(nil)).Return(nil, domain.ErrPruned)
+	restapi := &Controller{StorageService: mockStorage}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	for _, accept := range []string{ApplicationJson, ApplicationCbor} {
+		res := getWithAccept(t, ts.URL+"/api/v1/blocks/invalid", accept)
+		require.Equal(t, accept, res.Header.Get(ContentType))
+		err := DecodeResponse(res, http.StatusOK, nil, false)
+		require.ErrorIs(t, err, ErrInvalidRequest)
+		require.ErrorContains(t, err, "invalid 'blockNumber' parameter")
+
+		res = getWithAccept(t, ts.URL+"/api/v1/blocks/7", accept)
+		require.Equal(t, http.StatusGone, res.StatusCode)
+		require.Equal(t, accept, res.Header.Get(ContentType))
+		require.ErrorContains(t, DecodeResponse(res, http.StatusOK, nil, false), domain.ErrPruned.Error())
+	}
+
+	res := getWithAccept(t, ts.URL+"/api/v1/blocks/1", "text/html")
+	require.Equal(t, http.StatusNotAcceptable, res.StatusCode)
+	require.Equal(t, ApplicationJson, res.Header.Get(ContentType))
+	require.ErrorContains(t, DecodeResponse(res, http.StatusOK, nil, false), "none of the accepted media types is supported")
+}
+
+func TestContentNegotiation_WrappedWriter(t *testing.T) {
+	type wrappedWriter struct {
+		http.ResponseWriter
+	}
+	handler := contentNegotiationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// middleware wrapping the writer after the negotiation must not change the content type
+		(&ResponseWriter{}).WriteResponse(&wrappedWriter{ResponseWriter: w}, r, ErrorResponse{Message: "ok"})
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Accept, ApplicationCbor)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, ApplicationCbor, rec.Header().Get(ContentType))
+	var res ErrorResponse
+	require.NoError(t, types.Cbor.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, "ok", res.Message)
+}
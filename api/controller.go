@@ -6,31 +6,56 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/alphabill-org/alphabill-explorer-backend/api/graphql"
+	"github.com/alphabill-org/alphabill-explorer-backend/api/jsonrpc"
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/lifecycle"
+	moneyservice "github.com/alphabill-org/alphabill-explorer-backend/service/money"
 	"github.com/alphabill-org/alphabill-explorer-backend/service/partition"
 	"github.com/alphabill-org/alphabill-explorer-backend/service/search"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/txsubmit"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/types/hex"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
 	paramPartitionID  = "partitionID"
 	paramBlockNumber  = "blockNumber"
+	paramBlockHash    = "blockHash"
 	paramStartBlock   = "startBlock"
 	paramLimit        = "limit"
 	paramIncludeEmpty = "includeEmpty"
 	paramTxHash       = "txHash"
-	paramStartID      = "startID"
+	paramTxOrderHash  = "txOrderHash"
+	paramWebhookID    = "webhookID"
+	paramStatus       = "status"
 	paramUnitID       = "unitID"
 	paramSearchKey    = "q"
+	paramHitType      = "type"
 	paramPubKey       = "pubKey"
+	paramAtBlock      = "atBlock"
+	paramStateProof   = "includeStateProof"
+	paramNodeID       = "nodeID"
+	paramWindow       = "window"
 
 	blockNumberLatest = "latest"
 
 	defaultBlocksPageLimit = 10
 	defaultTxsPageLimit    = 20
+	defaultBillsPageLimit  = 20
+	defaultDeliveriesLimit = 20
+	defaultSuggestLimit    = 10
+	defaultHoldersLimit    = 100
+	maxSuggestLimit        = 20
+
+	maxTxOrderSize = 64 * 1024
 )
 
 type (
@@ -38,30 +63,87 @@ type (
 		//block
 		GetLastBlocks(ctx context.Context, partitionIDs []types.PartitionID, count int, includeEmpty bool) (map[types.PartitionID][]*domain.BlockInfo, error)
 		GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error)
-		GetBlocksInRange(
-			ctx context.Context, partitionID types.PartitionID, dbStartBlock uint64, count int, includeEmpty bool,
-		) (res []*domain.BlockInfo, prevBlockNumber uint64, err error)
+		GetBlockByHash(ctx context.Context, blockHash []byte) (*domain.BlockInfo, error)
+		GetBlocksPage(
+			ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool,
+		) (blocks []*domain.BlockInfo, hasMore bool, err error)
+		GetBlocksPages(
+			ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool,
+		) (map[types.PartitionID][]*domain.BlockInfo, error)
+		GetBlocksByNumbers(ctx context.Context, partitionID types.PartitionID, blockNumbers []uint64) ([]*domain.BlockInfo, error)
 
 		//tx
 		GetTxByHash(ctx context.Context, txHash domain.TxHash) (res *domain.TxInfo, err error)
-		GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, partitionID types.PartitionID) ([]*domain.TxInfo, error)
-		GetTxsByUnitID(ctx context.Context, unitID types.UnitID) ([]*domain.TxInfo, error)
+		GetTxsPageByBlockNumber(
+			ctx context.Context, blockNumber uint64, partitionID types.PartitionID, page domain.PageRequest[domain.TxHash],
+		) (transactions []*domain.TxInfo, hasMore bool, err error)
+		GetTxsByHashes(ctx context.Context, txHashes []domain.TxHash) ([]*domain.TxInfo, error)
+		GetTxsPageByUnitID(
+			ctx context.Context, unitID types.UnitID, page domain.PageRequest[primitive.ObjectID],
+		) (transactions []*domain.TxInfo, hasMore bool, err error)
+		GetTxsPagesByUnitIDs(
+			ctx context.Context, unitIDs []types.UnitID, page domain.PageRequest[primitive.ObjectID],
+		) (map[string][]*domain.TxInfo, error)
 		GetTxsPage(
-			ctx context.Context, partitionID types.PartitionID, startID string, limit int,
-		) (transactions []*domain.TxInfo, previousID string, err error)
+			ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[primitive.ObjectID],
+		) (transactions []*domain.TxInfo, hasMore bool, err error)
+		GetTxsPages(
+			ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[primitive.ObjectID],
+		) (map[types.PartitionID][]*domain.TxInfo, error)
 		FindTxs(ctx context.Context, searchKey []byte, partitionIDs []types.PartitionID) ([]*domain.TxInfo, error)
+		ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error
 	}
 
 	PartitionService interface {
 		GetRoundNumber(ctx context.Context) ([]partition.RoundInfo, error)
+		GetPartitions(ctx context.Context) ([]*domain.PartitionInfo, error)
+		GetPartition(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionInfo, error)
 	}
 
 	MoneyService interface {
 		GetBillsByPubKeyHash(ctx context.Context, ownerID hex.Bytes) ([]*sdktypes.Bill, error)
+		GetOwnerBillIDs(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error)
+		GetTopHolders(ctx context.Context, limit int) (*moneyservice.TopHolders, error)
+		GetBalanceDistribution(ctx context.Context) (*moneyservice.Distribution, error)
+		GetMoneySupply(ctx context.Context) (*domain.MoneySupply, error)
+	}
+
+	// RawBlockService provides access to the archived CBOR encoded blocks
+	RawBlockService interface {
+		GetRawBlock(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) ([]byte, error)
+	}
+
+	TxSubmitService interface {
+		SubmitTx(ctx context.Context, partitionID types.PartitionID, tx *types.TransactionOrder) (*txsubmit.TxStatus, error)
+		GetTxStatus(ctx context.Context, txOrderHash domain.TxHash) (*txsubmit.TxStatus, error)
+	}
+
+	WebhookService interface {
+		CreateSubscription(ctx context.Context, owner, url string, filter domain.WebhookFilter) (*domain.WebhookSubscription, error)
+		GetSubscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error)
+		GetSubscription(ctx context.Context, owner string, id primitive.ObjectID) (*domain.WebhookSubscription, error)
+		DeleteSubscription(ctx context.Context, owner string, id primitive.ObjectID) error
+		GetDeliveries(ctx context.Context, owner string, id primitive.ObjectID, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+		ReplayDeliveries(ctx context.Context, owner string, id primitive.ObjectID) (int64, error)
 	}
 
 	SearchService interface {
-		Search(ctx context.Context, searchKey string, partitionIDs []types.PartitionID) (*search.Result, error)
+		Search(ctx context.Context, searchKey string, partitionIDs []types.PartitionID, hitTypes []search.HitType) (*search.Result, error)
+		Suggest(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*search.Suggestion, error)
+	}
+
+	LifecycleService interface {
+		GetLifecycle(ctx context.Context, unitID types.UnitID) (*lifecycle.Lifecycle, error)
+	}
+
+	UnitStateService interface {
+		GetUnitState(ctx context.Context, unitID types.UnitID, partitionID *types.PartitionID, includeStateProof bool) (*unitstate.UnitState, error)
+		GetUnitStateAt(ctx context.Context, unitID types.UnitID, partitionID *types.PartitionID, blockNumber uint64) (*unitstate.UnitState, error)
+	}
+
+	ValidatorService interface {
+		GetValidators(ctx context.Context, partitionID types.PartitionID, window time.Duration) (*validator.Validators, error)
+		GetValidator(ctx context.Context, partitionID types.PartitionID, nodeID string, window time.Duration) (*validator.Validator, error)
 	}
 
 	Controller struct {
@@ -69,21 +151,50 @@ type (
 		PartitionService PartitionService
 		MoneyService     MoneyService
 		SearchService    SearchService
+		LifecycleService LifecycleService
+		UnitStateService UnitStateService
+		ValidatorService ValidatorService
+		RawBlockService  RawBlockService
+		TxSubmitService  TxSubmitService
+		WebhookService   WebhookService // optional, webhooks API is disabled when nil
+		RateLimiter      *RateLimiter   // optional, requests are not limited when nil
 		rw               *ResponseWriter
+
+		gqlOnce     sync.Once
+		gqlExecutor *graphql.Executor
+		gqlErr      error
+
+		rpcOnce   sync.Once
+		rpcServer *jsonrpc.Server
 	}
 
 	RoundNumberResponse []partition.RoundInfo
 
 	SearchResponse struct {
-		Blocks  map[types.PartitionID]BlockInfo
-		Txs     []TxInfo
-		UnitIDs map[types.PartitionID][]types.UnitID
-		Unit    *sdktypes.Unit[any] `json:",omitempty"`
+		Hits     []SearchHit
+		Failures []*search.Failure
+		Blocks   map[types.PartitionID]BlockInfo
+		Txs      []TxInfo
+		UnitIDs  map[types.PartitionID][]types.UnitID
+		Unit     *sdktypes.Unit[any] `json:",omitempty"`
+	}
+
+	SearchHit struct {
+		Type        search.HitType
+		Match       search.MatchKind
+		PartitionID types.PartitionID
+		Block       *BlockInfo          `json:",omitempty"`
+		Tx          *TxInfo             `json:",omitempty"`
+		Unit        *sdktypes.Unit[any] `json:",omitempty"`
+		UnitIDs     []types.UnitID      `json:",omitempty"`
+		Token       *domain.TokenText   `json:",omitempty"`
+		Similarity  float64             `json:",omitempty"`
 	}
 
 	BlockResponse map[types.PartitionID]BlockInfo
 
 	BlockInfo struct {
+		BlockHash          hex.Bytes
 		PartitionID        types.PartitionID
 		PartitionTypeID    types.PartitionTypeID
 		ShardID            types.ShardID
@@ -108,6 +219,13 @@ func NewController(
 	PartitionService PartitionService,
 	MoneyService MoneyService,
 	searchService SearchService,
+	lifecycleService LifecycleService,
+	unitStateService UnitStateService,
+	validatorService ValidatorService,
+	rawBlockService RawBlockService,
+	txSubmitService TxSubmitService,
+	webhookService WebhookService,
+	rateLimiter *RateLimiter,
 ) (*Controller, error) {
 	if StorageService == nil {
 		return nil, errors.New("storage service is nil")
@@ -121,47 +239,52 @@ func NewController(
 	if searchService == nil {
 		return nil, errors.New("search service is nil")
 	}
+	if lifecycleService == nil {
+		return nil, errors.New("lifecycle service is nil")
+	}
+	if unitStateService == nil {
+		return nil, errors.New("unit state service is nil")
+	}
+	if validatorService == nil {
+		return nil, errors.New("validator service is nil")
+	}
+	if txSubmitService == nil {
+		return nil, errors.New("tx submit service is nil")
+	}
 
 	return &Controller{
 		StorageService:   StorageService,
 		PartitionService: PartitionService,
 		MoneyService:     MoneyService,
 		SearchService:    searchService,
+		LifecycleService: lifecycleService,
+		UnitStateService: unitStateService,
+		ValidatorService: validatorService,
+		RawBlockService:  rawBlockService,
+		TxSubmitService:  txSubmitService,
+		WebhookService:   webhookService,
+		RateLimiter:      rateLimiter,
 		rw:               &ResponseWriter{},
 	}, nil
 }
 
-// @Summary Retrieve round and epoch number for each partition
-// @Description Retrieve round and epoch number for each partition
-// @Tags Info
-// @Produce json
-// @Success 200 {array} partition.RoundInfo
-// @Router /round-number [get]
+// roundNumber retrieves round and epoch number for each partition.
 func (c *Controller) roundNumber(w http.ResponseWriter, r *http.Request) {
 	roundInfos, err := c.PartitionService.GetRoundNumber(r.Context())
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, err)
+		c.rw.WriteInternalErrorResponse(w, r, err)
 		return
 	}
-	c.rw.WriteResponse(w, roundInfos)
+	c.rw.WriteCacheableResponse(w, r, roundInfos, cacheControlLatest)
 }
 
-// @Summary Retrieve blocks and transactions matching the search key
-// @Description Retrieve blocks and transactions matching the search key
-// @Tags Search
-// @Produce json
-// @Param q query string true "Search key"
-// @Param partitionID query int false "Filter results by partition ID(s)"
-// @Success 200 {object} SearchResponse "Block information successfully retrieved"
-// @Failure 400 {object} string "Empty search key"
-// @Failure 400 {object} string "invalid partitionID"
-// @Failure 404 {object} string "no results found"
-// @Router /search [get]
+// search retrieves the hits matching the search key ranked by the match kind, failed lookups of the partitions are
+// reported in Failures.
 func (c *Controller) search(w http.ResponseWriter, r *http.Request) {
 	qp := r.URL.Query()
 	searchKey := qp.Get(paramSearchKey)
 	if searchKey == "" {
-		c.rw.WriteMissingParamResponse(w, paramSearchKey)
+		c.rw.WriteMissingParamResponse(w, r, paramSearchKey)
 		return
 	}
 
@@ -169,36 +292,113 @@ func (c *Controller) search(w http.ResponseWriter, r *http.Request) {
 	for _, pid := range qp[paramPartitionID] {
 		id, err := strconv.ParseUint(pid, 10, 64)
 		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramPartitionID)
+			c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
 			return
 		}
 		partitionIDs = append(partitionIDs, types.PartitionID(id))
 	}
+	var hitTypes []search.HitType
+	for _, t := range qp[paramHitType] {
+		hitType, err := search.ParseHitType(t)
+		if err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramHitType)
+			return
+		}
+		hitTypes = append(hitTypes, hitType)
+	}
 
-	result, err := c.SearchService.Search(r.Context(), searchKey, partitionIDs)
+	result, err := c.SearchService.Search(r.Context(), searchKey, partitionIDs, hitTypes)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrFailedToDecodeHex) {
-			c.rw.WriteErrorResponse(w, fmt.Errorf("no results found for '%s'", searchKey), http.StatusNotFound)
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("no results found for '%s'", searchKey), http.StatusNotFound)
 			return
 		}
-		c.rw.WriteInternalErrorResponse(w, err)
+		c.rw.WriteInternalErrorResponse(w, r, err)
 		return
 	}
 
-	if len(result.Txs) == 0 && len(result.Blocks) == 0 && len(result.UnitIDs) == 0 && result.Unit == nil {
-		c.rw.WriteErrorResponse(w, fmt.Errorf("no results found for '%s'", searchKey), http.StatusNotFound)
+	if len(result.Hits) == 0 && len(result.Failures) == 0 {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("no results found for '%s'", searchKey), http.StatusNotFound)
 		return
 	}
 
-	c.rw.WriteResponse(w, formatSearchResponse(result))
+	c.rw.WriteResponse(w, r, formatSearchResponse(result))
+}
+
+// suggest retrieves the transaction hashes, unit IDs and block hashes starting with the hex prefix.
+func (c *Controller) suggest(w http.ResponseWriter, r *http.Request) {
+	qp := r.URL.Query()
+	prefix := qp.Get(paramSearchKey)
+	if prefix == "" {
+		c.rw.WriteMissingParamResponse(w, r, paramSearchKey)
+		return
+	}
+
+	var partitionIDs []types.PartitionID
+	for _, pid := range qp[paramPartitionID] {
+		id, err := strconv.ParseUint(pid, 10, 64)
+		if err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+			return
+		}
+		partitionIDs = append(partitionIDs, types.PartitionID(id))
+	}
+	limit := defaultSuggestLimit
+	if s := qp.Get(paramLimit); s != "" {
+		var err error
+		if limit, err = ParseMaxResponseItems(s, maxSuggestLimit); err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+			return
+		}
+	}
+
+	suggestions, err := c.SearchService.Suggest(r.Context(), prefix, partitionIDs, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrFailedToDecodeHex) || errors.Is(err, search.ErrPrefixTooShort) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("invalid %s: %w", paramSearchKey, err))
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	if suggestions == nil {
+		suggestions = []*search.Suggestion{}
+	}
+	c.rw.WriteResponse(w, r, suggestions)
 }
 
 func formatSearchResponse(result *search.Result) SearchResponse {
 	response := SearchResponse{
-		Blocks:  make(map[types.PartitionID]BlockInfo),
-		Txs:     []TxInfo{},
-		UnitIDs: make(map[types.PartitionID][]types.UnitID),
-		Unit:    result.Unit,
+		Hits:     []SearchHit{},
+		Failures: []*search.Failure{},
+		Blocks:   make(map[types.PartitionID]BlockInfo),
+		Txs:      []TxInfo{},
+		UnitIDs:  make(map[types.PartitionID][]types.UnitID),
+		Unit:     result.Unit,
+	}
+
+	for _, hit := range result.Hits {
+		h := SearchHit{
+			Type:        hit.Type,
+			Match:       hit.Match,
+			PartitionID: hit.PartitionID,
+			Unit:        hit.Unit,
+			UnitIDs:     hit.UnitIDs,
+			Token:       hit.Token,
+			Similarity:  hit.Similarity,
+		}
+		if hit.Block != nil {
+			block := blockInfoResponse(hit.Block)
+			h.Block = &block
+		}
+		if hit.Tx != nil {
+			tx := txInfoResponse(hit.Tx)
+			h.Tx = &tx
+		}
+		response.Hits = append(response.Hits, h)
+	}
+	if result.Failures != nil {
+		response.Failures = result.Failures
 	}
 
 	for partitionID, block := range result.Blocks {
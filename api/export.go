@@ -0,0 +1,339 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/gorilla/mux"
+)
+
+const (
+	TextCsv              = "text/csv"
+	ApplicationJsonLines = "application/x-ndjson"
+
+	paramFormat   = "format"
+	paramEndBlock = "endBlock"
+	paramFrom     = "from"
+	paramTo       = "to"
+
+	exportFormatCsv       = "csv"
+	exportFormatJsonLines = "jsonl"
+
+	// the export is flushed to the client after this many rows
+	exportFlushRows = 500
+	// exports take longer than the server write timeout, the deadline is extended on every flush
+	exportWriteTimeout = 30 * time.Second
+)
+
+var exportCsvHeader = []string{
+	"Time", "PartitionID", "BlockNumber", "TxRecordHash", "TxOrderHash", "TxType", "UnitID", "Successful", "Amount", "Fee",
+}
+
+type (
+	// ExportedTx is a row of the transaction export.
+	ExportedTx struct {
+		Time         string // block time in RFC 3339 format, empty when the block is not stored
+		PartitionID  types.PartitionID
+		BlockNumber  uint64
+		TxRecordHash string
+		TxOrderHash  string
+		TxType       uint16
+		UnitID       string
+		Successful   bool
+		Amount       *uint64 `json:",omitempty"` // transferred amount of the money partition transactions
+		Fee          uint64
+	}
+
+	txExportEncoder interface {
+		Encode(tx *ExportedTx) error
+		Flush() error
+	}
+
+	csvExportEncoder struct {
+		w *csv.Writer
+	}
+
+	jsonLinesExportEncoder struct {
+		w   *bufio.Writer
+		enc *json.Encoder
+	}
+
+	// countingWriter counts the bytes written to the response, once something is written
+	// the error can no longer be reported with the error response.
+	countingWriter struct {
+		w io.Writer
+		n int64
+	}
+)
+
+// exportUnitTxs streams the transactions targeting the unit, oldest first, as CSV or JSON lines.
+func (c *Controller) exportUnitTxs(w http.ResponseWriter, r *http.Request) {
+	unitID, err := util.FromHex([]byte(mux.Vars(r)[paramUnitID]))
+	if err != nil || len(unitID) == 0 {
+		c.rw.WriteInvalidParamResponse(w, r, paramUnitID)
+		return
+	}
+	filter, format, ok := c.parseExportParams(w, r)
+	if !ok {
+		return
+	}
+	filter.UnitIDs = []types.UnitID{unitID}
+	c.exportTxs(w, r, filter, format, "txs-"+string(util.ToHex(unitID)))
+}
+
+// exportAddressTxs streams the transactions targeting the bills the public key owns or has owned since the oldest stored
+// block, oldest first, as CSV or JSON lines.
+func (c *Controller) exportAddressTxs(w http.ResponseWriter, r *http.Request) {
+	pubKeyStr := mux.Vars(r)[paramPubKey]
+	address, err := predicate.ParseAddress(pubKeyStr)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramPubKey)
+		return
+	}
+	filter, format, ok := c.parseExportParams(w, r)
+	if !ok {
+		return
+	}
+
+	unitIDs, err := c.MoneyService.GetOwnerBillIDs(r.Context(), address.OwnerID)
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load bills with pubKey %s : %w", pubKeyStr, err))
+		return
+	}
+	if len(unitIDs) == 0 {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("bills with pubKey %s not found", pubKeyStr), http.StatusNotFound)
+		return
+	}
+	filter.UnitIDs = unitIDs
+	c.exportTxs(w, r, filter, format, "txs-"+pubKeyStr)
+}
+
+func (c *Controller) parseExportParams(w http.ResponseWriter, r *http.Request) (domain.TxExportFilter, string, bool) {
+	var filter domain.TxExportFilter
+	qp := r.URL.Query()
+
+	format := qp.Get(paramFormat)
+	switch format {
+	case "":
+		format = exportFormatCsv
+	case exportFormatCsv, exportFormatJsonLines:
+	default:
+		c.rw.WriteInvalidParamResponse(w, r, paramFormat)
+		return filter, "", false
+	}
+
+	var err error
+	if s := qp.Get(paramStartBlock); s != "" {
+		if filter.StartBlock, err = strconv.ParseUint(s, 10, 64); err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramStartBlock)
+			return filter, "", false
+		}
+	}
+	if s := qp.Get(paramEndBlock); s != "" {
+		if filter.EndBlock, err = strconv.ParseUint(s, 10, 64); err != nil || filter.EndBlock < filter.StartBlock {
+			c.rw.WriteInvalidParamResponse(w, r, paramEndBlock)
+			return filter, "", false
+		}
+	}
+	if s := qp.Get(paramFrom); s != "" {
+		if filter.StartTime, err = parseExportTime(s, false); err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramFrom)
+			return filter, "", false
+		}
+	}
+	if s := qp.Get(paramTo); s != "" {
+		if filter.EndTime, err = parseExportTime(s, true); err != nil || filter.EndTime.Before(filter.StartTime) {
+			c.rw.WriteInvalidParamResponse(w, r, paramTo)
+			return filter, "", false
+		}
+	}
+	return filter, format, true
+}
+
+/*
+exportTxs streams the transactions matching the filter to the client. Rows are flushed in batches so the
+export is never held in memory. When loading the transactions fails after the response has been started
+the connection is aborted so that the client doesn't mistake the truncated export for a complete one.
+*/
+func (c *Controller) exportTxs(w http.ResponseWriter, r *http.Request, filter domain.TxExportFilter, format, filename string) {
+	rc := http.NewResponseController(w)
+	cw := &countingWriter{w: w}
+	var encoder txExportEncoder
+	if format == exportFormatJsonLines {
+		w.Header().Set(ContentType, ApplicationJsonLines)
+		encoder = newJsonLinesExportEncoder(cw)
+		filename += ".jsonl"
+	} else {
+		w.Header().Set(ContentType, TextCsv)
+		encoder = newCsvExportEncoder(cw)
+		filename += ".csv"
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	flush := func() error {
+		if err := encoder.Flush(); err != nil {
+			return err
+		}
+		// not all writers support flushing and deadlines (eg the test recorder), errors are ignored
+		_ = rc.Flush()
+		_ = rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		return nil
+	}
+
+	rows := 0
+	err := c.StorageService.ExportTxs(r.Context(), filter, func(tx *domain.BlockTx) error {
+		if err := encoder.Encode(newExportedTx(tx)); err != nil {
+			return fmt.Errorf("failed to encode transaction: %w", err)
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if cw.n == 0 {
+			w.Header().Del("Content-Disposition")
+			c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to export transactions: %w", err))
+			return
+		}
+		log.Error("transaction export aborted", "rows", rows, "err", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func newExportedTx(tx *domain.BlockTx) *ExportedTx {
+	row := &ExportedTx{
+		PartitionID:  tx.PartitionID,
+		BlockNumber:  tx.BlockNumber,
+		TxRecordHash: string(util.ToHex(tx.TxRecordHash)),
+		TxOrderHash:  string(util.ToHex(tx.TxOrderHash)),
+	}
+	if tx.Timestamp > 0 {
+		row.Time = time.Unix(int64(tx.Timestamp), 0).UTC().Format(time.RFC3339)
+	}
+	if tx.Transaction == nil {
+		return row
+	}
+	row.Successful = tx.Transaction.TxStatus() == types.TxStatusSuccessful
+	row.Fee = tx.Transaction.GetActualFee()
+	txo, err := tx.Transaction.GetTransactionOrderV1()
+	if err != nil {
+		return row
+	}
+	row.TxType = txo.Type
+	row.UnitID = string(util.ToHex(txo.UnitID))
+	if tx.PartitionTypeID == money.PartitionTypeID {
+		if amount, ok := moneyTxAmount(txo); ok {
+			row.Amount = &amount
+		}
+	}
+	return row
+}
+
+// moneyTxAmount returns the amount transferred by the money partition transaction.
+func moneyTxAmount(txo *types.TransactionOrder) (uint64, bool) {
+	switch txo.Type {
+	case money.TransactionTypeTransfer:
+		var attr money.TransferAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return 0, false
+		}
+		return attr.TargetValue, true
+	case money.TransactionTypeSplit:
+		var attr money.SplitAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return 0, false
+		}
+		var amount uint64
+		for _, unit := range attr.TargetUnits {
+			amount += unit.Amount
+		}
+		return amount, true
+	case money.TransactionTypeTransDC:
+		var attr money.TransferDCAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return 0, false
+		}
+		return attr.Value, true
+	}
+	return 0, false
+}
+
+// parseExportTime parses RFC 3339 time or date, date is the start of the day or the end of the day when endOfDay is set.
+func parseExportTime(s string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+func newCsvExportEncoder(w io.Writer) *csvExportEncoder {
+	e := &csvExportEncoder{w: csv.NewWriter(w)}
+	_ = e.w.Write(exportCsvHeader)
+	return e
+}
+
+func (e *csvExportEncoder) Encode(tx *ExportedTx) error {
+	var amount string
+	if tx.Amount != nil {
+		amount = strconv.FormatUint(*tx.Amount, 10)
+	}
+	return e.w.Write([]string{
+		tx.Time,
+		strconv.FormatUint(uint64(tx.PartitionID), 10),
+		strconv.FormatUint(tx.BlockNumber, 10),
+		tx.TxRecordHash,
+		tx.TxOrderHash,
+		strconv.FormatUint(uint64(tx.TxType), 10),
+		tx.UnitID,
+		strconv.FormatBool(tx.Successful),
+		amount,
+		strconv.FormatUint(tx.Fee, 10),
+	})
+}
+
+func (e *csvExportEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func newJsonLinesExportEncoder(w io.Writer) *jsonLinesExportEncoder {
+	bw := bufio.NewWriter(w)
+	return &jsonLinesExportEncoder{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (e *jsonLinesExportEncoder) Encode(tx *ExportedTx) error {
+	return e.enc.Encode(tx)
+}
+
+func (e *jsonLinesExportEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newExportTestTx(t *testing.T, txType uint16, attr any) *domain.BlockTx {
+	attrBytes, err := types.Cbor.Marshal(attr)
+	require.NoError(t, err)
+	txo := &types.TransactionOrder{Payload: types.Payload{PartitionID: 1, Type: txType, UnitID: []byte{1}, Attributes: attrBytes}}
+	txoBytes, err := txo.MarshalCBOR()
+	require.NoError(t, err)
+	return &domain.BlockTx{
+		TxInfo: domain.TxInfo{
+			TxRecordHash: []byte{0xA},
+			TxOrderHash:  []byte{0xB},
+			BlockNumber:  7,
+			PartitionID:  1,
+			Transaction: &types.TransactionRecord{
+				TransactionOrder: txoBytes,
+				ServerMetadata:   &types.ServerMetadata{ActualFee: 2, SuccessIndicator: types.TxStatusSuccessful},
+			},
+		},
+		PartitionTypeID: money.PartitionTypeID,
+		Timestamp:       1700000000,
+	}
+}
+
+func TestExportUnitTxs(t *testing.T) {
+	transfer := newExportTestTx(t, money.TransactionTypeTransfer, &money.TransferAttributes{TargetValue: 100})
+	split := newExportTestTx(t, money.TransactionTypeSplit, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{Amount: 5}, {Amount: 6}}})
+	lock := newExportTestTx(t, money.TransactionTypeLock, []any{})
+
+	storage := mocks.NewStorageService(t)
+	restapi := &Controller{StorageService: storage, PartitionService: partitionServiceStub{}}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	expectExport := func(filter domain.TxExportFilter, txs ...*domain.BlockTx) {
+		storage.EXPECT().ExportTxs(mock.Anything, filter, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ domain.TxExportFilter, fn func(*domain.BlockTx) error) error {
+				for _, tx := range txs {
+					if err := fn(tx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}).Once()
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		expectExport(domain.TxExportFilter{UnitIDs: []types.UnitID{{1}}, StartBlock: 5, EndBlock: 10}, transfer, split, lock)
+		res, err := http.Get(ts.URL + "/api/v1/units/0x01/txs/export?startBlock=5&endBlock=10")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, TextCsv, res.Header.Get(ContentType))
+		require.Equal(t, `attachment; filename="txs-0x01.csv"`, res.Header.Get("Content-Disposition"))
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			exportCsvHeader,
+			{"2023-11-14T22:13:20Z", "1", "7", "0x0a", "0x0b", "1", "0x01", "true", "100", "2"},
+			{"2023-11-14T22:13:20Z", "1", "7", "0x0a", "0x0b", "2", "0x01", "true", "11", "2"},
+			{"2023-11-14T22:13:20Z", "1", "7", "0x0a", "0x0b", "5", "0x01", "true", "", "2"},
+		}, records)
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC)
+		expectExport(domain.TxExportFilter{UnitIDs: []types.UnitID{{1}}, StartTime: from, EndTime: to}, transfer, lock)
+		res, err := http.Get(ts.URL + "/api/v1/units/0x01/txs/export?format=jsonl&from=2023-11-01&to=2023-11-30")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, ApplicationJsonLines, res.Header.Get(ContentType))
+
+		var rows []*ExportedTx
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var row ExportedTx
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			rows = append(rows, &row)
+		}
+		require.Len(t, rows, 2)
+		require.EqualValues(t, 100, *rows[0].Amount)
+		require.Nil(t, rows[1].Amount)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"format=xls", "startBlock=x", "startBlock=10&endBlock=5", "from=yesterday", "from=2023-11-02&to=2023-11-01"} {
+			res, err := http.Get(ts.URL + "/api/v1/units/0x01/txs/export?" + query)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		storage.EXPECT().ExportTxs(mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+		res, err := http.Get(ts.URL + "/api/v1/units/0x01/txs/export")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		require.Equal(t, ApplicationJson, res.Header.Get(ContentType))
+		require.Empty(t, res.Header.Get("Content-Disposition"))
+	})
+}
@@ -0,0 +1,699 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/graphql"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/partition"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	paramQuery         = "query"
+	paramOperationName = "operationName"
+	paramVariables     = "variables"
+	paramFirst         = "first"
+	paramAfter         = "after"
+)
+
+// graphQLLimits protect the database from expensive queries, complexity is the (estimated) number
+// of fields the query resolves, lists without "limit" or "first" argument are expected to have 10 items.
+var graphQLLimits = graphql.Limits{
+	MaxDepth:        8,
+	MaxComplexity:   5000,
+	DefaultListSize: defaultBlocksPageLimit,
+}
+
+type (
+	// gqlUnit is the source object of the Unit type
+	gqlUnit struct {
+		ID types.UnitID
+	}
+
+	// gqlTx is the source object of the Transaction type, the cursor of the transaction pages the list the
+	// transaction was loaded from
+	gqlTx struct {
+		*domain.TxInfo
+		list string
+	}
+
+	// gqlAddress is the source object of the Address type
+	gqlAddress struct {
+		PubKey     string
+		PubKeyHash []byte
+	}
+)
+
+// graphQL executes GraphQL query, the request is either JSON encoded graphql.Request in the POST body or
+// "query", "operationName" and "variables" query parameters of the GET request. The handler is mounted
+// outside of the versioned REST API.
+func (c *Controller) graphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		qp := r.URL.Query()
+		req.Query = qp.Get(paramQuery)
+		req.OperationName = qp.Get(paramOperationName)
+		if vars := qp.Get(paramVariables); vars != "" {
+			dec := json.NewDecoder(strings.NewReader(vars))
+			dec.UseNumber()
+			if err := dec.Decode(&req.Variables); err != nil {
+				c.rw.WriteInvalidParamResponse(w, r, paramVariables)
+				return
+			}
+		}
+	} else {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&req); err != nil {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("failed to decode request body: %w", err))
+			return
+		}
+	}
+	if req.Query == "" {
+		c.rw.WriteMissingParamResponse(w, r, paramQuery)
+		return
+	}
+
+	executor, err := c.graphQLExecutor()
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	c.rw.WriteJsonResponse(w, executor.Execute(r.Context(), &req))
+}
+
+// graphQLSchemaSDL returns the GraphQL schema in the schema definition language.
+func (c *Controller) graphQLSchemaSDL(w http.ResponseWriter, r *http.Request) {
+	executor, err := c.graphQLExecutor()
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	c.rw.WriteRawResponse(w, "text/plain; charset=utf-8", []byte(executor.Schema().SDL()))
+}
+
+func (c *Controller) graphQLExecutor() (*graphql.Executor, error) {
+	c.gqlOnce.Do(func() {
+		var schema *graphql.Schema
+		if schema, c.gqlErr = c.graphQLSchema(); c.gqlErr != nil {
+			c.gqlErr = fmt.Errorf("failed to create GraphQL schema: %w", c.gqlErr)
+			return
+		}
+		c.gqlExecutor, c.gqlErr = graphql.NewExecutor(schema, graphQLLimits)
+	})
+	return c.gqlExecutor, c.gqlErr
+}
+
+func (c *Controller) graphQLSchema() (*graphql.Schema, error) {
+	partitionType := &graphql.Object{
+		Name:        "Partition",
+		Description: "Partition indexed by the explorer",
+		Fields: []*graphql.Field{
+			{Name: "id", Type: "Int!", Resolve: graphql.Property(func(p *partition.RoundInfo) any { return p.PartitionID })},
+			{Name: "typeID", Type: "Int!", Resolve: graphql.Property(func(p *partition.RoundInfo) any { return p.PartitionTypeID })},
+			{Name: "roundNumber", Type: "Int!", Resolve: graphql.Property(func(p *partition.RoundInfo) any { return p.RoundNumber })},
+			{Name: "epochNumber", Type: "Int!", Resolve: graphql.Property(func(p *partition.RoundInfo) any { return p.EpochNumber })},
+			{
+				Name:        "lowestBlockNumber",
+				Description: "The first block available in the explorer",
+				Type:        "Int!",
+				Resolve:     graphql.Property(func(p *partition.RoundInfo) any { return p.LowestBlockNumber }),
+			},
+			{
+				Name:        "blocks",
+				Description: "Blocks of the partition, latest first",
+				Type:        "[Block!]!",
+				Args:        blocksArgs(),
+				Resolve:     c.gqlPartitionBlocks,
+			},
+			{
+				Name:        "transactions",
+				Description: "Transactions of the partition, latest first",
+				Type:        "[Transaction!]!",
+				Args:        txsArgs(),
+				Resolve:     c.gqlPartitionTxs,
+			},
+		},
+	}
+
+	blockType := &graphql.Object{
+		Name: "Block",
+		Fields: []*graphql.Field{
+			{Name: "number", Type: "Int!", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return b.BlockNumber })},
+			{Name: "partitionID", Type: "Int!", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return b.PartitionID })},
+			{Name: "hash", Type: "String", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return hexString(b.BlockHash) })},
+			{Name: "previousBlockHash", Type: "String", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return hexString(b.PreviousBlockHash) })},
+			{Name: "proposerID", Type: "String!", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return b.ProposerID })},
+			{Name: "timestamp", Type: "Int!", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return b.Timestamp })},
+			{Name: "txCount", Type: "Int!", Resolve: graphql.Property(func(b *domain.BlockInfo) any { return len(b.TxHashes) })},
+			{Name: "transactions", Type: "[Transaction!]!", Resolve: c.gqlBlockTxs},
+		},
+	}
+
+	txType := &graphql.Object{
+		Name: "Transaction",
+		Fields: []*graphql.Field{
+			{Name: "hash", Type: "String!", Resolve: graphql.Property(func(tx *gqlTx) any { return hexString(tx.TxRecordHash) })},
+			{Name: "orderHash", Type: "String!", Resolve: graphql.Property(func(tx *gqlTx) any { return hexString(tx.TxOrderHash) })},
+			{Name: "partitionID", Type: "Int!", Resolve: graphql.Property(func(tx *gqlTx) any { return tx.PartitionID })},
+			{Name: "blockNumber", Type: "Int!", Resolve: graphql.Property(func(tx *gqlTx) any { return tx.BlockNumber })},
+			{
+				Name: "type",
+				Type: "Int",
+				Resolve: graphql.Property(func(tx *gqlTx) any {
+					if tx.Transaction == nil {
+						return nil
+					}
+					txo, err := tx.Transaction.GetTransactionOrderV1()
+					if err != nil {
+						return nil
+					}
+					return txo.Type
+				}),
+			},
+			{
+				Name:        "success",
+				Description: "True when the transaction was executed successfully",
+				Type:        "Boolean!",
+				Resolve: graphql.Property(func(tx *gqlTx) any {
+					return tx.Transaction != nil && tx.Transaction.TxStatus() == types.TxStatusSuccessful
+				}),
+			},
+			{
+				Name: "fee",
+				Type: "Int!",
+				Resolve: graphql.Property(func(tx *gqlTx) any {
+					if tx.Transaction == nil {
+						return 0
+					}
+					return tx.Transaction.GetActualFee()
+				}),
+			},
+			{Name: "targetUnits", Type: "[Unit!]!", Resolve: graphql.Property(func(tx *gqlTx) any { return txTargetUnits(tx.TxInfo) })},
+			{Name: "block", Type: "Block", Resolve: c.gqlTxBlocks},
+			{
+				Name:        "cursor",
+				Description: "Cursor for paging the transactions after this transaction",
+				Type:        "String!",
+				Resolve: graphql.Property(func(tx *gqlTx) any {
+					return (&pageCursor{Version: cursorVersion, List: tx.list, Key: txObjectIDKey(tx.TxInfo)}).String()
+				}),
+			},
+		},
+	}
+
+	unitType := &graphql.Object{
+		Name: "Unit",
+		Fields: []*graphql.Field{
+			{Name: "id", Type: "String!", Resolve: graphql.Property(func(u *gqlUnit) any { return hexString(u.ID) })},
+			{
+				Name:        "transactions",
+				Description: "Transactions targeting the unit, latest first",
+				Type:        "[Transaction!]!",
+				Args:        connectionArgs("Cursor of the transaction to continue after", defaultTxsPageLimit),
+				Resolve:     c.gqlUnitTxs,
+			},
+		},
+	}
+
+	billType := &graphql.Object{
+		Name: "Bill",
+		Fields: []*graphql.Field{
+			{Name: "id", Type: "String!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return hexString(b.ID) })},
+			{Name: "partitionID", Type: "Int!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return b.PartitionID })},
+			{Name: "value", Type: "Int!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return b.Value })},
+			{Name: "counter", Type: "Int!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return b.Counter })},
+			{Name: "lockStatus", Type: "Int!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return b.LockStatus })},
+			{Name: "unit", Type: "Unit!", Resolve: graphql.Property(func(b *sdktypes.Bill) any { return &gqlUnit{ID: b.ID} })},
+			{
+				Name:        "cursor",
+				Description: "Cursor for paging the bills after this bill",
+				Type:        "String!",
+				Resolve: graphql.Property(func(b *sdktypes.Bill) any {
+					return (&pageCursor{Version: cursorVersion, List: listBills, Key: b.ID}).String()
+				}),
+			},
+		},
+	}
+
+	addressType := &graphql.Object{
+		Name: "Address",
+		Fields: []*graphql.Field{
+			{Name: "pubKey", Type: "String!", Resolve: graphql.Property(func(a *gqlAddress) any { return a.PubKey })},
+			{Name: "pubKeyHash", Type: "String!", Resolve: graphql.Property(func(a *gqlAddress) any { return hexString(a.PubKeyHash) })},
+			{
+				Name:        "bills",
+				Description: "Bills owned by the address, ordered by the bill ID",
+				Type:        "[Bill!]!",
+				Args:        connectionArgs("Cursor of the bill to continue after", defaultBillsPageLimit),
+				Resolve:     c.gqlAddressBills,
+			},
+		},
+	}
+
+	queryType := &graphql.Object{
+		Name: "Query",
+		Fields: []*graphql.Field{
+			{
+				Name:    "partitions",
+				Type:    "[Partition!]!",
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, _ graphql.Args) (any, error) { return c.gqlPartitions(ctx) }),
+			},
+			{
+				Name: "partition",
+				Type: "Partition",
+				Args: []*graphql.Argument{{Name: "id", Type: "Int!"}},
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					id, _ := args.Int("id")
+					partitions, err := c.gqlPartitions(ctx)
+					if err != nil {
+						return nil, err
+					}
+					for _, p := range partitions {
+						if p.PartitionID == types.PartitionID(id) {
+							return p, nil
+						}
+					}
+					return nil, nil
+				}),
+			},
+			{
+				Name: "block",
+				Type: "Block",
+				Args: []*graphql.Argument{{Name: "partitionID", Type: "Int!"}, {Name: "number", Type: "Int!"}},
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					pid, _ := args.Int("partitionID")
+					number, _ := args.Int("number")
+					if number < 0 {
+						return nil, fmt.Errorf("invalid block number %d", number)
+					}
+					blocks, err := c.StorageService.GetBlock(ctx, uint64(number), []types.PartitionID{types.PartitionID(pid)})
+					if err != nil {
+						return nil, gqlStoreError(fmt.Errorf("failed to load block: %w", err))
+					}
+					return blocks[types.PartitionID(pid)], nil
+				}),
+			},
+			{
+				Name:        "blocks",
+				Description: "Blocks of the partition, latest first",
+				Type:        "[Block!]!",
+				Args:        append([]*graphql.Argument{{Name: "partitionID", Type: "Int!"}}, blocksArgs()...),
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					pid, _ := args.Int("partitionID")
+					return c.gqlBlocks(ctx, types.PartitionID(pid), args)
+				}),
+			},
+			{
+				Name: "transaction",
+				Type: "Transaction",
+				Args: []*graphql.Argument{{Name: "hash", Type: "String!", Description: "Transaction record or order hash (HEX encoded)"}},
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					hash, _ := args.String("hash")
+					txHash, err := util.DecodeHex(hash)
+					if err != nil {
+						return nil, fmt.Errorf("invalid transaction hash %q", hash)
+					}
+					tx, err := c.StorageService.GetTxByHash(ctx, txHash)
+					if err != nil {
+						return nil, gqlStoreError(fmt.Errorf("failed to load transaction: %w", err))
+					}
+					return &gqlTx{TxInfo: tx, list: listTxs}, nil
+				}),
+			},
+			{
+				Name:        "transactions",
+				Description: "Transactions of the partition, latest first",
+				Type:        "[Transaction!]!",
+				Args:        append([]*graphql.Argument{{Name: "partitionID", Type: "Int!"}}, txsArgs()...),
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					pid, _ := args.Int("partitionID")
+					return c.gqlTxs(ctx, types.PartitionID(pid), args)
+				}),
+			},
+			{
+				Name: "unit",
+				Type: "Unit!",
+				Args: []*graphql.Argument{{Name: "id", Type: "String!", Description: "Unit ID (0xHEX encoded)"}},
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					id, _ := args.String("id")
+					unitID, err := util.FromHex([]byte(id))
+					if err != nil {
+						return nil, fmt.Errorf("invalid unit ID %q", id)
+					}
+					return &gqlUnit{ID: unitID}, nil
+				}),
+			},
+			{
+				Name: "address",
+				Type: "Address!",
+				Args: []*graphql.Argument{{Name: "pubKey", Type: "String!", Description: "Public key, public key hash or owner predicate (HEX or base64 encoded)"}},
+				Resolve: graphql.ResolveEach(func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+					pubKey, _ := args.String("pubKey")
+					address, err := predicate.ParseAddress(pubKey)
+					if err != nil {
+						return nil, fmt.Errorf("invalid address %q", pubKey)
+					}
+					return &gqlAddress{PubKey: pubKey, PubKeyHash: address.OwnerID}, nil
+				}),
+			},
+		},
+	}
+
+	return graphql.NewSchema(queryType, partitionType, blockType, txType, unitType, billType, addressType)
+}
+
+func blocksArgs() []*graphql.Argument {
+	return []*graphql.Argument{
+		{Name: "startBlock", Type: "Int", Description: "Block number to start from, latest blocks are returned when not set"},
+		{Name: "limit", Type: "Int", Default: int64(defaultBlocksPageLimit)},
+		{Name: "includeEmpty", Type: "Boolean", Default: true},
+	}
+}
+
+func txsArgs() []*graphql.Argument {
+	return []*graphql.Argument{
+		{Name: "cursor", Type: "String", Description: "Cursor of the transaction to continue after, latest transactions are returned when not set"},
+		{Name: "limit", Type: "Int", Default: int64(defaultTxsPageLimit)},
+	}
+}
+
+// connectionArgs are the arguments of the lists which are paged with the cursor of the last item.
+func connectionArgs(afterDescription string, defaultLimit int) []*graphql.Argument {
+	return []*graphql.Argument{
+		{Name: paramFirst, Type: "Int", Default: int64(defaultLimit)},
+		{Name: paramAfter, Type: "String", Description: afterDescription + ", the first items are returned when not set"},
+	}
+}
+
+func gqlLimit(args graphql.Args, name string, defaultLimit int) (int, error) {
+	limit, ok := args.Int(name)
+	if !ok {
+		return defaultLimit, nil
+	}
+	if limit <= 0 || limit > maxPageLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+	}
+	return int(limit), nil
+}
+
+func (c *Controller) gqlPartitions(ctx context.Context) ([]*partition.RoundInfo, error) {
+	roundInfos, err := c.PartitionService.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load partitions: %w", err))
+	}
+	res := make([]*partition.RoundInfo, len(roundInfos))
+	for i := range roundInfos {
+		res[i] = &roundInfos[i]
+	}
+	return res, nil
+}
+
+func (c *Controller) gqlBlocks(ctx context.Context, partitionID types.PartitionID, args graphql.Args) ([]*domain.BlockInfo, error) {
+	page, includeEmpty, err := gqlBlocksPage(args)
+	if err != nil {
+		return nil, err
+	}
+	blocks, _, err := c.StorageService.GetBlocksPage(ctx, partitionID, page, includeEmpty)
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load blocks: %w", err))
+	}
+	return blocks, nil
+}
+
+// gqlPartitionBlocks loads the blocks of all the partitions with a single store call.
+func (c *Controller) gqlPartitionBlocks(ctx context.Context, sources []any, args graphql.Args) ([]any, error) {
+	page, includeEmpty, err := gqlBlocksPage(args)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := c.StorageService.GetBlocksPages(ctx, gqlPartitionIDs(sources), page, includeEmpty)
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load blocks: %w", err))
+	}
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		partitionBlocks := blocks[src.(*partition.RoundInfo).PartitionID]
+		if partitionBlocks == nil {
+			partitionBlocks = []*domain.BlockInfo{}
+		}
+		res[i] = partitionBlocks
+	}
+	return res, nil
+}
+
+func gqlBlocksPage(args graphql.Args) (domain.PageRequest[uint64], bool, error) {
+	limit, err := gqlLimit(args, paramLimit, defaultBlocksPageLimit)
+	if err != nil {
+		return domain.PageRequest[uint64]{}, false, err
+	}
+	includeEmpty, ok := args.Bool(paramIncludeEmpty)
+	if !ok {
+		includeEmpty = true
+	}
+	page := domain.PageRequest[uint64]{Limit: limit}
+	if startBlock, ok := args.Int(paramStartBlock); ok {
+		if startBlock < 0 {
+			return domain.PageRequest[uint64]{}, false, fmt.Errorf("invalid start block %d", startBlock)
+		}
+		// the page starts with the start block
+		if key := uint64(startBlock); key < math.MaxUint64 {
+			key++
+			page.Key = &key
+		}
+	}
+	return page, includeEmpty, nil
+}
+
+func (c *Controller) gqlTxs(ctx context.Context, partitionID types.PartitionID, args graphql.Args) ([]*gqlTx, error) {
+	page, err := gqlTxsPage(args)
+	if err != nil {
+		return nil, err
+	}
+	txs, _, err := c.StorageService.GetTxsPage(ctx, partitionID, page)
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load transactions: %w", err))
+	}
+	return gqlTxList(txs, listTxs), nil
+}
+
+// gqlPartitionTxs loads the transactions of all the partitions with a single store call.
+func (c *Controller) gqlPartitionTxs(ctx context.Context, sources []any, args graphql.Args) ([]any, error) {
+	page, err := gqlTxsPage(args)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := c.StorageService.GetTxsPages(ctx, gqlPartitionIDs(sources), page)
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load transactions: %w", err))
+	}
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		res[i] = gqlTxList(txs[src.(*partition.RoundInfo).PartitionID], listTxs)
+	}
+	return res, nil
+}
+
+func gqlTxsPage(args graphql.Args) (domain.PageRequest[primitive.ObjectID], error) {
+	limit, err := gqlLimit(args, paramLimit, defaultTxsPageLimit)
+	if err != nil {
+		return domain.PageRequest[primitive.ObjectID]{}, err
+	}
+	cursorStr, _ := args.String(QueryParamCursor)
+	cursor, err := parseCursor(cursorStr, listTxs, len(primitive.ObjectID{}))
+	if err != nil {
+		return domain.PageRequest[primitive.ObjectID]{}, fmt.Errorf("invalid cursor %q", cursorStr)
+	}
+	return pageRequest(cursor, limit, decodeObjectID), nil
+}
+
+// gqlPartitionIDs returns the distinct IDs of the partitions.
+func gqlPartitionIDs(sources []any) []types.PartitionID {
+	var ids []types.PartitionID
+	for _, src := range sources {
+		if id := src.(*partition.RoundInfo).PartitionID; !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// gqlBlockTxs loads the transactions of all the blocks with a single query.
+func (c *Controller) gqlBlockTxs(ctx context.Context, sources []any, _ graphql.Args) ([]any, error) {
+	var hashes []domain.TxHash
+	for _, src := range sources {
+		hashes = append(hashes, src.(*domain.BlockInfo).TxHashes...)
+	}
+	txs := make(map[string]*domain.TxInfo)
+	if len(hashes) > 0 {
+		loaded, err := c.StorageService.GetTxsByHashes(ctx, hashes)
+		if err != nil {
+			return nil, gqlInternalError(fmt.Errorf("failed to load transactions: %w", err))
+		}
+		for _, tx := range loaded {
+			txs[string(tx.TxRecordHash)] = tx
+		}
+	}
+
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		blockTxs := []*gqlTx{}
+		for _, hash := range src.(*domain.BlockInfo).TxHashes {
+			if tx, ok := txs[string(hash)]; ok {
+				blockTxs = append(blockTxs, &gqlTx{TxInfo: tx, list: listTxs})
+			}
+		}
+		res[i] = blockTxs
+	}
+	return res, nil
+}
+
+// gqlTxBlocks loads the blocks of all the transactions with a query per partition.
+func (c *Controller) gqlTxBlocks(ctx context.Context, sources []any, _ graphql.Args) ([]any, error) {
+	type blockKey struct {
+		partitionID types.PartitionID
+		number      uint64
+	}
+	numbers := make(map[types.PartitionID][]uint64)
+	for _, src := range sources {
+		tx := src.(*gqlTx)
+		numbers[tx.PartitionID] = append(numbers[tx.PartitionID], tx.BlockNumber)
+	}
+	blocks := make(map[blockKey]*domain.BlockInfo)
+	for partitionID, blockNumbers := range numbers {
+		loaded, err := c.StorageService.GetBlocksByNumbers(ctx, partitionID, blockNumbers)
+		if err != nil {
+			return nil, gqlInternalError(fmt.Errorf("failed to load blocks: %w", err))
+		}
+		for _, b := range loaded {
+			blocks[blockKey{b.PartitionID, b.BlockNumber}] = b
+		}
+	}
+
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		tx := src.(*gqlTx)
+		if b, ok := blocks[blockKey{tx.PartitionID, tx.BlockNumber}]; ok {
+			res[i] = b
+		}
+	}
+	return res, nil
+}
+
+// gqlUnitTxs loads a page of the transactions of all the units with a single query, the page size is limited by
+// the query.
+func (c *Controller) gqlUnitTxs(ctx context.Context, sources []any, args graphql.Args) ([]any, error) {
+	limit, err := gqlLimit(args, paramFirst, defaultTxsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+	after, _ := args.String(paramAfter)
+	cursor, err := parseCursor(after, listUnitTxs, len(primitive.ObjectID{}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q", after)
+	}
+	var unitIDs []types.UnitID
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		if id := src.(*gqlUnit).ID; !seen[string(id)] {
+			seen[string(id)] = true
+			unitIDs = append(unitIDs, id)
+		}
+	}
+	txs, err := c.StorageService.GetTxsPagesByUnitIDs(ctx, unitIDs, pageRequest(cursor, limit, decodeObjectID))
+	if err != nil {
+		return nil, gqlInternalError(fmt.Errorf("failed to load transactions: %w", err))
+	}
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		res[i] = gqlTxList(txs[string(src.(*gqlUnit).ID)], listUnitTxs)
+	}
+	return res, nil
+}
+
+// gqlAddressBills returns a page of the bills of all the addresses, the money node returns all the bills of the owner
+// at once and has no query for several owners, the bills are loaded once per owner.
+func (c *Controller) gqlAddressBills(ctx context.Context, sources []any, args graphql.Args) ([]any, error) {
+	limit, err := gqlLimit(args, paramFirst, defaultBillsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+	after, _ := args.String(paramAfter)
+	cursor, err := parseCursor(after, listBills, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q", after)
+	}
+	page := pageRequest(cursor, limit, func(key []byte) types.UnitID { return key })
+	pages := make(map[string][]*sdktypes.Bill)
+	res := make([]any, len(sources))
+	for i, src := range sources {
+		ownerID := src.(*gqlAddress).PubKeyHash
+		if _, ok := pages[string(ownerID)]; !ok {
+			bills, err := c.MoneyService.GetBillsByPubKeyHash(ctx, ownerID)
+			if err != nil {
+				return nil, gqlInternalError(fmt.Errorf("failed to load bills: %w", err))
+			}
+			pages[string(ownerID)], _ = billsPage(bills, page)
+		}
+		res[i] = pages[string(ownerID)]
+	}
+	return res, nil
+}
+
+// gqlTxList wraps the transactions loaded from the list.
+func gqlTxList(txs []*domain.TxInfo, list string) []*gqlTx {
+	res := make([]*gqlTx, len(txs))
+	for i, tx := range txs {
+		res[i] = &gqlTx{TxInfo: tx, list: list}
+	}
+	return res
+}
+
+func txTargetUnits(tx *domain.TxInfo) []*gqlUnit {
+	units := []*gqlUnit{}
+	if tx.Transaction == nil || tx.Transaction.ServerMetadata == nil {
+		return units
+	}
+	for _, id := range tx.Transaction.ServerMetadata.TargetUnits {
+		units = append(units, &gqlUnit{ID: id})
+	}
+	return units
+}
+
+func hexString(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return hex.Bytes(b).String()
+}
+
+// gqlStoreError hides the internal errors but reports pruned data.
+func gqlStoreError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case errors.Is(err, domain.ErrPruned):
+		return domain.ErrPruned
+	}
+	return gqlInternalError(err)
+}
+
+// gqlInternalError logs the error and returns error without the internal details.
+func gqlInternalError(err error) error {
+	log.Error("GraphQL resolver error", "err", err)
+	return errors.New("internal error")
+}
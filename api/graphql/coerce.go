@@ -0,0 +1,224 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// coerceVariables coerces the variable values of the request according to the variable definitions.
+func coerceVariables(defs []*variableDefinition, provided map[string]any) (map[string]any, error) {
+	res := make(map[string]any)
+	for _, def := range defs {
+		if !isScalar(def.typ.namedType()) {
+			return nil, fmt.Errorf("variable $%s: input type must be a scalar, got %s", def.name, def.typ)
+		}
+		if v, ok := provided[def.name]; ok {
+			cv, err := coerceInput(def.typ, v)
+			if err != nil {
+				return nil, fmt.Errorf("variable $%s: %w", def.name, err)
+			}
+			res[def.name] = cv
+			continue
+		}
+		if def.defaultValue != nil {
+			cv, err := coerceLiteral(def.typ, def.defaultValue, nil)
+			if err != nil {
+				return nil, fmt.Errorf("variable $%s: %w", def.name, err)
+			}
+			res[def.name] = cv
+			continue
+		}
+		if def.typ.nonNull {
+			return nil, fmt.Errorf("variable $%s of required type %s was not provided", def.name, def.typ)
+		}
+	}
+	return res, nil
+}
+
+// coerceArguments coerces the arguments of the field, default values are set for missing arguments.
+func (ex *execution) coerceArguments(fd *Field, arguments []*argument) (Args, error) {
+	provided := make(map[string]value, len(arguments))
+	for _, arg := range arguments {
+		if fd.argument(arg.name) == nil {
+			return nil, fmt.Errorf("unknown argument %q", arg.name)
+		}
+		if _, ok := provided[arg.name]; ok {
+			return nil, fmt.Errorf("argument %q is set more than once", arg.name)
+		}
+		provided[arg.name] = arg.value
+	}
+
+	args := make(Args, len(fd.Args))
+	for _, def := range fd.Args {
+		v, ok := provided[def.Name]
+		if name, isVar := v.(variable); isVar {
+			_, ok = ex.variables[string(name)]
+		}
+		if ok {
+			cv, err := ex.coerceValue(def.typ, v)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q: %w", def.Name, err)
+			}
+			args[def.Name] = cv
+			continue
+		}
+		if def.Default != nil {
+			cv, err := coerceInput(def.typ, def.Default)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q: invalid default value: %w", def.Name, err)
+			}
+			args[def.Name] = cv
+			continue
+		}
+		if def.typ.nonNull {
+			return nil, fmt.Errorf("argument %q of type %s is required", def.Name, def.typ)
+		}
+	}
+	return args, nil
+}
+
+func (ex *execution) coerceValue(typ *typeRef, v value) (any, error) {
+	return coerceLiteral(typ, v, ex.variables)
+}
+
+// coerceLiteral coerces the literal value of the query, "variables" contain the coerced variable values.
+func coerceLiteral(typ *typeRef, v value, variables map[string]any) (any, error) {
+	if name, ok := v.(variable); ok {
+		vv, ok := variables[string(name)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", name)
+		}
+		return coerceInput(typ, vv)
+	}
+	if _, ok := v.(nullValue); ok {
+		if typ.nonNull {
+			return nil, fmt.Errorf("expected non-null value of type %s", typ)
+		}
+		return nil, nil
+	}
+	if typ.elem != nil {
+		list, ok := v.(listValue)
+		if !ok {
+			// single value is accepted as a list of one item
+			item, err := coerceLiteral(typ.elem, v, variables)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		res := make([]any, len(list))
+		for i, item := range list {
+			var err error
+			if res[i], err = coerceLiteral(typ.elem, item, variables); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	switch v := v.(type) {
+	case intValue:
+		switch typ.name {
+		case scalarInt:
+			return int64(v), nil
+		case scalarFloat:
+			return float64(v), nil
+		case scalarID:
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+	case floatValue:
+		if typ.name == scalarFloat {
+			return float64(v), nil
+		}
+	case stringValue:
+		if typ.name == scalarString || typ.name == scalarID {
+			return string(v), nil
+		}
+	case booleanValue:
+		if typ.name == scalarBoolean {
+			return bool(v), nil
+		}
+	}
+	return nil, fmt.Errorf("expected value of type %s", typ)
+}
+
+// coerceInput coerces the input value (decoded from JSON or already coerced) into the type.
+func coerceInput(typ *typeRef, v any) (any, error) {
+	if v == nil {
+		if typ.nonNull {
+			return nil, fmt.Errorf("expected non-null value of type %s", typ)
+		}
+		return nil, nil
+	}
+	if typ.elem != nil {
+		list, ok := v.([]any)
+		if !ok {
+			item, err := coerceInput(typ.elem, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		res := make([]any, len(list))
+		for i, item := range list {
+			var err error
+			if res[i], err = coerceInput(typ.elem, item); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	switch typ.name {
+	case scalarInt:
+		switch v := v.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return i, nil
+			}
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+				return int64(v), nil
+			}
+		}
+	case scalarFloat:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case scalarString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case scalarID:
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case json.Number:
+			if _, err := v.Int64(); err == nil {
+				return v.String(), nil
+			}
+		}
+	case scalarBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("expected value of type %s, got %v", typ, v)
+}
@@ -0,0 +1,473 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+type (
+	Request struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName,omitempty"`
+		Variables     map[string]any `json:"variables,omitempty"`
+	}
+
+	Response struct {
+		Data   *Result  `json:"data,omitempty"`
+		Errors []*Error `json:"errors,omitempty"`
+	}
+
+	Error struct {
+		Message string   `json:"message"`
+		Path    []string `json:"path,omitempty"`
+	}
+
+	// Result is the result object of the selection set, fields are kept in the order of the query.
+	Result struct {
+		keys   []string
+		values map[string]any
+	}
+
+	Limits struct {
+		MaxDepth        int // maximum nesting level of the fields
+		MaxComplexity   int // maximum number of fields the query may resolve
+		DefaultListSize int // expected size of the lists without "limit" or "first" argument when calculating complexity
+	}
+
+	Executor struct {
+		schema *Schema
+		limits Limits
+	}
+
+	// execution holds the state of a single request
+	execution struct {
+		schema    *Schema
+		limits    Limits
+		fragments map[string]*fragment
+		variables map[string]any
+		args      map[*field]Args
+		errors    []*Error
+	}
+
+	collectedField struct {
+		key    string
+		fields []*field
+	}
+)
+
+// listSizeArgs are the names of the arguments limiting the size of the returned list
+var listSizeArgs = []string{"limit", "first"}
+
+func NewExecutor(schema *Schema, limits Limits) (*Executor, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	if limits.MaxDepth <= 0 || limits.MaxComplexity <= 0 || limits.DefaultListSize <= 0 {
+		return nil, fmt.Errorf("invalid limits %+v", limits)
+	}
+	return &Executor{schema: schema, limits: limits}, nil
+}
+
+// Schema returns the schema the executor was created with.
+func (e *Executor) Schema() *Schema {
+	return e.schema
+}
+
+// Execute validates and executes the query, errors are reported in the response.
+func (e *Executor) Execute(ctx context.Context, req *Request) *Response {
+	doc, err := parseDocument(req.Query)
+	if err != nil {
+		return errorResponse(err)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+	ex := &execution{
+		schema:    e.schema,
+		limits:    e.limits,
+		fragments: doc.fragments,
+		args:      make(map[*field]Args),
+	}
+	if ex.variables, err = coerceVariables(op.variables, req.Variables); err != nil {
+		return errorResponse(err)
+	}
+	complexity, err := ex.analyze(e.schema.Query, op.selectionSet, 1, 1, map[string]bool{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	if complexity > e.limits.MaxComplexity {
+		return errorResponse(fmt.Errorf("query complexity %d exceeds the limit %d", complexity, e.limits.MaxComplexity))
+	}
+
+	data := ex.executeSelectionSet(ctx, e.schema.Query, []any{nil}, op.selectionSet, nil)
+	return &Response{Data: data[0], Errors: ex.errors}
+}
+
+func errorResponse(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	var op *operation
+	switch {
+	case name == "" && len(doc.operations) > 1:
+		return nil, fmt.Errorf("operation name is required when document contains multiple operations")
+	case name == "":
+		op = doc.operations[0]
+	default:
+		for _, o := range doc.operations {
+			if o.name == name {
+				op = o
+			}
+		}
+		if op == nil {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+	}
+	if op.kind != "query" {
+		return nil, fmt.Errorf("%s operations are not supported", op.kind)
+	}
+	return op, nil
+}
+
+/*
+analyze validates the selection set against the schema, coerces the arguments of
+the fields and returns the complexity of the selection set. Each resolved field adds
+"multiplier" to the complexity, the fields of lists are multiplied by the list size.
+*/
+func (ex *execution) analyze(obj *Object, selections []selection, depth, multiplier int, visiting map[string]bool) (int, error) {
+	if depth > ex.limits.MaxDepth {
+		return 0, fmt.Errorf("query depth exceeds the limit %d", ex.limits.MaxDepth)
+	}
+	complexity := 0
+	for _, sel := range selections {
+		var (
+			cost int
+			err  error
+		)
+		switch sel := sel.(type) {
+		case *field:
+			cost, err = ex.analyzeField(obj, sel, depth, multiplier, visiting)
+		case *fragmentSpread:
+			frag, ok := ex.fragments[sel.name]
+			if !ok {
+				return 0, fmt.Errorf("unknown fragment %q", sel.name)
+			}
+			if visiting[sel.name] {
+				return 0, fmt.Errorf("fragment %q spreads itself", sel.name)
+			}
+			if _, err = ex.include(sel.directives); err != nil {
+				return 0, err
+			}
+			if frag.typeCondition != obj.Name {
+				return 0, fmt.Errorf("fragment %q on %s can't be spread on type %s", sel.name, frag.typeCondition, obj.Name)
+			}
+			visiting[sel.name] = true
+			cost, err = ex.analyze(obj, frag.selectionSet, depth, multiplier, visiting)
+			delete(visiting, sel.name)
+		case *inlineFragment:
+			if sel.typeCondition != "" && sel.typeCondition != obj.Name {
+				return 0, fmt.Errorf("inline fragment on %s can't be spread on type %s", sel.typeCondition, obj.Name)
+			}
+			if _, err = ex.include(sel.directives); err != nil {
+				return 0, err
+			}
+			cost, err = ex.analyze(obj, sel.selectionSet, depth, multiplier, visiting)
+		}
+		if err != nil {
+			return 0, err
+		}
+		complexity = saturatingAdd(complexity, cost)
+	}
+	return complexity, nil
+}
+
+func (ex *execution) analyzeField(obj *Object, f *field, depth, multiplier int, visiting map[string]bool) (int, error) {
+	if _, err := ex.include(f.directives); err != nil {
+		return 0, err
+	}
+	if f.name == typenameField {
+		if len(f.arguments) > 0 || len(f.selectionSet) > 0 {
+			return 0, fmt.Errorf("field %s can't have arguments or selections", typenameField)
+		}
+		return 0, nil
+	}
+	fd := obj.field(f.name)
+	if fd == nil {
+		return 0, fmt.Errorf("cannot query field %q on type %s", f.name, obj.Name)
+	}
+	args, err := ex.coerceArguments(fd, f.arguments)
+	if err != nil {
+		return 0, fmt.Errorf("field %s.%s: %w", obj.Name, f.name, err)
+	}
+	ex.args[f] = args
+
+	named := fd.typ.namedType()
+	if isScalar(named) {
+		if len(f.selectionSet) > 0 {
+			return 0, fmt.Errorf("field %s.%s of type %s can't have selections", obj.Name, f.name, fd.Type)
+		}
+		return multiplier, nil
+	}
+	if len(f.selectionSet) == 0 {
+		return 0, fmt.Errorf("field %s.%s of type %s must have selections", obj.Name, f.name, fd.Type)
+	}
+	childMultiplier := multiplier
+	if fd.typ.elem != nil {
+		size := int64(ex.limits.DefaultListSize)
+		for _, name := range listSizeArgs {
+			if limit, ok := args.Int(name); ok && limit > 0 {
+				size = limit
+				break
+			}
+		}
+		childMultiplier = saturatingMul(multiplier, size)
+	}
+	cost, err := ex.analyze(ex.schema.objects[named], f.selectionSet, depth+1, childMultiplier, visiting)
+	if err != nil {
+		return 0, err
+	}
+	return saturatingAdd(multiplier, cost), nil
+}
+
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+func saturatingMul(a int, b int64) int {
+	if b != 0 && int64(a) > math.MaxInt/b {
+		return math.MaxInt
+	}
+	return a * int(b)
+}
+
+// include evaluates the @skip and @include directives.
+func (ex *execution) include(directives []*directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.name)
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			return false, fmt.Errorf("directive @%s requires single argument \"if\"", d.name)
+		}
+		v, err := ex.coerceValue(&typeRef{name: scalarBoolean, nonNull: true}, d.arguments[0].value)
+		if err != nil {
+			return false, fmt.Errorf("directive @%s: %w", d.name, err)
+		}
+		if v.(bool) == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// collectFields returns the fields of the selection set grouped by the response key.
+func (ex *execution) collectFields(selections []selection, fields []*collectedField) []*collectedField {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if ok, _ := ex.include(sel.directives); !ok {
+				continue
+			}
+			key := sel.responseKey()
+			found := false
+			for _, cf := range fields {
+				if cf.key == key {
+					cf.fields = append(cf.fields, sel)
+					found = true
+					break
+				}
+			}
+			if !found {
+				fields = append(fields, &collectedField{key: key, fields: []*field{sel}})
+			}
+		case *fragmentSpread:
+			if ok, _ := ex.include(sel.directives); ok {
+				fields = ex.collectFields(ex.fragments[sel.name].selectionSet, fields)
+			}
+		case *inlineFragment:
+			if ok, _ := ex.include(sel.directives); ok {
+				fields = ex.collectFields(sel.selectionSet, fields)
+			}
+		}
+	}
+	return fields
+}
+
+// executeSelectionSet resolves the selection set for all the "sources" of type "obj" breadth first.
+func (ex *execution) executeSelectionSet(ctx context.Context, obj *Object, sources []any, selections []selection, path []string) []*Result {
+	results := make([]*Result, len(sources))
+	for i := range results {
+		results[i] = &Result{values: make(map[string]any)}
+	}
+	for _, cf := range ex.collectFields(selections, nil) {
+		f := cf.fields[0]
+		if f.name == typenameField {
+			for _, r := range results {
+				r.set(cf.key, obj.Name)
+			}
+			continue
+		}
+		fieldPath := append(path[:len(path):len(path)], cf.key)
+		fd := obj.field(f.name)
+		values, err := fd.Resolve(ctx, sources, ex.args[f])
+		if err == nil && len(values) != len(sources) {
+			err = fmt.Errorf("resolver returned %d values for %d objects", len(values), len(sources))
+		}
+		if err != nil {
+			// the field is null in all the results, the error is already reported
+			ex.errors = append(ex.errors, &Error{Message: err.Error(), Path: fieldPath})
+			for _, r := range results {
+				r.set(cf.key, nil)
+			}
+			continue
+		}
+
+		var subSelections []selection
+		for _, f := range cf.fields {
+			subSelections = append(subSelections, f.selectionSet...)
+		}
+		values = ex.completeValues(ctx, fd.typ, values, subSelections, fieldPath)
+		for i, r := range results {
+			r.set(cf.key, values[i])
+		}
+	}
+	return results
+}
+
+// completeValues converts resolved values into the response values according to the field type.
+func (ex *execution) completeValues(ctx context.Context, typ *typeRef, values []any, selections []selection, path []string) []any {
+	res := make([]any, len(values))
+	nullViolation := false
+
+	if typ.elem != nil {
+		// all the items of all the lists are completed together
+		var items []any
+		lengths := make([]int, len(values))
+		for i, v := range values {
+			list, ok := toList(v)
+			if !ok {
+				lengths[i] = -1
+				nullViolation = nullViolation || typ.nonNull
+				continue
+			}
+			lengths[i] = len(list)
+			items = append(items, list...)
+		}
+		items = ex.completeValues(ctx, typ.elem, items, selections, path)
+		for i, n := range lengths {
+			if n >= 0 {
+				res[i], items = items[:n:n], items[n:]
+			}
+		}
+	} else if obj, ok := ex.schema.objects[typ.name]; ok {
+		var sources []any
+		var idx []int
+		for i, v := range values {
+			if isNil(v) {
+				nullViolation = nullViolation || typ.nonNull
+				continue
+			}
+			sources = append(sources, v)
+			idx = append(idx, i)
+		}
+		if len(sources) > 0 {
+			for i, r := range ex.executeSelectionSet(ctx, obj, sources, selections, path) {
+				res[idx[i]] = r
+			}
+		}
+	} else {
+		for i, v := range values {
+			if isNil(v) {
+				nullViolation = nullViolation || typ.nonNull
+				continue
+			}
+			res[i] = v
+		}
+	}
+
+	if nullViolation {
+		ex.errors = append(ex.errors, &Error{Message: "cannot return null for non-nullable field", Path: path})
+	}
+	return res
+}
+
+func toList(v any) ([]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if list, ok := v.([]any); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func (r *Result) set(key string, value any) {
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// Get returns the value of the field with given response key.
+func (r *Result) Get(key string) any {
+	return r.values[key]
+}
+
+func (r *Result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %w", key, err)
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (e *Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	return strings.Join(e.Path, ".") + ": " + e.Message
+}
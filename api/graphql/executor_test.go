@@ -0,0 +1,232 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testBlock struct {
+		Number uint64
+		TxIDs  []string
+	}
+
+	testTx struct {
+		ID string
+	}
+)
+
+// newTestSchema creates schema of blocks and transactions, "txLoads" counts the calls of the transactions resolver.
+func newTestSchema(t *testing.T, txLoads *int) *Schema {
+	tx := &Object{
+		Name: "Transaction",
+		Fields: []*Field{
+			{Name: "id", Type: "String!", Resolve: Property(func(tx *testTx) any { return tx.ID })},
+		},
+	}
+	block := &Object{
+		Name:        "Block",
+		Description: "Block of the chain",
+		Fields: []*Field{
+			{Name: "number", Type: "Int!", Resolve: Property(func(b *testBlock) any { return b.Number })},
+			{
+				Name: "transactions",
+				Type: "[Transaction!]!",
+				Resolve: func(ctx context.Context, sources []any, args Args) ([]any, error) {
+					*txLoads++
+					res := make([]any, len(sources))
+					for i, src := range sources {
+						var txs []*testTx
+						for _, id := range src.(*testBlock).TxIDs {
+							txs = append(txs, &testTx{ID: id})
+						}
+						res[i] = txs
+					}
+					return res, nil
+				},
+			},
+		},
+	}
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name: "blocks",
+				Type: "[Block!]!",
+				Args: []*Argument{{Name: "limit", Type: "Int", Default: 3}},
+				Resolve: ResolveEach(func(ctx context.Context, _ any, args Args) (any, error) {
+					limit, _ := args.Int("limit")
+					var blocks []*testBlock
+					for i := int64(1); i <= limit; i++ {
+						blocks = append(blocks, &testBlock{Number: uint64(i), TxIDs: []string{fmt.Sprintf("tx%d", i)}})
+					}
+					return blocks, nil
+				}),
+			},
+			{
+				Name: "block",
+				Type: "Block",
+				Args: []*Argument{{Name: "number", Type: "Int!"}},
+				Resolve: ResolveEach(func(ctx context.Context, _ any, args Args) (any, error) {
+					number, _ := args.Int("number")
+					if number == 0 {
+						return nil, errors.New("block not found")
+					}
+					return &testBlock{Number: uint64(number)}, nil
+				}),
+			},
+			{
+				Name: "echo",
+				Type: "[String]",
+				Args: []*Argument{{Name: "values", Type: "[String!]"}},
+				Resolve: ResolveEach(func(ctx context.Context, _ any, args Args) (any, error) {
+					return args["values"], nil
+				}),
+			},
+		},
+	}
+	schema, err := NewSchema(query, block, tx)
+	require.NoError(t, err)
+	return schema
+}
+
+func execute(t *testing.T, schema *Schema, req *Request) string {
+	executor, err := NewExecutor(schema, Limits{MaxDepth: 4, MaxComplexity: 100, DefaultListSize: 10})
+	require.NoError(t, err)
+	data, err := json.Marshal(executor.Execute(context.Background(), req))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExecute_BatchesNestedFields(t *testing.T) {
+	txLoads := 0
+	schema := newTestSchema(t, &txLoads)
+
+	res := execute(t, schema, &Request{Query: `{ blocks(limit: 3) { number transactions { id } } }`})
+	require.JSONEq(t, `{"data":{"blocks":[
+		{"number":1,"transactions":[{"id":"tx1"}]},
+		{"number":2,"transactions":[{"id":"tx2"}]},
+		{"number":3,"transactions":[{"id":"tx3"}]}
+	]}}`, res)
+	require.Equal(t, 1, txLoads)
+}
+
+func TestExecute_AliasesFragmentsAndVariables(t *testing.T) {
+	txLoads := 0
+	schema := newTestSchema(t, &txLoads)
+
+	res := execute(t, schema, &Request{
+		Query: `
+			query Blocks($n: Int!, $withTxs: Boolean = false) {
+				first: block(number: 1) { ...fields }
+				second: block(number: $n) { ...fields __typename }
+			}
+			fragment fields on Block {
+				number
+				transactions @include(if: $withTxs) { id }
+			}`,
+		OperationName: "Blocks",
+		Variables:     map[string]any{"n": json.Number("2")},
+	})
+	require.Equal(t, `{"data":{"first":{"number":1},"second":{"number":2,"__typename":"Block"}}}`, res)
+	require.Zero(t, txLoads)
+}
+
+func TestExecute_ListArgument(t *testing.T) {
+	schema := newTestSchema(t, new(int))
+
+	res := execute(t, schema, &Request{Query: `{ a: echo(values: ["x", "y"]) b: echo(values: "z") }`})
+	require.Equal(t, `{"data":{"a":["x","y"],"b":["z"]}}`, res)
+}
+
+func TestExecute_ResolverError(t *testing.T) {
+	schema := newTestSchema(t, new(int))
+
+	res := execute(t, schema, &Request{Query: `{ block(number: 0) { number } }`})
+	require.JSONEq(t, `{"data":{"block":null},"errors":[{"message":"block not found","path":["block"]}]}`, res)
+}
+
+func TestExecute_InvalidQueries(t *testing.T) {
+	schema := newTestSchema(t, new(int))
+	executor, err := NewExecutor(schema, Limits{MaxDepth: 4, MaxComplexity: 100, DefaultListSize: 10})
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{`{ blocks { number `, `syntax error: unexpected <EOF> at 18`},
+		{`{ blocks { hash } }`, `cannot query field "hash" on type Block`},
+		{`{ block { number } }`, `field Query.block: argument "number" of type Int! is required`},
+		{`{ block(number: "1") { number } }`, `field Query.block: argument "number": expected value of type Int!`},
+		{`{ block(number: 1, hash: 2) { number } }`, `field Query.block: unknown argument "hash"`},
+		{`{ blocks }`, `field Query.blocks of type [Block!]! must have selections`},
+		{`{ blocks { number { x } } }`, `field Block.number of type Int! can't have selections`},
+		{`{ blocks { ...f } } fragment f on Block { ...f }`, `fragment "f" spreads itself`},
+		{`{ blocks { ...missing } }`, `unknown fragment "missing"`},
+		{`{ blocks @defer { number } }`, `unknown directive @defer`},
+		{`mutation { blocks { number } }`, `mutation operations are not supported`},
+		{`query($n: Int!) { block(number: $n) { number } }`, `variable $n of required type Int! was not provided`},
+		{`{ blocks(limit: 50) { transactions { id } } }`, `query complexity 551 exceeds the limit 100`},
+		{`{ blocks { number } } { blocks { number } }`, `operation name is required when document contains multiple operations`},
+	}
+	for _, tc := range tests {
+		res := executor.Execute(context.Background(), &Request{Query: tc.query})
+		require.Nil(t, res.Data, tc.query)
+		require.Len(t, res.Errors, 1, tc.query)
+		require.Equal(t, tc.err, res.Errors[0].Message, tc.query)
+	}
+}
+
+func TestExecute_MaxDepth(t *testing.T) {
+	schema := newTestSchema(t, new(int))
+	executor, err := NewExecutor(schema, Limits{MaxDepth: 2, MaxComplexity: 100, DefaultListSize: 10})
+	require.NoError(t, err)
+
+	res := executor.Execute(context.Background(), &Request{Query: `{ blocks(limit: 1) { transactions { id } } }`})
+	require.Nil(t, res.Data)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "query depth exceeds the limit 2", res.Errors[0].Message)
+}
+
+func TestSchema_SDL(t *testing.T) {
+	schema := newTestSchema(t, new(int))
+	require.Equal(t, `schema {
+  query: Query
+}
+
+"""Block of the chain"""
+type Block {
+  number: Int!
+  transactions: [Transaction!]!
+}
+
+type Query {
+  blocks(limit: Int = 3): [Block!]!
+  block(number: Int!): Block
+  echo(values: [String!]): [String]
+}
+
+type Transaction {
+  id: String!
+}
+`, schema.SDL())
+}
+
+func TestNewSchema_Invalid(t *testing.T) {
+	resolve := Property(func(any) any { return nil })
+
+	_, err := NewSchema(&Object{Name: "Query", Fields: []*Field{{Name: "x", Type: "Unknown", Resolve: resolve}}})
+	require.ErrorContains(t, err, "invalid field Query.x: unknown type Unknown")
+
+	_, err = NewSchema(&Object{Name: "Query", Fields: []*Field{{Name: "x", Type: "[Int", Resolve: resolve}}})
+	require.ErrorContains(t, err, `invalid field Query.x: invalid type "[Int"`)
+
+	_, err = NewSchema(&Object{Name: "Query", Fields: []*Field{{Name: "x", Type: "Int"}}})
+	require.ErrorContains(t, err, "invalid field Query.x: resolver is not set")
+}
@@ -0,0 +1,510 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type (
+	document struct {
+		operations []*operation
+		fragments  map[string]*fragment
+	}
+
+	operation struct {
+		kind         string // "query", "mutation" or "subscription"
+		name         string
+		variables    []*variableDefinition
+		selectionSet []selection
+	}
+
+	variableDefinition struct {
+		name         string
+		typ          *typeRef
+		defaultValue value
+	}
+
+	fragment struct {
+		name          string
+		typeCondition string
+		selectionSet  []selection
+	}
+
+	// selection is one of *field, *fragmentSpread or *inlineFragment
+	selection interface{}
+
+	field struct {
+		alias        string
+		name         string
+		arguments    []*argument
+		directives   []*directive
+		selectionSet []selection
+		pos          int
+	}
+
+	fragmentSpread struct {
+		name       string
+		directives []*directive
+		pos        int
+	}
+
+	inlineFragment struct {
+		typeCondition string
+		directives    []*directive
+		selectionSet  []selection
+	}
+
+	argument struct {
+		name  string
+		value value
+	}
+
+	directive struct {
+		name      string
+		arguments []*argument
+	}
+
+	// value is one of the literal types below
+	value interface{}
+
+	variable     string
+	enumValue    string
+	listValue    []value
+	objectValue  map[string]value
+	nullValue    struct{}
+	intValue     int64
+	floatValue   float64
+	stringValue  string
+	booleanValue bool
+
+	// typeRef is reference to a named type with list and non-null wrappers, eg "[Block!]!"
+	typeRef struct {
+		name    string   // set when the type is not a list
+		elem    *typeRef // set when the type is a list
+		nonNull bool
+	}
+)
+
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// namedType returns the name of the innermost named type.
+func (t *typeRef) namedType() string {
+	if t.elem != nil {
+		return t.elem.namedType()
+	}
+	return t.name
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parseDocument parses executable GraphQL document.
+func parseDocument(src string) (*document, error) {
+	p := &parser{lex: &lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selectionSet: selections})
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peekName("fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("fragment %q is defined more than once", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document does not contain any operations")
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return fmt.Errorf("syntax error: %w", err)
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.tok.kind == tokenName && p.tok.value == name
+}
+
+func (p *parser) unexpected() error {
+	return fmt.Errorf("syntax error: unexpected %s at %d", p.tok, p.tok.pos)
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return fmt.Errorf("syntax error: expected %q, got %s at %d", punct, p.tok, p.tok.pos)
+	}
+	return p.advance()
+}
+
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", fmt.Errorf("syntax error: expected name, got %s at %d", p.tok, p.tok.pos)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var err error
+	if p.tok.kind == tokenName {
+		if op.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		if op.variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.selectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*variableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var defs []*variableDefinition
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		def := &variableDefinition{name: name, typ: typ}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if def.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		if _, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+func (p *parser) parseType() (*typeRef, error) {
+	t := &typeRef{}
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		if t.elem, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if err = p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else if t.name, err = p.name(); err != nil {
+		return nil, err
+	}
+	ok, err := p.skip("!")
+	t.nonNull = ok
+	return t, err
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("syntax error: fragment can't be named \"on\"")
+	}
+	if !p.peekName("on") {
+		return nil, fmt.Errorf("syntax error: expected \"on\", got %s at %d", p.tok, p.tok.pos)
+	}
+	if err = p.advance(); err != nil {
+		return nil, err
+	}
+	frag := &fragment{name: name}
+	if frag.typeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+	if _, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if frag.selectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []selection
+	for !p.peek("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("syntax error: empty selection set at %d", p.tok.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	pos := p.tok.pos
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.tok.kind == tokenName && !p.peekName("on") {
+			spread := &fragmentSpread{pos: pos}
+			if spread.name, err = p.name(); err != nil {
+				return nil, err
+			}
+			if spread.directives, err = p.parseDirectives(); err != nil {
+				return nil, err
+			}
+			return spread, nil
+		}
+		inline := &inlineFragment{}
+		if p.peekName("on") {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+			if inline.typeCondition, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if inline.directives, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		if inline.selectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+		return inline, nil
+	}
+
+	f := &field{pos: pos}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	f.name = name
+	if p.peek("(") {
+		if f.arguments, err = p.parseArguments(false); err != nil {
+			return nil, err
+		}
+	}
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if f.selectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments(constant bool) ([]*argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []*argument
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		val, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &argument{name: name, value: val})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		d := &directive{name: name}
+		if p.peek("(") {
+			if d.arguments, err = p.parseArguments(false); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+func (p *parser) parseValue(constant bool) (value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, fmt.Errorf("syntax error: unexpected variable at %d", tok.pos)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			return variable(name), err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := listValue{}
+			for !p.peek("]") {
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			obj := objectValue{}
+			for !p.peek("}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect(":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+			return obj, p.advance()
+		}
+	case tokenInt:
+		v, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error: invalid integer %s at %d", tok.value, tok.pos)
+		}
+		return intValue(v), p.advance()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error: invalid float %s at %d", tok.value, tok.pos)
+		}
+		return floatValue(v), p.advance()
+	case tokenString:
+		return stringValue(tok.value), p.advance()
+	case tokenName:
+		switch tok.value {
+		case "true", "false":
+			return booleanValue(tok.value == "true"), p.advance()
+		case "null":
+			return nullValue{}, p.advance()
+		default:
+			return enumValue(tok.value), p.advance()
+		}
+	}
+	return nil, p.unexpected()
+}
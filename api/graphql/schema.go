@@ -0,0 +1,245 @@
+/*
+Package graphql implements executor of GraphQL queries over a schema of object types.
+
+Fields are resolved in batches: the resolver of a field receives all the parent objects
+of the current level of the query at once, which allows loading the data of nested
+fields of lists with a single query instead of a query per parent object.
+*/
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Schema describes the object types available for the queries.
+	Schema struct {
+		Query   *Object
+		objects map[string]*Object
+	}
+
+	Object struct {
+		Name        string
+		Description string
+		Fields      []*Field
+	}
+
+	Field struct {
+		Name        string
+		Description string
+		Type        string // type in SDL notation, eg "[Block!]!"
+		Args        []*Argument
+		Resolve     ResolveFunc
+
+		typ *typeRef
+	}
+
+	Argument struct {
+		Name        string
+		Description string
+		Type        string // type in SDL notation, input types are limited to scalars and lists of scalars
+		Default     any
+
+		typ *typeRef
+	}
+
+	// ResolveFunc resolves the field for all the "sources", result must contain one value for each source.
+	ResolveFunc func(ctx context.Context, sources []any, args Args) ([]any, error)
+
+	// Args are the coerced field arguments: Int is int64, Float is float64, String and ID are string,
+	// Boolean is bool and lists are []any.
+	Args map[string]any
+)
+
+const (
+	scalarInt     = "Int"
+	scalarFloat   = "Float"
+	scalarString  = "String"
+	scalarBoolean = "Boolean"
+	scalarID      = "ID"
+
+	typenameField = "__typename"
+)
+
+func isScalar(name string) bool {
+	switch name {
+	case scalarInt, scalarFloat, scalarString, scalarBoolean, scalarID:
+		return true
+	}
+	return false
+}
+
+// NewSchema validates the types reachable from the query root type and creates new schema.
+func NewSchema(query *Object, types ...*Object) (*Schema, error) {
+	if query == nil {
+		return nil, fmt.Errorf("query type is nil")
+	}
+	s := &Schema{Query: query, objects: make(map[string]*Object)}
+	for _, obj := range append([]*Object{query}, types...) {
+		if _, ok := s.objects[obj.Name]; ok || isScalar(obj.Name) {
+			return nil, fmt.Errorf("type %s is defined more than once", obj.Name)
+		}
+		s.objects[obj.Name] = obj
+	}
+	for _, obj := range s.objects {
+		if len(obj.Fields) == 0 {
+			return nil, fmt.Errorf("type %s has no fields", obj.Name)
+		}
+		for _, f := range obj.Fields {
+			if err := s.initField(f); err != nil {
+				return nil, fmt.Errorf("invalid field %s.%s: %w", obj.Name, f.Name, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Schema) initField(f *Field) error {
+	if f.Resolve == nil {
+		return fmt.Errorf("resolver is not set")
+	}
+	typ, err := parseTypeRef(f.Type)
+	if err != nil {
+		return err
+	}
+	if name := typ.namedType(); !isScalar(name) && s.objects[name] == nil {
+		return fmt.Errorf("unknown type %s", name)
+	}
+	f.typ = typ
+	for _, arg := range f.Args {
+		if arg.typ, err = parseTypeRef(arg.Type); err != nil {
+			return fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+		if !isScalar(arg.typ.namedType()) {
+			return fmt.Errorf("argument %s: input type must be a scalar, got %s", arg.Name, arg.Type)
+		}
+	}
+	return nil
+}
+
+func parseTypeRef(s string) (*typeRef, error) {
+	p := &parser{lex: &lexer{src: s}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, fmt.Errorf("invalid type %q", s)
+	}
+	return typ, nil
+}
+
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (f *Field) argument(name string) *Argument {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// SDL returns the schema in the GraphQL schema definition language.
+func (s *Schema) SDL() string {
+	var sb strings.Builder
+	sb.WriteString("schema {\n  query: " + s.Query.Name + "\n}\n")
+
+	names := make([]string, 0, len(s.objects))
+	for name := range s.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		obj := s.objects[name]
+		sb.WriteString("\n")
+		writeDescription(&sb, "", obj.Description)
+		sb.WriteString("type " + obj.Name + " {\n")
+		for _, f := range obj.Fields {
+			writeDescription(&sb, "  ", f.Description)
+			sb.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				args := make([]string, 0, len(f.Args))
+				for _, a := range f.Args {
+					arg := a.Name + ": " + a.Type
+					if a.Default != nil {
+						if str, ok := a.Default.(string); ok {
+							arg += " = " + strconv.Quote(str)
+						} else {
+							arg += fmt.Sprintf(" = %v", a.Default)
+						}
+					}
+					args = append(args, arg)
+				}
+				sb.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sb.WriteString(": " + f.Type + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+func writeDescription(sb *strings.Builder, indent, description string) {
+	if description != "" {
+		sb.WriteString(indent + `"""` + description + `"""` + "\n")
+	}
+}
+
+// Int returns the value of the Int argument, false when the argument is null or not set.
+func (a Args) Int(name string) (int64, bool) {
+	v, ok := a[name].(int64)
+	return v, ok
+}
+
+// String returns the value of the String or ID argument, false when the argument is null or not set.
+func (a Args) String(name string) (string, bool) {
+	v, ok := a[name].(string)
+	return v, ok
+}
+
+// Bool returns the value of the Boolean argument, false when the argument is null or not set.
+func (a Args) Bool(name string) (value bool, ok bool) {
+	value, ok = a[name].(bool)
+	return value, ok
+}
+
+// ResolveEach creates batch resolver which resolves each source separately.
+func ResolveEach(f func(ctx context.Context, source any, args Args) (any, error)) ResolveFunc {
+	return func(ctx context.Context, sources []any, args Args) ([]any, error) {
+		res := make([]any, len(sources))
+		for i, src := range sources {
+			v, err := f(ctx, src, args)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = v
+		}
+		return res, nil
+	}
+}
+
+// Property creates batch resolver returning the value "f" returns for each source.
+func Property[T any](f func(source T) any) ResolveFunc {
+	return func(_ context.Context, sources []any, _ Args) ([]any, error) {
+		res := make([]any, len(sources))
+		for i, src := range sources {
+			res[i] = f(src.(T))
+		}
+		return res, nil
+	}
+}
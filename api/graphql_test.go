@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/graphql"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/partition"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// roundInfoStub returns the round numbers of the partitions.
+type roundInfoStub struct {
+	partitionServiceStub
+	roundInfos []partition.RoundInfo
+}
+
+func (s roundInfoStub) GetRoundNumber(context.Context) ([]partition.RoundInfo, error) {
+	return s.roundInfos, nil
+}
+
+func newGraphQLServer(t *testing.T, storage StorageService) *httptest.Server {
+	r := mux.NewRouter()
+	restapi := &Controller{StorageService: storage}
+	r.HandleFunc("/graphql", restapi.graphQL).Methods(http.MethodGet, http.MethodPost)
+	r.HandleFunc("/graphql/schema", restapi.graphQLSchemaSDL)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func postGraphQL(t *testing.T, ts *httptest.Server, body string) (int, string) {
+	res, err := http.Post(ts.URL+"/graphql", ApplicationJson, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(data)
+}
+
+func TestGraphQL_BlocksWithTransactions(t *testing.T) {
+	blocks := []*domain.BlockInfo{
+		{PartitionID: partitionID1, BlockNumber: 2, TxHashes: []domain.TxHash{{0x01}, {0x02}}},
+		{PartitionID: partitionID1, BlockNumber: 1, TxHashes: []domain.TxHash{{0x03}}},
+	}
+	txs := []*domain.TxInfo{
+		{TxRecordHash: []byte{0x03}, PartitionID: partitionID1, BlockNumber: 1},
+		{TxRecordHash: []byte{0x02}, PartitionID: partitionID1, BlockNumber: 2},
+		{TxRecordHash: []byte{0x01}, PartitionID: partitionID1, BlockNumber: 2},
+	}
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetBlocksPage(mock.Anything, partitionID1, domain.PageRequest[uint64]{Limit: 2}, true).
+		Return(blocks, false, nil).Once()
+	// transactions and their blocks are loaded with a single query for all the blocks
+	mockStorage.EXPECT().GetTxsByHashes(mock.Anything, []domain.TxHash{{0x01}, {0x02}, {0x03}}).
+		Return(txs, nil).Once()
+	mockStorage.EXPECT().GetBlocksByNumbers(mock.Anything, partitionID1, []uint64{2, 2, 1}).
+		Return(blocks, nil).Once()
+	ts := newGraphQLServer(t, mockStorage)
+
+	status, body := postGraphQL(t, ts, `{"query": "{ blocks(partitionID: 1, limit: 2) { number transactions { hash block { number } } } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"blocks":[
+		{"number":2,"transactions":[{"hash":"0x01","block":{"number":2}},{"hash":"0x02","block":{"number":2}}]},
+		{"number":1,"transactions":[{"hash":"0x03","block":{"number":1}}]}
+	]}}`, body)
+}
+
+func TestGraphQL_TargetUnitTransactions(t *testing.T) {
+	unit1, unit2 := types.UnitID{0x01}, types.UnitID{0x02}
+	txs := []*domain.TxInfo{
+		{TxRecordHash: []byte{0x01}, Transaction: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{unit1, unit2}}}},
+		{TxRecordHash: []byte{0x02}, Transaction: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{unit2}}}},
+	}
+	mockStorage := mocks.NewStorageService(t)
+	// the transactions of all the partitions and all the target units are loaded with a single store call
+	mockStorage.EXPECT().GetTxsPages(mock.Anything, []types.PartitionID{partitionID1, partitionID2}, domain.PageRequest[primitive.ObjectID]{Limit: 2}).
+		Return(map[types.PartitionID][]*domain.TxInfo{partitionID1: txs}, nil).Once()
+	mockStorage.EXPECT().GetTxsPagesByUnitIDs(mock.Anything, []types.UnitID{unit1, unit2}, domain.PageRequest[primitive.ObjectID]{Limit: 1}).
+		Return(map[string][]*domain.TxInfo{string(unit2): {txs[1]}}, nil).Once()
+	r := mux.NewRouter()
+	restapi := &Controller{StorageService: mockStorage, PartitionService: roundInfoStub{roundInfos: []partition.RoundInfo{{PartitionID: partitionID1}, {PartitionID: partitionID2}}}}
+	r.HandleFunc("/graphql", restapi.graphQL).Methods(http.MethodPost)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	status, body := postGraphQL(t, ts, `{"query": "{ partitions { id transactions(limit: 2) { hash targetUnits { id transactions(first: 1) { hash } } } } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"partitions":[
+		{"id":1,"transactions":[
+			{"hash":"0x01","targetUnits":[{"id":"0x01","transactions":[]},{"id":"0x02","transactions":[{"hash":"0x02"}]}]},
+			{"hash":"0x02","targetUnits":[{"id":"0x02","transactions":[{"hash":"0x02"}]}]}
+		]},
+		{"id":2,"transactions":[]}
+	]}}`, body)
+}
+
+func TestGraphQL_UnitTransactions_GET(t *testing.T) {
+	unitID := types.UnitID{0xAA}
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxsPagesByUnitIDs(mock.Anything, []types.UnitID{unitID}, domain.PageRequest[primitive.ObjectID]{Limit: defaultTxsPageLimit}).
+		Return(map[string][]*domain.TxInfo{string(unitID): {{
+			TxRecordHash: []byte{0x01},
+			Transaction: &types.TransactionRecord{
+				ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{unitID}, ActualFee: 3, SuccessIndicator: types.TxStatusSuccessful},
+			},
+		}}}, nil).Once()
+	ts := newGraphQLServer(t, mockStorage)
+
+	qp := url.Values{}
+	qp.Set(paramQuery, `query($id: String!) { unit(id: $id) { id transactions { hash fee success } } }`)
+	qp.Set(paramVariables, `{"id": "0xAA"}`)
+	res, err := http.Get(ts.URL + "/graphql?" + qp.Encode())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data":{"unit":{"id":"0xaa","transactions":[{"hash":"0x01","fee":3,"success":true}]}}}`, string(body))
+}
+
+func TestGraphQL_UnitTransactionsPage(t *testing.T) {
+	unitID := types.UnitID{0xAA}
+	id := primitive.NewObjectID()
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxsPagesByUnitIDs(mock.Anything, []types.UnitID{unitID}, domain.PageRequest[primitive.ObjectID]{Key: &id, Limit: 2}).
+		Return(map[string][]*domain.TxInfo{string(unitID): {{TxRecordHash: []byte{0x01}}}}, nil).Once()
+	ts := newGraphQLServer(t, mockStorage)
+
+	after := (&pageCursor{Version: cursorVersion, List: listUnitTxs, Key: id[:]}).String()
+	status, body := postGraphQL(t, ts, fmt.Sprintf(`{"query": "{ unit(id: \"0xAA\") { transactions(first: 2, after: \"%s\") { hash } } }"}`, after))
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"unit":{"transactions":[{"hash":"0x01"}]}}}`, body)
+
+	status, body = postGraphQL(t, ts, `{"query": "{ unit(id: \"0xAA\") { transactions(first: 101) { hash } } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "limit must be between 1 and 100")
+
+	// the requested page size counts in the complexity of the query
+	status, body = postGraphQL(t, ts, `{"query": "{ unit(id: \"0xAA\") { transactions(first: 100) { targetUnits { transactions(first: 100) { hash } } } } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "exceeds the limit 5000")
+}
+
+func TestGraphQL_UnitTransactionsRESTCursor(t *testing.T) {
+	unitID := types.UnitID{0xAA}
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxsPageByUnitID(mock.Anything, unitID, domain.PageRequest[primitive.ObjectID]{Limit: 1}).
+		Return([]*domain.TxInfo{{ID: ids[1], TxRecordHash: []byte{0x02}}}, true, nil).Once()
+	mockStorage.EXPECT().GetTxsPagesByUnitIDs(mock.Anything, []types.UnitID{unitID}, domain.PageRequest[primitive.ObjectID]{Key: &ids[1], Limit: 1}).
+		Return(map[string][]*domain.TxInfo{string(unitID): {{ID: ids[0], TxRecordHash: []byte{0x01}}}}, nil).Once()
+	r := mux.NewRouter()
+	restapi := &Controller{StorageService: mockStorage}
+	r.HandleFunc("/units/{unitID}/txs", restapi.getTxsByUnitID)
+	r.HandleFunc("/graphql", restapi.graphQL).Methods(http.MethodPost)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	// the cursor of the REST API continues the GraphQL list of the unit transactions
+	res, err := http.Get(ts.URL + "/units/0xAA/txs?limit=1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	m := regexp.MustCompile(`<([^>]*)>; rel="next"`).FindStringSubmatch(res.Header.Get(HeaderLink))
+	require.Len(t, m, 2)
+	u, err := url.Parse(m[1])
+	require.NoError(t, err)
+	after := u.Query().Get(QueryParamCursor)
+
+	status, body := postGraphQL(t, ts, fmt.Sprintf(`{"query": "{ unit(id: \"0xAA\") { transactions(first: 1, after: \"%s\") { hash cursor } } }"}`, after))
+	require.Equal(t, http.StatusOK, status)
+	cursor := (&pageCursor{Version: cursorVersion, List: listUnitTxs, Key: ids[0][:]}).String()
+	require.JSONEq(t, fmt.Sprintf(`{"data":{"unit":{"transactions":[{"hash":"0x01","cursor":"%s"}]}}}`, cursor), body)
+}
+
+func TestGraphQL_AddressBills(t *testing.T) {
+	bills := []*sdktypes.Bill{{ID: types.UnitID{3}, Value: 30}, {ID: types.UnitID{1}, Value: 10}, {ID: types.UnitID{2}, Value: 20}}
+	r := mux.NewRouter()
+	money := &moneyServiceStub{bills: bills}
+	restapi := &Controller{StorageService: mocks.NewStorageService(t), MoneyService: money}
+	r.HandleFunc("/graphql", restapi.graphQL).Methods(http.MethodPost)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	pubKeyHash := "0x" + strings.Repeat("01", 32)
+	status, body := postGraphQL(t, ts, fmt.Sprintf(`{"query": "{ address(pubKey: \"%s\") { bills(first: 2) { id value } } }"}`, pubKeyHash))
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"address":{"bills":[{"id":"0x01","value":10},{"id":"0x02","value":20}]}}}`, body)
+
+	after := (&pageCursor{Version: cursorVersion, List: listBills, Key: types.UnitID{2}}).String()
+	status, body = postGraphQL(t, ts, fmt.Sprintf(`{"query": "{ address(pubKey: \"%s\") { bills(first: 2, after: \"%s\") { id } } }"}`, pubKeyHash, after))
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"address":{"bills":[{"id":"0x03"}]}}}`, body)
+
+	// the bills of the owner are loaded once for all the addresses of the owner
+	money.billsCalls = 0
+	address := &gqlAddress{PubKeyHash: []byte{1}}
+	values, err := restapi.gqlAddressBills(context.Background(), []any{address, address}, graphql.Args{paramFirst: int64(1)})
+	require.NoError(t, err)
+	require.Equal(t, []any{bills[:1], bills[:1]}, values)
+	require.Equal(t, 1, money.billsCalls)
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetBlock(mock.Anything, uint64(5), []types.PartitionID{partitionID1}).Return(nil, nil)
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{0x01}).Return(nil, errors.New("connection lost"))
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{0x02}).Return(nil, domain.ErrNotFound)
+	ts := newGraphQLServer(t, mockStorage)
+
+	// missing block and transaction are null, internal error details are not returned
+	status, body := postGraphQL(t, ts, `{"query": "{ block(partitionID: 1, number: 5) { number } a: transaction(hash: \"0x01\") { hash } b: transaction(hash: \"0x02\") { hash } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"block":null,"a":null,"b":null},"errors":[{"message":"internal error","path":["a"]}]}`, body)
+
+	// too complex queries are rejected before querying the database
+	status, body = postGraphQL(t, ts, `{"query": "{ blocks(partitionID: 1, limit: 100) { transactions { block { transactions { hash } } } } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "exceeds the limit 5000")
+
+	status, body = postGraphQL(t, ts, `{"query": "{ blocks(partitionID: 1, limit: 1000) { number } }"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"data":{"blocks":null},"errors":[{"message":"limit must be between 1 and 100","path":["blocks"]}]}`, body)
+
+	status, body = postGraphQL(t, ts, `{"query": 1}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, body, "failed to decode request body")
+
+	status, body = postGraphQL(t, ts, `{}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, body, fmt.Sprintf("missing '%s' parameter", paramQuery))
+}
+
+func TestGraphQL_Schema(t *testing.T) {
+	ts := newGraphQLServer(t, mocks.NewStorageService(t))
+
+	res, err := http.Get(ts.URL + "/graphql/schema")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "type Query {")
+	require.Contains(t, string(body), "blocks(partitionID: Int!, startBlock: Int, limit: Int = 10, includeEmpty: Boolean = true): [Block!]!")
+}
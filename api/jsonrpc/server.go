@@ -0,0 +1,230 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+)
+
+const (
+	Version = "2.0"
+
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	maxRequestSize = 1024 * 1024
+)
+
+type (
+	// Handler executes the method call, errors other than *Error are returned as internal errors.
+	Handler func(ctx context.Context, params Params) (any, error)
+
+	/*
+		Server is the JSON-RPC 2.0 server over HTTP POST. It supports batch requests and
+		notifications, params are positional (array) like in the Alphabill node RPC.
+	*/
+	Server struct {
+		methods      map[string]Handler
+		maxBatchSize int
+		limiter      Limiter
+	}
+
+	// Limiter is called before every call of the batch except the first, which is covered by the
+	// limits of the HTTP request. The call is not executed when the limiter returns an error.
+	Limiter func(ctx context.Context) error
+
+	Request struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Method  string          `json:"method"`
+		Params  Params          `json:"params,omitempty"`
+	}
+
+	Response struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  any             `json:"result,omitempty"`
+		Error   *Error          `json:"error,omitempty"`
+	}
+
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	}
+
+	// Params are the positional params of the call.
+	Params []json.RawMessage
+)
+
+func NewServer(maxBatchSize int) *Server {
+	return &Server{methods: make(map[string]Handler), maxBatchSize: maxBatchSize}
+}
+
+// SetLimiter sets the limiter of the batch calls.
+func (s *Server) SetLimiter(limiter Limiter) {
+	s.limiter = limiter
+}
+
+// Register adds the method, registering the same name again replaces the handler.
+func (s *Server) Register(method string, handler Handler) {
+	s.methods[method] = handler
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(w, errorResponse(nil, &Error{Code: CodeParseError, Message: fmt.Sprintf("failed to read request: %v", err)}))
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeJSON(w, errorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()}))
+			return
+		}
+		if len(batch) == 0 {
+			writeJSON(w, errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "empty batch"}))
+			return
+		}
+		if s.maxBatchSize > 0 && len(batch) > s.maxBatchSize {
+			writeJSON(w, errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: fmt.Sprintf("batch size exceeds the limit %d", s.maxBatchSize)}))
+			return
+		}
+		var responses []*Response
+		for i, msg := range batch {
+			if res := s.handle(r.Context(), msg, i > 0); res != nil {
+				responses = append(responses, res)
+			}
+		}
+		if len(responses) == 0 {
+			// batch of notifications
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, responses)
+		return
+	}
+
+	if res := s.handle(r.Context(), body, false); res != nil {
+		writeJSON(w, res)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handle executes the single request, nil is returned for the notifications. The call is
+// checked with the limiter when "limit" is set.
+func (s *Server) handle(ctx context.Context, msg json.RawMessage, limit bool) *Response {
+	var req Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return errorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()})
+		}
+		return errorResponse(nil, &Error{Code: CodeInvalidRequest, Message: err.Error()})
+	}
+	if req.Version != Version || req.Method == "" || !validID(req.ID) {
+		return errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "invalid request"})
+	}
+
+	handler, ok := s.methods[req.Method]
+	var result any
+	var err error
+	switch {
+	case !ok:
+		err = &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist", req.Method)}
+	case limit && s.limiter != nil:
+		if err = s.limiter(ctx); err == nil {
+			result, err = handler(ctx, req.Params)
+		}
+	default:
+		result, err = handler(ctx, req.Params)
+	}
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			log.Error("JSON-RPC method failed", "method", req.Method, "err", err)
+			rpcErr = &Error{Code: CodeInternalError, Message: "internal error"}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	return &Response{Version: Version, ID: req.ID, Result: result}
+}
+
+/*
+Bind decodes the params into the args, the first "required" params must be present and the rest are
+optional, ie the args are left unchanged when the params are missing or null.
+*/
+func (p Params) Bind(required int, args ...any) error {
+	if len(p) < required {
+		return InvalidParams(fmt.Errorf("missing value for required argument %d", len(p)))
+	}
+	if len(p) > len(args) {
+		return InvalidParams(fmt.Errorf("too many arguments, want at most %d", len(args)))
+	}
+	for i, param := range p {
+		if bytes.Equal(param, []byte("null")) {
+			if i < required {
+				return InvalidParams(fmt.Errorf("missing value for required argument %d", i))
+			}
+			continue
+		}
+		if err := json.Unmarshal(param, args[i]); err != nil {
+			return InvalidParams(fmt.Errorf("invalid argument %d: %w", i, err))
+		}
+	}
+	return nil
+}
+
+// InvalidParams returns the invalid params error.
+func InvalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func validID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	// id must be a string, number or null
+	switch id[0] {
+	case '"', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'n':
+		return true
+	}
+	return false
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &Response{Version: Version, ID: id, Error: err}
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Error("failed to encode JSON-RPC response", "err", err)
+	}
+}
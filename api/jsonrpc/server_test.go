@@ -0,0 +1,120 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	s := NewServer(2)
+	s.Register("add", func(_ context.Context, params Params) (any, error) {
+		var a, b int
+		if err := params.Bind(1, &a, &b); err != nil {
+			return nil, err
+		}
+		return a + b, nil
+	})
+	s.Register("fail", func(context.Context, Params) (any, error) {
+		return nil, errors.New("db error")
+	})
+	s.Register("appError", func(context.Context, Params) (any, error) {
+		return nil, &Error{Code: -32001, Message: "not found"}
+	})
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func post(t *testing.T, ts *httptest.Server, body string) (int, string) {
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var raw json.RawMessage
+	if res.StatusCode == http.StatusNoContent {
+		return res.StatusCode, ""
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&raw))
+	return res.StatusCode, string(raw)
+}
+
+func TestServer(t *testing.T) {
+	ts := newTestServer(t)
+
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{"call", `{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2]}`, `{"jsonrpc":"2.0","id":1,"result":3}`},
+		{"optional param", `{"jsonrpc":"2.0","id":"a","method":"add","params":[1,null]}`, `{"jsonrpc":"2.0","id":"a","result":1}`},
+		{"missing param", `{"jsonrpc":"2.0","id":1,"method":"add"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"missing value for required argument 0"}}`},
+		{"too many params", `{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2,3]}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"too many arguments, want at most 2"}}`},
+		{"invalid param", `{"jsonrpc":"2.0","id":1,"method":"add","params":["x"]}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0: json: cannot unmarshal string into Go value of type int"}}`},
+		{"method not found", `{"jsonrpc":"2.0","id":1,"method":"sub"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method sub does not exist"}}`},
+		{"internal error", `{"jsonrpc":"2.0","id":1,"method":"fail"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"internal error"}}`},
+		{"application error", `{"jsonrpc":"2.0","id":1,"method":"appError"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32001,"message":"not found"}}`},
+		{"parse error", `{"jsonrpc":`, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"unexpected end of JSON input"}}`},
+		{"invalid version", `{"jsonrpc":"1.0","id":1,"method":"add"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"invalid request"}}`},
+		{"invalid id", `{"jsonrpc":"2.0","id":{},"method":"add"}`, `{"jsonrpc":"2.0","id":{},"error":{"code":-32600,"message":"invalid request"}}`},
+		{"empty batch", `[]`, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}`},
+		{"batch too large", `[{},{},{}]`, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch size exceeds the limit 2"}}`},
+		{
+			"batch",
+			`[{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2]},{"jsonrpc":"2.0","method":"add","params":[1]}]`,
+			`[{"jsonrpc":"2.0","id":1,"result":3}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, ts, tt.request)
+			require.Equal(t, http.StatusOK, status)
+			require.JSONEq(t, tt.want, body)
+		})
+	}
+
+	t.Run("notifications", func(t *testing.T) {
+		status, _ := post(t, ts, `{"jsonrpc":"2.0","method":"add","params":[1]}`)
+		require.Equal(t, http.StatusNoContent, status)
+		status, _ = post(t, ts, `[{"jsonrpc":"2.0","method":"fail"},{"jsonrpc":"2.0","method":"sub"}]`)
+		require.Equal(t, http.StatusNoContent, status)
+	})
+}
+
+func TestServer_Limiter(t *testing.T) {
+	s := NewServer(3)
+	calls := 0
+	s.Register("call", func(context.Context, Params) (any, error) {
+		calls++
+		return calls, nil
+	})
+	tokens := 1
+	s.SetLimiter(func(context.Context) error {
+		if tokens == 0 {
+			return &Error{Code: -32005, Message: "rate limit exceeded"}
+		}
+		tokens--
+		return nil
+	})
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	// the first call of the batch is not limited, the rest take a token each
+	_, body := post(t, ts, `[{"jsonrpc":"2.0","id":1,"method":"call"},{"jsonrpc":"2.0","id":2,"method":"call"},{"jsonrpc":"2.0","id":3,"method":"call"}]`)
+	require.JSONEq(t, `[
+		{"jsonrpc":"2.0","id":1,"result":1},
+		{"jsonrpc":"2.0","id":2,"result":2},
+		{"jsonrpc":"2.0","id":3,"error":{"code":-32005,"message":"rate limit exceeded"}}
+	]`, body)
+	require.Equal(t, 2, calls)
+
+	// single request is not limited
+	_, body = post(t, ts, `{"jsonrpc":"2.0","id":4,"method":"call"}`)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":4,"result":3}`, body)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+)
+
+// getTopHolders retrieves the owners with the largest balances, the balances are maintained from the synced blocks of
+// the money partition. The bills created before the first synced block are not included until they are transferred.
+func (c *Controller) getTopHolders(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r.URL.Query().Get(paramLimit), defaultHoldersLimit)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+		return
+	}
+	holders, err := c.MoneyService.GetTopHolders(r.Context(), limit)
+	if err != nil {
+		c.writeMoneyError(w, r, fmt.Errorf("failed to load top holders: %w", err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, holders, cacheControlLatest)
+}
+
+// getBalanceDistribution retrieves the number of the holders and their total balance by the balance bucket, the buckets
+// start at zero and the powers of ten. The empty buckets are omitted.
+func (c *Controller) getBalanceDistribution(w http.ResponseWriter, r *http.Request) {
+	distribution, err := c.MoneyService.GetBalanceDistribution(r.Context())
+	if err != nil {
+		c.writeMoneyError(w, r, fmt.Errorf("failed to load balance distribution: %w", err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, distribution, cacheControlLatest)
+}
+
+// getMoneySupply retrieves the total supply, the value of the bills, the dust collector and the fee credits, and the
+// number of the holders after the latest synced block.
+func (c *Controller) getMoneySupply(w http.ResponseWriter, r *http.Request) {
+	supply, err := c.MoneyService.GetMoneySupply(r.Context())
+	if err != nil {
+		c.writeMoneyError(w, r, fmt.Errorf("failed to load money supply: %w", err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, supply, cacheControlLatest)
+}
+
+func (c *Controller) writeMoneyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		c.rw.WriteErrorResponse(w, r, err, http.StatusNotFound)
+		return
+	}
+	c.rw.WriteInternalErrorResponse(w, r, err)
+}
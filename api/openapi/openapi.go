@@ -0,0 +1,371 @@
+/*
+Package openapi contains the OpenAPI 3.1 document of the explorer API and validates the
+HTTP responses against it, the API tests use it to catch the drift between the document and the handlers.
+*/
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// documentedHeaders are the response headers which must be declared in the document when the handler sets them.
+var documentedHeaders = []string{"Link", "ETag", "Cache-Control", "Content-Disposition", "Retry-After"}
+
+// schemaKeywords are the keywords of the Schema and the annotations which don't constrain the value, the document
+// must not use any other keywords as they would not be validated.
+var schemaKeywords = []string{
+	"$ref", "type", "properties", "required", "additionalProperties", "items", "anyOf", "enum", "const", "pattern",
+	"minimum", "maximum",
+	"description", "format", "default", "contentMediaType", "title", "example", "examples", "deprecated",
+}
+
+type (
+	// Document is the subset of the OpenAPI document needed to validate the responses.
+	Document struct {
+		OpenAPI    string               `json:"openapi"`
+		Paths      map[string]*PathItem `json:"paths"`
+		Components struct {
+			Schemas   map[string]*Schema   `json:"schemas"`
+			Responses map[string]*Response `json:"responses"`
+			Headers   map[string]*Header   `json:"headers"`
+		} `json:"components"`
+	}
+
+	PathItem struct {
+		Get    *Operation `json:"get"`
+		Post   *Operation `json:"post"`
+		Delete *Operation `json:"delete"`
+	}
+
+	Operation struct {
+		OperationID string               `json:"operationId"`
+		Responses   map[string]*Response `json:"responses"`
+	}
+
+	Response struct {
+		Ref     string                `json:"$ref"`
+		Headers map[string]*Header    `json:"headers"`
+		Content map[string]*MediaType `json:"content"`
+	}
+
+	Header struct {
+		Ref    string  `json:"$ref"`
+		Schema *Schema `json:"schema"`
+	}
+
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	// Schema is the subset of JSON Schema keywords used by the document.
+	Schema struct {
+		Ref                  string             `json:"$ref"`
+		Type                 Types              `json:"type"`
+		Properties           map[string]*Schema `json:"properties"`
+		Required             []string           `json:"required"`
+		AdditionalProperties *Schema            `json:"additionalProperties"`
+		Items                *Schema            `json:"items"`
+		AnyOf                []*Schema          `json:"anyOf"`
+		Enum                 []any              `json:"enum"`
+		Const                any                `json:"const"`
+		Pattern              string             `json:"pattern"`
+		Minimum              *float64           `json:"minimum"`
+		Maximum              *float64           `json:"maximum"`
+
+		// false when the "additionalProperties" keyword is false
+		allowAdditional bool
+	}
+
+	// Types is the "type" keyword which is either a single type or a list of types.
+	Types []string
+)
+
+// JSON returns the OpenAPI document.
+func JSON() []byte {
+	return document
+}
+
+// Load parses the OpenAPI document.
+func Load() (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(document, doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// Operation returns the operation of the method and the path template, nil when the document doesn't have it.
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	}
+	return nil
+}
+
+/*
+ValidateResponse checks that the response of the operation is documented: the status code and the content type
+are declared, the headers listed in documentedHeaders are declared and the JSON body (each line of the JSON lines
+body) is valid according to the schema. Bodies of the other content types are not validated.
+*/
+func (d *Document) ValidateResponse(method, path string, statusCode int, header http.Header, body []byte) error {
+	op := d.Operation(method, path)
+	if op == nil {
+		return fmt.Errorf("operation %s %s is not documented", method, path)
+	}
+	res, ok := op.Responses[strconv.Itoa(statusCode)]
+	if !ok {
+		return fmt.Errorf("status code %d is not documented", statusCode)
+	}
+	if ref := res.Ref; ref != "" {
+		if res, ok = d.Components.Responses[strings.TrimPrefix(ref, "#/components/responses/")]; !ok {
+			return fmt.Errorf("unknown response %s", ref)
+		}
+	}
+
+	for _, name := range documentedHeaders {
+		if header.Get(name) == "" {
+			continue
+		}
+		if _, ok := res.Headers[name]; !ok {
+			return fmt.Errorf("header %s is not documented", name)
+		}
+	}
+
+	if len(body) == 0 && len(res.Content) == 0 {
+		return nil
+	}
+	contentType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", header.Get("Content-Type"), err)
+	}
+	mediaType, ok := res.Content[contentType]
+	if !ok {
+		return fmt.Errorf("content type %s is not documented", contentType)
+	}
+	switch {
+	case contentType == "application/json":
+		return d.validateJSON(mediaType.Schema, body)
+	case strings.HasSuffix(contentType, "ndjson"):
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for line := 1; scanner.Scan(); line++ {
+			if err := d.validateJSON(mediaType.Schema, scanner.Bytes()); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		return scanner.Err()
+	}
+	return nil
+}
+
+func (d *Document) validateJSON(schema *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return d.Validate(schema, value)
+}
+
+// Validate validates the JSON value decoded with json.Decoder.UseNumber against the schema.
+func (d *Document) Validate(schema *Schema, value any) error {
+	return d.validate(schema, value, "$")
+}
+
+func (d *Document) validate(schema *Schema, value any, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		refSchema, ok := d.Components.Schemas[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", path, schema.Ref)
+		}
+		if err := d.validate(refSchema, value, path); err != nil {
+			return err
+		}
+	}
+
+	if len(schema.AnyOf) > 0 {
+		var errs []string
+		for _, s := range schema.AnyOf {
+			err := d.validate(s, value, path)
+			if err == nil {
+				errs = nil
+				break
+			}
+			errs = append(errs, err.Error())
+		}
+		if errs != nil {
+			return fmt.Errorf("%s: value doesn't match any of the schemas: %s", path, strings.Join(errs, "; "))
+		}
+	}
+	if len(schema.Type) > 0 && !slices.Contains(schema.Type, jsonType(value)) &&
+		!(jsonType(value) == "integer" && slices.Contains(schema.Type, "number")) {
+		return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(schema.Type, " or "), jsonType(value))
+	}
+	if schema.Const != nil && !jsonEqual(schema.Const, value) {
+		return fmt.Errorf("%s: expected %v, got %v", path, schema.Const, value)
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return jsonEqual(e, value) }) {
+		return fmt.Errorf("%s: value %v is not one of %v", path, value, schema.Enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if schema.Pattern != "" {
+			if err := matchPattern(schema.Pattern, v); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	case json.Number:
+		if schema.Minimum != nil {
+			if f, err := v.Float64(); err != nil || f < *schema.Minimum {
+				return fmt.Errorf("%s: value %s is less than the minimum %v", path, v, *schema.Minimum)
+			}
+		}
+		if schema.Maximum != nil {
+			if f, err := v.Float64(); err != nil || f > *schema.Maximum {
+				return fmt.Errorf("%s: value %s is greater than the maximum %v", path, v, *schema.Maximum)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required property %s", path, name)
+			}
+		}
+		for name, propValue := range v {
+			propPath := path + "." + name
+			if propSchema, ok := schema.Properties[name]; ok {
+				if err := d.validate(propSchema, propValue, propPath); err != nil {
+					return err
+				}
+				continue
+			}
+			if schema.AdditionalProperties != nil {
+				if !schema.AdditionalProperties.allowAdditional {
+					return fmt.Errorf("%s: property is not documented", propPath)
+				}
+				if err := d.validate(schema.AdditionalProperties, propValue, propPath); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the schema, boolean schemas "true" and "false" are supported. Keywords which are not in
+// schemaKeywords are rejected.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*s = Schema{allowAdditional: true}
+		return nil
+	case "false":
+		*s = Schema{}
+		return nil
+	}
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for keyword := range keywords {
+		if !slices.Contains(schemaKeywords, keyword) {
+			return fmt.Errorf("unsupported schema keyword %q", keyword)
+		}
+	}
+	type schema Schema
+	if err := json.Unmarshal(data, (*schema)(s)); err != nil {
+		return err
+	}
+	s.allowAdditional = true
+	return nil
+}
+
+// UnmarshalJSON decodes the single type or the list of types.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid type: %w", err)
+	}
+	*t = list
+	return nil
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil || !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func matchPattern(pattern, s string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	if !re.MatchString(s) {
+		return fmt.Errorf("value %q doesn't match the pattern %q", s, pattern)
+	}
+	return nil
+}
+
+// jsonEqual compares the value from the document with the value decoded with UseNumber.
+func jsonEqual(expected, value any) bool {
+	a, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	b, err := json.Marshal(value)
+	return err == nil && bytes.Equal(a, b)
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) any {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	tests := []struct {
+		schema string
+		value  string
+		err    string
+	}{
+		{"ErrorResponse", `{"message":"x"}`, ""},
+		{"ErrorResponse", `{}`, "$: missing required property message"},
+		{"ErrorResponse", `{"message":"x","code":1}`, "$.code: property is not documented"},
+		{"ErrorResponse", `{"message":1}`, "$.message: expected string, got integer"},
+		{"Hex", `"0x01ab"`, ""},
+		{"Hex", `""`, ""},
+		{"Hex", `"01"`, `$: value "01" doesn't match the pattern`},
+		{"Bytes", `null`, ""},
+		{"PartitionID", `-1`, "$: value -1 is less than the minimum 0"},
+		{"PartitionID", `1.5`, "$: expected integer, got number"},
+		{"Holder", `{"Rank":1,"OwnerID":"0x01","Balance":1,"BillCount":1,"Share":100.5}`, "$.Share: value 100.5 is greater than the maximum 100"},
+		{"TxStatus", `{"TxOrderHash":"0x01","PartitionID":1,"Status":"lost"}`, "$.Status: value lost is not one of"},
+		{"BlockResponse", `{"1":{"PartitionID":1}}`, "$.1: missing required property"},
+		{"WebhookDelivery", `{"ID":"1","SubscriptionID":"0123456789abcdef01234567","Event":null,"Status":"failed","Attempts":1,"NextAttemptAt":"2024-01-01T00:00:00Z"}`, ""},
+		{"WebhookDelivery", `{"ID":"1","SubscriptionID":"0123456789abcdef01234567","Event":1,"Status":"failed","Attempts":1,"NextAttemptAt":"2024-01-01T00:00:00Z"}`, "$.Event: value doesn't match any of the schemas"},
+		{"RPCResponse", `{"jsonrpc":"1.0","id":1}`, "$.jsonrpc: expected 2.0, got 1.0"},
+	}
+	for _, tt := range tests {
+		err := doc.Validate(&Schema{Ref: "#/components/schemas/" + tt.schema}, decode(t, tt.value))
+		if tt.err == "" {
+			require.NoError(t, err, tt.value)
+		} else {
+			require.ErrorContains(t, err, tt.err, tt.value)
+		}
+	}
+}
+
+func TestSchema_UnmarshalJSON(t *testing.T) {
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"number","minimum":0,"maximum":100,"description":"x"}`), &s))
+	require.Equal(t, 100.0, *s.Maximum)
+
+	// keywords which are not validated are rejected
+	require.EqualError(t, json.Unmarshal([]byte(`{"type":"string","maxLength":10}`), &s), `unsupported schema keyword "maxLength"`)
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"properties":{"a":{"oneOf":[]}}}`), &s), `unsupported schema keyword "oneOf"`)
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+	header := func(kv ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i < len(kv); i += 2 {
+			h.Set(kv[i], kv[i+1])
+		}
+		return h
+	}
+
+	require.NoError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/partitions/{partitionID}/txs", http.StatusOK,
+		header("Content-Type", "application/json", "Link", `<x>; rel="next"`), []byte(`[]`)))
+	require.NoError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/txs/{txHash}", http.StatusNotFound,
+		header("Content-Type", "application/cbor"), []byte{0xa0}))
+	require.NoError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/units/{unitID}/txs/export", http.StatusOK,
+		header("Content-Type", "application/x-ndjson", "Content-Disposition", "attachment"), nil))
+
+	require.EqualError(t, doc.ValidateResponse(http.MethodPut, "/api/v1/txs/{txHash}", http.StatusOK, nil, nil),
+		"operation PUT /api/v1/txs/{txHash} is not documented")
+	require.EqualError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/txs/{txHash}", http.StatusTeapot, nil, nil),
+		"status code 418 is not documented")
+	require.EqualError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/txs/{txHash}", http.StatusNotFound,
+		header("Content-Type", "application/json", "Link", "x"), []byte(`{"message":"x"}`)),
+		"header Link is not documented")
+	require.EqualError(t, doc.ValidateResponse(http.MethodGet, "/api/v1/txs/{txHash}", http.StatusNotFound,
+		header("Content-Type", "text/plain"), []byte("x")),
+		"content type text/plain is not documented")
+	require.ErrorContains(t, doc.ValidateResponse(http.MethodGet, "/api/v1/units/{unitID}/txs/export", http.StatusOK,
+		header("Content-Type", "application/x-ndjson"), []byte("{}\n")),
+		"line 1: $: missing required property")
+}
@@ -0,0 +1,576 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/openapi"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/lifecycle"
+	moneyservice "github.com/alphabill-org/alphabill-explorer-backend/service/money"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/search"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/txsubmit"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	moneyServiceStub struct {
+		bills        []*sdktypes.Bill
+		billsCalls   int
+		billIDs      []types.UnitID
+		holders      *moneyservice.TopHolders
+		distribution *moneyservice.Distribution
+		supply       *domain.MoneySupply
+		err          error
+	}
+
+	searchServiceStub struct {
+		result      *search.Result
+		suggestions []*search.Suggestion
+		err         error
+	}
+
+	lifecycleServiceStub struct {
+		lifecycle *lifecycle.Lifecycle
+		err       error
+	}
+
+	unitStateServiceStub struct {
+		state *unitstate.UnitState
+		err   error
+	}
+
+	validatorServiceStub struct {
+		validators *validator.Validators
+		validator  *validator.Validator
+		err        error
+	}
+
+	openAPITestServices struct {
+		storage   *mocks.StorageService
+		rawBlock  *mocks.RawBlockService
+		txSubmit  *mocks.TxSubmitService
+		webhook   *mocks.WebhookService
+		money     *moneyServiceStub
+		search    *searchServiceStub
+		lifecycle *lifecycleServiceStub
+		unitState *unitStateServiceStub
+		validator *validatorServiceStub
+	}
+
+	openAPITestCase struct {
+		name   string
+		method string
+		url    string
+		header map[string]string
+		body   string
+		setup  func(s *openAPITestServices)
+		status int
+	}
+)
+
+func (s *moneyServiceStub) GetBillsByPubKeyHash(context.Context, hex.Bytes) ([]*sdktypes.Bill, error) {
+	s.billsCalls++
+	return s.bills, s.err
+}
+
+func (s *moneyServiceStub) GetOwnerBillIDs(context.Context, hex.Bytes) ([]types.UnitID, error) {
+	return s.billIDs, s.err
+}
+
+func (s *moneyServiceStub) GetTopHolders(context.Context, int) (*moneyservice.TopHolders, error) {
+	return s.holders, s.err
+}
+
+func (s *moneyServiceStub) GetBalanceDistribution(context.Context) (*moneyservice.Distribution, error) {
+	return s.distribution, s.err
+}
+
+func (s *moneyServiceStub) GetMoneySupply(context.Context) (*domain.MoneySupply, error) {
+	return s.supply, s.err
+}
+
+func (s *searchServiceStub) Search(context.Context, string, []types.PartitionID, []search.HitType) (*search.Result, error) {
+	return s.result, s.err
+}
+
+func (s *searchServiceStub) Suggest(context.Context, string, []types.PartitionID, int) ([]*search.Suggestion, error) {
+	return s.suggestions, s.err
+}
+
+func (s *lifecycleServiceStub) GetLifecycle(context.Context, types.UnitID) (*lifecycle.Lifecycle, error) {
+	return s.lifecycle, s.err
+}
+
+func (s *unitStateServiceStub) GetUnitState(context.Context, types.UnitID, *types.PartitionID, bool) (*unitstate.UnitState, error) {
+	return s.state, s.err
+}
+
+func (s *unitStateServiceStub) GetUnitStateAt(context.Context, types.UnitID, *types.PartitionID, uint64) (*unitstate.UnitState, error) {
+	return s.state, s.err
+}
+
+func (s *validatorServiceStub) GetValidators(context.Context, types.PartitionID, time.Duration) (*validator.Validators, error) {
+	return s.validators, s.err
+}
+
+func (s *validatorServiceStub) GetValidator(context.Context, types.PartitionID, string, time.Duration) (*validator.Validator, error) {
+	return s.validator, s.err
+}
+
+/*
+TestOpenAPI sends requests to every route of the router and validates the responses against the OpenAPI
+document, the test fails when a route is not documented, a documented operation has no route or test case
+or the response differs from the document.
+*/
+func TestSwaggerUI(t *testing.T) {
+	ts := httptest.NewServer((&Controller{}).Router())
+	defer ts.Close()
+
+	// the UI renders the OpenAPI document of the API
+	res, err := http.Get(ts.URL + "/swagger/index.html")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `url: "\/api\/openapi.json"`)
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	require.Equal(t, "3.1.0", doc.OpenAPI)
+
+	const apiKey = "key"
+	rateLimiter, err := NewRateLimiter(RateLimitConfig{Tiers: map[string]RateLimitTier{"pro": {}}, APIKeys: map[string]string{apiKey: "pro"}})
+	require.NoError(t, err)
+
+	block := &domain.BlockInfo{
+		BlockHash: []byte{0xab, 0xcd}, PartitionID: partitionID1, PartitionTypeID: money.PartitionTypeID, ProposerID: "node1", PreviousBlockHash: []byte{1},
+		TxHashes: []domain.TxHash{{2}}, UnicityCertificate: []byte{3}, BlockNumber: 5,
+	}
+	emptyBlock := &domain.BlockInfo{PartitionID: partitionID1, BlockNumber: 4}
+	tx := &domain.TxInfo{
+		ID: primitive.NewObjectID(), TxRecordHash: []byte{1}, TxOrderHash: []byte{2}, BlockNumber: 5, PartitionID: partitionID1,
+		Transaction: &types.TransactionRecord{TransactionOrder: []byte{4}, ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}},
+	}
+	bill := &sdktypes.Bill{NetworkID: 3, PartitionID: partitionID1, ID: []byte{1}, Value: 10, Counter: 1}
+	txStatus := &txsubmit.TxStatus{TxOrderHash: []byte{2}, PartitionID: partitionID1, Status: txsubmit.StatusPending, Timeout: 10}
+	txType := uint16(money.TransactionTypeTransfer)
+	subscription := &domain.WebhookSubscription{
+		ID: primitive.NewObjectID(), URL: "https://example.com", Secret: []byte{1},
+		Filter: domain.WebhookFilter{UnitID: []byte{1}, TxType: &txType},
+	}
+	delivery := &domain.WebhookDelivery{
+		ID: "1", SubscriptionID: subscription.ID, Status: domain.WebhookDeliveryFailed, Attempts: 10, LastError: "timeout",
+		Event: &domain.WebhookEvent{ID: "1", SubscriptionID: subscription.ID, PartitionID: partitionID1, TxRecordHash: []byte{1}, TxOrderHash: []byte{2}, UnitID: []byte{1}},
+	}
+	txOrder, err := (&types.TransactionOrder{Payload: types.Payload{PartitionID: partitionID1, UnitID: []byte{1}}}).MarshalCBOR()
+	require.NoError(t, err)
+	exportTx := newExportTestTx(t, money.TransactionTypeTransfer, &money.TransferAttributes{TargetValue: 100})
+	expectExport := func(s *openAPITestServices) {
+		s.storage.EXPECT().ExportTxs(mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ domain.TxExportFilter, fn func(*domain.BlockTx) error) error {
+				return fn(exportTx)
+			})
+	}
+	validatorWindow := &validator.Window{Duration: "24h0m0s", From: 1700000000 - 86400, To: 1700000000, FirstBlockNumber: 1, LastBlockNumber: 10, Blocks: 8, EmptyBlocks: 2, MissedRounds: 2, TxCount: 6, Fees: 6}
+	validatorStats := &validator.Stats{NodeID: "node1", BlocksProposed: 8, EmptyBlocks: 2, NonEmptyBlocks: 6, TxCount: 6, Fees: 6, Share: 100, FirstBlockNumber: 1, LastBlockNumber: 10, LastProposedAt: 1700000000}
+	pubKey := "0x" + strings.Repeat("02", 33)
+	webhookID := "/api/v1/webhooks/" + subscription.ID.Hex()
+	withKey := map[string]string{HeaderAPIKey: apiKey}
+	dbErr := errors.New("db error")
+	failedPartitionID := partitionID1
+
+	tests := []openAPITestCase{
+		{name: "round numbers", method: http.MethodGet, url: "/api/v1/round-number", status: http.StatusOK},
+		{name: "round numbers not modified", method: http.MethodGet, url: "/api/v1/round-number", header: map[string]string{HeaderIfNoneMatch: "*"}, status: http.StatusNotModified},
+		{name: "round numbers not acceptable", method: http.MethodGet, url: "/api/v1/round-number", header: map[string]string{Accept: "text/html"}, status: http.StatusNotAcceptable},
+		{name: "round numbers unknown API key", method: http.MethodGet, url: "/api/v1/round-number", header: map[string]string{HeaderAPIKey: "unknown"}, status: http.StatusUnauthorized},
+		{name: "partitions", method: http.MethodGet, url: "/api/v1/partitions", status: http.StatusOK},
+		{name: "partition", method: http.MethodGet, url: "/api/v1/partitions/1", status: http.StatusOK},
+		{name: "partition invalid partition ID", method: http.MethodGet, url: "/api/v1/partitions/x", status: http.StatusBadRequest},
+		{name: "partition not found", method: http.MethodGet, url: "/api/v1/partitions/2", status: http.StatusNotFound},
+
+		{name: "search", method: http.MethodGet, url: "/api/v1/search?q=0x01", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.search.result = &search.Result{
+				Hits: []*search.Hit{
+					{Type: search.HitTx, Match: search.MatchTxRecordHash, PartitionID: partitionID1, Tx: tx},
+					{Type: search.HitOwner, Match: search.MatchOwnerPubKeyHash, PartitionID: partitionID1, UnitIDs: []types.UnitID{{1}}},
+					{Type: search.HitBlock, Match: search.MatchBlockNumber, PartitionID: partitionID1, Block: block},
+				},
+				Failures: []*search.Failure{{Lookup: "txs", Error: "db error"}, {PartitionID: &failedPartitionID, Lookup: "unit", Error: "timeout"}},
+				Blocks:   map[types.PartitionID]*domain.BlockInfo{partitionID1: block},
+				Txs:      []*domain.TxInfo{tx},
+				UnitIDs:  map[types.PartitionID][]types.UnitID{partitionID1: {{1}}},
+			}
+		}},
+		{name: "search token text", method: http.MethodGet, url: "/api/v1/search?q=alpha", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.search.result = &search.Result{
+				Hits: []*search.Hit{
+					{Type: search.HitTokenType, Match: search.MatchTokenSymbol, PartitionID: partitionID1, UnitIDs: []types.UnitID{{1}}, Similarity: 1,
+						Token: &domain.TokenText{UnitID: types.UnitID{1}, PartitionID: partitionID1, BlockNumber: 5, Kind: domain.TokenTextFungibleTokenType, Symbol: "ALPHA", Name: "Alpha coin"}},
+					{Type: search.HitUnit, Match: search.MatchTokenFuzzy, PartitionID: partitionID1, UnitIDs: []types.UnitID{{2}}, Similarity: 0.375,
+						Token: &domain.TokenText{UnitID: types.UnitID{2}, PartitionID: partitionID1, BlockNumber: 6, Kind: domain.TokenTextNonFungibleToken, Name: "Alpa", URI: "ipfs://alpa"}},
+				},
+			}
+		}},
+		{name: "search not found", method: http.MethodGet, url: "/api/v1/search?q=0x01&type=unit", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.search.result = &search.Result{}
+		}},
+		{name: "search without key", method: http.MethodGet, url: "/api/v1/search", status: http.StatusBadRequest},
+		{name: "search invalid type", method: http.MethodGet, url: "/api/v1/search?q=0x01&type=bill", status: http.StatusBadRequest},
+		{name: "suggest", method: http.MethodGet, url: "/api/v1/search/suggest?q=0xabcdef&limit=5", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.search.suggestions = []*search.Suggestion{
+				{Type: search.HitBlock, Match: search.MatchBlockHashPrefix, PartitionID: partitionID1, Key: []byte{0xab, 0xcd, 0xef, 0}},
+				{Type: search.HitTx, Match: search.MatchTxRecordHashPrefix, PartitionID: partitionID1, Key: []byte{0xab, 0xcd, 0xef, 1}},
+			}
+		}},
+		{name: "suggest nothing found", method: http.MethodGet, url: "/api/v1/search/suggest?q=0xabcdef", status: http.StatusOK},
+		{name: "suggest too short prefix", method: http.MethodGet, url: "/api/v1/search/suggest?q=0xab", status: http.StatusBadRequest, setup: func(s *openAPITestServices) {
+			s.search.err = search.ErrPrefixTooShort
+		}},
+		{name: "suggest invalid limit", method: http.MethodGet, url: "/api/v1/search/suggest?q=0xabcdef&limit=0", status: http.StatusBadRequest},
+
+		{name: "latest blocks", method: http.MethodGet, url: "/api/v1/blocks/latest", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetLastBlocks(mock.Anything, mock.Anything, 1, true).Return(map[types.PartitionID][]*domain.BlockInfo{partitionID1: {block}}, nil)
+		}},
+		{name: "block", method: http.MethodGet, url: "/api/v1/blocks/4?partitionID=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlock(mock.Anything, uint64(4), mock.Anything).Return(map[types.PartitionID]*domain.BlockInfo{partitionID1: emptyBlock}, nil)
+		}},
+		{name: "block as CBOR", method: http.MethodGet, url: "/api/v1/blocks/5", header: map[string]string{Accept: ApplicationCbor}, status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlock(mock.Anything, uint64(5), mock.Anything).Return(map[types.PartitionID]*domain.BlockInfo{partitionID1: block}, nil)
+		}},
+		{name: "block not found", method: http.MethodGet, url: "/api/v1/blocks/5", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlock(mock.Anything, uint64(5), mock.Anything).Return(nil, nil)
+		}},
+		{name: "block pruned", method: http.MethodGet, url: "/api/v1/blocks/5", status: http.StatusGone, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlock(mock.Anything, uint64(5), mock.Anything).Return(nil, domain.ErrPruned)
+		}},
+		{name: "block storage error", method: http.MethodGet, url: "/api/v1/blocks/5", status: http.StatusInternalServerError, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlock(mock.Anything, uint64(5), mock.Anything).Return(nil, dbErr)
+		}},
+		{name: "invalid block number", method: http.MethodGet, url: "/api/v1/blocks/x", status: http.StatusBadRequest},
+		{name: "block by hash", method: http.MethodGet, url: "/api/v1/blocks/hash/0xabcd", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlockByHash(mock.Anything, []byte{0xab, 0xcd}).Return(block, nil)
+		}},
+		{name: "block by hash not found", method: http.MethodGet, url: "/api/v1/blocks/hash/0xabcd", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlockByHash(mock.Anything, []byte{0xab, 0xcd}).Return(nil, domain.ErrNotFound)
+		}},
+		{name: "block by invalid hash", method: http.MethodGet, url: "/api/v1/blocks/hash/xyz", status: http.StatusBadRequest},
+
+		{name: "blocks", method: http.MethodGet, url: "/api/v1/partitions/1/blocks?limit=2", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetBlocksPage(mock.Anything, partitionID1, mock.Anything, true).Return([]*domain.BlockInfo{block, emptyBlock}, true, nil)
+		}},
+		{name: "blocks invalid limit", method: http.MethodGet, url: "/api/v1/partitions/1/blocks?limit=0", status: http.StatusBadRequest},
+
+		{name: "raw block", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/raw", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.rawBlock.EXPECT().GetRawBlock(mock.Anything, partitionID1, uint64(5)).Return([]byte{0x80}, nil)
+		}},
+		{name: "raw block not found", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/raw", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.rawBlock.EXPECT().GetRawBlock(mock.Anything, partitionID1, uint64(5)).Return(nil, domain.ErrNotFound)
+		}},
+		{name: "raw block pruned", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/raw", status: http.StatusGone, setup: func(s *openAPITestServices) {
+			s.rawBlock.EXPECT().GetRawBlock(mock.Anything, partitionID1, uint64(5)).Return(nil, domain.ErrPruned)
+		}},
+
+		{name: "validators", method: http.MethodGet, url: "/api/v1/partitions/1/validators?window=24h", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.validator.validators = &validator.Validators{PartitionID: partitionID1, Window: validatorWindow, Validators: []*validator.Stats{validatorStats}}
+		}},
+		{name: "validators invalid window", method: http.MethodGet, url: "/api/v1/partitions/1/validators?window=x", status: http.StatusBadRequest},
+		{name: "validators window not configured", method: http.MethodGet, url: "/api/v1/partitions/1/validators?window=2h", status: http.StatusBadRequest, setup: func(s *openAPITestServices) {
+			s.validator.err = validator.ErrInvalidWindow
+		}},
+		{name: "validators invalid partition ID", method: http.MethodGet, url: "/api/v1/partitions/x/validators", status: http.StatusBadRequest},
+		{name: "validators not found", method: http.MethodGet, url: "/api/v1/partitions/1/validators", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.validator.err = domain.ErrNotFound
+		}},
+		{name: "validator", method: http.MethodGet, url: "/api/v1/partitions/1/validators/node1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.validator.validator = &validator.Validator{PartitionID: partitionID1, Window: validatorWindow, Validator: validatorStats}
+		}},
+		{name: "validator not found", method: http.MethodGet, url: "/api/v1/partitions/1/validators/node2", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.validator.err = domain.ErrNotFound
+		}},
+		{name: "validator error", method: http.MethodGet, url: "/api/v1/partitions/1/validators/node1", status: http.StatusInternalServerError, setup: func(s *openAPITestServices) {
+			s.validator.err = errors.New("failed")
+		}},
+
+		{name: "block txs", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/txs?limit=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPageByBlockNumber(mock.Anything, uint64(5), partitionID1, mock.Anything).Return([]*domain.TxInfo{tx}, true, nil)
+		}},
+		{name: "block txs not found", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/txs", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPageByBlockNumber(mock.Anything, uint64(5), partitionID1, mock.Anything).Return(nil, false, domain.ErrNotFound)
+		}},
+		{name: "block txs pruned", method: http.MethodGet, url: "/api/v1/partitions/1/blocks/5/txs", status: http.StatusGone, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPageByBlockNumber(mock.Anything, uint64(5), partitionID1, mock.Anything).Return(nil, false, domain.ErrPruned)
+		}},
+
+		{name: "txs", method: http.MethodGet, url: "/api/v1/partitions/1/txs?limit=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPage(mock.Anything, partitionID1, mock.Anything).Return([]*domain.TxInfo{tx}, true, nil)
+		}},
+		{name: "txs invalid cursor", method: http.MethodGet, url: "/api/v1/partitions/1/txs?cursor=x", status: http.StatusBadRequest},
+
+		{name: "submit tx", method: http.MethodPost, url: "/api/v1/partitions/1/txs", body: string(txOrder), status: http.StatusAccepted, setup: func(s *openAPITestServices) {
+			s.txSubmit.EXPECT().SubmitTx(mock.Anything, partitionID1, mock.Anything).Return(txStatus, nil)
+		}},
+		{name: "submit invalid tx", method: http.MethodPost, url: "/api/v1/partitions/1/txs", body: "x", status: http.StatusBadRequest},
+		{name: "submit tx to unknown partition", method: http.MethodPost, url: "/api/v1/partitions/1/txs", body: string(txOrder), status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.txSubmit.EXPECT().SubmitTx(mock.Anything, partitionID1, mock.Anything).Return(nil, domain.ErrNotFound)
+		}},
+
+		{name: "tx", method: http.MethodGet, url: "/api/v1/txs/0x01", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{1}).Return(tx, nil)
+		}},
+		{name: "tx without transaction record", method: http.MethodGet, url: "/api/v1/txs/0x01", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{1}).Return(&domain.TxInfo{TxRecordHash: []byte{1}}, nil)
+		}},
+		{name: "tx not found", method: http.MethodGet, url: "/api/v1/txs/0x01", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{1}).Return(nil, domain.ErrNotFound)
+		}},
+		{name: "invalid tx hash", method: http.MethodGet, url: "/api/v1/txs/x", status: http.StatusBadRequest},
+
+		{name: "tx status", method: http.MethodGet, url: "/api/v1/txs/0x02/status", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.txSubmit.EXPECT().GetTxStatus(mock.Anything, domain.TxHash{2}).Return(&txsubmit.TxStatus{
+				TxOrderHash: []byte{2}, PartitionID: partitionID1, Status: txsubmit.StatusIncluded, TxRecordHash: []byte{1}, BlockNumber: 5, Successful: true,
+			}, nil)
+		}},
+		{name: "tx status not found", method: http.MethodGet, url: "/api/v1/txs/0x02/status", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.txSubmit.EXPECT().GetTxStatus(mock.Anything, domain.TxHash{2}).Return(nil, domain.ErrNotFound)
+		}},
+
+		{name: "unit txs", method: http.MethodGet, url: "/api/v1/units/0x01/txs?limit=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPageByUnitID(mock.Anything, types.UnitID{1}, mock.Anything).Return([]*domain.TxInfo{tx}, true, nil)
+		}},
+		{name: "unit txs not found", method: http.MethodGet, url: "/api/v1/units/0x01/txs", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().GetTxsPageByUnitID(mock.Anything, types.UnitID{1}, mock.Anything).Return(nil, false, nil)
+		}},
+
+		{name: "bills", method: http.MethodGet, url: "/api/v1/address/" + pubKey + "/bills?limit=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.bills = []*sdktypes.Bill{bill, {ID: []byte{2}}}
+		}},
+		{name: "bills of owner predicate", method: http.MethodGet, url: "/api/v1/address/" + base64.RawURLEncoding.EncodeToString(predicate.P2PKH(make([]byte, 32))) + "/bills", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.bills = []*sdktypes.Bill{bill}
+		}},
+		{name: "bills invalid address", method: http.MethodGet, url: "/api/v1/address/0x0102/bills", status: http.StatusBadRequest},
+		{name: "bills not found", method: http.MethodGet, url: "/api/v1/address/" + pubKey + "/bills", status: http.StatusNotFound},
+		{name: "bills invalid limit", method: http.MethodGet, url: "/api/v1/address/" + pubKey + "/bills?limit=x", status: http.StatusBadRequest},
+
+		{name: "money holders", method: http.MethodGet, url: "/api/v1/money/holders?limit=2", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.holders = &moneyservice.TopHolders{PartitionID: partitionID1, BlockNumber: 5, HolderCount: 2, TotalSupply: 100, Holders: []*moneyservice.Holder{
+				{Rank: 1, OwnerID: make([]byte, 32), Balance: 60, BillCount: 2, Share: 60},
+				{Rank: 2, OwnerID: make([]byte, 32), Balance: 40, BillCount: 1, Share: 40},
+			}}
+		}},
+		{name: "money holders invalid limit", method: http.MethodGet, url: "/api/v1/money/holders?limit=0", status: http.StatusBadRequest},
+		{name: "money holders not configured", method: http.MethodGet, url: "/api/v1/money/holders", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.money.err = domain.ErrNotFound
+		}},
+		{name: "money distribution", method: http.MethodGet, url: "/api/v1/money/distribution", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.distribution = &moneyservice.Distribution{PartitionID: partitionID1, BlockNumber: 5, HolderCount: 2, Buckets: []*domain.BalanceBucket{
+				{Min: 10, Max: 100, HolderCount: 2, Balance: 100},
+				{Min: 1e18, HolderCount: 0},
+			}}
+		}},
+		{name: "money distribution error", method: http.MethodGet, url: "/api/v1/money/distribution", status: http.StatusInternalServerError, setup: func(s *openAPITestServices) {
+			s.money.err = errors.New("failed")
+		}},
+		{name: "money supply", method: http.MethodGet, url: "/api/v1/money/supply", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.supply = &domain.MoneySupply{PartitionID: partitionID1, BlockNumber: 5, TotalSupply: 120, BillsValue: 100, DustCollector: 5, FeeCredit: 15, HolderCount: 2, UntrackedBills: 1}
+		}},
+		{name: "money supply not configured", method: http.MethodGet, url: "/api/v1/money/supply", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.money.err = domain.ErrNotFound
+		}},
+
+		{name: "unit state at block", method: http.MethodGet, url: "/api/v1/units/0x01?atBlock=5", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			value, counter := uint64(100), uint64(2)
+			s.unitState.state = &unitstate.UnitState{
+				UnitID: types.UnitID{1}, PartitionID: partitionID1, Source: unitstate.SourceHistory, BlockNumber: 5, LastTxBlock: 4, Complete: true,
+				Data: &unitstate.UnitData{Kind: unitstate.KindBill, Value: &value, OwnerPredicate: predicate.Decode(predicate.P2PKH(make([]byte, 32))), Counter: &counter, Locked: true},
+			}
+		}},
+		{name: "unit state from node", method: http.MethodGet, url: "/api/v1/units/0x01?includeStateProof=true&partitionID=1", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.unitState.state = &unitstate.UnitState{
+				UnitID: types.UnitID{1}, PartitionID: partitionID1, Source: unitstate.SourceNode, BlockNumber: 7,
+				Data:       map[string]any{"value": "100"},
+				StateProof: &types.UnitStateProof{UnitID: types.UnitID{1}},
+				Proof:      &unitstate.ProofVerification{RoundNumber: 7, Error: "block 7 is not synced"},
+			}
+		}},
+		{name: "unit state not found", method: http.MethodGet, url: "/api/v1/units/0x01", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.unitState.err = domain.ErrNotFound
+		}},
+		{name: "unit state invalid atBlock", method: http.MethodGet, url: "/api/v1/units/0x01?atBlock=x", status: http.StatusBadRequest},
+		{name: "unit state at block with state proof", method: http.MethodGet, url: "/api/v1/units/0x01?atBlock=5&includeStateProof=true", status: http.StatusBadRequest},
+		{name: "unit lifecycle", method: http.MethodGet, url: "/api/v1/units/0x01/lifecycle", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			created := &lifecycle.Event{Kind: lifecycle.EventCreated, TxType: 2, TxRecordHash: domain.TxHash{1}, BlockNumber: 5, Timestamp: 1700000000,
+				RelatedUnits: []*lifecycle.RelatedUnit{{Relation: lifecycle.RelationSplitSource, PartitionID: partitionID1, UnitID: types.UnitID{2}}}}
+			closed := &lifecycle.Event{Kind: lifecycle.EventFeeCreditClosed, TxType: 17, TxRecordHash: domain.TxHash{2}, BlockNumber: 6, Timestamp: 1700000001,
+				RelatedUnits: []*lifecycle.RelatedUnit{{Relation: lifecycle.RelationReclaimTarget, UnitID: types.UnitID{3}}}}
+			s.lifecycle.lifecycle = &lifecycle.Lifecycle{
+				UnitID:          types.UnitID{1},
+				PartitionID:     partitionID1,
+				PartitionTypeID: 1,
+				Created:         created,
+				Events:          []*lifecycle.Event{created, closed},
+				RelatedUnits:    append(created.RelatedUnits, closed.RelatedUnits...),
+				State:           lifecycle.StateActive,
+				Truncated:       true,
+			}
+		}},
+		{name: "unit lifecycle not found", method: http.MethodGet, url: "/api/v1/units/0x01/lifecycle", status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.lifecycle.err = domain.ErrNotFound
+		}},
+		{name: "unit lifecycle invalid unit ID", method: http.MethodGet, url: "/api/v1/units/xyz/lifecycle", status: http.StatusBadRequest},
+		{name: "unit export", method: http.MethodGet, url: "/api/v1/units/0x01/txs/export", status: http.StatusOK, setup: expectExport},
+		{name: "unit export as JSON lines", method: http.MethodGet, url: "/api/v1/units/0x01/txs/export?format=jsonl", status: http.StatusOK, setup: expectExport},
+		{name: "unit export invalid format", method: http.MethodGet, url: "/api/v1/units/0x01/txs/export?format=xml", status: http.StatusBadRequest},
+		{name: "unit export storage error", method: http.MethodGet, url: "/api/v1/units/0x01/txs/export", status: http.StatusInternalServerError, setup: func(s *openAPITestServices) {
+			s.storage.EXPECT().ExportTxs(mock.Anything, mock.Anything, mock.Anything).Return(dbErr)
+		}},
+		{name: "address export", method: http.MethodGet, url: "/api/v1/address/" + pubKey + "/txs/export?format=jsonl", status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.money.billIDs = []types.UnitID{bill.ID}
+			expectExport(s)
+		}},
+		{name: "address export not found", method: http.MethodGet, url: "/api/v1/address/" + pubKey + "/txs/export", status: http.StatusNotFound},
+
+		{name: "webhooks", method: http.MethodGet, url: "/api/v1/webhooks", header: withKey, status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().GetSubscriptions(mock.Anything, mock.Anything).Return([]*domain.WebhookSubscription{{ID: subscription.ID, URL: subscription.URL}}, nil)
+		}},
+		{name: "webhooks without API key", method: http.MethodGet, url: "/api/v1/webhooks", status: http.StatusUnauthorized},
+		{name: "create webhook", method: http.MethodPost, url: "/api/v1/webhooks", header: withKey, body: `{"URL":"https://example.com"}`, status: http.StatusCreated, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().CreateSubscription(mock.Anything, mock.Anything, "https://example.com", mock.Anything).Return(subscription, nil)
+		}},
+		{name: "create webhook invalid request", method: http.MethodPost, url: "/api/v1/webhooks", header: withKey, body: "x", status: http.StatusBadRequest},
+		{name: "webhook", method: http.MethodGet, url: webhookID, header: withKey, status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().GetSubscription(mock.Anything, mock.Anything, subscription.ID).Return(subscription, nil)
+		}},
+		{name: "webhook not found", method: http.MethodGet, url: webhookID, header: withKey, status: http.StatusNotFound, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().GetSubscription(mock.Anything, mock.Anything, subscription.ID).Return(nil, domain.ErrNotFound)
+		}},
+		{name: "invalid webhook ID", method: http.MethodGet, url: "/api/v1/webhooks/x", header: withKey, status: http.StatusBadRequest},
+		{name: "delete webhook", method: http.MethodDelete, url: webhookID, header: withKey, status: http.StatusNoContent, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().DeleteSubscription(mock.Anything, mock.Anything, subscription.ID).Return(nil)
+		}},
+		{name: "webhook deliveries", method: http.MethodGet, url: webhookID + "/deliveries", header: withKey, status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().GetDeliveries(mock.Anything, mock.Anything, subscription.ID, domain.WebhookDeliveryStatus(""), defaultDeliveriesLimit).
+				Return([]*domain.WebhookDelivery{delivery}, nil)
+		}},
+		{name: "webhook deliveries invalid status", method: http.MethodGet, url: webhookID + "/deliveries?status=x", header: withKey, status: http.StatusBadRequest},
+		{name: "replay webhook deliveries", method: http.MethodPost, url: webhookID + "/replay", header: withKey, status: http.StatusOK, setup: func(s *openAPITestServices) {
+			s.webhook.EXPECT().ReplayDeliveries(mock.Anything, mock.Anything, subscription.ID).Return(1, nil)
+		}},
+
+		{name: "GraphQL GET", method: http.MethodGet, url: "/api/graphql?query=%7Bunknown%7D", status: http.StatusOK},
+		{name: "GraphQL GET without query", method: http.MethodGet, url: "/api/graphql", status: http.StatusBadRequest},
+		{name: "GraphQL POST", method: http.MethodPost, url: "/api/graphql", body: `{"query":"{ partitions { id } }"}`, status: http.StatusOK},
+		{name: "GraphQL schema", method: http.MethodGet, url: "/api/graphql/schema", status: http.StatusOK},
+		{name: "OpenAPI document", method: http.MethodGet, url: "/api/openapi.json", status: http.StatusOK},
+
+		{name: "JSON-RPC", method: http.MethodPost, url: "/rpc", body: `{"jsonrpc":"2.0","id":1,"method":"explorer_getRoundNumbers"}`, status: http.StatusOK},
+		{name: "JSON-RPC batch", method: http.MethodPost, url: "/rpc", body: `[{"jsonrpc":"2.0","id":1,"method":"explorer_getRoundNumbers"},{"jsonrpc":"2.0","id":2,"method":"x"}]`, status: http.StatusOK},
+		{name: "JSON-RPC notification", method: http.MethodPost, url: "/rpc", body: `{"jsonrpc":"2.0","method":"explorer_getRoundNumbers"}`, status: http.StatusNoContent},
+	}
+
+	tested := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &openAPITestServices{
+				storage:   mocks.NewStorageService(t),
+				rawBlock:  mocks.NewRawBlockService(t),
+				txSubmit:  mocks.NewTxSubmitService(t),
+				webhook:   mocks.NewWebhookService(t),
+				money:     &moneyServiceStub{},
+				search:    &searchServiceStub{},
+				lifecycle: &lifecycleServiceStub{},
+				unitState: &unitStateServiceStub{},
+				validator: &validatorServiceStub{},
+			}
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+			restapi := &Controller{
+				StorageService:   s.storage,
+				PartitionService: partitionServiceStub{},
+				MoneyService:     s.money,
+				SearchService:    s.search,
+				LifecycleService: s.lifecycle,
+				UnitStateService: s.unitState,
+				ValidatorService: s.validator,
+				RawBlockService:  s.rawBlock,
+				TxSubmitService:  s.txSubmit,
+				WebhookService:   s.webhook,
+				RateLimiter:      rateLimiter,
+			}
+			router := restapi.Router()
+			ts := httptest.NewServer(router)
+			defer ts.Close()
+
+			req, err := http.NewRequest(tt.method, ts.URL+tt.url, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			var match mux.RouteMatch
+			require.True(t, router.Match(req, &match), "no route")
+			path, err := match.Route.GetPathTemplate()
+			require.NoError(t, err)
+			tested[tt.method+" "+path] = true
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, res.StatusCode, string(body))
+			require.NoError(t, doc.ValidateResponse(tt.method, path, res.StatusCode, res.Header, body))
+		})
+	}
+
+	// every route is documented and tested, every documented operation has a route
+	routes := make(map[string]bool)
+	require.NoError(t, (&Controller{}).Router().Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || path == "/health" || path == "/swagger/" {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+			routes[method+" "+path] = true
+			require.NotNil(t, doc.Operation(method, path), "%s %s is not documented", method, path)
+			require.True(t, tested[method+" "+path], "%s %s is not tested", method, path)
+		}
+		return nil
+	}))
+	for path, item := range doc.Paths {
+		for method, op := range map[string]*openapi.Operation{http.MethodGet: item.Get, http.MethodPost: item.Post, http.MethodDelete: item.Delete} {
+			if op != nil {
+				require.True(t, routes[method+" "+path], "documented operation %s %s has no route", method, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	cursorVersion = 1
+
+	// names of the paged lists, cursor of one list can't be used with another
+	listBlocks   = "blocks"
+	listTxs      = "txs"
+	listBlockTxs = "blocktxs"
+	listUnitTxs  = "unittxs"
+	listBills    = "bills"
+
+	maxPageLimit = 100
+)
+
+/*
+pageCursor is the position in the list, it's returned in the Link header of the response
+and the client sends it back in the "cursor" query parameter to get the next or previous page.
+The cursor is opaque for the clients, it's encoded as base64url of the CBOR array.
+*/
+type pageCursor struct {
+	_        struct{} `cbor:",toarray"`
+	Version  uint64
+	List     string
+	Key      []byte // key of the last item of the previous page (first item when paging backward)
+	Backward bool
+}
+
+func (c *pageCursor) String() string {
+	data, err := cbor.Marshal(c)
+	if err != nil {
+		// can't happen, all the fields are encodable
+		panic(fmt.Errorf("failed to encode cursor: %w", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseCursor decodes the cursor of the "list", nil is returned for an empty string.
+// When "keyLen" is not zero the key of the cursor must be of that length.
+func parseCursor(s, list string, keyLen int) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	c := &pageCursor{}
+	if err = cbor.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	switch {
+	case c.Version != cursorVersion:
+		return nil, fmt.Errorf("unsupported cursor version %d", c.Version)
+	case c.List != list:
+		return nil, fmt.Errorf("cursor of the list %q can't be used with the list %q", c.List, list)
+	case len(c.Key) == 0 || (keyLen != 0 && len(c.Key) != keyLen):
+		return nil, errors.New("invalid cursor key")
+	}
+	return c, nil
+}
+
+// parseLimit parses the "limit" query parameter, values over maxPageLimit are capped.
+func parseLimit(s string, defaultLimit int) (int, error) {
+	if s == "" {
+		return defaultLimit, nil
+	}
+	return ParseMaxResponseItems(s, maxPageLimit)
+}
+
+// pageRequest creates the store page request from the cursor, "key" decodes the cursor key.
+func pageRequest[K any](c *pageCursor, limit int, key func([]byte) K) domain.PageRequest[K] {
+	page := domain.PageRequest[K]{Limit: limit}
+	if c != nil {
+		k := key(c.Key)
+		page.Key = &k
+		page.Backward = c.Backward
+	}
+	return page
+}
+
+func encodeUint64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func decodeUint64(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+// setPageLinks sets the Link header with the "next" and "prev" links of the page, see pageCursors.
+func setPageLinks(w http.ResponseWriter, u *url.URL, list string, c *pageCursor, firstKey, lastKey []byte, hasMore bool) {
+	next, prev := pageCursors(list, c, firstKey, lastKey, hasMore)
+	var links []string
+	if next != nil {
+		links = append(links, pageLink(u, next, "next"))
+	}
+	if prev != nil {
+		links = append(links, pageLink(u, prev, "prev"))
+	}
+	if len(links) == 0 {
+		w.Header().Del(HeaderLink)
+		return
+	}
+	w.Header().Set(HeaderLink, strings.Join(links, ", "))
+}
+
+/*
+pageCursors returns the cursors of the next and previous pages, nil when there is no such page. The page was
+requested with the cursor "c" (nil for the first page), "firstKey" and "lastKey" are the keys of the first and
+the last item of the page (nil for an empty page) and "hasMore" tells whether there are more items in the paging direction.
+*/
+func pageCursors(list string, c *pageCursor, firstKey, lastKey []byte, hasMore bool) (next, prev *pageCursor) {
+	backward := c != nil && c.Backward
+	if firstKey == nil && c != nil {
+		// empty page, the client may turn back from the cursor position
+		firstKey, lastKey = c.Key, c.Key
+	}
+	if lastKey != nil && (backward || hasMore) {
+		next = &pageCursor{Version: cursorVersion, List: list, Key: lastKey}
+	}
+	if firstKey != nil && ((backward && hasMore) || (!backward && c != nil)) {
+		prev = &pageCursor{Version: cursorVersion, List: list, Key: firstKey, Backward: true}
+	}
+	return next, prev
+}
+
+func pageLink(u *url.URL, c *pageCursor, rel string) string {
+	link := *u
+	qp := link.Query()
+	qp.Set(QueryParamCursor, c.String())
+	qp.Del(paramStartBlock) // the cursor defines the position
+	link.RawQuery = qp.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, &link, rel)
+}
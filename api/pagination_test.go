@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseCursor(t *testing.T) {
+	c := &pageCursor{Version: cursorVersion, List: listBlocks, Key: encodeUint64(42), Backward: true}
+	parsed, err := parseCursor(c.String(), listBlocks, 8)
+	require.NoError(t, err)
+	require.Equal(t, c, parsed)
+
+	parsed, err = parseCursor("", listBlocks, 8)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	_, err = parseCursor(c.String(), listTxs, 0)
+	require.ErrorContains(t, err, `cursor of the list "blocks" can't be used with the list "txs"`)
+
+	_, err = parseCursor(c.String(), listBlocks, 12)
+	require.ErrorContains(t, err, "invalid cursor key")
+
+	_, err = parseCursor((&pageCursor{Version: 2, List: listBlocks, Key: encodeUint64(1)}).String(), listBlocks, 8)
+	require.ErrorContains(t, err, "unsupported cursor version 2")
+
+	_, err = parseCursor("not a cursor!", listBlocks, 8)
+	require.ErrorContains(t, err, "failed to decode cursor")
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := parseLimit("", 20)
+	require.NoError(t, err)
+	require.Equal(t, 20, limit)
+
+	limit, err = parseLimit("1000", 20)
+	require.NoError(t, err)
+	require.Equal(t, maxPageLimit, limit)
+
+	_, err = parseLimit("0", 20)
+	require.Error(t, err)
+}
+
+// pageLinks returns the cursors of the "next" and "prev" links of the response.
+func pageLinks(t *testing.T, res *http.Response) map[string]*pageCursor {
+	links := make(map[string]*pageCursor)
+	for _, m := range regexp.MustCompile(`<([^>]*)>; rel="(\w+)"`).FindAllStringSubmatch(res.Header.Get(HeaderLink), -1) {
+		u, err := url.Parse(m[1])
+		require.NoError(t, err)
+		links[m[2]], err = parseCursor(u.Query().Get(QueryParamCursor), listTxs, 12)
+		require.NoError(t, err)
+	}
+	return links
+}
+
+func TestGetTxs_Paging(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	txs := []*domain.TxInfo{{ID: ids[2]}, {ID: ids[1]}, {ID: ids[0]}}
+
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxsPage(mock.Anything, partitionID1, domain.PageRequest[primitive.ObjectID]{Limit: 2}).
+		Return(txs[:2], true, nil)
+	mockStorage.EXPECT().GetTxsPage(mock.Anything, partitionID1, domain.PageRequest[primitive.ObjectID]{Key: &ids[1], Limit: 2}).
+		Return(txs[2:], false, nil)
+	mockStorage.EXPECT().GetTxsPage(mock.Anything, partitionID1, domain.PageRequest[primitive.ObjectID]{Key: &ids[0], Backward: true, Limit: 2}).
+		Return(txs[:2], false, nil)
+	r := mux.NewRouter()
+	restapi := &Controller{StorageService: mockStorage}
+	r.HandleFunc("/partitions/{partitionID}/txs", restapi.getTxs)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	get := func(c *pageCursor) (*http.Response, []TxInfo) {
+		u := fmt.Sprintf("%s/partitions/%d/txs?limit=2", ts.URL, partitionID1)
+		if c != nil {
+			u += "&cursor=" + c.String()
+		}
+		res, err := http.Get(u)
+		require.NoError(t, err)
+		var result []TxInfo
+		require.NoError(t, DecodeResponse(res, http.StatusOK, &result, false))
+		return res, result
+	}
+
+	// first page
+	res, result := get(nil)
+	require.Len(t, result, 2)
+	links := pageLinks(t, res)
+	require.Len(t, links, 1)
+	require.Equal(t, ids[1][:], links["next"].Key)
+	require.False(t, links["next"].Backward)
+
+	// last page
+	res, result = get(links["next"])
+	require.Len(t, result, 1)
+	links = pageLinks(t, res)
+	require.Len(t, links, 1)
+	require.Equal(t, ids[0][:], links["prev"].Key)
+	require.True(t, links["prev"].Backward)
+
+	// back to the first page
+	res, result = get(links["prev"])
+	require.Len(t, result, 2)
+	links = pageLinks(t, res)
+	require.Len(t, links, 1)
+	require.Equal(t, ids[1][:], links["next"].Key)
+
+	// invalid cursor
+	res, err := http.Get(fmt.Sprintf("%s/partitions/%d/txs?cursor=%s", ts.URL, partitionID1, &pageCursor{Version: cursorVersion, List: listBills, Key: []byte{1}}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestBillsPage(t *testing.T) {
+	bills := []*sdktypes.Bill{{ID: types.UnitID{3}}, {ID: types.UnitID{1}}, {ID: types.UnitID{4}}, {ID: types.UnitID{2}}}
+	ids := func(bills []*sdktypes.Bill) (res []byte) {
+		for _, b := range bills {
+			res = append(res, b.ID[0])
+		}
+		return res
+	}
+	key := func(id byte) *types.UnitID {
+		k := types.UnitID{id}
+		return &k
+	}
+
+	page, hasMore := billsPage(bills, domain.PageRequest[types.UnitID]{Limit: 3})
+	require.Equal(t, []byte{1, 2, 3}, ids(page))
+	require.True(t, hasMore)
+
+	page, hasMore = billsPage(bills, domain.PageRequest[types.UnitID]{Key: key(2), Limit: 3})
+	require.Equal(t, []byte{3, 4}, ids(page))
+	require.False(t, hasMore)
+
+	page, hasMore = billsPage(bills, domain.PageRequest[types.UnitID]{Key: key(4), Backward: true, Limit: 2})
+	require.Equal(t, []byte{2, 3}, ids(page))
+	require.True(t, hasMore)
+
+	// the bill with the key may be spent already
+	page, hasMore = billsPage([]*sdktypes.Bill{{ID: types.UnitID{4}}, {ID: types.UnitID{1}}}, domain.PageRequest[types.UnitID]{Key: key(3), Limit: 2})
+	require.Equal(t, []byte{4}, ids(page))
+	require.False(t, hasMore)
+}
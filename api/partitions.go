@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/gorilla/mux"
+)
+
+// getPartitions returns the partitions indexed by the explorer with the health of the nodes, the indexed block range,
+// the sync lag and the totals of the stored blocks and transactions. The registry is refreshed periodically and
+// persisted, the partitions which nodes are not available keep the latest known state.
+func (c *Controller) getPartitions(w http.ResponseWriter, r *http.Request) {
+	partitions, err := c.PartitionService.GetPartitions(r.Context())
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load partitions: %w", err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, partitions, cacheControlLatest)
+}
+
+// getPartition returns the partition with the health of the nodes, the indexed block range, the sync lag and the totals
+// of the stored blocks and transactions.
+func (c *Controller) getPartition(w http.ResponseWriter, r *http.Request) {
+	partitionID, err := strconv.ParseUint(mux.Vars(r)[paramPartitionID], 10, 32)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+		return
+	}
+	info, err := c.PartitionService.GetPartition(r.Context(), types.PartitionID(partitionID))
+	if err != nil {
+		err = fmt.Errorf("failed to load partition %d: %w", partitionID, err)
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, err, http.StatusNotFound)
+		} else {
+			c.rw.WriteInternalErrorResponse(w, r, err)
+		}
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, info, cacheControlLatest)
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	HeaderAPIKey        = "X-API-Key"
+	HeaderRetryAfter    = "Retry-After"
+	HeaderForwardedFor  = "X-Forwarded-For"
+	bucketSweepInterval = time.Minute
+)
+
+// partitionRPCRoutes are the routes (optionally prefixed with the method) which call the partition
+// nodes, they are limited by the PartitionRequests limit in addition to the Requests limit.
+var partitionRPCRoutes = map[string]struct{}{
+	"/api/v1/search": {},
+	"POST /api/v1/partitions/{partitionID}/txs": {},
+	"/api/v1/round-number":                      {},
+	"/api/v1/address/{pubKey}/bills":            {},
+	"/api/v1/units/{unitID}":                    {},
+	"/api/v1/address/{pubKey}/txs/export":       {},
+	"/api/graphql":                              {},
+	"/rpc":                                      {},
+}
+
+type (
+	// RateLimit is a token bucket limit, zero Rate means unlimited.
+	RateLimit struct {
+		Rate  float64 // requests per second
+		Burst int     // size of the bucket, at least one request
+	}
+
+	// RateLimitTier is the set of limits applied to a client.
+	RateLimitTier struct {
+		Requests          RateLimit // all the API requests
+		PartitionRequests RateLimit // requests which call the partition nodes
+	}
+
+	RateLimitConfig struct {
+		Anonymous RateLimitTier            // per IP limits of the requests without API key
+		Tiers     map[string]RateLimitTier // API key tiers by name
+		APIKeys   map[string]string        // tier names by API key
+		// TrustProxy tells to use the last address of the X-Forwarded-For header as the client
+		// address, it must only be set when the explorer is behind a reverse proxy.
+		TrustProxy bool
+	}
+
+	/*
+		RateLimiter limits the API requests per client. Requests with API key (X-API-Key header) are
+		limited per key using the limits of the key tier, other requests are limited per client IP.
+	*/
+	RateLimiter struct {
+		anonymous  RateLimitTier
+		tiers      map[string]RateLimitTier
+		apiKeys    map[string]string
+		trustProxy bool
+
+		mu        sync.Mutex
+		buckets   map[string]*tokenBucket
+		lastSweep time.Time
+		now       func() time.Time
+	}
+
+	// rateLimitKey is the request context key of the rateLimitFunc
+	rateLimitKey struct{}
+
+	// rateLimitFunc takes a token for another operation of the request, eg a call of the JSON-RPC batch,
+	// from the limits of the client making the request. Returns false when the client has exceeded its limits.
+	rateLimitFunc func() bool
+
+	tokenBucket struct {
+		limit  RateLimit
+		tokens float64
+		last   time.Time
+	}
+)
+
+var errUnknownAPIKey = errors.New("unknown API key")
+
+func NewRateLimiter(cfg RateLimitConfig) (*RateLimiter, error) {
+	if err := cfg.Anonymous.validate(); err != nil {
+		return nil, fmt.Errorf("invalid anonymous limits: %w", err)
+	}
+	for name, tier := range cfg.Tiers {
+		if err := tier.validate(); err != nil {
+			return nil, fmt.Errorf("invalid limits of the tier %q: %w", name, err)
+		}
+	}
+	for key, tier := range cfg.APIKeys {
+		if key == "" {
+			return nil, errors.New("empty API key")
+		}
+		if _, ok := cfg.Tiers[tier]; !ok {
+			return nil, fmt.Errorf("unknown tier %q of the API key", tier)
+		}
+	}
+	return &RateLimiter{
+		anonymous:  cfg.Anonymous,
+		tiers:      cfg.Tiers,
+		apiKeys:    cfg.APIKeys,
+		trustProxy: cfg.TrustProxy,
+		buckets:    make(map[string]*tokenBucket),
+		now:        time.Now,
+	}, nil
+}
+
+func (t RateLimitTier) validate() error {
+	if err := t.Requests.validate(); err != nil {
+		return fmt.Errorf("requests: %w", err)
+	}
+	if err := t.PartitionRequests.validate(); err != nil {
+		return fmt.Errorf("partition requests: %w", err)
+	}
+	return nil
+}
+
+func (l RateLimit) validate() error {
+	if l.Rate < 0 || math.IsNaN(l.Rate) || math.IsInf(l.Rate, 0) {
+		return fmt.Errorf("invalid rate %v", l.Rate)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("invalid burst %d", l.Burst)
+	}
+	return nil
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return max(1, math.Floor(l.Rate))
+}
+
+/*
+allow takes a token for the request of the client from the requests bucket and from the partition
+requests bucket when "partitionRPC" is set. When the request is not allowed the time after which
+the client may retry is returned.
+*/
+func (rl *RateLimiter) allow(client string, tier RateLimitTier, partitionRPC bool) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	// check both buckets before taking the tokens, a rejected request must not use up any of them
+	buckets := []*tokenBucket{rl.bucket(client, tier.Requests, now)}
+	if partitionRPC {
+		buckets = append(buckets, rl.bucket(client+"/partition", tier.PartitionRequests, now))
+	}
+	allowed, retryAfter := true, time.Duration(0)
+	for _, b := range buckets {
+		if b != nil && b.tokens < 1 {
+			allowed = false
+			retryAfter = max(retryAfter, time.Duration((1-b.tokens)/b.limit.Rate*float64(time.Second)))
+		}
+	}
+	if !allowed {
+		return false, retryAfter
+	}
+	for _, b := range buckets {
+		if b != nil {
+			b.tokens--
+		}
+	}
+	return true, 0
+}
+
+// bucket returns the refilled bucket, nil is returned for unlimited requests.
+func (rl *RateLimiter) bucket(key string, limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Rate == 0 {
+		return nil
+	}
+	b, ok := rl.buckets[key]
+	if !ok || b.limit != limit {
+		b = &tokenBucket{limit: limit, tokens: limit.burst(), last: now}
+		rl.buckets[key] = b
+		return b
+	}
+	b.tokens = min(limit.burst(), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	return b
+}
+
+// sweep removes the buckets which are full, ie the clients haven't made requests for a while.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < bucketSweepInterval {
+		return
+	}
+	rl.lastSweep = now
+	for key, b := range rl.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= b.limit.burst() {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// client returns the rate limiting key and tier of the client making the request.
+func (rl *RateLimiter) client(r *http.Request) (string, RateLimitTier, error) {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		tier, ok := rl.apiKeys[key]
+		if !ok {
+			return "", RateLimitTier{}, errUnknownAPIKey
+		}
+		return "key:" + key, rl.tiers[tier], nil
+	}
+	return "ip:" + rl.clientIP(r), rl.anonymous, nil
+}
+
+func (rl *RateLimiter) knownAPIKey(key string) bool {
+	_, ok := rl.apiKeys[key]
+	return ok
+}
+
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	if rl.trustProxy {
+		// the last address is added by our proxy, the ones before it may be forged by the client
+		if values := r.Header.Values(HeaderForwardedFor); len(values) > 0 {
+			addrs := strings.Split(values[len(values)-1], ",")
+			if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitMiddleware responds with 429 Too Many Requests when the client has exceeded its limits
+// and with 401 Unauthorized when the request has an unknown API key.
+func (c *Controller) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.RateLimiter == nil || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		client, tier, err := c.RateLimiter.client(r)
+		if err != nil {
+			c.rw.ErrorResponse(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		partitionRPC := isPartitionRPCRoute(r)
+		if ok, retryAfter := c.RateLimiter.allow(client, tier, partitionRPC); !ok {
+			w.Header().Set(HeaderRetryAfter, strconv.FormatInt(max(1, int64(math.Ceil(retryAfter.Seconds()))), 10))
+			c.rw.ErrorResponse(w, r, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+			return
+		}
+		limit := rateLimitFunc(func() bool {
+			ok, _ := c.RateLimiter.allow(client, tier, partitionRPC)
+			return ok
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rateLimitKey{}, limit)))
+	})
+}
+
+func isPartitionRPCRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	_, ok := partitionRPCRoutes[tpl]
+	if !ok {
+		_, ok = partitionRPCRoutes[r.Method+" "+tpl]
+	}
+	return ok
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/jsonrpc"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/partition"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type partitionServiceStub struct{}
+
+func (partitionServiceStub) GetRoundNumber(context.Context) ([]partition.RoundInfo, error) {
+	return []partition.RoundInfo{}, nil
+}
+
+func (s partitionServiceStub) GetPartitions(ctx context.Context) ([]*domain.PartitionInfo, error) {
+	info, err := s.GetPartition(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	return []*domain.PartitionInfo{info}, nil
+}
+
+func (partitionServiceStub) GetPartition(_ context.Context, partitionID types.PartitionID) (*domain.PartitionInfo, error) {
+	if partitionID != 1 {
+		return nil, domain.ErrNotFound
+	}
+	checkedAt := time.Unix(1700000000, 0).UTC()
+	return &domain.PartitionInfo{
+		PartitionID:       1,
+		PartitionTypeID:   1,
+		TypeName:          "money",
+		NetworkID:         3,
+		Shards:            []string{""},
+		Nodes:             []*domain.NodeStatus{{URL: "http://localhost:26866", Healthy: true, RoundNumber: 100, CheckedAt: checkedAt, LastHealthyAt: &checkedAt}},
+		FirstBlockNumber:  1,
+		LatestBlockNumber: 98,
+		RoundNumber:       100,
+		EpochNumber:       1,
+		SyncLag:           2,
+		BlockCount:        90,
+		TxCount:           250,
+		UpdatedAt:         checkedAt,
+	}, nil
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	_, err := NewRateLimiter(RateLimitConfig{Anonymous: RateLimitTier{Requests: RateLimit{Rate: -1}}})
+	require.ErrorContains(t, err, "invalid anonymous limits: requests: invalid rate -1")
+
+	_, err = NewRateLimiter(RateLimitConfig{Tiers: map[string]RateLimitTier{"a": {PartitionRequests: RateLimit{Burst: -1}}}})
+	require.ErrorContains(t, err, `invalid limits of the tier "a": partition requests: invalid burst -1`)
+
+	_, err = NewRateLimiter(RateLimitConfig{APIKeys: map[string]string{"key": "b"}})
+	require.ErrorContains(t, err, `unknown tier "b" of the API key`)
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitConfig{})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	rl.now = func() time.Time { return now }
+	tier := RateLimitTier{Requests: RateLimit{Rate: 2, Burst: 3}, PartitionRequests: RateLimit{Rate: 0.5}}
+
+	// burst of requests
+	for range 3 {
+		ok, _ := rl.allow("a", tier, false)
+		require.True(t, ok)
+	}
+	ok, retryAfter := rl.allow("a", tier, false)
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// other clients have their own buckets
+	ok, _ = rl.allow("b", tier, false)
+	require.True(t, ok)
+
+	// bucket is refilled over time
+	now = now.Add(time.Second)
+	ok, _ = rl.allow("a", tier, true)
+	require.True(t, ok)
+	// partition requests are limited by both buckets
+	ok, retryAfter = rl.allow("a", tier, true)
+	require.False(t, ok)
+	require.Equal(t, 2*time.Second, retryAfter)
+	// the rejected request didn't use up the tokens of the requests bucket
+	ok, _ = rl.allow("a", tier, false)
+	require.True(t, ok)
+
+	// unlimited
+	for range 10 {
+		ok, _ = rl.allow("c", RateLimitTier{}, true)
+		require.True(t, ok)
+	}
+
+	// idle clients are removed
+	now = now.Add(bucketSweepInterval)
+	ok, _ = rl.allow("b", tier, false)
+	require.True(t, ok)
+	require.Len(t, rl.buckets, 1)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitConfig{
+		Anonymous: RateLimitTier{Requests: RateLimit{Rate: 0.1, Burst: 2}, PartitionRequests: RateLimit{Rate: 0.1}},
+		Tiers:     map[string]RateLimitTier{"partner": {Requests: RateLimit{Rate: 100}}},
+		APIKeys:   map[string]string{"secret": "partner"},
+	})
+	require.NoError(t, err)
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+	restapi := &Controller{StorageService: mockStorage, PartitionService: partitionServiceStub{}, RateLimiter: rl}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	get := func(path, apiKey string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		require.NoError(t, err)
+		if apiKey != "" {
+			req.Header.Set(HeaderAPIKey, apiKey)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	// partition endpoints have stricter limits
+	require.Equal(t, http.StatusOK, get("/api/v1/round-number", "").StatusCode)
+	res := get("/api/v1/round-number", "")
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	require.Equal(t, "10", res.Header.Get(HeaderRetryAfter))
+	require.ErrorContains(t, DecodeResponse(res, http.StatusOK, nil, false), "rate limit exceeded")
+
+	require.Equal(t, http.StatusNotFound, get("/api/v1/txs/0x01", "").StatusCode)
+	require.Equal(t, http.StatusTooManyRequests, get("/api/v1/txs/0x01", "").StatusCode)
+
+	// API key has its own limits
+	require.Equal(t, http.StatusOK, get("/api/v1/round-number", "secret").StatusCode)
+	require.Equal(t, http.StatusOK, get("/api/v1/round-number", "secret").StatusCode)
+	require.Equal(t, http.StatusUnauthorized, get("/api/v1/round-number", "unknown").StatusCode)
+
+	// health check is not limited
+	require.Equal(t, http.StatusOK, get("/health", "").StatusCode)
+}
+
+func TestRateLimitMiddleware_RPCBatch(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimitConfig{Anonymous: RateLimitTier{Requests: RateLimit{Rate: 0.1, Burst: 3}}})
+	require.NoError(t, err)
+	restapi := &Controller{PartitionService: partitionServiceStub{}, RateLimiter: rl}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	// every call of the batch takes a token, the calls exceeding the limits are not executed
+	res, err := http.Post(ts.URL+"/rpc", ApplicationJson, strings.NewReader(`[
+		{"jsonrpc":"2.0","id":1,"method":"explorer_getPartitions"},
+		{"jsonrpc":"2.0","id":2,"method":"explorer_getPartitions"},
+		{"jsonrpc":"2.0","id":3,"method":"explorer_getPartitions"},
+		{"jsonrpc":"2.0","id":4,"method":"explorer_getPartitions"}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var responses []struct {
+		ID     int
+		Result json.RawMessage
+		Error  *jsonrpc.Error
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&responses))
+	require.Len(t, responses, 4)
+	for _, r := range responses[:3] {
+		require.Nil(t, r.Error)
+		require.NotEmpty(t, r.Result)
+	}
+	require.Equal(t, rpcCodeRateLimited, responses[3].Error.Code)
+
+	// the batch used up the tokens of the client
+	res, err = http.Post(ts.URL+"/rpc", ApplicationJson, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"explorer_getPartitions"}`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+}
+
+func TestRateLimiter_ClientIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Add(HeaderForwardedFor, "1.1.1.1, 2.2.2.2")
+
+	rl := &RateLimiter{}
+	require.Equal(t, "10.0.0.1", rl.clientIP(r))
+	rl.trustProxy = true
+	require.Equal(t, "2.2.2.2", rl.clientIP(r))
+}
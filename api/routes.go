@@ -2,24 +2,28 @@ package api
 
 import (
 	"bytes"
+	"expvar"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
+	"github.com/alphabill-org/alphabill-explorer-backend/api/openapi"
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 
-	_ "github.com/alphabill-org/alphabill-explorer-backend/api/docs"
-
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
-// @title		Alphabill Blockchain Explorer API
-// @version		1.0
-// @description	API to query blocks and transactions of Alphabill
-// @BasePath	/api/v1
+// AdminRouter returns the router of the admin server publishing the runtime metrics, the admin server listens
+// on its own address which must not be exposed publicly.
+func AdminRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.Use(loggerMiddleware)
+	router.Path("/debug/vars").Handler(expvar.Handler()).Methods(http.MethodGet)
+	return router
+}
 
 func (c *Controller) Router() *mux.Router {
 	// TODO add request/response headers middleware
@@ -28,8 +32,9 @@ func (c *Controller) Router() *mux.Router {
 
 	router.Path("/health").HandlerFunc(c.healthRequest)
 
+	// Swagger UI of the OpenAPI document
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"), //The url pointing to API definition
+		httpSwagger.URL("/api/openapi.json"),
 		httpSwagger.DeepLinking(true),
 		httpSwagger.DocExpansion("list"),
 		httpSwagger.DomID("swagger-ui"),
@@ -40,29 +45,68 @@ func (c *Controller) Router() *mux.Router {
 	// content-type needs to be explicitly defined without this content-type header is not allowed and cors filter is not applied
 	// Link header is needed for pagination support.
 	// OPTIONS method needs to be explicitly defined for each handler func
-	apiRouter.Use(handlers.CORS(
-		handlers.AllowedHeaders([]string{ContentType}),
-		handlers.ExposedHeaders([]string{HeaderLink}),
-	))
+	cors := handlers.CORS(
+		handlers.AllowedMethods([]string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodDelete}),
+		handlers.AllowedHeaders([]string{ContentType, HeaderAPIKey, HeaderIfNoneMatch}),
+		handlers.ExposedHeaders([]string{HeaderLink, HeaderRetryAfter, HeaderETag, "Content-Disposition"}),
+	)
+	apiRouter.Use(cors)
+	apiRouter.Use(c.rateLimitMiddleware)
+
+	// JSON-RPC 2.0 API mirroring the REST API
+	router.Handle("/rpc", cors(c.rateLimitMiddleware(http.HandlerFunc(c.rpc)))).Methods(http.MethodPost, http.MethodOptions)
+
+	apiRouter.HandleFunc("/openapi.json", c.openAPIDocument).Methods(http.MethodGet, http.MethodOptions)
+	apiRouter.HandleFunc("/graphql", c.graphQL).Methods(http.MethodGet, http.MethodPost, http.MethodOptions)
+	apiRouter.HandleFunc("/graphql/schema", c.graphQLSchemaSDL).Methods(http.MethodGet, http.MethodOptions)
+
+	// exports are streamed as CSV or JSON lines so they are registered before the content negotiated v1 routes
+	apiRouter.HandleFunc("/v1/units/{unitID}/txs/export", c.exportUnitTxs).Methods(http.MethodGet, http.MethodOptions)
+	apiRouter.HandleFunc("/v1/address/{pubKey}/txs/export", c.exportAddressTxs).Methods(http.MethodGet, http.MethodOptions)
 
 	// version v1 router
 	apiV1 := apiRouter.PathPrefix("/v1").Subrouter()
+	apiV1.Use(contentNegotiationMiddleware)
 
 	apiV1.HandleFunc("/search", c.search).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/search/suggest", c.suggest).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/round-number", c.roundNumber).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions", c.getPartitions).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions/{partitionID}", c.getPartition).Methods(http.MethodGet, http.MethodOptions)
 
 	//block
+	apiV1.HandleFunc("/blocks/hash/{blockHash}", c.getBlockByHash).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/blocks/{blockNumber}", c.getBlock).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/partitions/{partitionID}/blocks", c.getBlocksInRange).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions/{partitionID}/blocks/{blockNumber}/raw", c.getRawBlock).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions/{partitionID}/validators", c.getValidators).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions/{partitionID}/validators/{nodeID}", c.getValidator).Methods(http.MethodGet, http.MethodOptions)
 
 	//tx
 	apiV1.HandleFunc("/txs/{txHash}", c.getTx).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/partitions/{partitionID}/txs", c.getTxs).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/partitions/{partitionID}/txs", c.submitTx).Methods(http.MethodPost)
+	apiV1.HandleFunc("/txs/{txOrderHash}/status", c.getTxStatus).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/partitions/{partitionID}/blocks/{blockNumber}/txs", c.getBlockTxsByBlockNumber).Methods(http.MethodGet, http.MethodOptions)
 	apiV1.HandleFunc("/units/{unitID}/txs", c.getTxsByUnitID).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/units/{unitID}", c.getUnit).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/units/{unitID}/lifecycle", c.getUnitLifecycle).Methods(http.MethodGet, http.MethodOptions)
 
 	//bill
 	apiV1.HandleFunc("/address/{pubKey}/bills", c.getBillsByPubKey).Methods(http.MethodGet, http.MethodOptions)
+
+	//money
+	apiV1.HandleFunc("/money/holders", c.getTopHolders).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/money/distribution", c.getBalanceDistribution).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/money/supply", c.getMoneySupply).Methods(http.MethodGet, http.MethodOptions)
+
+	//webhook
+	apiV1.HandleFunc("/webhooks", c.createWebhook).Methods(http.MethodPost, http.MethodOptions)
+	apiV1.HandleFunc("/webhooks", c.getWebhooks).Methods(http.MethodGet)
+	apiV1.HandleFunc("/webhooks/{webhookID}", c.getWebhook).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/webhooks/{webhookID}", c.deleteWebhook).Methods(http.MethodDelete)
+	apiV1.HandleFunc("/webhooks/{webhookID}/deliveries", c.getWebhookDeliveries).Methods(http.MethodGet, http.MethodOptions)
+	apiV1.HandleFunc("/webhooks/{webhookID}/replay", c.replayWebhookDeliveries).Methods(http.MethodPost, http.MethodOptions)
 	return router
 }
 
@@ -71,6 +115,11 @@ func (c *Controller) healthRequest(w http.ResponseWriter, _ *http.Request) {
 	w.Write([]byte(fmt.Sprintf("OK - %v", time.Now())))
 }
 
+// openAPIDocument returns the OpenAPI 3.1 document of the API, the tests validate the responses of the handlers against it.
+func (c *Controller) openAPIDocument(w http.ResponseWriter, _ *http.Request) {
+	c.rw.WriteRawResponse(w, ApplicationJson, openapi.JSON())
+}
+
 func loggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		buf, err := io.ReadAll(r.Body)
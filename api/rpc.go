@@ -0,0 +1,586 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/jsonrpc"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/search"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// application error codes of the JSON-RPC API, the REST API responds with 404 and 410 to these errors
+	rpcCodeNotFound = -32001
+	rpcCodePruned   = -32002
+	// the call of the batch exceeded the rate limits of the client, the REST API responds with 429
+	rpcCodeRateLimited = -32005
+
+	maxRPCBatchSize = 100
+)
+
+// RPCPage is a page of the list returned by the JSON-RPC API, the cursors are passed to the same
+// method to get the next (older) and previous (newer) pages.
+type RPCPage[T any] struct {
+	Items      []T
+	NextCursor string `json:",omitempty"`
+	PrevCursor string `json:",omitempty"`
+}
+
+/*
+rpc serves the JSON-RPC 2.0 API which mirrors the REST API, results are encoded as the JSON responses of
+the corresponding REST endpoints and the paged lists are returned as RPCPage. Methods:
+
+	explorer_getRoundNumbers()
+	explorer_getPartitions()
+	explorer_getPartition(partitionID)
+	explorer_getBlock(blockNumber|"latest", [partitionIDs])
+	explorer_getBlockByHash(blockHash)
+	explorer_getBlocks(partitionID, [cursor], [limit], [includeEmpty])
+	explorer_getTx(txHash)
+	explorer_getTxs(partitionID, [cursor], [limit])
+	explorer_getBlockTxs(partitionID, blockNumber, [cursor], [limit])
+	explorer_getTxsByUnit(unitID, [cursor], [limit])
+	explorer_getUnit(unitID, [atBlock], [partitionID], [includeStateProof])
+	explorer_getUnitLifecycle(unitID)
+	explorer_getTxStatus(txOrderHash)
+	explorer_getBillsByPubKey(pubKey, [cursor], [limit])
+	explorer_getTopHolders([limit])
+	explorer_getBalanceDistribution()
+	explorer_getMoneySupply()
+	explorer_getValidators(partitionID, [window])
+	explorer_getValidator(partitionID, nodeID, [window])
+	explorer_search(searchKey, [partitionIDs], [hitTypes])
+	explorer_suggest(prefix, [partitionIDs], [limit])
+*/
+func (c *Controller) rpc(w http.ResponseWriter, r *http.Request) {
+	c.rpcOnce.Do(func() {
+		c.rpcServer = jsonrpc.NewServer(maxRPCBatchSize)
+		c.rpcServer.SetLimiter(rpcRateLimit)
+		c.rpcServer.Register("explorer_getRoundNumbers", c.rpcGetRoundNumbers)
+		c.rpcServer.Register("explorer_getPartitions", c.rpcGetPartitions)
+		c.rpcServer.Register("explorer_getPartition", c.rpcGetPartition)
+		c.rpcServer.Register("explorer_getBlock", c.rpcGetBlock)
+		c.rpcServer.Register("explorer_getBlockByHash", c.rpcGetBlockByHash)
+		c.rpcServer.Register("explorer_getBlocks", c.rpcGetBlocks)
+		c.rpcServer.Register("explorer_getTx", c.rpcGetTx)
+		c.rpcServer.Register("explorer_getTxs", c.rpcGetTxs)
+		c.rpcServer.Register("explorer_getBlockTxs", c.rpcGetBlockTxs)
+		c.rpcServer.Register("explorer_getTxsByUnit", c.rpcGetTxsByUnit)
+		c.rpcServer.Register("explorer_getUnit", c.rpcGetUnit)
+		c.rpcServer.Register("explorer_getUnitLifecycle", c.rpcGetUnitLifecycle)
+		c.rpcServer.Register("explorer_getTxStatus", c.rpcGetTxStatus)
+		c.rpcServer.Register("explorer_getBillsByPubKey", c.rpcGetBillsByPubKey)
+		c.rpcServer.Register("explorer_getTopHolders", c.rpcGetTopHolders)
+		c.rpcServer.Register("explorer_getBalanceDistribution", c.rpcGetBalanceDistribution)
+		c.rpcServer.Register("explorer_getMoneySupply", c.rpcGetMoneySupply)
+		c.rpcServer.Register("explorer_getValidators", c.rpcGetValidators)
+		c.rpcServer.Register("explorer_getValidator", c.rpcGetValidator)
+		c.rpcServer.Register("explorer_search", c.rpcSearch)
+		c.rpcServer.Register("explorer_suggest", c.rpcSuggest)
+	})
+	c.rpcServer.ServeHTTP(w, r)
+}
+
+func (c *Controller) rpcGetRoundNumbers(ctx context.Context, params jsonrpc.Params) (any, error) {
+	if err := params.Bind(0); err != nil {
+		return nil, err
+	}
+	return c.PartitionService.GetRoundNumber(ctx)
+}
+
+func (c *Controller) rpcGetPartitions(ctx context.Context, params jsonrpc.Params) (any, error) {
+	if err := params.Bind(0); err != nil {
+		return nil, err
+	}
+	partitions, err := c.PartitionService.GetPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partitions: %w", err)
+	}
+	return partitions, nil
+}
+
+func (c *Controller) rpcGetPartition(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	if err := params.Bind(1, &partitionID); err != nil {
+		return nil, err
+	}
+	info, err := c.PartitionService.GetPartition(ctx, partitionID)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load partition %d: %w", partitionID, err))
+	}
+	return info, nil
+}
+
+func (c *Controller) rpcGetBlock(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var blockNumberParam json.RawMessage
+	var partitionIDs []types.PartitionID
+	if err := params.Bind(1, &blockNumberParam, &partitionIDs); err != nil {
+		return nil, err
+	}
+
+	result := make(BlockResponse)
+	var latest string
+	if json.Unmarshal(blockNumberParam, &latest) == nil {
+		if latest != blockNumberLatest {
+			return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid block number %q", latest))
+		}
+		blockMap, err := c.StorageService.GetLastBlocks(ctx, partitionIDs, 1, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest blocks: %w", err)
+		}
+		for partitionID, blocks := range blockMap {
+			if len(blocks) > 0 {
+				result[partitionID] = blockInfoResponse(blocks[0])
+			}
+		}
+		return result, nil
+	}
+
+	var blockNumber uint64
+	if err := json.Unmarshal(blockNumberParam, &blockNumber); err != nil {
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid block number: %w", err))
+	}
+	blockMap, err := c.StorageService.GetBlock(ctx, blockNumber, partitionIDs)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load block with block number %d: %w", blockNumber, err))
+	}
+	if len(blockMap) == 0 {
+		return nil, rpcNotFound(fmt.Errorf("block with block number %d not found", blockNumber))
+	}
+	for partitionID, block := range blockMap {
+		result[partitionID] = blockInfoResponse(block)
+	}
+	return result, nil
+}
+
+func (c *Controller) rpcGetBlockByHash(ctx context.Context, params jsonrpc.Params) (any, error) {
+	blockHash, err := rpcHexParam(params, paramBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := c.StorageService.GetBlockByHash(ctx, blockHash)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load block with block hash %X: %w", blockHash, err))
+	}
+	return blockInfoResponse(block), nil
+}
+
+func (c *Controller) rpcGetBlocks(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	var cursorStr string
+	var limit *int
+	includeEmpty := true
+	if err := params.Bind(1, &partitionID, &cursorStr, &limit, &includeEmpty); err != nil {
+		return nil, err
+	}
+	cursor, pageLimit, err := rpcPageParams(listBlocks, cursorStr, 8, limit, defaultBlocksPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, hasMore, err := c.StorageService.GetBlocksPage(ctx, partitionID, pageRequest(cursor, pageLimit, decodeUint64), includeEmpty)
+	if err != nil {
+		return nil, err
+	}
+	return rpcPage(listBlocks, cursor, blocks, hasMore, blockInfoResponse, func(b *domain.BlockInfo) []byte { return encodeUint64(b.BlockNumber) }), nil
+}
+
+func (c *Controller) rpcGetTx(ctx context.Context, params jsonrpc.Params) (any, error) {
+	txHash, err := rpcHexParam(params, paramTxHash)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := c.StorageService.GetTxByHash(ctx, txHash)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load tx with txHash %X: %w", txHash, err))
+	}
+	return txInfoResponse(txInfo), nil
+}
+
+func (c *Controller) rpcGetTxs(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	var cursorStr string
+	var limit *int
+	if err := params.Bind(1, &partitionID, &cursorStr, &limit); err != nil {
+		return nil, err
+	}
+	cursor, pageLimit, err := rpcPageParams(listTxs, cursorStr, len(primitive.ObjectID{}), limit, defaultTxsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, hasMore, err := c.StorageService.GetTxsPage(ctx, partitionID, pageRequest(cursor, pageLimit, decodeObjectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load txs of partition %d: %w", partitionID, err)
+	}
+	return rpcPage(listTxs, cursor, txs, hasMore, txInfoResponse, txObjectIDKey), nil
+}
+
+func (c *Controller) rpcGetBlockTxs(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	var blockNumber uint64
+	var cursorStr string
+	var limit *int
+	if err := params.Bind(2, &partitionID, &blockNumber, &cursorStr, &limit); err != nil {
+		return nil, err
+	}
+	cursor, pageLimit, err := rpcPageParams(listBlockTxs, cursorStr, 0, limit, defaultTxsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := pageRequest(cursor, pageLimit, func(key []byte) domain.TxHash { return key })
+	txs, hasMore, err := c.StorageService.GetTxsPageByBlockNumber(ctx, blockNumber, partitionID, page)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load txs with blockNumber %d: %w", blockNumber, err))
+	}
+	return rpcPage(listBlockTxs, cursor, txs, hasMore, txInfoResponse, func(tx *domain.TxInfo) []byte { return tx.TxRecordHash }), nil
+}
+
+func (c *Controller) rpcGetTxsByUnit(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var unitIDStr, cursorStr string
+	var limit *int
+	if err := params.Bind(1, &unitIDStr, &cursorStr, &limit); err != nil {
+		return nil, err
+	}
+	unitID, err := util.FromHex([]byte(unitIDStr))
+	if err != nil {
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid %s: %w", paramUnitID, err))
+	}
+	cursor, pageLimit, err := rpcPageParams(listUnitTxs, cursorStr, len(primitive.ObjectID{}), limit, defaultTxsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, hasMore, err := c.StorageService.GetTxsPageByUnitID(ctx, unitID, pageRequest(cursor, pageLimit, decodeObjectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load txs with unitID %s: %w", unitIDStr, err)
+	}
+	return rpcPage(listUnitTxs, cursor, txs, hasMore, txInfoResponse, txObjectIDKey), nil
+}
+
+func (c *Controller) rpcGetUnit(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var unitIDStr string
+	var atBlock *uint64
+	var partitionID *types.PartitionID
+	var includeStateProof bool
+	if err := params.Bind(1, &unitIDStr, &atBlock, &partitionID, &includeStateProof); err != nil {
+		return nil, err
+	}
+	unitID, err := util.DecodeHex(unitIDStr)
+	if err != nil || len(unitID) == 0 {
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid %s", paramUnitID))
+	}
+
+	var state *unitstate.UnitState
+	if atBlock != nil {
+		if *atBlock == 0 {
+			return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid %s", paramAtBlock))
+		}
+		if includeStateProof {
+			return nil, jsonrpc.InvalidParams(fmt.Errorf("%s can't be used with %s", paramStateProof, paramAtBlock))
+		}
+		state, err = c.UnitStateService.GetUnitStateAt(ctx, unitID, partitionID, *atBlock)
+	} else {
+		state, err = c.UnitStateService.GetUnitState(ctx, unitID, partitionID, includeStateProof)
+	}
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load state of unit %s: %w", unitIDStr, err))
+	}
+	return state, nil
+}
+
+func (c *Controller) rpcGetUnitLifecycle(ctx context.Context, params jsonrpc.Params) (any, error) {
+	unitID, err := rpcHexParam(params, paramUnitID)
+	if err != nil {
+		return nil, err
+	}
+	lc, err := c.LifecycleService.GetLifecycle(ctx, unitID)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load lifecycle of unit %X: %w", unitID, err))
+	}
+	return lc, nil
+}
+
+func (c *Controller) rpcGetTxStatus(ctx context.Context, params jsonrpc.Params) (any, error) {
+	txOrderHash, err := rpcHexParam(params, paramTxOrderHash)
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.TxSubmitService.GetTxStatus(ctx, txOrderHash)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load status of tx %X: %w", txOrderHash, err))
+	}
+	return status, nil
+}
+
+func (c *Controller) rpcGetBillsByPubKey(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var pubKey, cursorStr string
+	var limit *int
+	if err := params.Bind(1, &pubKey, &cursorStr, &limit); err != nil {
+		return nil, err
+	}
+	address, err := predicate.ParseAddress(pubKey)
+	if err != nil {
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid %s: %w", paramPubKey, err))
+	}
+	cursor, pageLimit, err := rpcPageParams(listBills, cursorStr, 0, limit, defaultBillsPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	bills, err := c.MoneyService.GetBillsByPubKeyHash(ctx, address.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bills with pubKey %s: %w", pubKey, err)
+	}
+	bills, hasMore := billsPage(bills, pageRequest(cursor, pageLimit, func(key []byte) types.UnitID { return key }))
+	owner := address.OwnerPredicate()
+	toBill := func(b *sdktypes.Bill) domain.Bill { return billResponse(b, owner) }
+	return rpcPage(listBills, cursor, bills, hasMore, toBill, func(b *sdktypes.Bill) []byte { return b.ID }), nil
+}
+
+func (c *Controller) rpcGetTopHolders(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var limit *int
+	if err := params.Bind(0, &limit); err != nil {
+		return nil, err
+	}
+	if limit == nil {
+		limit = new(int)
+		*limit = defaultHoldersLimit
+	}
+	if err := rpcLimit(*limit, maxPageLimit); err != nil {
+		return nil, err
+	}
+	holders, err := c.MoneyService.GetTopHolders(ctx, *limit)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load top holders: %w", err))
+	}
+	return holders, nil
+}
+
+func (c *Controller) rpcGetBalanceDistribution(ctx context.Context, params jsonrpc.Params) (any, error) {
+	if err := params.Bind(0); err != nil {
+		return nil, err
+	}
+	distribution, err := c.MoneyService.GetBalanceDistribution(ctx)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load balance distribution: %w", err))
+	}
+	return distribution, nil
+}
+
+func (c *Controller) rpcGetMoneySupply(ctx context.Context, params jsonrpc.Params) (any, error) {
+	if err := params.Bind(0); err != nil {
+		return nil, err
+	}
+	supply, err := c.MoneyService.GetMoneySupply(ctx)
+	if err != nil {
+		return nil, rpcError(err, fmt.Errorf("failed to load money supply: %w", err))
+	}
+	return supply, nil
+}
+
+func (c *Controller) rpcGetValidators(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	var windowStr string
+	if err := params.Bind(1, &partitionID, &windowStr); err != nil {
+		return nil, err
+	}
+	window, err := rpcWindowParam(windowStr)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := c.ValidatorService.GetValidators(ctx, partitionID, window)
+	if err != nil {
+		return nil, rpcValidatorError(err, fmt.Errorf("failed to load validators of partition %d: %w", partitionID, err))
+	}
+	return validators, nil
+}
+
+func (c *Controller) rpcGetValidator(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var partitionID types.PartitionID
+	var nodeID, windowStr string
+	if err := params.Bind(2, &partitionID, &nodeID, &windowStr); err != nil {
+		return nil, err
+	}
+	window, err := rpcWindowParam(windowStr)
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.ValidatorService.GetValidator(ctx, partitionID, nodeID, window)
+	if err != nil {
+		return nil, rpcValidatorError(err, fmt.Errorf("failed to load validator %s: %w", nodeID, err))
+	}
+	return v, nil
+}
+
+func (c *Controller) rpcSearch(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var searchKey string
+	var partitionIDs []types.PartitionID
+	var typeNames []string
+	if err := params.Bind(1, &searchKey, &partitionIDs, &typeNames); err != nil {
+		return nil, err
+	}
+	if searchKey == "" {
+		return nil, jsonrpc.InvalidParams(errors.New("empty search key"))
+	}
+	var hitTypes []search.HitType
+	for _, name := range typeNames {
+		hitType, err := search.ParseHitType(name)
+		if err != nil {
+			return nil, jsonrpc.InvalidParams(err)
+		}
+		hitTypes = append(hitTypes, hitType)
+	}
+
+	result, err := c.SearchService.Search(ctx, searchKey, partitionIDs, hitTypes)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrFailedToDecodeHex) {
+			return nil, rpcNotFound(fmt.Errorf("no results found for '%s'", searchKey))
+		}
+		return nil, err
+	}
+	if len(result.Hits) == 0 && len(result.Failures) == 0 {
+		return nil, rpcNotFound(fmt.Errorf("no results found for '%s'", searchKey))
+	}
+	return formatSearchResponse(result), nil
+}
+
+func (c *Controller) rpcSuggest(ctx context.Context, params jsonrpc.Params) (any, error) {
+	var prefix string
+	var partitionIDs []types.PartitionID
+	var limit *int
+	if err := params.Bind(1, &prefix, &partitionIDs, &limit); err != nil {
+		return nil, err
+	}
+	if limit == nil {
+		limit = new(int)
+		*limit = defaultSuggestLimit
+	}
+	if err := rpcLimit(*limit, maxSuggestLimit); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := c.SearchService.Suggest(ctx, prefix, partitionIDs, *limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrFailedToDecodeHex) || errors.Is(err, search.ErrPrefixTooShort) {
+			return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid prefix: %w", err))
+		}
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []*search.Suggestion{}
+	}
+	return suggestions, nil
+}
+
+// rpcHexParam decodes the single required hex encoded param.
+func rpcHexParam(params jsonrpc.Params, name string) ([]byte, error) {
+	var s string
+	if err := params.Bind(1, &s); err != nil {
+		return nil, err
+	}
+	b, err := util.DecodeHex(s)
+	if err != nil {
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("invalid %s: %w", name, err))
+	}
+	return b, nil
+}
+
+// rpcPageParams validates the page params like parseTxsPageParams does the query parameters.
+// rpcRateLimit takes a token for the call of the batch from the limits of the client, every call of the batch
+// counts as a request.
+func rpcRateLimit(ctx context.Context) error {
+	if limit, ok := ctx.Value(rateLimitKey{}).(rateLimitFunc); ok && !limit() {
+		return &jsonrpc.Error{Code: rpcCodeRateLimited, Message: "rate limit exceeded"}
+	}
+	return nil
+}
+
+func rpcPageParams(list, cursorStr string, keyLen int, limit *int, defaultLimit int) (*pageCursor, int, error) {
+	cursor, err := parseCursor(cursorStr, list, keyLen)
+	if err != nil {
+		return nil, 0, jsonrpc.InvalidParams(fmt.Errorf("invalid %s: %w", QueryParamCursor, err))
+	}
+	if limit == nil {
+		return cursor, defaultLimit, nil
+	}
+	if err = rpcLimit(*limit, maxPageLimit); err != nil {
+		return nil, 0, err
+	}
+	return cursor, *limit, nil
+}
+
+// rpcLimit checks the limit param, the limits out of range are rejected rather than adjusted so that the
+// client doesn't mistake a shorter page for the end of the list.
+func rpcLimit(limit, maxLimit int) error {
+	if limit <= 0 || limit > maxLimit {
+		return jsonrpc.InvalidParams(fmt.Errorf("invalid %s: value must be between 1 and %d, got %d", paramLimit, maxLimit, limit))
+	}
+	return nil
+}
+
+func rpcPage[S, T any](list string, cursor *pageCursor, items []S, hasMore bool, response func(S) T, key func(S) []byte) *RPCPage[T] {
+	page := &RPCPage[T]{Items: make([]T, 0, len(items))}
+	for _, item := range items {
+		page.Items = append(page.Items, response(item))
+	}
+	var firstKey, lastKey []byte
+	if len(items) > 0 {
+		firstKey, lastKey = key(items[0]), key(items[len(items)-1])
+	}
+	next, prev := pageCursors(list, cursor, firstKey, lastKey, hasMore)
+	if next != nil {
+		page.NextCursor = next.String()
+	}
+	if prev != nil {
+		page.PrevCursor = prev.String()
+	}
+	return page
+}
+
+// rpcError returns the application error of the not found and pruned errors, "internal" otherwise.
+// rpcWindowParam parses the optional window duration, zero means the default window.
+func rpcWindowParam(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(s)
+	if err != nil || window <= 0 {
+		return 0, jsonrpc.InvalidParams(fmt.Errorf("invalid %s %q", paramWindow, s))
+	}
+	return window, nil
+}
+
+func rpcValidatorError(err, internal error) error {
+	if errors.Is(err, validator.ErrInvalidWindow) {
+		return jsonrpc.InvalidParams(err)
+	}
+	return rpcError(err, internal)
+}
+
+func rpcError(err, internal error) error {
+	switch {
+	case errors.Is(err, domain.ErrPruned):
+		return &jsonrpc.Error{Code: rpcCodePruned, Message: err.Error()}
+	case errors.Is(err, domain.ErrNotFound):
+		return rpcNotFound(err)
+	}
+	return internal
+}
+
+func rpcNotFound(err error) *jsonrpc.Error {
+	return &jsonrpc.Error{Code: rpcCodeNotFound, Message: err.Error()}
+}
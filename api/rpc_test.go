@@ -0,0 +1,266 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/api/jsonrpc"
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/lifecycle"
+	moneyservice "github.com/alphabill-org/alphabill-explorer-backend/service/money"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRPC(t *testing.T) {
+	storage := mocks.NewStorageService(t)
+	restapi := &Controller{StorageService: storage, PartitionService: partitionServiceStub{}}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	call := func(body string, result any) {
+		res, err := http.Post(ts.URL+"/rpc", ApplicationJson, strings.NewReader(body))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(result))
+	}
+
+	t.Run("getTx", func(t *testing.T) {
+		txHash := domain.TxHash{1, 2}
+		storage.EXPECT().GetTxByHash(mock.Anything, txHash).Return(&domain.TxInfo{TxRecordHash: txHash}, nil).Once()
+		storage.EXPECT().GetTxByHash(mock.Anything, domain.TxHash{3}).Return(nil, domain.ErrNotFound).Once()
+
+		var responses []struct {
+			ID     int
+			Result *TxInfo
+			Error  *jsonrpc.Error
+		}
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getTx","params":["0x0102"]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getTx","params":["0x03"]},
+			{"jsonrpc":"2.0","id":3,"method":"explorer_getTx","params":["xyz"]}
+		]`, &responses)
+		require.Len(t, responses, 3)
+		require.Equal(t, &TxInfo{TxRecordHash: txHash}, responses[0].Result)
+		require.Equal(t, rpcCodeNotFound, responses[1].Error.Code)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[2].Error.Code)
+	})
+
+	t.Run("getBlockByHash", func(t *testing.T) {
+		blockHash := []byte{0xab, 0xcd}
+		storage.EXPECT().GetBlockByHash(mock.Anything, blockHash).Return(&domain.BlockInfo{BlockHash: blockHash, BlockNumber: 5}, nil).Once()
+		storage.EXPECT().GetBlockByHash(mock.Anything, []byte{1}).Return(nil, domain.ErrNotFound).Once()
+
+		var responses []struct {
+			ID     int
+			Result *BlockInfo
+			Error  *jsonrpc.Error
+		}
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getBlockByHash","params":["0xabcd"]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getBlockByHash","params":["0x01"]}
+		]`, &responses)
+		require.Len(t, responses, 2)
+		require.Equal(t, &BlockInfo{BlockHash: blockHash, BlockNumber: 5}, responses[0].Result)
+		require.Equal(t, rpcCodeNotFound, responses[1].Error.Code)
+	})
+
+	t.Run("getUnit", func(t *testing.T) {
+		restapi.UnitStateService = &unitStateServiceStub{state: &unitstate.UnitState{UnitID: types.UnitID{1}, Source: unitstate.SourceHistory, BlockNumber: 5}}
+
+		var responses []struct {
+			ID     int
+			Result *unitstate.UnitState
+			Error  *jsonrpc.Error
+		}
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getUnit","params":["0x01",5]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getUnit","params":["0x01",5,null,true]},
+			{"jsonrpc":"2.0","id":3,"method":"explorer_getUnit","params":["xyz"]}
+		]`, &responses)
+		require.Len(t, responses, 3)
+		require.EqualValues(t, 5, responses[0].Result.BlockNumber)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[1].Error.Code)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[2].Error.Code)
+	})
+
+	t.Run("getUnitLifecycle", func(t *testing.T) {
+		lifecycles := &lifecycleServiceStub{lifecycle: &lifecycle.Lifecycle{UnitID: types.UnitID{1}, State: lifecycle.StateLocked}}
+		restapi.LifecycleService = lifecycles
+
+		var response struct {
+			Result *lifecycle.Lifecycle
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getUnitLifecycle","params":["0x01"]}`, &response)
+		require.Equal(t, lifecycles.lifecycle, response.Result)
+
+		lifecycles.lifecycle, lifecycles.err = nil, domain.ErrNotFound
+		response.Result = nil
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getUnitLifecycle","params":["0x01"]}`, &response)
+		require.Equal(t, rpcCodeNotFound, response.Error.Code)
+	})
+
+	t.Run("getTopHolders", func(t *testing.T) {
+		money := &moneyServiceStub{holders: &moneyservice.TopHolders{HolderCount: 1, Holders: []*moneyservice.Holder{{Rank: 1, OwnerID: []byte{1}, Balance: 10}}}}
+		restapi.MoneyService = money
+
+		var responses []struct {
+			Result *moneyservice.TopHolders
+			Error  *jsonrpc.Error
+		}
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getTopHolders"},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getTopHolders","params":[0]}
+		]`, &responses)
+		require.Len(t, responses, 2)
+		require.Equal(t, money.holders, responses[0].Result)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[1].Error.Code)
+	})
+
+	t.Run("getMoneySupply", func(t *testing.T) {
+		money := &moneyServiceStub{supply: &domain.MoneySupply{BlockNumber: 5, TotalSupply: 100}}
+		restapi.MoneyService = money
+
+		var response struct {
+			Result *domain.MoneySupply
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getMoneySupply"}`, &response)
+		require.Equal(t, money.supply, response.Result)
+
+		money.supply, money.err = nil, domain.ErrNotFound
+		response.Result = nil
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getBalanceDistribution"}`, &response)
+		require.Equal(t, rpcCodeNotFound, response.Error.Code)
+	})
+
+	t.Run("getValidators", func(t *testing.T) {
+		validators := &validatorServiceStub{validators: &validator.Validators{PartitionID: 1, Window: &validator.Window{Duration: "1h0m0s"}, Validators: []*validator.Stats{{NodeID: "node1"}}}}
+		restapi.ValidatorService = validators
+
+		var responses []struct {
+			Result *validator.Validators
+			Error  *jsonrpc.Error
+		}
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getValidators","params":[1,"1h"]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getValidators","params":[1,"x"]}
+		]`, &responses)
+		require.Len(t, responses, 2)
+		require.Equal(t, validators.validators, responses[0].Result)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[1].Error.Code)
+
+		validators.err = validator.ErrInvalidWindow
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getValidators","params":[1,"2h"]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getValidator","params":[1]}
+		]`, &responses)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[0].Error.Code)
+		require.Equal(t, jsonrpc.CodeInvalidParams, responses[1].Error.Code)
+	})
+
+	t.Run("getValidator", func(t *testing.T) {
+		validators := &validatorServiceStub{validator: &validator.Validator{PartitionID: 1, Validator: &validator.Stats{NodeID: "node1", BlocksProposed: 2}}}
+		restapi.ValidatorService = validators
+
+		var response struct {
+			Result *validator.Validator
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getValidator","params":[1,"node1"]}`, &response)
+		require.Equal(t, validators.validator, response.Result)
+
+		validators.validator, validators.err = nil, domain.ErrNotFound
+		response.Result = nil
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getValidator","params":[1,"node2","24h"]}`, &response)
+		require.Equal(t, rpcCodeNotFound, response.Error.Code)
+	})
+
+	t.Run("getTxsByUnit", func(t *testing.T) {
+		id1, id2 := primitive.NewObjectID(), primitive.NewObjectID()
+		storage.EXPECT().GetTxsPageByUnitID(mock.Anything, types.UnitID{1}, domain.PageRequest[primitive.ObjectID]{Limit: 2}).
+			Return([]*domain.TxInfo{{ID: id2, TxRecordHash: []byte{2}}, {ID: id1, TxRecordHash: []byte{1}}}, true, nil).Once()
+
+		var response struct {
+			Result RPCPage[*TxInfo]
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getTxsByUnit","params":["0x01",null,2]}`, &response)
+		require.Len(t, response.Result.Items, 2)
+		require.NotEmpty(t, response.Result.NextCursor)
+		require.Empty(t, response.Result.PrevCursor)
+
+		// cursor of the unit transactions can't be used with other transaction lists
+		_, err := parseCursor(response.Result.NextCursor, listTxs, len(primitive.ObjectID{}))
+		require.Error(t, err)
+		cursor, err := parseCursor(response.Result.NextCursor, listUnitTxs, len(primitive.ObjectID{}))
+		require.NoError(t, err)
+		storage.EXPECT().GetTxsPageByUnitID(mock.Anything, types.UnitID{1}, domain.PageRequest[primitive.ObjectID]{Key: &id1, Limit: 2}).
+			Return([]*domain.TxInfo{}, false, nil).Once()
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getTxsByUnit","params":["0x01","`+cursor.String()+`",2]}`, &response)
+		require.NotNil(t, response.Result.Items)
+		require.Empty(t, response.Result.Items)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		var responses []struct {
+			ID    int
+			Error *jsonrpc.Error
+		}
+		// limits out of range are rejected, not adjusted
+		call(`[
+			{"jsonrpc":"2.0","id":1,"method":"explorer_getTxsByUnit","params":["0x01",null,101]},
+			{"jsonrpc":"2.0","id":2,"method":"explorer_getTxsByUnit","params":["0x01",null,0]},
+			{"jsonrpc":"2.0","id":3,"method":"explorer_getTopHolders","params":[1000]}
+		]`, &responses)
+		require.Len(t, responses, 3)
+		for _, res := range responses {
+			require.Equal(t, jsonrpc.CodeInvalidParams, res.Error.Code)
+		}
+		require.Equal(t, "invalid limit: value must be between 1 and 100, got 101", responses[0].Error.Message)
+	})
+
+	t.Run("getRoundNumbers", func(t *testing.T) {
+		var response struct {
+			Result []any
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getRoundNumbers","params":[]}`, &response)
+		require.Nil(t, response.Error)
+		require.NotNil(t, response.Result)
+	})
+
+	t.Run("getPartitions", func(t *testing.T) {
+		var response struct {
+			Result []*domain.PartitionInfo
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getPartitions"}`, &response)
+		require.Nil(t, response.Error)
+		require.Len(t, response.Result, 1)
+		require.Equal(t, "money", response.Result[0].TypeName)
+	})
+
+	t.Run("getPartition", func(t *testing.T) {
+		var response struct {
+			Result *domain.PartitionInfo
+			Error  *jsonrpc.Error
+		}
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getPartition","params":[1]}`, &response)
+		require.Nil(t, response.Error)
+		require.EqualValues(t, 2, response.Result.SyncLag)
+
+		response.Result = nil
+		call(`{"jsonrpc":"2.0","id":1,"method":"explorer_getPartition","params":[2]}`, &response)
+		require.NotNil(t, response.Error)
+		require.Equal(t, rpcCodeNotFound, response.Error.Code)
+	})
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"container/list"
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+// cacheMetrics publishes the hit and miss counters of the storage caches (GET /debug/vars of the admin server).
+var cacheMetrics = expvar.NewMap("storage_cache")
+
+type (
+	/*
+		CachedStorageService caches the finalized blocks and transactions loaded from the StorageService
+		in the LRU caches. Lists are not cached as new items are added to them with every block.
+	*/
+	CachedStorageService struct {
+		StorageService
+		txs    *lruCache[string, *domain.TxInfo]
+		blocks *lruCache[blockCacheKey, *domain.BlockInfo]
+	}
+
+	blockCacheKey struct {
+		partitionID types.PartitionID
+		blockNumber uint64
+	}
+
+	// lruCache is a size limited cache which evicts the least recently used entries, entries expire after ttl
+	// so that the rolled back or pruned data is not served forever.
+	lruCache[K comparable, V any] struct {
+		mu      sync.Mutex
+		size    int
+		ttl     time.Duration
+		items   map[K]*list.Element
+		order   *list.List // front is the most recently used
+		metrics string
+		now     func() time.Time
+	}
+
+	lruEntry[K comparable, V any] struct {
+		key     K
+		value   V
+		expires time.Time
+	}
+)
+
+// NewCachedStorageService creates the caches of "size" blocks and transactions.
+func NewCachedStorageService(storage StorageService, size int, ttl time.Duration) *CachedStorageService {
+	return &CachedStorageService{
+		StorageService: storage,
+		txs:            newLRUCache[string, *domain.TxInfo]("txs", size, ttl),
+		blocks:         newLRUCache[blockCacheKey, *domain.BlockInfo]("blocks", size, ttl),
+	}
+}
+
+func (s *CachedStorageService) GetTxByHash(ctx context.Context, txHash domain.TxHash) (*domain.TxInfo, error) {
+	if tx, ok := s.txs.get(string(txHash)); ok {
+		return tx, nil
+	}
+	tx, err := s.StorageService.GetTxByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	s.txs.add(string(txHash), tx)
+	return tx, nil
+}
+
+func (s *CachedStorageService) GetTxsByHashes(ctx context.Context, txHashes []domain.TxHash) ([]*domain.TxInfo, error) {
+	var txs []*domain.TxInfo
+	var missing []domain.TxHash
+	for _, hash := range txHashes {
+		if tx, ok := s.txs.get(string(hash)); ok {
+			txs = append(txs, tx)
+		} else {
+			missing = append(missing, hash)
+		}
+	}
+	if len(missing) == 0 {
+		return txs, nil
+	}
+	loaded, err := s.StorageService.GetTxsByHashes(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range loaded {
+		s.txs.add(string(tx.TxRecordHash), tx)
+	}
+	return append(txs, loaded...), nil
+}
+
+// GetBlock returns the cached blocks when the blocks of all the requested partitions are cached,
+// without the partition filter the blocks are always loaded from the storage.
+func (s *CachedStorageService) GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error) {
+	if len(partitionIDs) > 0 {
+		blocks := make(map[types.PartitionID]*domain.BlockInfo, len(partitionIDs))
+		for _, partitionID := range partitionIDs {
+			block, ok := s.blocks.get(blockCacheKey{partitionID: partitionID, blockNumber: blockNumber})
+			if !ok {
+				blocks = nil
+				break
+			}
+			blocks[partitionID] = block
+		}
+		if blocks != nil {
+			return blocks, nil
+		}
+	}
+	blocks, err := s.StorageService.GetBlock(ctx, blockNumber, partitionIDs)
+	if err != nil {
+		return nil, err
+	}
+	for partitionID, block := range blocks {
+		s.blocks.add(blockCacheKey{partitionID: partitionID, blockNumber: blockNumber}, block)
+	}
+	return blocks, nil
+}
+
+func newLRUCache[K comparable, V any](name string, size int, ttl time.Duration) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		size:    size,
+		ttl:     ttl,
+		items:   make(map[K]*list.Element),
+		order:   list.New(),
+		metrics: name,
+		now:     time.Now,
+	}
+}
+
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		if c.now().Before(entry.expires) {
+			c.order.MoveToFront(elem)
+			cacheMetrics.Add(c.metrics+".hits", 1)
+			return entry.value, true
+		}
+		c.remove(elem)
+	}
+	cacheMetrics.Add(c.metrics+".misses", 1)
+	var zero V
+	return zero, false
+}
+
+func (c *lruCache[K, V]) add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expires: c.now().Add(c.ttl)})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		cacheMetrics.Add(c.metrics+".evictions", 1)
+	}
+}
+
+func (c *lruCache[K, V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry[K, V]).key)
+}
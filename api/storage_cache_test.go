@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache[int, string]("test", 2, time.Minute)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	c.add(1, "a")
+	c.add(2, "b")
+	v, ok := c.get(1)
+	require.True(t, ok)
+	require.Equal(t, "a", v)
+
+	// 2 is the least recently used
+	c.add(3, "c")
+	_, ok = c.get(2)
+	require.False(t, ok)
+	_, ok = c.get(1)
+	require.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get(3)
+	require.False(t, ok)
+	require.Equal(t, 1, c.order.Len())
+}
+
+func TestCachedStorageService(t *testing.T) {
+	ctx := context.Background()
+	tx1 := &domain.TxInfo{TxRecordHash: domain.TxHash{1}}
+	tx2 := &domain.TxInfo{TxRecordHash: domain.TxHash{2}}
+	block := &domain.BlockInfo{PartitionID: partitionID1, BlockNumber: 5}
+
+	mockStorage := mocks.NewStorageService(t)
+	mockStorage.EXPECT().GetTxByHash(mock.Anything, tx1.TxRecordHash).Return(tx1, nil).Once()
+	mockStorage.EXPECT().GetTxsByHashes(mock.Anything, []domain.TxHash{tx2.TxRecordHash}).Return([]*domain.TxInfo{tx2}, nil).Once()
+	mockStorage.EXPECT().GetBlock(mock.Anything, uint64(5), []types.PartitionID(nil)).
+		Return(map[types.PartitionID]*domain.BlockInfo{partitionID1: block}, nil).Twice()
+	s := NewCachedStorageService(mockStorage, 10, time.Minute)
+
+	for range 2 {
+		tx, err := s.GetTxByHash(ctx, tx1.TxRecordHash)
+		require.NoError(t, err)
+		require.Equal(t, tx1, tx)
+
+		txs, err := s.GetTxsByHashes(ctx, []domain.TxHash{tx1.TxRecordHash, tx2.TxRecordHash})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*domain.TxInfo{tx1, tx2}, txs)
+	}
+
+	// blocks of all partitions are always loaded
+	for range 2 {
+		blocks, err := s.GetBlock(ctx, 5, nil)
+		require.NoError(t, err)
+		require.Equal(t, block, blocks[partitionID1])
+	}
+	blocks, err := s.GetBlock(ctx, 5, []types.PartitionID{partitionID1})
+	require.NoError(t, err)
+	require.Equal(t, block, blocks[partitionID1])
+}
+
+func TestCacheMetrics_AdminRouter(t *testing.T) {
+	// metrics are published only by the admin server
+	rec := httptest.NewRecorder()
+	(&Controller{}).Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	AdminRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"storage_cache"`)
+}
@@ -3,192 +3,270 @@ package api
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/txsubmit"
 	"github.com/alphabill-org/alphabill-explorer-backend/util"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// @Summary Retrieve a transaction by hash
-// @Description Retrieves transaction details using a transaction hash provided as a path parameter.
-// @Tags Transactions
-// @Accept json
-// @Produce json
-// @Param txHash path string true "The hash of the transaction to retrieve (HEX encoded)"
-// @Success 200 {object} TxInfo "Successfully retrieved the transaction information"
-// @Failure 400 {string} string "Missing 'txHash' variable in the URL"
-// @Failure 404 {string} string "Transaction with the specified hash not found"
-// @Failure 500 {string} string "Failed to load transaction details"
-// @Router /txs/{txHash} [get]
+// getTx retrieves transaction details using a transaction hash provided as a path parameter.
 func (c *Controller) getTx(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	txHash, ok := vars[paramTxHash]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramTxHash)
+		c.rw.WriteMissingParamResponse(w, r, paramTxHash)
 		return
 	}
 	txHashBytes, err := util.DecodeHex(txHash)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramTxHash)
+		c.rw.WriteInvalidParamResponse(w, r, paramTxHash)
 		return
 	}
 	txInfo, err := c.StorageService.GetTxByHash(r.Context(), txHashBytes)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			c.rw.WriteErrorResponse(w, fmt.Errorf("tx with txHash %s not found", txHash), http.StatusNotFound)
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("tx with txHash %s not found", txHash), http.StatusNotFound)
 			return
 		}
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load tx with txHash %s : %w", txHash, err))
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load tx with txHash %s : %w", txHash, err))
 		return
 	}
 
-	c.rw.WriteResponse(w, txInfoResponse(txInfo))
+	c.rw.WriteCacheableResponse(w, r, txInfoResponse(txInfo), cacheControlSynced)
 }
 
-// @Summary Retrieve transactions, latest first.
-// @Description Retrieves a list of transactions.
-// @Tags Transactions
-// @Produce json
-// @Param partitionID path string true "Partition ID to get the transactions for"
-// @Param startID query string false "ID of the transaction to start from, if not provided, the latest transactions are returned"
-// @Param limit query int false "The maximum number of transactions to retrieve, default 20"
-// @Success 200 {array} TxInfo "Successfully retrieved list of transactions"
-// @Router /partitions/{partitionID}/txs [get]
+// getTxs retrieves a page of transactions. Links to the next (older) and previous (newer) pages are returned in the
+// Link header.
 func (c *Controller) getTxs(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	partitionIDStr, ok := vars[paramPartitionID]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramPartitionID)
+		c.rw.WriteMissingParamResponse(w, r, paramPartitionID)
 		return
 	}
 	partitionID, err := strconv.ParseUint(partitionIDStr, 10, 64)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramPartitionID)
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
 		return
 	}
 
-	startID := r.URL.Query().Get(paramStartID)
-	limitStr := r.URL.Query().Get(paramLimit)
-	limit := defaultTxsPageLimit
-	if limitStr != "" {
-		limit, err = ParseMaxResponseItems(limitStr, 100)
-		if err != nil {
-			c.rw.WriteInvalidParamResponse(w, paramLimit)
-			return
-		}
+	cursor, limit, ok := c.parseTxsPageParams(w, r, listTxs, len(primitive.ObjectID{}), defaultTxsPageLimit)
+	if !ok {
+		return
 	}
 
-	txs, previousID, err := c.StorageService.GetTxsPage(r.Context(), types.PartitionID(partitionID), startID, limit)
+	txs, hasMore, err := c.StorageService.GetTxsPage(r.Context(), types.PartitionID(partitionID), pageRequest(cursor, limit, decodeObjectID))
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load txs with startID %s and limit %d : %w", startID, limit, err))
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load txs of partition %d: %w", partitionID, err))
 		return
 	}
 
-	var response = []TxInfo{}
-	for _, txInfo := range txs {
-		response = append(response, txInfoResponse(txInfo))
-	}
-
-	setLinkHeader(r.URL, w, previousID)
-	c.rw.WriteResponse(w, response)
+	c.writeTxsPage(w, r, listTxs, cursor, txs, hasMore, txObjectIDKey, "")
 }
 
-// @Summary Retrieve transactions by block number
-// @Description Retrieves a list of transactions for a given block number.
-// @Tags Transactions
-// @Accept json
-// @Produce json
-// @Param partitionID path int true "Partition ID to get the transactions for"
-// @Param blockNumber path int true "The block number for which to retrieve transactions"
-// @Success 200 {array} TxInfo "Successfully retrieved list of transactions for the block"
-// @Failure 400 {string} string "Missing or invalid 'blockNumber' variable in the URL"
-// @Failure 404 {string} string "No transactions found for the specified block number"
-// @Router /partitions/{partitionID}/blocks/{blockNumber}/txs [get]
+// getBlockTxsByBlockNumber retrieves a page of transactions for a given block number in the order they are in the
+// block. Links to the next and previous pages are returned in the Link header.
 func (c *Controller) getBlockTxsByBlockNumber(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	blockNumberStr, ok := vars[paramBlockNumber]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramBlockNumber)
+		c.rw.WriteMissingParamResponse(w, r, paramBlockNumber)
 		return
 	}
 	blockNumber, err := strconv.ParseUint(blockNumberStr, 10, 64)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramBlockNumber)
+		c.rw.WriteInvalidParamResponse(w, r, paramBlockNumber)
 		return
 	}
 	partitionIDStr, ok := vars[paramPartitionID]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramPartitionID)
+		c.rw.WriteMissingParamResponse(w, r, paramPartitionID)
 		return
 	}
 	partitionID, err := strconv.ParseUint(partitionIDStr, 10, 64)
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramPartitionID)
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
 		return
 	}
 
-	txs, err := c.StorageService.GetTxsByBlockNumber(r.Context(), blockNumber, types.PartitionID(partitionID))
-	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load txs with blockNumber %d : %w", blockNumber, err))
+	cursor, limit, ok := c.parseTxsPageParams(w, r, listBlockTxs, 0, defaultTxsPageLimit)
+	if !ok {
 		return
 	}
 
-	if txs == nil {
-		c.rw.WriteErrorResponse(w, fmt.Errorf("txs for block number %d not found", blockNumber), http.StatusNotFound)
+	page := pageRequest(cursor, limit, func(key []byte) domain.TxHash { return key })
+	txs, hasMore, err := c.StorageService.GetTxsPageByBlockNumber(r.Context(), blockNumber, types.PartitionID(partitionID), page)
+	if err != nil {
+		if errors.Is(err, domain.ErrPruned) {
+			c.rw.WriteErrorResponse(w, r, err, http.StatusGone)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("txs for block number %d not found", blockNumber), http.StatusNotFound)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load txs with blockNumber %d : %w", blockNumber, err))
 		return
 	}
 
-	var response = []TxInfo{}
-	for _, txInfo := range txs {
-		response = append(response, txInfoResponse(txInfo))
-	}
-
-	c.rw.WriteResponse(w, response)
+	c.writeTxsPage(w, r, listBlockTxs, cursor, txs, hasMore, func(tx *domain.TxInfo) []byte { return tx.TxRecordHash }, cacheControlSynced)
 }
 
-// @Summary Retrieve transactions by unit ID
-// @Description Get transactions associated with a specific unit ID
-// @Tags Transactions
-// @Accept json
-// @Produce json
-// @Param unitID path string true "Unit ID (0xHEX encoded)"
-// @Success 200 {array} TxInfo "List of transactions"
-// @Failure 400 {object} ErrorResponse "Error: Missing 'unitID' variable in the URL"
-// @Failure 404 {object} ErrorResponse "Error: Transactions with specified unit ID not found"
-// @Router /units/{unitID}/txs [get]
+// getTxsByUnitID gets a page of transactions associated with a specific unit ID, latest first. Links to the next
+// (older) and previous (newer) pages are returned in the Link header.
 func (c *Controller) getTxsByUnitID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	unitID, ok := vars[paramUnitID]
 	if !ok {
-		c.rw.WriteMissingParamResponse(w, paramUnitID)
+		c.rw.WriteMissingParamResponse(w, r, paramUnitID)
 		return
 	}
 
 	uid, err := util.FromHex([]byte(unitID))
 	if err != nil {
-		c.rw.WriteInvalidParamResponse(w, paramUnitID)
+		c.rw.WriteInvalidParamResponse(w, r, paramUnitID)
 		return
 	}
-	txs, err := c.StorageService.GetTxsByUnitID(r.Context(), uid)
+
+	cursor, limit, ok := c.parseTxsPageParams(w, r, listUnitTxs, len(primitive.ObjectID{}), defaultTxsPageLimit)
+	if !ok {
+		return
+	}
+
+	txs, hasMore, err := c.StorageService.GetTxsPageByUnitID(r.Context(), uid, pageRequest(cursor, limit, decodeObjectID))
 	if err != nil {
-		c.rw.WriteInternalErrorResponse(w, fmt.Errorf("failed to load txs with unitID %s : %w", unitID, err))
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load txs with unitID %s : %w", unitID, err))
 		return
 	}
 
-	if txs == nil {
-		c.rw.WriteErrorResponse(w, fmt.Errorf("txs with unitID %s not found", unitID), http.StatusNotFound)
+	if len(txs) == 0 && cursor == nil {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("txs with unitID %s not found", unitID), http.StatusNotFound)
 		return
 	}
 
+	c.writeTxsPage(w, r, listUnitTxs, cursor, txs, hasMore, txObjectIDKey, "")
+}
+
+// submitTx forwards the CBOR encoded transaction order to the partition node. The status of the transaction can be
+// followed using the /txs/{txOrderHash}/status endpoint.
+func (c *Controller) submitTx(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	partitionIDStr, ok := vars[paramPartitionID]
+	if !ok {
+		c.rw.WriteMissingParamResponse(w, r, paramPartitionID)
+		return
+	}
+	partitionID, err := strconv.ParseUint(partitionIDStr, 10, 64)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTxOrderSize))
+	if err != nil {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("failed to read transaction order: %w", err))
+		return
+	}
+	tx := &types.TransactionOrder{}
+	if err = tx.UnmarshalCBOR(body); err != nil {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("failed to decode transaction order: %w", err))
+		return
+	}
+
+	status, err := c.TxSubmitService.SubmitTx(r.Context(), types.PartitionID(partitionID), tx)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("partition %d not found", partitionID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, txsubmit.ErrTxRejected) {
+			c.rw.WriteErrorResponse(w, r, err)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to submit tx to partition %d: %w", partitionID, err))
+		return
+	}
+
+	c.rw.WriteResponseWithStatus(w, r, http.StatusAccepted, status)
+}
+
+// getTxStatus reports whether the transaction is pending, included in a block or expired (not included before its
+// timeout round). Pending and expired status is only known for the transactions submitted through the explorer.
+func (c *Controller) getTxStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txOrderHash, ok := vars[paramTxOrderHash]
+	if !ok {
+		c.rw.WriteMissingParamResponse(w, r, paramTxOrderHash)
+		return
+	}
+	txOrderHashBytes, err := util.DecodeHex(txOrderHash)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramTxOrderHash)
+		return
+	}
+
+	status, err := c.TxSubmitService.GetTxStatus(r.Context(), txOrderHashBytes)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("tx with txOrderHash %s not found", txOrderHash), http.StatusNotFound)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load status of tx %s: %w", txOrderHash, err))
+		return
+	}
+
+	c.rw.WriteResponse(w, r, status)
+}
+
+// parseTxsPageParams parses the cursor and limit query parameters, writes error response and returns false when they are invalid.
+func (c *Controller) parseTxsPageParams(w http.ResponseWriter, r *http.Request, list string, keyLen, defaultLimit int) (*pageCursor, int, bool) {
+	qp := r.URL.Query()
+	cursor, err := parseCursor(qp.Get(QueryParamCursor), list, keyLen)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, QueryParamCursor)
+		return nil, 0, false
+	}
+	limit, err := parseLimit(qp.Get(paramLimit), defaultLimit)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+		return nil, 0, false
+	}
+	return cursor, limit, true
+}
+
+// writeTxsPage writes the page of transactions, the response is cacheable when "cacheControl" is not empty.
+func (c *Controller) writeTxsPage(
+	w http.ResponseWriter, r *http.Request, list string, cursor *pageCursor, txs []*domain.TxInfo, hasMore bool,
+	key func(*domain.TxInfo) []byte, cacheControl string,
+) {
 	var response = []TxInfo{}
 	for _, txInfo := range txs {
 		response = append(response, txInfoResponse(txInfo))
 	}
+	var firstKey, lastKey []byte
+	if len(txs) > 0 {
+		firstKey, lastKey = key(txs[0]), key(txs[len(txs)-1])
+	}
+	setPageLinks(w, r.URL, list, cursor, firstKey, lastKey, hasMore)
+	if cacheControl != "" {
+		c.rw.WriteCacheableResponse(w, r, response, cacheControl)
+		return
+	}
+	c.rw.WriteResponse(w, r, response)
+}
+
+func txObjectIDKey(tx *domain.TxInfo) []byte {
+	return tx.ID[:]
+}
 
-	c.rw.WriteResponse(w, response)
+func decodeObjectID(key []byte) primitive.ObjectID {
+	return primitive.ObjectID(key)
 }
 
 func txInfoResponse(tx *domain.TxInfo) TxInfo {
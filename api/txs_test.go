@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,9 +11,12 @@ import (
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/txsubmit"
+	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestGetTxByHash_Success(t *testing.T) {
@@ -42,12 +46,12 @@ func TestGetTxByHash_Success(t *testing.T) {
 func TestGetTxs_Success(t *testing.T) {
 	r := mux.NewRouter()
 	mockStorage := mocks.NewStorageService(t)
-	mockStorage.EXPECT().GetTxsPage(mock.Anything, partitionID1, "", defaultTxsPageLimit).
+	mockStorage.EXPECT().GetTxsPage(mock.Anything, partitionID1, domain.PageRequest[primitive.ObjectID]{Limit: defaultTxsPageLimit}).
 		Return([]*domain.TxInfo{
 			{TxRecordHash: []byte{0x01}},
 			{TxRecordHash: []byte{0x02}},
 			{TxRecordHash: []byte{0x03}},
-		}, "xxx", nil)
+		}, true, nil)
 	restapi := &Controller{StorageService: mockStorage}
 	r.HandleFunc("/partitions/{partitionID}/txs", restapi.getTxs)
 	ts := httptest.NewServer(r)
@@ -66,5 +70,48 @@ func TestGetTxs_Success(t *testing.T) {
 	require.NotNil(t, result)
 	require.Len(t, result, 3)
 
-	require.Contains(t, res.Header.Get("Link"), "offsetKey=xxx")
+	// the first page has only the link to the next page
+	require.Contains(t, res.Header.Get("Link"), `rel="next"`)
+	require.NotContains(t, res.Header.Get("Link"), `rel="prev"`)
+}
+
+func TestSubmitTx(t *testing.T) {
+	tx := &types.TransactionOrder{Payload: types.Payload{PartitionID: partitionID1, UnitID: types.UnitID{1}}}
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	status := &txsubmit.TxStatus{TxOrderHash: domain.TxHash{1, 2}, PartitionID: partitionID1, Status: txsubmit.StatusPending}
+
+	mockSubmit := mocks.NewTxSubmitService(t)
+	mockSubmit.EXPECT().SubmitTx(mock.Anything, partitionID1, tx).Return(status, nil).Once()
+	mockSubmit.EXPECT().SubmitTx(mock.Anything, partitionID1, tx).Return(nil, fmt.Errorf("%w: invalid fee", txsubmit.ErrTxRejected)).Once()
+	mockSubmit.EXPECT().GetTxStatus(mock.Anything, status.TxOrderHash).Return(status, nil)
+	mockSubmit.EXPECT().GetTxStatus(mock.Anything, domain.TxHash{3}).Return(nil, domain.ErrNotFound)
+	restapi := &Controller{TxSubmitService: mockSubmit}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+	url := fmt.Sprintf("%s/api/v1/partitions/%d/txs", ts.URL, partitionID1)
+
+	res, err := http.Post(url, ApplicationCbor, bytes.NewReader(txBytes))
+	require.NoError(t, err)
+	var result txsubmit.TxStatus
+	require.NoError(t, DecodeResponse(res, http.StatusAccepted, &result, false))
+	require.Equal(t, *status, result)
+
+	res, err = http.Post(url, ApplicationCbor, bytes.NewReader(txBytes))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	require.ErrorContains(t, DecodeResponse(res, http.StatusAccepted, nil, false), "invalid fee")
+
+	res, err = http.Post(url, ApplicationCbor, bytes.NewReader([]byte{0xff}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	res, err = http.Get(fmt.Sprintf("%s/api/v1/txs/0x%s/status", ts.URL, status.TxOrderHash))
+	require.NoError(t, err)
+	require.NoError(t, DecodeResponse(res, http.StatusOK, &result, false))
+	require.Equal(t, *status, result)
+
+	res, err = http.Get(ts.URL + "/api/v1/txs/0x03/status")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
 }
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/gorilla/mux"
+)
+
+// getUnit retrieves the current state of the unit from the partition node or, with atBlock, the state at the block
+// reconstructed by replaying the stored transactions of the unit. With includeStateProof the state proof of the node is
+// returned and verified against the unit data and the trust base.
+func (c *Controller) getUnit(w http.ResponseWriter, r *http.Request) {
+	unitIDStr := mux.Vars(r)[paramUnitID]
+	unitID, err := util.DecodeHex(unitIDStr)
+	if err != nil || len(unitID) == 0 {
+		c.rw.WriteInvalidParamResponse(w, r, paramUnitID)
+		return
+	}
+	qp := r.URL.Query()
+	var partitionID *types.PartitionID
+	if s := qp.Get(paramPartitionID); s != "" {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+			return
+		}
+		pid := types.PartitionID(id)
+		partitionID = &pid
+	}
+	includeStateProof := false
+	if s := qp.Get(paramStateProof); s != "" {
+		if includeStateProof, err = strconv.ParseBool(s); err != nil {
+			c.rw.WriteInvalidParamResponse(w, r, paramStateProof)
+			return
+		}
+	}
+
+	var state *unitstate.UnitState
+	if s := qp.Get(paramAtBlock); s != "" {
+		blockNumber, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || blockNumber == 0 {
+			c.rw.WriteInvalidParamResponse(w, r, paramAtBlock)
+			return
+		}
+		if includeStateProof {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("%s can't be used with %s", paramStateProof, paramAtBlock))
+			return
+		}
+		state, err = c.UnitStateService.GetUnitStateAt(r.Context(), unitID, partitionID, blockNumber)
+	} else {
+		state, err = c.UnitStateService.GetUnitState(r.Context(), unitID, partitionID, includeStateProof)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("unit %s not found: %w", unitIDStr, err), http.StatusNotFound)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load state of unit %s: %w", unitIDStr, err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, state, cacheControlLatest)
+}
+
+// getUnitLifecycle retrieves the creation, the state-changing transactions, the related units (split targets, swap
+// inputs, fee credit records on other partitions) and the final state of the unit, assembled from the stored
+// transactions.
+func (c *Controller) getUnitLifecycle(w http.ResponseWriter, r *http.Request) {
+	unitIDStr := mux.Vars(r)[paramUnitID]
+	unitID, err := util.DecodeHex(unitIDStr)
+	if err != nil || len(unitID) == 0 {
+		c.rw.WriteInvalidParamResponse(w, r, paramUnitID)
+		return
+	}
+	lc, err := c.LifecycleService.GetLifecycle(r.Context(), unitID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.rw.WriteErrorResponse(w, r, fmt.Errorf("lifecycle of unit %s not found", unitIDStr), http.StatusNotFound)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to load lifecycle of unit %s: %w", unitIDStr, err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, lc, cacheControlLatest)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/gorilla/mux"
+)
+
+// getValidators aggregates the stored blocks of the window ending with the latest stored block by the proposer: blocks
+// proposed, empty and non-empty blocks and the fees of the transactions. The rounds without a block are counted as
+// missed rounds of the partition.
+func (c *Controller) getValidators(w http.ResponseWriter, r *http.Request) {
+	partitionID, window, ok := c.parseValidatorParams(w, r)
+	if !ok {
+		return
+	}
+	validators, err := c.ValidatorService.GetValidators(r.Context(), partitionID, window)
+	if err != nil {
+		c.writeValidatorError(w, r, fmt.Errorf("failed to load validators of partition %d: %w", partitionID, err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, validators, cacheControlLatest)
+}
+
+// getValidator aggregates the stored blocks of the window ending with the latest stored block proposed by the
+// validator.
+func (c *Controller) getValidator(w http.ResponseWriter, r *http.Request) {
+	partitionID, window, ok := c.parseValidatorParams(w, r)
+	if !ok {
+		return
+	}
+	nodeID := mux.Vars(r)[paramNodeID]
+	v, err := c.ValidatorService.GetValidator(r.Context(), partitionID, nodeID, window)
+	if err != nil {
+		c.writeValidatorError(w, r, fmt.Errorf("failed to load validator %s: %w", nodeID, err))
+		return
+	}
+	c.rw.WriteCacheableResponse(w, r, v, cacheControlLatest)
+}
+
+func (c *Controller) parseValidatorParams(w http.ResponseWriter, r *http.Request) (types.PartitionID, time.Duration, bool) {
+	partitionID, err := strconv.ParseUint(mux.Vars(r)[paramPartitionID], 10, 32)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramPartitionID)
+		return 0, 0, false
+	}
+	var window time.Duration
+	if s := r.URL.Query().Get(paramWindow); s != "" {
+		if window, err = time.ParseDuration(s); err != nil || window <= 0 {
+			c.rw.WriteInvalidParamResponse(w, r, paramWindow)
+			return 0, 0, false
+		}
+	}
+	return types.PartitionID(partitionID), window, true
+}
+
+func (c *Controller) writeValidatorError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, validator.ErrInvalidWindow):
+		c.rw.WriteErrorResponse(w, r, err)
+	case errors.Is(err, domain.ErrNotFound):
+		c.rw.WriteErrorResponse(w, r, err, http.StatusNotFound)
+	default:
+		c.rw.WriteInternalErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/webhook"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxWebhookRequestSize = 16 * 1024
+
+type (
+	CreateWebhookRequest struct {
+		URL    string
+		Filter domain.WebhookFilter
+	}
+
+	ReplayWebhookResponse struct {
+		Replayed int64
+	}
+)
+
+// createWebhook registers the webhook URL which is notified about the transactions matching the filter. The response
+// contains the secret the payloads are signed with, it's not returned later.
+func (c *Controller) createWebhook(w http.ResponseWriter, r *http.Request) {
+	owner, ok := c.webhookOwner(w, r)
+	if !ok {
+		return
+	}
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxWebhookRequestSize)).Decode(&req); err != nil {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("failed to decode request: %w", err))
+		return
+	}
+	subscription, err := c.WebhookService.CreateSubscription(r.Context(), owner, req.URL, req.Filter)
+	if err != nil {
+		if errors.Is(err, webhook.ErrInvalidSubscription) {
+			c.rw.WriteErrorResponse(w, r, err)
+			return
+		}
+		c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to create webhook subscription: %w", err))
+		return
+	}
+	c.rw.WriteResponseWithStatus(w, r, http.StatusCreated, subscription)
+}
+
+// getWebhooks retrieves the webhook subscriptions created with the API key.
+func (c *Controller) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	owner, ok := c.webhookOwner(w, r)
+	if !ok {
+		return
+	}
+	subscriptions, err := c.WebhookService.GetSubscriptions(r.Context(), owner)
+	if err != nil {
+		c.rw.WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []*domain.WebhookSubscription{}
+	}
+	c.rw.WriteResponse(w, r, subscriptions)
+}
+
+// getWebhook retrieves a webhook subscription.
+func (c *Controller) getWebhook(w http.ResponseWriter, r *http.Request) {
+	owner, id, ok := c.webhookParams(w, r)
+	if !ok {
+		return
+	}
+	subscription, err := c.WebhookService.GetSubscription(r.Context(), owner, id)
+	if err != nil {
+		c.writeWebhookError(w, r, id, err)
+		return
+	}
+	c.rw.WriteResponse(w, r, subscription)
+}
+
+// deleteWebhook deletes the subscription together with its pending and failed deliveries.
+func (c *Controller) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	owner, id, ok := c.webhookParams(w, r)
+	if !ok {
+		return
+	}
+	if err := c.WebhookService.DeleteSubscription(r.Context(), owner, id); err != nil {
+		c.writeWebhookError(w, r, id, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getWebhookDeliveries retrieves the latest deliveries of the subscription, failed deliveries are the dead-lettered
+// events.
+func (c *Controller) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	owner, id, ok := c.webhookParams(w, r)
+	if !ok {
+		return
+	}
+	qp := r.URL.Query()
+	status := domain.WebhookDeliveryStatus(qp.Get(paramStatus))
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryFailed:
+	default:
+		c.rw.WriteInvalidParamResponse(w, r, paramStatus)
+		return
+	}
+	limit, err := parseLimit(qp.Get(paramLimit), defaultDeliveriesLimit)
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramLimit)
+		return
+	}
+
+	deliveries, err := c.WebhookService.GetDeliveries(r.Context(), owner, id, status, limit)
+	if err != nil {
+		c.writeWebhookError(w, r, id, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+	c.rw.WriteResponse(w, r, deliveries)
+}
+
+// replayWebhookDeliveries schedules the failed (dead-lettered) deliveries of the subscription for delivery again.
+func (c *Controller) replayWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	owner, id, ok := c.webhookParams(w, r)
+	if !ok {
+		return
+	}
+	replayed, err := c.WebhookService.ReplayDeliveries(r.Context(), owner, id)
+	if err != nil {
+		c.writeWebhookError(w, r, id, err)
+		return
+	}
+	c.rw.WriteResponse(w, r, ReplayWebhookResponse{Replayed: replayed})
+}
+
+/*
+webhookOwner returns the owner of the subscriptions, ie the hash of the API key of the request.
+Webhooks can only be managed with the API keys configured for the rate limits, error response is
+written and false returned when the request has no valid API key.
+*/
+func (c *Controller) webhookOwner(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if c.WebhookService == nil {
+		c.rw.WriteErrorResponse(w, r, errors.New("webhooks are not enabled"), http.StatusNotFound)
+		return "", false
+	}
+	key := r.Header.Get(HeaderAPIKey)
+	if key == "" || c.RateLimiter == nil || !c.RateLimiter.knownAPIKey(key) {
+		c.rw.WriteErrorResponse(w, r, errors.New("valid API key is required"), http.StatusUnauthorized)
+		return "", false
+	}
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:]), true
+}
+
+func (c *Controller) webhookParams(w http.ResponseWriter, r *http.Request) (string, primitive.ObjectID, bool) {
+	owner, ok := c.webhookOwner(w, r)
+	if !ok {
+		return "", primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)[paramWebhookID])
+	if err != nil {
+		c.rw.WriteInvalidParamResponse(w, r, paramWebhookID)
+		return "", primitive.NilObjectID, false
+	}
+	return owner, id, true
+}
+
+func (c *Controller) writeWebhookError(w http.ResponseWriter, r *http.Request, id primitive.ObjectID, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		c.rw.WriteErrorResponse(w, r, fmt.Errorf("webhook subscription %s not found", id.Hex()), http.StatusNotFound)
+		return
+	}
+	c.rw.WriteInternalErrorResponse(w, r, fmt.Errorf("failed to process webhook subscription %s: %w", id.Hex(), err))
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/webhook"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWebhooks(t *testing.T) {
+	const apiKey = "key"
+	keyHash := sha256.Sum256([]byte(apiKey))
+	owner := hex.EncodeToString(keyHash[:])
+	rateLimiter, err := NewRateLimiter(RateLimitConfig{Tiers: map[string]RateLimitTier{"pro": {}}, APIKeys: map[string]string{apiKey: "pro"}})
+	require.NoError(t, err)
+	webhookService := mocks.NewWebhookService(t)
+	restapi := &Controller{PartitionService: partitionServiceStub{}, WebhookService: webhookService, RateLimiter: rateLimiter}
+	ts := httptest.NewServer(restapi.Router())
+	defer ts.Close()
+
+	do := func(method, path, key string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set(HeaderAPIKey, key)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("API key is required", func(t *testing.T) {
+		res := do(http.MethodGet, "/webhooks", "", nil)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		res = do(http.MethodGet, "/webhooks", "unknown", nil)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		filter := domain.WebhookFilter{UnitID: []byte{1}}
+		id := primitive.NewObjectID()
+		webhookService.EXPECT().CreateSubscription(mock.Anything, owner, "https://example.com", filter).
+			Return(&domain.WebhookSubscription{ID: id, URL: "https://example.com", Secret: []byte{2}, Filter: filter}, nil).Once()
+		body, err := json.Marshal(CreateWebhookRequest{URL: "https://example.com", Filter: filter})
+		require.NoError(t, err)
+
+		res := do(http.MethodPost, "/webhooks", apiKey, body)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var subscription domain.WebhookSubscription
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&subscription))
+		require.Equal(t, id, subscription.ID)
+		require.EqualValues(t, []byte{2}, subscription.Secret)
+
+		webhookService.EXPECT().CreateSubscription(mock.Anything, owner, "ftp://example.com", filter).
+			Return(nil, fmt.Errorf("%w: URL must be an absolute http(s) URL", webhook.ErrInvalidSubscription)).Once()
+		body, err = json.Marshal(CreateWebhookRequest{URL: "ftp://example.com", Filter: filter})
+		require.NoError(t, err)
+		res = do(http.MethodPost, "/webhooks", apiKey, body)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		webhookService.EXPECT().GetSubscriptions(mock.Anything, owner).Return(nil, nil).Once()
+		res := do(http.MethodGet, "/webhooks", apiKey, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var subscriptions []*domain.WebhookSubscription
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&subscriptions))
+		require.NotNil(t, subscriptions)
+		require.Empty(t, subscriptions)
+	})
+
+	t.Run("get and delete", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		webhookService.EXPECT().GetSubscription(mock.Anything, owner, id).Return(nil, domain.ErrNotFound).Once()
+		res := do(http.MethodGet, "/webhooks/"+id.Hex(), apiKey, nil)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		res = do(http.MethodGet, "/webhooks/invalid", apiKey, nil)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		webhookService.EXPECT().DeleteSubscription(mock.Anything, owner, id).Return(nil).Once()
+		res = do(http.MethodDelete, "/webhooks/"+id.Hex(), apiKey, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+
+	t.Run("deliveries", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		res := do(http.MethodGet, "/webhooks/"+id.Hex()+"/deliveries?status=unknown", apiKey, nil)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		webhookService.EXPECT().GetDeliveries(mock.Anything, owner, id, domain.WebhookDeliveryFailed, 5).
+			Return([]*domain.WebhookDelivery{{ID: "1", Status: domain.WebhookDeliveryFailed}}, nil).Once()
+		res = do(http.MethodGet, "/webhooks/"+id.Hex()+"/deliveries?status=failed&limit=5", apiKey, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var deliveries []*domain.WebhookDelivery
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&deliveries))
+		require.Len(t, deliveries, 1)
+
+		webhookService.EXPECT().ReplayDeliveries(mock.Anything, owner, id).Return(1, nil).Once()
+		res = do(http.MethodPost, "/webhooks/"+id.Hex()+"/replay", apiKey, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var replay ReplayWebhookResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&replay))
+		require.EqualValues(t, 1, replay.Replayed)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ts := httptest.NewServer((&Controller{PartitionService: partitionServiceStub{}, RateLimiter: rateLimiter}).Router())
+		defer ts.Close()
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/webhooks", nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderAPIKey, apiKey)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
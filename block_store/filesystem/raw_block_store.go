@@ -0,0 +1,201 @@
+package filesystem
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const (
+	// blocksPerDir is the number of rounds stored in a single shard directory
+	blocksPerDir = 10000
+
+	rawBlockFileExt = ".cbor.gz"
+)
+
+/*
+RawBlockStore archives CBOR encoded blocks on the filesystem.
+
+Blocks are sharded by partition and round, block N of partition P is stored as
+gzip compressed file <dir>/<P>/<N/blocksPerDir>/<N>.cbor.gz
+*/
+type RawBlockStore struct {
+	dir string
+}
+
+func NewRawBlockStore(dir string) (*RawBlockStore, error) {
+	if dir == "" {
+		return nil, errors.New("raw block archive directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create raw block archive directory: %w", err)
+	}
+	return &RawBlockStore{dir: dir}, nil
+}
+
+func (s *RawBlockStore) SetRawBlock(_ context.Context, partitionID types.PartitionID, blockNumber uint64, data []byte) error {
+	path := s.blockPath(partitionID, blockNumber)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create shard directory: %w", err)
+	}
+
+	// write into temporary file first so that readers never see partially written blocks
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create raw block file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	if _, err = zw.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write raw block: %w", err)
+	}
+	if err = zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write raw block: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close raw block file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store raw block file: %w", err)
+	}
+	return nil
+}
+
+func (s *RawBlockStore) GetRawBlock(_ context.Context, partitionID types.PartitionID, blockNumber uint64) ([]byte, error) {
+	f, err := os.Open(s.blockPath(partitionID, blockNumber))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open raw block file: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress raw block: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress raw block: %w", err)
+	}
+	return data, nil
+}
+
+// PruneRawBlocks removes all the archived blocks of the partition with block number less than "beforeBlockNumber".
+func (s *RawBlockStore) PruneRawBlocks(_ context.Context, partitionID types.PartitionID, beforeBlockNumber uint64) error {
+	partitionDir := filepath.Join(s.dir, strconv.FormatUint(uint64(partitionID), 10))
+	shards, err := os.ReadDir(partitionDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read partition directory: %w", err)
+	}
+
+	lastShard := beforeBlockNumber / blocksPerDir
+	for _, shard := range shards {
+		shardIdx, err := strconv.ParseUint(shard.Name(), 10, 64)
+		if err != nil || !shard.IsDir() || shardIdx > lastShard {
+			continue
+		}
+		shardDir := filepath.Join(partitionDir, shard.Name())
+		if shardIdx < lastShard {
+			if err = os.RemoveAll(shardDir); err != nil {
+				return fmt.Errorf("failed to remove shard directory: %w", err)
+			}
+			continue
+		}
+		// the shard containing the cutoff block is pruned file by file
+		files, err := os.ReadDir(shardDir)
+		if err != nil {
+			return fmt.Errorf("failed to read shard directory: %w", err)
+		}
+		for _, file := range files {
+			blockNumber, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), rawBlockFileExt), 10, 64)
+			if err != nil || blockNumber >= beforeBlockNumber {
+				continue
+			}
+			if err = os.Remove(filepath.Join(shardDir, file.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove raw block file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRawBlocks removes all the archived blocks of the partition with block number greater than or equal to "fromBlockNumber".
+func (s *RawBlockStore) DeleteRawBlocks(_ context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	partitionDir := filepath.Join(s.dir, strconv.FormatUint(uint64(partitionID), 10))
+	shards, err := os.ReadDir(partitionDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read partition directory: %w", err)
+	}
+
+	firstShard := fromBlockNumber / blocksPerDir
+	for _, shard := range shards {
+		shardIdx, err := strconv.ParseUint(shard.Name(), 10, 64)
+		if err != nil || !shard.IsDir() || shardIdx < firstShard {
+			continue
+		}
+		shardDir := filepath.Join(partitionDir, shard.Name())
+		if shardIdx > firstShard {
+			if err = os.RemoveAll(shardDir); err != nil {
+				return fmt.Errorf("failed to remove shard directory: %w", err)
+			}
+			continue
+		}
+		files, err := os.ReadDir(shardDir)
+		if err != nil {
+			return fmt.Errorf("failed to read shard directory: %w", err)
+		}
+		for _, file := range files {
+			blockNumber, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), rawBlockFileExt), 10, 64)
+			if err != nil || blockNumber < fromBlockNumber {
+				continue
+			}
+			if err = os.Remove(filepath.Join(shardDir, file.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove raw block file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ArchiveRawBlocks moves the archived blocks of the partition into directory <dir>/<P>.reset-<unix time of reset>.
+func (s *RawBlockStore) ArchiveRawBlocks(_ context.Context, partitionID types.PartitionID, resetAt time.Time) error {
+	partitionDir := filepath.Join(s.dir, strconv.FormatUint(uint64(partitionID), 10))
+	archiveDir := partitionDir + ".reset-" + strconv.FormatInt(resetAt.Unix(), 10)
+	if err := os.Rename(partitionDir, archiveDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to archive partition directory: %w", err)
+	}
+	return nil
+}
+
+func (s *RawBlockStore) blockPath(partitionID types.PartitionID, blockNumber uint64) string {
+	return filepath.Join(
+		s.dir,
+		strconv.FormatUint(uint64(partitionID), 10),
+		strconv.FormatUint(blockNumber/blocksPerDir, 10),
+		strconv.FormatUint(blockNumber, 10)+rawBlockFileExt,
+	)
+}
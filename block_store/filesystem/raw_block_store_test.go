@@ -0,0 +1,115 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawBlockStore_SetAndGet(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewRawBlockStore(dir)
+	require.NoError(t, err)
+
+	data := []byte{0x83, 0x01, 0x02, 0x03}
+	require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(1), 12345, data))
+	require.FileExists(t, filepath.Join(dir, "1", "1", "12345.cbor.gz"))
+
+	res, err := store.GetRawBlock(ctx, types.PartitionID(1), 12345)
+	require.NoError(t, err)
+	require.Equal(t, data, res)
+
+	// overwrite existing block
+	data = []byte{0x80}
+	require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(1), 12345, data))
+	res, err = store.GetRawBlock(ctx, types.PartitionID(1), 12345)
+	require.NoError(t, err)
+	require.Equal(t, data, res)
+}
+
+func TestRawBlockStore_NotFound(t *testing.T) {
+	store, err := NewRawBlockStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.GetRawBlock(context.Background(), types.PartitionID(2), 1)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRawBlockStore_PruneRawBlocks(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewRawBlockStore(t.TempDir())
+	require.NoError(t, err)
+
+	blockNumbers := []uint64{1, 9999, 10000, 15000, 20000, 20001}
+	for _, bn := range blockNumbers {
+		require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(1), bn, []byte{0x80}))
+		require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(2), bn, []byte{0x80}))
+	}
+
+	require.NoError(t, store.PruneRawBlocks(ctx, types.PartitionID(1), 20001))
+
+	for _, bn := range blockNumbers {
+		_, err = store.GetRawBlock(ctx, types.PartitionID(1), bn)
+		if bn < 20001 {
+			require.ErrorIs(t, err, domain.ErrNotFound, "block %d", bn)
+		} else {
+			require.NoError(t, err, "block %d", bn)
+		}
+		// other partitions are not affected
+		_, err = store.GetRawBlock(ctx, types.PartitionID(2), bn)
+		require.NoError(t, err)
+	}
+
+	// pruning partition without archived blocks is no-op
+	require.NoError(t, store.PruneRawBlocks(ctx, types.PartitionID(3), 100))
+}
+
+func TestRawBlockStore_DeleteRawBlocks(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewRawBlockStore(t.TempDir())
+	require.NoError(t, err)
+
+	blockNumbers := []uint64{1, 9999, 10000, 15000, 20000, 20001}
+	for _, bn := range blockNumbers {
+		require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(1), bn, []byte{0x80}))
+		require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(2), bn, []byte{0x80}))
+	}
+
+	require.NoError(t, store.DeleteRawBlocks(ctx, types.PartitionID(1), 15000))
+
+	for _, bn := range blockNumbers {
+		_, err = store.GetRawBlock(ctx, types.PartitionID(1), bn)
+		if bn >= 15000 {
+			require.ErrorIs(t, err, domain.ErrNotFound, "block %d", bn)
+		} else {
+			require.NoError(t, err, "block %d", bn)
+		}
+		// other partitions are not affected
+		_, err = store.GetRawBlock(ctx, types.PartitionID(2), bn)
+		require.NoError(t, err)
+	}
+}
+
+func TestRawBlockStore_ArchiveRawBlocks(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewRawBlockStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetRawBlock(ctx, types.PartitionID(1), 5, []byte{0x80}))
+	resetAt := time.Unix(1700000000, 0)
+	require.NoError(t, store.ArchiveRawBlocks(ctx, types.PartitionID(1), resetAt))
+
+	_, err = store.GetRawBlock(ctx, types.PartitionID(1), 5)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.FileExists(t, filepath.Join(dir, "1.reset-1700000000", "0", "5.cbor.gz"))
+
+	// archiving partition without archived blocks is no-op
+	require.NoError(t, store.ArchiveRawBlocks(ctx, types.PartitionID(2), resetAt))
+}
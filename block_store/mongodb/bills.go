@@ -0,0 +1,420 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// supplyDelta is the change of the supply counters stored in the metadata of the partition.
+	supplyDelta struct {
+		billsValue     int64
+		untrackedBills int64
+		dustCollector  int64
+		feeCredit      int64
+	}
+
+	// supplyDocument is the supply counters of the partition, blocknumber is the last block the changes are applied of.
+	supplyDocument struct {
+		BlockNumber    uint64 `bson:"blocknumber"`
+		BillsValue     int64  `bson:"billsvalue"`
+		UntrackedBills int64  `bson:"untrackedbills"`
+		DustCollector  int64  `bson:"dustcollector"`
+		FeeCredit      int64  `bson:"feecredit"`
+	}
+)
+
+// GetTrackedBills returns the stored bills of the unit IDs, the bills which are not stored are omitted.
+func (s *MongoBlockStore) GetTrackedBills(ctx context.Context, partitionID types.PartitionID, unitIDs []types.UnitID) ([]*domain.TrackedBill, error) {
+	filter := bson.M{partitionIDKey: partitionID, unitIDKey: bson.M{"$in": unitIDs}}
+	cursor, err := s.db.Collection(billsCollectionName).Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bills: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var bills []*domain.TrackedBill
+	if err = cursor.All(ctx, &bills); err != nil {
+		return nil, fmt.Errorf("failed to decode bills: %w", err)
+	}
+	return bills, nil
+}
+
+/*
+GetOwnerBillIDs returns the IDs of the stored bills owned by the owner and of the bills the owner has owned before or
+after any of the stored bill changes, ie the bills the owner has owned since the oldest block which is not pruned.
+*/
+func (s *MongoBlockStore) GetOwnerBillIDs(ctx context.Context, partitionID types.PartitionID, ownerID hex.Bytes) ([]types.UnitID, error) {
+	filter := bson.M{partitionIDKey: partitionID, ownerIDKey: ownerID}
+	opts := options.Find().SetProjection(bson.M{unitIDKey: 1})
+	cursor, err := s.db.Collection(billsCollectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bills: %w", err)
+	}
+	var bills []*domain.TrackedBill
+	if err = cursor.All(ctx, &bills); err != nil {
+		return nil, fmt.Errorf("failed to decode bills: %w", err)
+	}
+
+	// the changes of the block are matched first, then the changes of the owner's bills in the block
+	ownerChanges := []bson.M{{billOwnerBeforeKey: ownerID}, {billOwnerAfterKey: ownerID}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{partitionIDKey: partitionID, "$or": ownerChanges}}},
+		{{Key: "$unwind", Value: "$" + billsKey}},
+		{{Key: "$match", Value: bson.M{"$or": ownerChanges}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + billUnitIDKey}}},
+	}
+	cursor, err = s.db.Collection(billChangesCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bill changes: %w", err)
+	}
+	var changed []struct {
+		UnitID types.UnitID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &changed); err != nil {
+		return nil, fmt.Errorf("failed to decode bill changes: %w", err)
+	}
+
+	unitIDs := make([]types.UnitID, 0, len(bills)+len(changed))
+	seen := make(map[string]bool)
+	for _, bill := range bills {
+		seen[string(bill.UnitID)] = true
+		unitIDs = append(unitIDs, bill.UnitID)
+	}
+	for _, c := range changed {
+		if !seen[string(c.UnitID)] {
+			seen[string(c.UnitID)] = true
+			unitIDs = append(unitIDs, c.UnitID)
+		}
+	}
+	return unitIDs, nil
+}
+
+/*
+ApplyBillChanges stores the changes of the money partition block, sets the bills to their state after the block,
+recalculates the balances of the owners of the changed bills and updates the supply. The changes are stored first
+for reverting the block on rollback. When the changes of the block are already stored (the processing of the block
+was interrupted) the stored changes are applied again instead of the given ones, which were calculated from the
+partially updated bills.
+*/
+func (s *MongoBlockStore) ApplyBillChanges(ctx context.Context, changes *domain.BillChanges) error {
+	collection := s.db.Collection(billChangesCollectionName)
+	if _, err := collection.InsertOne(ctx, changes); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to store bill changes: %w", err)
+		}
+		stored := &domain.BillChanges{}
+		filter := bson.M{partitionIDKey: changes.PartitionID, blockNumberKey: changes.BlockNumber}
+		if err = collection.FindOne(ctx, filter).Decode(stored); err != nil {
+			return fmt.Errorf("failed to load stored bill changes: %w", err)
+		}
+		changes = stored
+	}
+	if err := s.setBills(ctx, changes.PartitionID, changes.Bills, false); err != nil {
+		return err
+	}
+
+	delta := newSupplyDelta(changes, false)
+	filter := bson.M{partitionIDKey: changes.PartitionID, supplyBlockNumberKey: bson.M{"$not": bson.M{"$gte": changes.BlockNumber}}}
+	update := bson.M{
+		"$inc": delta.inc(),
+		"$set": bson.M{supplyBlockNumberKey: changes.BlockNumber},
+	}
+	if _, err := s.db.Collection(metadataCollectionName).UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to update supply: %w", err)
+	}
+	return nil
+}
+
+/*
+revertBillChanges reverts the changes of the blocks starting from "fromBlockNumber", newest block first, and
+deletes them. Reverting is repeatable, the supply is only reverted for the blocks it was updated with.
+*/
+func (s *MongoBlockStore) revertBillChanges(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$gte": fromBlockNumber}}
+	opts := options.Find().SetSort(bson.D{{Key: blockNumberKey, Value: -1}})
+	cursor, err := s.db.Collection(billChangesCollectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to query bill changes: %w", err)
+	}
+	var blocks []*domain.BillChanges
+	if err = cursor.All(ctx, &blocks); err != nil {
+		return fmt.Errorf("failed to decode bill changes: %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	supply, err := s.getSupply(ctx, partitionID)
+	if err != nil {
+		return err
+	}
+	var delta supplyDelta
+	for _, changes := range blocks {
+		if err = s.setBills(ctx, partitionID, changes.Bills, true); err != nil {
+			return err
+		}
+		if changes.BlockNumber <= supply.BlockNumber {
+			delta.add(newSupplyDelta(changes, true))
+		}
+	}
+	if supply.BlockNumber >= fromBlockNumber {
+		update := bson.M{
+			"$inc": delta.inc(),
+			"$set": bson.M{supplyBlockNumberKey: max(fromBlockNumber, 1) - 1},
+		}
+		supplyFilter := bson.M{partitionIDKey: partitionID, supplyBlockNumberKey: bson.M{"$gte": fromBlockNumber}}
+		if _, err = s.db.Collection(metadataCollectionName).UpdateOne(ctx, supplyFilter, update); err != nil {
+			return fmt.Errorf("failed to revert supply: %w", err)
+		}
+	}
+	if _, err = s.db.Collection(billChangesCollectionName).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete bill changes: %w", err)
+	}
+	return nil
+}
+
+// setBills sets the changed bills to their state after the block or before the block when reverting.
+func (s *MongoBlockStore) setBills(ctx context.Context, partitionID types.PartitionID, changes []*domain.BillChange, revert bool) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(changes))
+	var owners []hex.Bytes
+	for _, change := range changes {
+		bill := change.After
+		if revert {
+			bill = change.Before
+		}
+		filter := bson.M{partitionIDKey: partitionID, unitIDKey: change.UnitID}
+		if bill == nil {
+			models = append(models, mongo.NewDeleteOneModel().SetFilter(filter))
+		} else {
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(bill).SetUpsert(true))
+		}
+		for _, b := range []*domain.TrackedBill{change.Before, change.After} {
+			if b != nil && !b.Untracked {
+				owners = append(owners, b.OwnerID)
+			}
+		}
+	}
+	if _, err := s.db.Collection(billsCollectionName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true)); err != nil {
+		return fmt.Errorf("failed to set bills: %w", err)
+	}
+	return s.updateBalances(ctx, partitionID, owners)
+}
+
+// updateBalances recalculates the balances of the owners from the tracked bills, the owners without bills are deleted.
+func (s *MongoBlockStore) updateBalances(ctx context.Context, partitionID types.PartitionID, owners []hex.Bytes) error {
+	if len(owners) == 0 {
+		return nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{partitionIDKey: partitionID, ownerIDKey: bson.M{"$in": owners}, untrackedKey: false}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$" + ownerIDKey,
+			balanceKey:   bson.M{"$sum": "$" + valueKey},
+			billCountKey: bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := s.db.Collection(billsCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to sum balances: %w", err)
+	}
+	var sums []struct {
+		OwnerID   hex.Bytes `bson:"_id"`
+		Balance   int64     `bson:"balance"`
+		BillCount int64     `bson:"billcount"`
+	}
+	if err = cursor.All(ctx, &sums); err != nil {
+		return fmt.Errorf("failed to decode balances: %w", err)
+	}
+	balances := make(map[string]*domain.Balance, len(sums))
+	for _, sum := range sums {
+		balances[string(sum.OwnerID)] = &domain.Balance{PartitionID: partitionID, OwnerID: sum.OwnerID, Balance: uint64(sum.Balance), BillCount: uint64(sum.BillCount)}
+	}
+
+	models := make([]mongo.WriteModel, 0, len(owners))
+	for _, owner := range owners {
+		filter := bson.M{partitionIDKey: partitionID, ownerIDKey: owner}
+		if balance, ok := balances[string(owner)]; ok {
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(balance).SetUpsert(true))
+		} else {
+			models = append(models, mongo.NewDeleteOneModel().SetFilter(filter))
+		}
+	}
+	if _, err = s.db.Collection(balancesCollectionName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to set balances: %w", err)
+	}
+	return nil
+}
+
+// GetTopHolders returns up to "limit" owners with the largest balances, largest first.
+func (s *MongoBlockStore) GetTopHolders(ctx context.Context, partitionID types.PartitionID, limit int) ([]*domain.Balance, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: balanceKey, Value: -1}, {Key: ownerIDKey, Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := s.db.Collection(balancesCollectionName).Find(ctx, bson.M{partitionIDKey: partitionID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var balances []*domain.Balance
+	if err = cursor.All(ctx, &balances); err != nil {
+		return nil, fmt.Errorf("failed to decode balances: %w", err)
+	}
+	return balances, nil
+}
+
+/*
+GetBalanceDistribution returns the number of the owners and their total balance in the balance ranges starting at
+the boundaries, the last range is unbounded. The boundaries must be sorted in the ascending order, the ranges
+without owners are omitted.
+*/
+func (s *MongoBlockStore) GetBalanceDistribution(ctx context.Context, partitionID types.PartitionID, boundaries []uint64) ([]*domain.BalanceBucket, error) {
+	if len(boundaries) == 0 {
+		return nil, errors.New("no bucket boundaries")
+	}
+	// $bucket needs the upper bound of the last range, larger balances are counted in the default bucket
+	bounds := make(bson.A, 0, len(boundaries)+1)
+	for _, b := range boundaries {
+		bounds = append(bounds, int64(b))
+	}
+	bounds = append(bounds, int64(math.MaxInt64))
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{partitionIDKey: partitionID}}},
+		{{Key: "$bucket", Value: bson.M{
+			"groupBy":    "$" + balanceKey,
+			"boundaries": bounds,
+			"default":    int64(math.MaxInt64),
+			"output": bson.M{
+				holderCountKey: bson.M{"$sum": 1},
+				balanceKey:     bson.M{"$sum": "$" + balanceKey},
+			},
+		}}},
+	}
+	cursor, err := s.db.Collection(balancesCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance distribution: %w", err)
+	}
+	var results []struct {
+		Min         int64 `bson:"_id"`
+		HolderCount int64 `bson:"holdercount"`
+		Balance     int64 `bson:"balance"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode balance distribution: %w", err)
+	}
+
+	buckets := make([]*domain.BalanceBucket, 0, len(results))
+	for _, r := range results {
+		bucket := &domain.BalanceBucket{Min: uint64(r.Min), HolderCount: uint64(r.HolderCount), Balance: uint64(r.Balance)}
+		for i, b := range boundaries {
+			if b == bucket.Min && i+1 < len(boundaries) {
+				bucket.Max = boundaries[i+1]
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+/*
+GetMoneySupply returns the supply counters and the number of the owners of the money partition, the total supply
+is not stored. The counters which would be negative because of the transactions of the bills created before the
+first synced block are returned as zero.
+*/
+func (s *MongoBlockStore) GetMoneySupply(ctx context.Context, partitionID types.PartitionID) (*domain.MoneySupply, error) {
+	var metadata struct {
+		LatestBlockNumber uint64          `bson:"latestblocknumber"`
+		Supply            *supplyDocument `bson:"supply"`
+	}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&metadata)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("partition %s: %w", partitionID, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to query supply: %w", err)
+	}
+	holderCount, err := s.db.Collection(balancesCollectionName).CountDocuments(ctx, bson.M{partitionIDKey: partitionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count holders: %w", err)
+	}
+
+	supply := &domain.MoneySupply{PartitionID: partitionID, BlockNumber: metadata.LatestBlockNumber, HolderCount: uint64(holderCount)}
+	if metadata.Supply != nil {
+		supply.BillsValue = nonNegative(metadata.Supply.BillsValue)
+		supply.UntrackedBills = nonNegative(metadata.Supply.UntrackedBills)
+		supply.DustCollector = nonNegative(metadata.Supply.DustCollector)
+		supply.FeeCredit = nonNegative(metadata.Supply.FeeCredit)
+	}
+	return supply, nil
+}
+
+func (s *MongoBlockStore) getSupply(ctx context.Context, partitionID types.PartitionID) (*supplyDocument, error) {
+	var metadata struct {
+		Supply supplyDocument `bson:"supply"`
+	}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&metadata)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to query supply: %w", err)
+	}
+	return &metadata.Supply, nil
+}
+
+// newSupplyDelta returns the change of the supply made by the block, or the reverse change when reverting.
+func newSupplyDelta(changes *domain.BillChanges, revert bool) supplyDelta {
+	delta := supplyDelta{dustCollector: changes.DustCollector, feeCredit: changes.FeeCredit}
+	for _, change := range changes.Bills {
+		delta.billsValue += billValue(change.After) - billValue(change.Before)
+		delta.untrackedBills += untracked(change.After) - untracked(change.Before)
+	}
+	if revert {
+		return supplyDelta{billsValue: -delta.billsValue, untrackedBills: -delta.untrackedBills, dustCollector: -delta.dustCollector, feeCredit: -delta.feeCredit}
+	}
+	return delta
+}
+
+func (d *supplyDelta) add(other supplyDelta) {
+	d.billsValue += other.billsValue
+	d.untrackedBills += other.untrackedBills
+	d.dustCollector += other.dustCollector
+	d.feeCredit += other.feeCredit
+}
+
+func (d supplyDelta) inc() bson.M {
+	return bson.M{
+		supplyKey + ".billsvalue":     d.billsValue,
+		supplyKey + ".untrackedbills": d.untrackedBills,
+		supplyKey + ".dustcollector":  d.dustCollector,
+		supplyKey + ".feecredit":      d.feeCredit,
+	}
+}
+
+func billValue(bill *domain.TrackedBill) int64 {
+	if bill == nil || bill.Untracked {
+		return 0
+	}
+	return int64(bill.Value)
+}
+
+func untracked(bill *domain.TrackedBill) int64 {
+	if bill != nil && bill.Untracked {
+		return 1
+	}
+	return 0
+}
+
+func nonNegative(v int64) uint64 {
+	return uint64(max(v, 0))
+}
@@ -2,18 +2,19 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (s *MongoBlockStore) SetBlockInfo(ctx context.Context, blockInfo *domain.BlockInfo) error {
 	filter := bson.M{partitionIDKey: blockInfo.PartitionID, blockNumberKey: blockInfo.BlockNumber}
-	update := bson.M{"$set": blockInfo}
+	update := bson.M{"$set": newBlockDocument(blockInfo)}
 
 	_, err := s.db.Collection(blocksCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
 	if err != nil {
@@ -22,6 +23,9 @@ func (s *MongoBlockStore) SetBlockInfo(ctx context.Context, blockInfo *domain.Bl
 	return nil
 }
 
+// GetBlock returns the blocks with the given number of the partitions (all the partitions when "partitionIDs" is
+// empty). When none of the partitions has the block domain.ErrPruned is returned if the block of any of the partitions
+// has been pruned, the pruned blocks are not reported when the block of another partition is found.
 func (s *MongoBlockStore) GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error) {
 	filter := bson.M{blockNumberKey: blockNumber}
 	if len(partitionIDs) > 0 {
@@ -47,9 +51,65 @@ func (s *MongoBlockStore) GetBlock(ctx context.Context, blockNumber uint64, part
 		return nil, fmt.Errorf("cursor encountered an error: %w", err)
 	}
 
+	if len(blockMap) == 0 {
+		if len(partitionIDs) == 0 {
+			latestBlockNumbers, err := s.GetBlockNumbers(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			for partitionID := range latestBlockNumbers {
+				partitionIDs = append(partitionIDs, partitionID)
+			}
+		}
+		for _, partitionID := range partitionIDs {
+			if err = s.checkPruned(ctx, partitionID, blockNumber); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return blockMap, nil
 }
 
+// GetBlockByHash returns the block with the given block hash, domain.ErrNotFound when the block is not stored.
+func (s *MongoBlockStore) GetBlockByHash(ctx context.Context, blockHash []byte) (*domain.BlockInfo, error) {
+	var block domain.BlockInfo
+	err := s.db.Collection(blocksCollectionName).FindOne(ctx, bson.M{blockHashKey: blockHash}).Decode(&block)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query block by hash: %w", err)
+	}
+	return &block, nil
+}
+
+// GetBlocksByNumbers returns the stored blocks of the partition with given block numbers.
+func (s *MongoBlockStore) GetBlocksByNumbers(ctx context.Context, partitionID types.PartitionID, blockNumbers []uint64) ([]*domain.BlockInfo, error) {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$in": blockNumbers}}
+
+	cursor, err := s.db.Collection(blocksCollectionName).Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var blocks []*domain.BlockInfo
+	for cursor.Next(ctx) {
+		var block domain.BlockInfo
+		if err = cursor.Decode(&block); err != nil {
+			return nil, fmt.Errorf("failed to decode block: %w", err)
+		}
+		blocks = append(blocks, &block)
+	}
+
+	if err = cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor encountered an error: %w", err)
+	}
+
+	return blocks, nil
+}
+
 func (s *MongoBlockStore) GetLastBlocks(
 	ctx context.Context,
 	partitionIDs []types.PartitionID,
@@ -69,7 +129,7 @@ func (s *MongoBlockStore) GetLastBlocks(
 	}
 
 	for _, partitionID := range partitionIDs {
-		blocks, _, err := s.GetBlocksInRange(ctx, partitionID, math.MaxInt64, count, includeEmpty)
+		blocks, _, err := s.GetBlocksPage(ctx, partitionID, domain.PageRequest[uint64]{Limit: count}, includeEmpty)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get blocks for partition %d: %w", partitionID, err)
 		}
@@ -79,51 +139,43 @@ func (s *MongoBlockStore) GetLastBlocks(
 	return blockMap, nil
 }
 
-func (s *MongoBlockStore) GetBlocksInRange(
+// GetBlocksPages returns the pages of the blocks of the partitions, latest block first, with a query per partition.
+// The page key is the block number, blocks without transactions are skipped unless "includeEmpty" is set.
+func (s *MongoBlockStore) GetBlocksPages(
 	ctx context.Context,
-	partitionID types.PartitionID,
-	latestBlock uint64,
-	count int,
+	partitionIDs []types.PartitionID,
+	page domain.PageRequest[uint64],
 	includeEmpty bool,
-) ([]*domain.BlockInfo, uint64, error) {
-	filter := bson.M{
-		partitionIDKey: partitionID,
-		blockNumberKey: bson.M{"$lte": latestBlock},
+) (map[types.PartitionID][]*domain.BlockInfo, error) {
+	pages := make(map[types.PartitionID][]*domain.BlockInfo, len(partitionIDs))
+	for _, partitionID := range partitionIDs {
+		blocks, _, err := s.GetBlocksPage(ctx, partitionID, page, includeEmpty)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blocks of partition %d: %w", partitionID, err)
+		}
+		pages[partitionID] = blocks
 	}
+	return pages, nil
+}
 
+// GetBlocksPage returns a page of the blocks of the partition, latest block first.
+// The page key is the block number, blocks without transactions are skipped unless "includeEmpty" is set.
+func (s *MongoBlockStore) GetBlocksPage(
+	ctx context.Context,
+	partitionID types.PartitionID,
+	page domain.PageRequest[uint64],
+	includeEmpty bool,
+) ([]*domain.BlockInfo, bool, error) {
+	filter := bson.M{partitionIDKey: partitionID}
 	if !includeEmpty {
 		filter[txCountKey] = bson.M{
 			"$gt": 0,
 		}
 	}
 
-	opts := options.Find().
-		SetSort(bson.D{{Key: blockNumberKey, Value: -1}}).
-		SetLimit(int64(count))
-
-	cursor, err := s.db.Collection(blocksCollectionName).Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query blocks: %w", err)
+	var key any
+	if page.Key != nil {
+		key = *page.Key
 	}
-	defer cursor.Close(ctx)
-
-	var blocks []*domain.BlockInfo
-	for cursor.Next(ctx) {
-		var block domain.BlockInfo
-		if err = cursor.Decode(&block); err != nil {
-			return nil, 0, fmt.Errorf("failed to decode block: %w", err)
-		}
-		blocks = append(blocks, &block)
-	}
-
-	if len(blocks) == 0 {
-		return blocks, 0, nil
-	}
-
-	prevBlockNumber := uint64(0)
-	if len(blocks) == count {
-		prevBlockNumber = blocks[len(blocks)-1].BlockNumber - 1
-	}
-
-	return blocks, prevBlockNumber, nil
+	return findPage[domain.BlockInfo](ctx, s.db.Collection(blocksCollectionName), filter, blockNumberKey, key, page.Backward, page.Limit)
 }
@@ -13,10 +13,19 @@ import (
 )
 
 const (
-	databaseName           = "blockExplorerDB"
-	blocksCollectionName   = "blocks"
-	txCollectionName       = "transactions"
-	metadataCollectionName = "metadata"
+	databaseName                       = "blockExplorerDB"
+	blocksCollectionName               = "blocks"
+	txCollectionName                   = "transactions"
+	metadataCollectionName             = "metadata"
+	rawBlocksCollectionName            = "rawblocks"
+	submittedTxsCollectionName         = "submittedtxs"
+	webhookSubscriptionsCollectionName = "webhooksubscriptions"
+	webhookDeliveriesCollectionName    = "webhookdeliveries"
+	tokenTextsCollectionName           = "tokentexts"
+	billsCollectionName                = "bills"
+	balancesCollectionName             = "balances"
+	billChangesCollectionName          = "billchanges"
+	partitionsCollectionName           = "partitions"
 
 	partitionIDKey       = "partitionid"
 	blockNumberKey       = "blocknumber"
@@ -25,13 +34,68 @@ const (
 	txHashesKey          = "txhashes"
 	txCountKey           = "txcount"
 	targetUnitsKey       = "transaction.servermetadata.targetunits"
+	actualFeeKey         = "transaction.servermetadata.actualfee"
 	latestBlockNumberKey = "latestblocknumber"
+	prunedBelowKey       = "prunedbelow"
+	timestampKey         = "timestamp"
+	partitionTypeIDKey   = "partitiontypeid"
+	chainIdentityKey     = "chainidentity"
+	resetAtKey           = "resetat"
+	submittedAtKey       = "submittedat"
+	ownerKey             = "owner"
+	subscriptionIDKey    = "subscriptionid"
+	statusKey            = "status"
+	attemptsKey          = "attempts"
+	nextAttemptAtKey     = "nextattemptat"
+	eventCreatedAtKey    = "event.createdat"
+	expireAtKey          = "expireat"
+	searchKeysKey        = "searchkeys"
+	blockHashKey         = "blockhash"
+	unitIDKey            = "unitid"
+	trigramsKey          = "trigrams"
+	scoreKey             = "score"
+	ownerIDKey           = "ownerid"
+	valueKey             = "value"
+	untrackedKey         = "untracked"
+	balanceKey           = "balance"
+	billCountKey         = "billcount"
+	holderCountKey       = "holdercount"
+	supplyKey            = "supply"
+	supplyBlockNumberKey = "supply.blocknumber"
+	proposerIDKey        = "proposerid"
+	feesKey              = "fees"
+	shardKey             = "shard"
+	blockCountKey        = "blockcount"
+	blockStatsKey        = "blockstats"
+	billsKey             = "bills"
+	billUnitIDKey        = "bills.unitid"
+	billOwnerBeforeKey   = "bills.before.ownerid"
+	billOwnerAfterKey    = "bills.after.ownerid"
+
+	// archiveCollectionSuffix is appended to the collection name to get the name of the collection
+	// where the data of the reset partitions is archived
+	archiveCollectionSuffix = "_archive"
 
 	connectTimeout       = time.Minute
 	connectionRetries    = 5
 	connectionRetryDelay = 5 * time.Second
+
+	// submittedTxsTTL is how long the transactions submitted through the explorer are tracked
+	submittedTxsTTL = 7 * 24 * time.Hour
 )
 
+// blockDataCollections are the collections holding per block data of partitions (documents
+// with partitionid and blocknumber fields) which is pruned, rolled back and reset together
+var blockDataCollections = []string{txCollectionName, blocksCollectionName}
+
+// unitDataCollections are the collections holding data of the units created in the partition blocks (documents
+// with partitionid and blocknumber fields) which is rolled back and reset with the blocks but never pruned
+var unitDataCollections = []string{tokenTextsCollectionName}
+
+// balanceCollections are the collections holding the bills and the balances of the money partitions (documents with
+// partitionid field) derived from all the blocks, which is reverted on rollback and reset with the blocks
+var balanceCollections = []string{billsCollectionName, balancesCollectionName, billChangesCollectionName}
+
 type MongoBlockStore struct {
 	db *mongo.Database
 }
@@ -100,6 +164,15 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: txCountKey, Value: 1}, {Key: blockNumberKey, Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: timestampKey, Value: 1}}, // for retention by age
+		},
+		{
+			Keys: bson.D{{Key: searchKeysKey, Value: 1}}, // for prefix search
+		},
+		{
+			Keys: bson.D{{Key: blockHashKey, Value: 1}}, // for GetBlockByHash query
+		},
 	})
 	if err != nil {
 		return err
@@ -119,6 +192,104 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		}},
 		{Keys: bson.D{{Key: targetUnitsKey, Value: 1}}},
 		{Keys: bson.D{{Key: blockNumberKey, Value: 1}, {Key: partitionIDKey, Value: 1}}},
+		{Keys: bson.D{{Key: searchKeysKey, Value: 1}}}, // for prefix search
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(tokenTextsCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: partitionIDKey, Value: 1}, {Key: unitIDKey, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: blockNumberKey, Value: 1}}}, // for rollback
+		{Keys: bson.D{{Key: trigramsKey, Value: 1}}},                                     // for text search
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(billsCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: partitionIDKey, Value: 1}, {Key: unitIDKey, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: ownerIDKey, Value: 1}}}, // for balances
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(balancesCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: partitionIDKey, Value: 1}, {Key: ownerIDKey, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: balanceKey, Value: -1}}}, // for top holders and distribution
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(billChangesCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: partitionIDKey, Value: 1}, {Key: blockNumberKey, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// for the bills of the owner
+		{Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: billOwnerBeforeKey, Value: 1}}},
+		{Keys: bson.D{{Key: partitionIDKey, Value: 1}, {Key: billOwnerAfterKey, Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(partitionsCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: partitionIDKey, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(rawBlocksCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: partitionIDKey, Value: 1}, {Key: blockNumberKey, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(submittedTxsCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: txOrderHashKey, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: submittedAtKey, Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(submittedTxsTTL.Seconds())),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(webhookSubscriptionsCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: ownerKey, Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(webhookDeliveriesCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: statusKey, Value: 1}, {Key: nextAttemptAtKey, Value: 1}}},
+		{Keys: bson.D{{Key: subscriptionIDKey, Value: 1}, {Key: statusKey, Value: 1}, {Key: eventCreatedAtKey, Value: -1}}},
+		{
+			// delivered events are removed at the expireat time
+			Keys:    bson.D{{Key: expireAtKey, Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
 	})
 	return err
 }
@@ -206,6 +377,29 @@ func (s *MongoBlockStore) ResetCollections(ctx context.Context) error {
 	if err := s.db.Collection(metadataCollectionName).Drop(ctx); err != nil {
 		return err
 	}
+	if err := s.db.Collection(rawBlocksCollectionName).Drop(ctx); err != nil {
+		return err
+	}
+	if err := s.db.Collection(submittedTxsCollectionName).Drop(ctx); err != nil {
+		return err
+	}
+	if err := s.db.Collection(webhookSubscriptionsCollectionName).Drop(ctx); err != nil {
+		return err
+	}
+	if err := s.db.Collection(webhookDeliveriesCollectionName).Drop(ctx); err != nil {
+		return err
+	}
+	if err := s.db.Collection(tokenTextsCollectionName).Drop(ctx); err != nil {
+		return err
+	}
+	for _, collection := range balanceCollections {
+		if err := s.db.Collection(collection).Drop(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.db.Collection(partitionsCollectionName).Drop(ctx); err != nil {
+		return err
+	}
 	return s.initialize(ctx)
 }
 
@@ -216,6 +410,29 @@ func (s *MongoBlockStore) initialize(ctx context.Context) error {
 	if err := ensureCollectionExists(ctx, s.db, txCollectionName); err != nil {
 		return err
 	}
+	if err := ensureCollectionExists(ctx, s.db, rawBlocksCollectionName); err != nil {
+		return err
+	}
+	if err := ensureCollectionExists(ctx, s.db, submittedTxsCollectionName); err != nil {
+		return err
+	}
+	if err := ensureCollectionExists(ctx, s.db, webhookSubscriptionsCollectionName); err != nil {
+		return err
+	}
+	if err := ensureCollectionExists(ctx, s.db, webhookDeliveriesCollectionName); err != nil {
+		return err
+	}
+	if err := ensureCollectionExists(ctx, s.db, tokenTextsCollectionName); err != nil {
+		return err
+	}
+	for _, collection := range balanceCollections {
+		if err := ensureCollectionExists(ctx, s.db, collection); err != nil {
+			return err
+		}
+	}
+	if err := ensureCollectionExists(ctx, s.db, partitionsCollectionName); err != nil {
+		return err
+	}
 	if err := createMetadataCollection(ctx, s.db); err != nil {
 		return err
 	}
@@ -225,6 +442,9 @@ func (s *MongoBlockStore) initialize(ctx context.Context) error {
 	if err := s.MigrateTxCount(ctx); err != nil {
 		return fmt.Errorf("failed to run migration: %w", err)
 	}
+	if err := s.MigrateBlockFees(ctx); err != nil {
+		return fmt.Errorf("failed to run block fees migration: %w", err)
+	}
 
 	return nil
 }
@@ -3,7 +3,9 @@
 package mongodb
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"testing"
 	"time"
@@ -15,6 +17,8 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	mongocontainer "github.com/testcontainers/testcontainers-go/modules/mongodb"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type MongoBillStoreSuite struct {
@@ -111,14 +115,23 @@ func (suite *MongoBillStoreSuite) TestMongoBillStore_GetBlock_NoPartitionsSpecif
 	require.EqualValues(suite.T(), 2, blockMap[partition2].BlockNumber)
 }
 
-func (suite *MongoBillStoreSuite) TestMongoBillStore_GetBlockRange() {
-	blocks, prevBlockNumber, err := suite.store.GetBlocksInRange(suite.ctx, partition1, 4, 2, true)
+func (suite *MongoBillStoreSuite) TestMongoBillStore_GetBlocksPage() {
+	key := uint64(5)
+	blocks, hasMore, err := suite.store.GetBlocksPage(suite.ctx, partition1, domain.PageRequest[uint64]{Key: &key, Limit: 2}, true)
 	require.NoError(suite.T(), err)
 	require.Len(suite.T(), blocks, 2)
-	require.EqualValues(suite.T(), 2, prevBlockNumber)
+	require.True(suite.T(), hasMore)
 	require.EqualValues(suite.T(), partition1, blocks[0].PartitionID)
 	require.EqualValues(suite.T(), 4, blocks[0].BlockNumber)
 	require.EqualValues(suite.T(), 3, blocks[1].BlockNumber)
+
+	key = 3
+	blocks, hasMore, err = suite.store.GetBlocksPage(suite.ctx, partition1, domain.PageRequest[uint64]{Key: &key, Backward: true, Limit: 2}, true)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), blocks, 2)
+	require.True(suite.T(), hasMore)
+	require.EqualValues(suite.T(), 5, blocks[0].BlockNumber)
+	require.EqualValues(suite.T(), 4, blocks[1].BlockNumber)
 }
 
 func (suite *MongoBillStoreSuite) TestMongoBillStore_GetLastBlocks() {
@@ -196,6 +209,53 @@ func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxByTxOrderHash() {
 	require.EqualValues(suite.T(), txInfo.TxOrderHash, txHash)
 }
 
+func (suite *MongoBillStoreSuite) TestMongoBillStore_PruneBlocks() {
+	require.NoError(suite.T(), suite.store.PruneBlocks(suite.ctx, partition1, 3))
+
+	prunedBelow, err := suite.store.GetPrunedBelow(suite.ctx, partition1)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 3, prunedBelow)
+	lowest, err := suite.store.GetLowestBlockNumbers(suite.ctx, []types.PartitionID{partition1, partition2})
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 3, lowest[partition1])
+	require.EqualValues(suite.T(), 1, lowest[partition2])
+
+	_, err = suite.store.GetBlock(suite.ctx, 2, []types.PartitionID{partition1})
+	require.ErrorIs(suite.T(), err, domain.ErrPruned)
+	_, err = suite.store.GetTxsByBlockNumber(suite.ctx, 2, partition1)
+	require.ErrorIs(suite.T(), err, domain.ErrPruned)
+
+	// block of the other partition is returned
+	blockMap, err := suite.store.GetBlock(suite.ctx, 2, nil)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), blockMap, 1)
+	require.NotNil(suite.T(), blockMap[partition2])
+
+	blockMap, err = suite.store.GetBlock(suite.ctx, 3, []types.PartitionID{partition1})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), blockMap, 1)
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_RetentionCutoff() {
+	require.NoError(suite.T(), suite.store.SetBlockNumber(suite.ctx, partition1, blockCount))
+	for i := uint64(1); i <= blockCount; i++ {
+		require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, &domain.BlockInfo{PartitionID: partition1, BlockNumber: i, Timestamp: 1000 + i*60}))
+	}
+
+	cutoff, err := suite.store.retentionCutoff(suite.ctx, partition1, RetentionPolicy{Blocks: 2}, time.Now())
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), blockCount-1, cutoff)
+
+	// blocks 3, 4 and 5 are not older than 150 seconds
+	cutoff, err = suite.store.retentionCutoff(suite.ctx, partition1, RetentionPolicy{Age: 150 * time.Second}, time.Unix(1000+5*60, 0))
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 3, cutoff)
+
+	cutoff, err = suite.store.retentionCutoff(suite.ctx, partition1, RetentionPolicy{Age: time.Minute}, time.Unix(100000, 0))
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), blockCount, cutoff)
+}
+
 func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxsByBlockNumber() {
 	txList, err := suite.store.GetTxsByBlockNumber(suite.ctx, 3, partition1)
 	require.NoError(suite.T(), err)
@@ -231,9 +291,37 @@ func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxsByUnitID() {
 	require.EqualValues(suite.T(), txList[1].Transaction.ServerMetadata.TargetUnits, []types.UnitID{unit4, unit5})
 }
 
+func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxsPagesByUnitIDs() {
+	unit3 := types.UnitID("unit3")
+	unit4 := types.UnitID("unit4")
+	unit5 := types.UnitID("unit5")
+	txInfoUnits34 := testTxInfo(partition1, testTxRecordHash(partition1, 3, 1), 3, []types.UnitID{unit3, unit4})
+	txInfoUnits45 := testTxInfo(partition1, testTxRecordHash(partition1, 5, 2), 5, []types.UnitID{unit4, unit5})
+	require.NoError(suite.T(), suite.store.SetTxInfo(suite.ctx, &txInfoUnits34))
+	require.NoError(suite.T(), suite.store.SetTxInfo(suite.ctx, &txInfoUnits45))
+
+	// the transactions are limited per unit, the units without transactions are omitted
+	pages, err := suite.store.GetTxsPagesByUnitIDs(suite.ctx, []types.UnitID{unit3, unit4, types.UnitID("unit6")}, domain.PageRequest[primitive.ObjectID]{Limit: 1})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), pages, 2)
+	require.Len(suite.T(), pages[string(unit3)], 1)
+	require.EqualValues(suite.T(), txInfoUnits34.TxRecordHash, pages[string(unit3)][0].TxRecordHash)
+	require.Len(suite.T(), pages[string(unit4)], 1)
+	require.EqualValues(suite.T(), txInfoUnits45.TxRecordHash, pages[string(unit4)][0].TxRecordHash)
+	require.EqualValues(suite.T(), []types.UnitID{unit4, unit5}, pages[string(unit4)][0].Transaction.ServerMetadata.TargetUnits)
+
+	key := pages[string(unit4)][0].ID
+	pages, err = suite.store.GetTxsPagesByUnitIDs(suite.ctx, []types.UnitID{unit4, unit5}, domain.PageRequest[primitive.ObjectID]{Key: &key, Limit: 10})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), pages, 1)
+	require.Len(suite.T(), pages[string(unit4)], 1)
+	require.EqualValues(suite.T(), txInfoUnits34.TxRecordHash, pages[string(unit4)][0].TxRecordHash)
+}
+
 func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxsPage() {
-	txList, previousID, err := suite.store.GetTxsPage(suite.ctx, partition1, "", 5)
+	txList, hasMore, err := suite.store.GetTxsPage(suite.ctx, partition1, domain.PageRequest[primitive.ObjectID]{Limit: 5})
 	require.NoError(suite.T(), err)
+	require.True(suite.T(), hasMore)
 	require.Len(suite.T(), txList, 5)
 	require.EqualValues(suite.T(), 5, txList[0].BlockNumber)
 	require.EqualValues(suite.T(), 5, txList[1].BlockNumber)
@@ -241,17 +329,161 @@ func (suite *MongoBillStoreSuite) TestMongoBillStore_GetTxsPage() {
 	require.EqualValues(suite.T(), 4, txList[3].BlockNumber)
 	require.EqualValues(suite.T(), 4, txList[4].BlockNumber)
 
-	txList, previousID, err = suite.store.GetTxsPage(suite.ctx, partition1, previousID, 2)
+	key := txList[4].ID
+	txList, hasMore, err = suite.store.GetTxsPage(suite.ctx, partition1, domain.PageRequest[primitive.ObjectID]{Key: &key, Limit: 2})
 	require.NoError(suite.T(), err)
+	require.True(suite.T(), hasMore)
 	require.Len(suite.T(), txList, 2)
 	require.EqualValues(suite.T(), 4, txList[0].BlockNumber)
 	require.EqualValues(suite.T(), 3, txList[1].BlockNumber)
 
-	txList, previousID, err = suite.store.GetTxsPage(suite.ctx, partition1, previousID, 200)
+	secondPage := txList
+	key = txList[1].ID
+	txList, hasMore, err = suite.store.GetTxsPage(suite.ctx, partition1, domain.PageRequest[primitive.ObjectID]{Key: &key, Limit: 200})
 	require.NoError(suite.T(), err)
-	require.Len(suite.T(), txList, 8)
-	require.EqualValues(suite.T(), 3, txList[0].BlockNumber)
+	require.False(suite.T(), hasMore)
+	require.Len(suite.T(), txList, 7)
 	require.EqualValues(suite.T(), 1, txList[len(txList)-1].BlockNumber)
+
+	// paging backward returns the previous page
+	key = txList[0].ID
+	txList, hasMore, err = suite.store.GetTxsPage(suite.ctx, partition1, domain.PageRequest[primitive.ObjectID]{Key: &key, Backward: true, Limit: 2})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), hasMore)
+	require.Equal(suite.T(), secondPage, txList)
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_FindByPrefix() {
+	txHash := testTxRecordHash(partition1, 3, 2)
+	prefix := hex.EncodeToString(txHash[:len(txHash)-1])
+
+	txList, err := suite.store.FindTxsByPrefix(suite.ctx, prefix, nil, 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), txList, 1)
+	require.EqualValues(suite.T(), txHash, txList[0].TxRecordHash)
+
+	// target units "unit1" and "unit2" of all the transactions of partition 2
+	txList, err = suite.store.FindTxsByPrefix(suite.ctx, hex.EncodeToString([]byte("unit")), []types.PartitionID{partition2}, 4)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), txList, 4)
+	require.EqualValues(suite.T(), partition2, txList[0].PartitionID)
+
+	blockHash := []byte{0xab, 0xcd, 0xef, 0x01}
+	require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, &domain.BlockInfo{BlockHash: blockHash, PartitionID: partition1, BlockNumber: blockCount + 1}))
+	blocks, err := suite.store.FindBlocksByPrefix(suite.ctx, "abcdef", nil, 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), blocks, 1)
+	require.EqualValues(suite.T(), blockHash, blocks[0].BlockHash)
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_GetBlockByHash() {
+	blockHash := []byte{0xab, 0xcd, 0xef, 0x01}
+	require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, &domain.BlockInfo{BlockHash: blockHash, PartitionID: partition2, BlockNumber: blockCount + 1}))
+
+	block, err := suite.store.GetBlockByHash(suite.ctx, blockHash)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), partition2, block.PartitionID)
+	require.EqualValues(suite.T(), blockCount+1, block.BlockNumber)
+
+	_, err = suite.store.GetBlockByHash(suite.ctx, []byte{1})
+	require.ErrorIs(suite.T(), err, domain.ErrNotFound)
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_FindTokenTexts() {
+	alpha := &domain.TokenText{UnitID: types.UnitID{1}, PartitionID: partition2, BlockNumber: 1, Kind: domain.TokenTextFungibleTokenType, Symbol: "ALPHA", Name: "Alpha coin"}
+	cat := &domain.TokenText{UnitID: types.UnitID{2}, PartitionID: partition2, BlockNumber: 2, Kind: domain.TokenTextNonFungibleToken, Name: "Cat #1", URI: "ipfs://cat1"}
+	require.NoError(suite.T(), suite.store.SetTokenTexts(suite.ctx, []*domain.TokenText{alpha, cat}))
+	// the text of the unit is replaced
+	require.NoError(suite.T(), suite.store.SetTokenTexts(suite.ctx, []*domain.TokenText{cat}))
+
+	texts, err := suite.store.FindTokenTexts(suite.ctx, "alpa", nil, 10)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.TokenText{alpha}, texts)
+
+	texts, err = suite.store.FindTokenTexts(suite.ctx, "CAT", []types.PartitionID{partition1}, 10)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), texts)
+
+	require.NoError(suite.T(), suite.store.RollbackBlocks(suite.ctx, partition2, 2))
+	texts, err = suite.store.FindTokenTexts(suite.ctx, "cat", nil, 10)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), texts, "texts of the rolled back blocks are deleted")
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_Balances() {
+	ownerX, ownerY := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	billA := &domain.TrackedBill{PartitionID: partition1, UnitID: types.UnitID{0xA}, OwnerID: ownerX, Value: 100}
+	billB := &domain.TrackedBill{PartitionID: partition1, UnitID: types.UnitID{0xB}, OwnerID: ownerX, Value: 5}
+	_, err := suite.store.GetBlockNumber(suite.ctx, partition1)
+	require.NoError(suite.T(), err)
+
+	block1 := &domain.BillChanges{PartitionID: partition1, BlockNumber: 1, Bills: []*domain.BillChange{
+		{UnitID: billA.UnitID, After: billA},
+		{UnitID: billB.UnitID, After: billB},
+		{UnitID: types.UnitID{0xC}, After: &domain.TrackedBill{PartitionID: partition1, UnitID: types.UnitID{0xC}, Untracked: true}},
+	}}
+	require.NoError(suite.T(), suite.store.ApplyBillChanges(suite.ctx, block1))
+	// applying the block again does not change the supply
+	require.NoError(suite.T(), suite.store.ApplyBillChanges(suite.ctx, block1))
+
+	movedA := &domain.TrackedBill{PartitionID: partition1, UnitID: billA.UnitID, OwnerID: ownerY, Value: 80}
+	block2 := &domain.BillChanges{PartitionID: partition1, BlockNumber: 2, FeeCredit: 20, Bills: []*domain.BillChange{
+		{UnitID: billA.UnitID, Before: billA, After: movedA},
+	}}
+	require.NoError(suite.T(), suite.store.ApplyBillChanges(suite.ctx, block2))
+
+	// the owner has owned the transferred bill
+	unitIDs, err := suite.store.GetOwnerBillIDs(suite.ctx, partition1, ownerX)
+	require.NoError(suite.T(), err)
+	require.ElementsMatch(suite.T(), []types.UnitID{billA.UnitID, billB.UnitID}, unitIDs)
+	unitIDs, err = suite.store.GetOwnerBillIDs(suite.ctx, partition1, ownerY)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []types.UnitID{billA.UnitID}, unitIDs)
+
+	holders, err := suite.store.GetTopHolders(suite.ctx, partition1, 10)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.Balance{
+		{PartitionID: partition1, OwnerID: ownerY, Balance: 80, BillCount: 1},
+		{PartitionID: partition1, OwnerID: ownerX, Balance: 5, BillCount: 1},
+	}, holders)
+
+	buckets, err := suite.store.GetBalanceDistribution(suite.ctx, partition1, []uint64{0, 10, 100})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.BalanceBucket{
+		{Min: 0, Max: 10, HolderCount: 1, Balance: 5},
+		{Min: 10, Max: 100, HolderCount: 1, Balance: 80},
+	}, buckets)
+
+	supply, err := suite.store.GetMoneySupply(suite.ctx, partition1)
+	require.NoError(suite.T(), err)
+	require.Zero(suite.T(), supply.TotalSupply, "total supply is reported by the partition")
+	require.EqualValues(suite.T(), 85, supply.BillsValue)
+	require.EqualValues(suite.T(), 20, supply.FeeCredit)
+	require.EqualValues(suite.T(), 1, supply.UntrackedBills)
+	require.EqualValues(suite.T(), 2, supply.HolderCount)
+
+	require.NoError(suite.T(), suite.store.RollbackBlocks(suite.ctx, partition1, 2))
+	holders, err = suite.store.GetTopHolders(suite.ctx, partition1, 10)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.Balance{{PartitionID: partition1, OwnerID: ownerX, Balance: 105, BillCount: 2}}, holders)
+	supply, err = suite.store.GetMoneySupply(suite.ctx, partition1)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 105, supply.BillsValue)
+	require.Zero(suite.T(), supply.FeeCredit)
+	require.EqualValues(suite.T(), 1, supply.HolderCount)
+
+	bills, err := suite.store.GetTrackedBills(suite.ctx, partition1, []types.UnitID{billA.UnitID})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.TrackedBill{billA}, bills)
+
+	// the changes of the pruned blocks are deleted, the bills are kept
+	require.NoError(suite.T(), suite.store.PruneBlocks(suite.ctx, partition1, 2))
+	count, err := suite.store.db.Collection(billChangesCollectionName).CountDocuments(suite.ctx, bson.M{partitionIDKey: partition1})
+	require.NoError(suite.T(), err)
+	require.Zero(suite.T(), count)
+	bills, err = suite.store.GetTrackedBills(suite.ctx, partition1, []types.UnitID{billA.UnitID})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.TrackedBill{billA}, bills)
 }
 
 func initTestDB(t *testing.T, ctx context.Context, store *MongoBlockStore) {
@@ -317,3 +549,79 @@ func testTxRecordHash(partitionID types.PartitionID, blockNumber, txNumber int)
 func testTxOrderHash(partitionID types.PartitionID, blockNumber, txNumber int) []byte {
 	return []byte(fmt.Sprintf("p%db%dtx%d_order", partitionID, blockNumber, txNumber))
 }
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_GetProposerStats() {
+	partitionID := types.PartitionID(3)
+	for _, b := range []*domain.BlockInfo{
+		{PartitionID: partitionID, BlockNumber: 1, ProposerID: "node1", TxCount: 2, Fees: 3, Timestamp: 100},
+		{PartitionID: partitionID, BlockNumber: 2, ProposerID: "node2", Timestamp: 101},
+		{PartitionID: partitionID, BlockNumber: 4, ProposerID: "node1", Timestamp: 103},
+		{PartitionID: partitionID, BlockNumber: 5, ProposerID: "node1", TxCount: 1, Fees: 1, Timestamp: 104},
+	} {
+		require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, b))
+	}
+
+	stats, err := suite.store.GetProposerStats(suite.ctx, partitionID, 0)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*domain.ProposerStats{
+		{NodeID: "node1", BlocksProposed: 3, EmptyBlocks: 1, TxCount: 3, Fees: 4, FirstBlockNumber: 1, LastBlockNumber: 5, LastProposedAt: 104},
+		{NodeID: "node2", BlocksProposed: 1, EmptyBlocks: 1, FirstBlockNumber: 2, LastBlockNumber: 2, LastProposedAt: 101},
+	}, stats)
+
+	stats, err = suite.store.GetProposerStats(suite.ctx, partitionID, 102)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), stats, 1)
+	require.EqualValues(suite.T(), 2, stats[0].BlocksProposed)
+}
+
+func (suite *MongoBillStoreSuite) TestMongoBillStore_PartitionInfo() {
+	partitionID := types.PartitionID(4)
+	for _, b := range []*domain.BlockInfo{
+		{PartitionID: partitionID, BlockNumber: 1},
+		{PartitionID: partitionID, BlockNumber: 2},
+	} {
+		require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, b))
+	}
+	require.NoError(suite.T(), suite.store.SetBlockNumber(suite.ctx, partitionID, 2))
+
+	stats, err := suite.store.GetPartitionStats(suite.ctx, partitionID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), &domain.PartitionStats{FirstBlockNumber: 1, LatestBlockNumber: 2, BlockCount: 2, Shards: []string{""}}, stats)
+
+	// the counts are updated with the new blocks, the pruned blocks are subtracted and the rolled back blocks recounted
+	require.NoError(suite.T(), suite.store.SetBlockInfo(suite.ctx, &domain.BlockInfo{PartitionID: partitionID, BlockNumber: 3, TxCount: 2}))
+	require.NoError(suite.T(), suite.store.SetBlockNumber(suite.ctx, partitionID, 3))
+	stats, err = suite.store.GetPartitionStats(suite.ctx, partitionID)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 3, stats.BlockCount)
+	require.EqualValues(suite.T(), 2, stats.TxCount)
+	require.NoError(suite.T(), suite.store.PruneBlocks(suite.ctx, partitionID, 2))
+	require.NoError(suite.T(), suite.store.PruneBlocks(suite.ctx, partitionID, 2))
+	stats, err = suite.store.GetPartitionStats(suite.ctx, partitionID)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 2, stats.FirstBlockNumber)
+	require.EqualValues(suite.T(), 2, stats.BlockCount)
+	require.NoError(suite.T(), suite.store.RollbackBlocks(suite.ctx, partitionID, 3))
+	stats, err = suite.store.GetPartitionStats(suite.ctx, partitionID)
+	require.NoError(suite.T(), err)
+	require.EqualValues(suite.T(), 2, stats.LatestBlockNumber)
+	require.EqualValues(suite.T(), 1, stats.BlockCount)
+	require.Zero(suite.T(), stats.TxCount)
+
+	_, err = suite.store.GetPartitionInfo(suite.ctx, partitionID)
+	require.ErrorIs(suite.T(), err, domain.ErrNotFound)
+
+	updatedAt := time.Now().UTC().Truncate(time.Millisecond)
+	info := &domain.PartitionInfo{PartitionID: partitionID, TypeName: "tokens", Shards: []string{""}, Nodes: []*domain.NodeStatus{}, LatestBlockNumber: 2, UpdatedAt: updatedAt}
+	require.NoError(suite.T(), suite.store.SetPartitionInfo(suite.ctx, info))
+	info.RoundNumber = 5
+	require.NoError(suite.T(), suite.store.SetPartitionInfo(suite.ctx, info))
+
+	stored, err := suite.store.GetPartitionInfo(suite.ctx, partitionID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), info, stored)
+
+	infos, err := suite.store.GetPartitionInfos(suite.ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), infos, 1)
+}
@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+findPage queries a page of the documents matching the filter, ordered by the "sortKey" descending.
+Page contains up to "limit" documents with the sort key value less than "key" (greater than "key" when
+"backward" is set), nil "key" means the first page. Returns the documents and whether there are more
+documents in the direction of the paging.
+*/
+func findPage[T any](
+	ctx context.Context,
+	coll *mongo.Collection,
+	filter bson.M,
+	sortKey string,
+	key any,
+	backward bool,
+	limit int,
+) ([]*T, bool, error) {
+	order, op := -1, "$lt"
+	if backward {
+		order, op = 1, "$gt"
+	}
+	if key != nil {
+		filter[sortKey] = bson.M{op: key}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortKey, Value: order}}).
+		SetLimit(int64(limit + 1)) // fetch one extra to find out whether there are more documents
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query %s: %w", coll.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []*T
+	for cursor.Next(ctx) {
+		var item T
+		if err = cursor.Decode(&item); err != nil {
+			return nil, false, fmt.Errorf("failed to decode %s document: %w", coll.Name(), err)
+		}
+		items = append(items, &item)
+	}
+	if err = cursor.Err(); err != nil {
+		return nil, false, fmt.Errorf("cursor encountered an error: %w", err)
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+	return items, hasMore, nil
+}
@@ -0,0 +1,235 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// shardLookupBlocks is the number of the latest blocks the shards of the partition are collected from
+const shardLookupBlocks = 1000
+
+/*
+blockStats are the counts of the stored blocks and transactions of the partition in the block range [From, BlockNumber],
+kept in the metadata of the partition. The blocks stored after the range are added to the counts when the statistics
+are requested, the pruned blocks are subtracted from the counts and on rollback the counts are removed and counted
+again from all the blocks.
+*/
+type blockStats struct {
+	From        uint64 `bson:"from"`
+	BlockNumber uint64 `bson:"blocknumber"`
+	BlockCount  uint64 `bson:"blockcount"`
+	TxCount     uint64 `bson:"txcount"`
+}
+
+// SetPartitionInfo stores the registry entry of the partition, replacing the previous entry.
+func (s *MongoBlockStore) SetPartitionInfo(ctx context.Context, info *domain.PartitionInfo) error {
+	filter := bson.M{partitionIDKey: info.PartitionID}
+	_, err := s.db.Collection(partitionsCollectionName).ReplaceOne(ctx, filter, info, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert partition info: %w", err)
+	}
+	return nil
+}
+
+// GetPartitionInfos returns the registry entries of all the partitions ordered by the partition ID.
+func (s *MongoBlockStore) GetPartitionInfos(ctx context.Context) ([]*domain.PartitionInfo, error) {
+	opts := options.Find().SetSort(bson.D{{Key: partitionIDKey, Value: 1}})
+	cursor, err := s.db.Collection(partitionsCollectionName).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partition infos: %w", err)
+	}
+	infos := make([]*domain.PartitionInfo, 0)
+	if err = cursor.All(ctx, &infos); err != nil {
+		return nil, fmt.Errorf("failed to decode partition infos: %w", err)
+	}
+	return infos, nil
+}
+
+// GetPartitionInfo returns the registry entry of the partition, domain.ErrNotFound if the partition is not registered.
+func (s *MongoBlockStore) GetPartitionInfo(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionInfo, error) {
+	var info domain.PartitionInfo
+	err := s.db.Collection(partitionsCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&info)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("partition %d: %w", partitionID, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to query partition info: %w", err)
+	}
+	return &info, nil
+}
+
+/*
+GetPartitionStats returns the statistics of the stored blocks and transactions of the partition, the shards are
+collected from the latest stored blocks. The counts stored in the metadata of the partition are updated with the
+blocks stored after them.
+*/
+func (s *MongoBlockStore) GetPartitionStats(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionStats, error) {
+	lowest, err := s.GetLowestBlockNumbers(ctx, []types.PartitionID{partitionID})
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.updateBlockStats(ctx, partitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{partitionIDKey: partitionID}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: blockNumberKey, Value: -1}}}},
+		{{Key: "$limit", Value: shardLookupBlocks}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"$ifNull": bson.A{"$" + shardKey, ""}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := s.db.Collection(blocksCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate shards: %w", err)
+	}
+	var shards []struct {
+		ID string `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &shards); err != nil {
+		return nil, fmt.Errorf("failed to decode shards: %w", err)
+	}
+
+	stats := &domain.PartitionStats{
+		FirstBlockNumber:  lowest[partitionID],
+		LatestBlockNumber: counts.BlockNumber,
+		BlockCount:        counts.BlockCount,
+		TxCount:           counts.TxCount,
+		Shards:            make([]string, 0, len(shards)),
+	}
+	for _, shard := range shards {
+		stats.Shards = append(stats.Shards, shard.ID)
+	}
+	return stats, nil
+}
+
+/*
+updateBlockStats adds the blocks stored after the counted range to the counts in the metadata of the partition and
+returns the counts up to the latest block. The counts are only stored when the latest block number and the counts
+have not been changed meanwhile by rollback or pruning.
+*/
+func (s *MongoBlockStore) updateBlockStats(ctx context.Context, partitionID types.PartitionID) (*blockStats, error) {
+	var metadata struct {
+		LatestBlockNumber uint64      `bson:"latestblocknumber"`
+		PrunedBelow       uint64      `bson:"prunedbelow"`
+		BlockStats        *blockStats `bson:"blockstats"`
+	}
+	filter := bson.M{partitionIDKey: partitionID}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, filter).Decode(&metadata)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to query block stats: %w", err)
+	}
+	counts := &blockStats{From: metadata.PrunedBelow}
+	from := metadata.PrunedBelow
+	if counted := metadata.BlockStats; counted != nil && counted.BlockNumber <= metadata.LatestBlockNumber {
+		if counted.BlockNumber == metadata.LatestBlockNumber {
+			return counted, nil
+		}
+		*counts = *counted
+		from = counted.BlockNumber + 1
+	}
+	added, err := s.countBlocks(ctx, partitionID, from, metadata.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	counts.BlockNumber = metadata.LatestBlockNumber
+	counts.BlockCount += added.BlockCount
+	counts.TxCount += added.TxCount
+
+	update := bson.M{"$set": bson.M{blockStatsKey: counts}}
+	filter = bson.M{partitionIDKey: partitionID, latestBlockNumberKey: metadata.LatestBlockNumber, blockStatsKey: metadata.BlockStats}
+	if _, err = s.db.Collection(metadataCollectionName).UpdateOne(ctx, filter, update); err != nil {
+		return nil, fmt.Errorf("failed to update block stats: %w", err)
+	}
+	return counts, nil
+}
+
+/*
+subtractPrunedBlocks subtracts the counted blocks below "beforeBlockNumber" and their transactions from the counts
+in the metadata of the partition and moves the start of the counted range to "beforeBlockNumber". It's called before
+the blocks are deleted, the blocks of an interrupted pruning are not subtracted again. The counts are removed when
+the whole counted range is pruned.
+*/
+func (s *MongoBlockStore) subtractPrunedBlocks(ctx context.Context, partitionID types.PartitionID, beforeBlockNumber uint64) error {
+	var metadata struct {
+		BlockStats *blockStats `bson:"blockstats"`
+	}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&metadata)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to query block stats: %w", err)
+	}
+	counted := metadata.BlockStats
+	if counted == nil || beforeBlockNumber <= counted.From {
+		return nil
+	}
+	if counted.BlockNumber < beforeBlockNumber {
+		return s.resetBlockStats(ctx, partitionID)
+	}
+	pruned, err := s.countBlocks(ctx, partitionID, counted.From, beforeBlockNumber-1)
+	if err != nil {
+		return err
+	}
+	// the counted range may be extended meanwhile, the start of the range is only moved by pruning
+	filter := bson.M{partitionIDKey: partitionID, blockStatsKey + ".from": counted.From}
+	update := bson.M{
+		"$set": bson.M{blockStatsKey + ".from": beforeBlockNumber},
+		"$inc": bson.M{
+			blockStatsKey + "." + blockCountKey: -int64(pruned.BlockCount),
+			blockStatsKey + "." + txCountKey:    -int64(pruned.TxCount),
+		},
+	}
+	if _, err = s.db.Collection(metadataCollectionName).UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to update block stats: %w", err)
+	}
+	return nil
+}
+
+// countBlocks returns the number of the stored blocks of the partition in the range [from, to] and their transactions.
+func (s *MongoBlockStore) countBlocks(ctx context.Context, partitionID types.PartitionID, from, to uint64) (*blockStats, error) {
+	counts := &blockStats{From: from, BlockNumber: to}
+	if from > to {
+		return counts, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$gte": from, "$lte": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         nil,
+			blockCountKey: bson.M{"$sum": 1},
+			txCountKey:    bson.M{"$sum": "$" + txCountKey},
+		}}},
+	}
+	cursor, err := s.db.Collection(blocksCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count blocks: %w", err)
+	}
+	var results []struct {
+		BlockCount int64 `bson:"blockcount"`
+		TxCount    int64 `bson:"txcount"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode block counts: %w", err)
+	}
+	if len(results) > 0 {
+		counts.BlockCount = uint64(results[0].BlockCount)
+		counts.TxCount = uint64(results[0].TxCount)
+	}
+	return counts, nil
+}
+
+// resetBlockStats removes the counts of the stored blocks and transactions, they are counted again from all the blocks.
+func (s *MongoBlockStore) resetBlockStats(ctx context.Context, partitionID types.PartitionID) error {
+	update := bson.M{"$unset": bson.M{blockStatsKey: ""}}
+	if _, err := s.db.Collection(metadataCollectionName).UpdateOne(ctx, bson.M{partitionIDKey: partitionID}, update); err != nil {
+		return fmt.Errorf("failed to reset block stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// RetentionPolicy defines how much data is kept for a partition, zero values mean unlimited.
+	RetentionPolicy struct {
+		Blocks uint64        // number of latest blocks (rounds) to keep
+		Age    time.Duration // maximum age of the blocks to keep
+	}
+
+	// RawBlockPruner removes archived raw blocks which are not stored in the database.
+	RawBlockPruner interface {
+		PruneRawBlocks(ctx context.Context, partitionID types.PartitionID, beforeBlockNumber uint64) error
+	}
+
+	prunerStore interface {
+		retentionCutoff(ctx context.Context, partitionID types.PartitionID, policy RetentionPolicy, now time.Time) (uint64, error)
+		GetPrunedBelow(ctx context.Context, partitionID types.PartitionID) (uint64, error)
+		PruneBlocks(ctx context.Context, partitionID types.PartitionID, beforeBlockNumber uint64) error
+	}
+
+	Pruner struct {
+		store     prunerStore
+		rawBlocks RawBlockPruner
+		policies  map[types.PartitionID]RetentionPolicy
+		interval  time.Duration
+	}
+)
+
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.Blocks == 0 && p.Age == 0
+}
+
+// NewPruner creates background pruner enforcing the retention policies, "rawBlocks" is optional.
+func NewPruner(store *MongoBlockStore, rawBlocks RawBlockPruner, policies map[types.PartitionID]RetentionPolicy, interval time.Duration) (*Pruner, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid pruning interval %s", interval)
+	}
+	return &Pruner{store: store, rawBlocks: rawBlocks, policies: policies, interval: interval}, nil
+}
+
+// Run prunes the data of all the partitions with retention policy every interval until ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) error {
+	for {
+		for partitionID, policy := range p.policies {
+			if err := p.prune(ctx, partitionID, policy, time.Now()); err != nil {
+				log.Error("failed to prune partition data", "partition", partitionID, "err", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+func (p *Pruner) prune(ctx context.Context, partitionID types.PartitionID, policy RetentionPolicy, now time.Time) error {
+	if policy.IsEmpty() {
+		return nil
+	}
+	cutoff, err := p.store.retentionCutoff(ctx, partitionID, policy, now)
+	if err != nil {
+		return err
+	}
+	if cutoff == 0 {
+		return nil
+	}
+	prunedBelow, err := p.store.GetPrunedBelow(ctx, partitionID)
+	if err != nil {
+		return err
+	}
+	if cutoff <= prunedBelow {
+		return nil
+	}
+
+	log.Info("pruning partition data", "partition", partitionID, "before", cutoff)
+	if err = p.store.PruneBlocks(ctx, partitionID, cutoff); err != nil {
+		return err
+	}
+	if p.rawBlocks != nil {
+		if err = p.rawBlocks.PruneRawBlocks(ctx, partitionID, cutoff); err != nil {
+			return fmt.Errorf("failed to prune raw blocks: %w", err)
+		}
+	}
+	return nil
+}
+
+// retentionCutoff returns the number of the first block to keep according to the policy, zero means nothing to prune.
+func (s *MongoBlockStore) retentionCutoff(ctx context.Context, partitionID types.PartitionID, policy RetentionPolicy, now time.Time) (uint64, error) {
+	latest, err := s.GetBlockNumber(ctx, partitionID)
+	if err != nil {
+		return 0, err
+	}
+	var firstRecent *uint64
+	if policy.Age > 0 {
+		minTimestamp := uint64(now.Add(-policy.Age).Unix())
+		filter := bson.M{partitionIDKey: partitionID, timestampKey: bson.M{"$gte": minTimestamp}}
+		opts := options.FindOne().SetSort(bson.D{{Key: blockNumberKey, Value: 1}})
+
+		var block domain.BlockInfo
+		err := s.db.Collection(blocksCollectionName).FindOne(ctx, filter, opts).Decode(&block)
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+		case err != nil:
+			return 0, fmt.Errorf("failed to query first block to keep: %w", err)
+		default:
+			firstRecent = &block.BlockNumber
+		}
+	}
+	return policy.cutoff(latest, firstRecent), nil
+}
+
+// cutoff returns the number of the first block to keep given the latest stored block and the first block not older
+// than the age limit (nil when all the blocks are older), zero means nothing to prune.
+func (p RetentionPolicy) cutoff(latest uint64, firstRecent *uint64) uint64 {
+	var cutoff uint64
+	if p.Blocks > 0 && latest >= p.Blocks {
+		cutoff = latest - p.Blocks + 1
+	}
+	if p.Age > 0 {
+		if firstRecent == nil {
+			// all the blocks are too old but the latest block is always kept
+			cutoff = max(cutoff, latest)
+		} else {
+			cutoff = max(cutoff, *firstRecent)
+		}
+	}
+	return cutoff
+}
+
+// PruneBlocks deletes all the blocks, transactions and bill changes of the partition with block number less than
+// "beforeBlockNumber", the pruned blocks can no longer be rolled back.
+func (s *MongoBlockStore) PruneBlocks(ctx context.Context, partitionID types.PartitionID, beforeBlockNumber uint64) error {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$lt": beforeBlockNumber}}
+
+	// mark the data as pruned before deleting it so that the API never reports the missing data as not found
+	if err := s.setPrunedBelow(ctx, partitionID, beforeBlockNumber); err != nil {
+		return err
+	}
+	if err := s.subtractPrunedBlocks(ctx, partitionID, beforeBlockNumber); err != nil {
+		return err
+	}
+	for _, collection := range blockDataCollections {
+		if _, err := s.db.Collection(collection).DeleteMany(ctx, filter); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", collection, err)
+		}
+	}
+	// the bill changes are only needed for rolling back the stored blocks, the bills and the balances are kept
+	if _, err := s.db.Collection(billChangesCollectionName).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to prune bill changes: %w", err)
+	}
+	if _, err := s.db.Collection(rawBlocksCollectionName).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to prune raw blocks: %w", err)
+	}
+	return nil
+}
+
+// GetPrunedBelow returns the block number below which the partition data has been pruned.
+func (s *MongoBlockStore) GetPrunedBelow(ctx context.Context, partitionID types.PartitionID) (uint64, error) {
+	var result struct {
+		PrunedBelow uint64 `bson:"prunedbelow"`
+	}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to query pruned block number: %w", err)
+	}
+	return result.PrunedBelow, nil
+}
+
+func (s *MongoBlockStore) setPrunedBelow(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) error {
+	filter := bson.M{partitionIDKey: partitionID}
+	update := bson.M{"$set": bson.M{partitionIDKey: partitionID, prunedBelowKey: blockNumber}}
+
+	_, err := s.db.Collection(metadataCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to set pruned block number: %w", err)
+	}
+	return nil
+}
+
+// GetLowestBlockNumbers returns the number of the first block available in the store for each given partition.
+func (s *MongoBlockStore) GetLowestBlockNumbers(ctx context.Context, partitionIDs []types.PartitionID) (map[types.PartitionID]uint64, error) {
+	result := make(map[types.PartitionID]uint64)
+	for _, partitionID := range partitionIDs {
+		opts := options.FindOne().SetSort(bson.D{{Key: blockNumberKey, Value: 1}})
+
+		var block domain.BlockInfo
+		err := s.db.Collection(blocksCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}, opts).Decode(&block)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to query lowest block number: %w", err)
+		}
+		result[partitionID] = block.BlockNumber
+	}
+	return result, nil
+}
+
+// checkPruned returns domain.ErrPruned when the block of the partition has been pruned.
+func (s *MongoBlockStore) checkPruned(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) error {
+	prunedBelow, err := s.GetPrunedBelow(ctx, partitionID)
+	if err != nil {
+		return err
+	}
+	if blockNumber < prunedBelow {
+		return fmt.Errorf("block %d of partition %d has been %w, lowest available block is %d",
+			blockNumber, partitionID, domain.ErrPruned, prunedBelow)
+	}
+	return nil
+}
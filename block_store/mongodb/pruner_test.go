@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+type prunerStoreStub struct {
+	cutoff      uint64
+	prunedBelow uint64
+	pruned      []uint64
+	err         error
+}
+
+func (s *prunerStoreStub) retentionCutoff(context.Context, types.PartitionID, RetentionPolicy, time.Time) (uint64, error) {
+	return s.cutoff, s.err
+}
+
+func (s *prunerStoreStub) GetPrunedBelow(context.Context, types.PartitionID) (uint64, error) {
+	return s.prunedBelow, nil
+}
+
+func (s *prunerStoreStub) PruneBlocks(_ context.Context, _ types.PartitionID, beforeBlockNumber uint64) error {
+	s.pruned = append(s.pruned, beforeBlockNumber)
+	s.prunedBelow = beforeBlockNumber
+	return nil
+}
+
+type rawBlockPrunerStub struct {
+	pruned []uint64
+}
+
+func (s *rawBlockPrunerStub) PruneRawBlocks(_ context.Context, _ types.PartitionID, beforeBlockNumber uint64) error {
+	s.pruned = append(s.pruned, beforeBlockNumber)
+	return nil
+}
+
+func TestRetentionPolicy_Cutoff(t *testing.T) {
+	first := func(n uint64) *uint64 { return &n }
+	tests := []struct {
+		name        string
+		policy      RetentionPolicy
+		latest      uint64
+		firstRecent *uint64
+		want        uint64
+	}{
+		{name: "fewer blocks than kept", policy: RetentionPolicy{Blocks: 100}, latest: 50, want: 0},
+		{name: "exactly the kept blocks", policy: RetentionPolicy{Blocks: 100}, latest: 100, want: 1},
+		{name: "blocks", policy: RetentionPolicy{Blocks: 100}, latest: 1000, want: 901},
+		{name: "age", policy: RetentionPolicy{Age: time.Hour}, latest: 1000, firstRecent: first(700), want: 700},
+		{name: "age, all blocks too old", policy: RetentionPolicy{Age: time.Hour}, latest: 1000, want: 1000},
+		{name: "blocks limit is stricter", policy: RetentionPolicy{Blocks: 100, Age: time.Hour}, latest: 1000, firstRecent: first(700), want: 901},
+		{name: "age limit is stricter", policy: RetentionPolicy{Blocks: 500, Age: time.Hour}, latest: 1000, firstRecent: first(900), want: 900},
+		{name: "empty policy", latest: 1000, firstRecent: first(700), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.policy.cutoff(tt.latest, tt.firstRecent))
+		})
+	}
+}
+
+func TestPruner_Prune(t *testing.T) {
+	store := &prunerStoreStub{cutoff: 100}
+	rawBlocks := &rawBlockPrunerStub{}
+	p := &Pruner{store: store, rawBlocks: rawBlocks, interval: time.Minute}
+	policy := RetentionPolicy{Blocks: 10}
+
+	require.NoError(t, p.prune(context.Background(), 1, policy, time.Now()))
+	require.Equal(t, []uint64{100}, store.pruned)
+	require.Equal(t, []uint64{100}, rawBlocks.pruned)
+
+	// already pruned
+	require.NoError(t, p.prune(context.Background(), 1, policy, time.Now()))
+	require.Len(t, store.pruned, 1)
+
+	// nothing to prune
+	store.cutoff = 0
+	store.prunedBelow = 0
+	require.NoError(t, p.prune(context.Background(), 1, policy, time.Now()))
+	require.Len(t, store.pruned, 1)
+
+	// empty policy
+	store.cutoff = 200
+	require.NoError(t, p.prune(context.Background(), 1, RetentionPolicy{}, time.Now()))
+	require.Len(t, store.pruned, 1)
+
+	store.err = errors.New("db error")
+	require.ErrorContains(t, p.prune(context.Background(), 1, policy, time.Now()), "db error")
+}
+
+func TestPruner_Run(t *testing.T) {
+	store := &prunerStoreStub{cutoff: 100}
+	p := &Pruner{store: store, policies: map[types.PartitionID]RetentionPolicy{1: {Blocks: 10}}, interval: time.Hour}
+
+	// partitions are pruned before waiting for the interval
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, p.Run(ctx), context.Canceled)
+	require.Equal(t, []uint64{100}, store.pruned)
+}
+
+func TestNewPruner(t *testing.T) {
+	_, err := NewPruner(nil, nil, nil, time.Minute)
+	require.ErrorContains(t, err, "store is nil")
+	_, err = NewPruner(&MongoBlockStore{}, nil, nil, 0)
+	require.ErrorContains(t, err, "invalid pruning interval 0s")
+}
@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type rawBlock struct {
+	PartitionID types.PartitionID
+	BlockNumber uint64
+	Data        []byte // gzip compressed CBOR encoded block
+}
+
+// SetRawBlock stores the CBOR encoded block in the raw block archive collection.
+func (s *MongoBlockStore) SetRawBlock(ctx context.Context, partitionID types.PartitionID, blockNumber uint64, data []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("failed to compress raw block: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress raw block: %w", err)
+	}
+
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: blockNumber}
+	update := bson.M{"$set": rawBlock{PartitionID: partitionID, BlockNumber: blockNumber, Data: buf.Bytes()}}
+
+	_, err := s.db.Collection(rawBlocksCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert raw block: %w", err)
+	}
+	return nil
+}
+
+// GetRawBlock returns the CBOR encoded block from the raw block archive collection.
+func (s *MongoBlockStore) GetRawBlock(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) ([]byte, error) {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: blockNumber}
+
+	var block rawBlock
+	err := s.db.Collection(rawBlocksCollectionName).FindOne(ctx, filter).Decode(&block)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if err = s.checkPruned(ctx, partitionID, blockNumber); err != nil {
+				return nil, err
+			}
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query raw block: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(block.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress raw block: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress raw block: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetChainIdentity returns the identity of the chain the stored partition data belongs to, nil when it's not known.
+func (s *MongoBlockStore) GetChainIdentity(ctx context.Context, partitionID types.PartitionID) (*domain.ChainIdentity, error) {
+	var result struct {
+		ChainIdentity *domain.ChainIdentity `bson:"chainidentity"`
+	}
+	err := s.db.Collection(metadataCollectionName).FindOne(ctx, bson.M{partitionIDKey: partitionID}).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query chain identity: %w", err)
+	}
+	return result.ChainIdentity, nil
+}
+
+func (s *MongoBlockStore) SetChainIdentity(ctx context.Context, partitionID types.PartitionID, identity *domain.ChainIdentity) error {
+	filter := bson.M{partitionIDKey: partitionID}
+	update := bson.M{"$set": bson.M{partitionIDKey: partitionID, chainIdentityKey: identity}}
+
+	_, err := s.db.Collection(metadataCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to set chain identity: %w", err)
+	}
+	return nil
+}
+
+// WipePartition deletes all the stored data of the partition, including the sync cursor.
+func (s *MongoBlockStore) WipePartition(ctx context.Context, partitionID types.PartitionID) error {
+	filter := bson.M{partitionIDKey: partitionID}
+	for _, collection := range slices.Concat(blockDataCollections, unitDataCollections, balanceCollections) {
+		if _, err := s.db.Collection(collection).DeleteMany(ctx, filter); err != nil {
+			return fmt.Errorf("failed to wipe %s: %w", collection, err)
+		}
+	}
+	// metadata is deleted last so that an interrupted wipe is detected and repeated
+	if _, err := s.db.Collection(metadataCollectionName).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to wipe %s: %w", metadataCollectionName, err)
+	}
+	return nil
+}
+
+/*
+ArchivePartition moves all the stored data of the partition into the archive collections
+("<collection>_archive") and marks the archived documents with the time of the reset.
+*/
+func (s *MongoBlockStore) ArchivePartition(ctx context.Context, partitionID types.PartitionID, resetAt time.Time) error {
+	for _, collection := range slices.Concat(blockDataCollections, unitDataCollections, balanceCollections, []string{metadataCollectionName}) {
+		if err := s.archiveCollection(ctx, collection, partitionID, resetAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArchiveRawBlocks moves the raw blocks of the partition into the raw blocks archive collection.
+func (s *MongoBlockStore) ArchiveRawBlocks(ctx context.Context, partitionID types.PartitionID, resetAt time.Time) error {
+	return s.archiveCollection(ctx, rawBlocksCollectionName, partitionID, resetAt)
+}
+
+func (s *MongoBlockStore) archiveCollection(ctx context.Context, collection string, partitionID types.PartitionID, resetAt time.Time) error {
+	filter := bson.M{partitionIDKey: partitionID}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{resetAtKey: resetAt}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           collection + archiveCollectionSuffix,
+			"on":             "_id",
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	}
+	cursor, err := s.db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", collection, err)
+	}
+	if err = cursor.Close(ctx); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", collection, err)
+	}
+	if _, err = s.db.Collection(collection).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete archived %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+RollbackBlocks deletes all the blocks and the data derived from them of the partition
+with block number greater than or equal to "fromBlockNumber" and moves the latest
+block number back to the last remaining block.
+*/
+func (s *MongoBlockStore) RollbackBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$gte": fromBlockNumber}}
+
+	// bill changes are reverted before the cursor is moved, an interrupted revert is repeated by the next sync
+	if err := s.revertBillChanges(ctx, partitionID, fromBlockNumber); err != nil {
+		return err
+	}
+
+	// move the cursor back first so that an interrupted rollback is continued by the next sync
+	latest, err := s.lastBlockNumberBefore(ctx, partitionID, fromBlockNumber)
+	if err != nil {
+		return err
+	}
+	if err = s.SetBlockNumber(ctx, partitionID, latest); err != nil {
+		return err
+	}
+	for _, collection := range slices.Concat(blockDataCollections, unitDataCollections) {
+		if _, err = s.db.Collection(collection).DeleteMany(ctx, filter); err != nil {
+			return fmt.Errorf("failed to roll back %s: %w", collection, err)
+		}
+	}
+	return s.resetBlockStats(ctx, partitionID)
+}
+
+// DeleteRawBlocks deletes all the archived blocks of the partition with block number greater than or equal to "fromBlockNumber".
+func (s *MongoBlockStore) DeleteRawBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$gte": fromBlockNumber}}
+	if _, err := s.db.Collection(rawBlocksCollectionName).DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete raw blocks: %w", err)
+	}
+	return nil
+}
+
+// GetLastBlockBefore returns the last stored block of the partition with block number less than "blockNumber", nil
+// when there is no such block.
+func (s *MongoBlockStore) GetLastBlockBefore(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) (*domain.BlockInfo, error) {
+	filter := bson.M{partitionIDKey: partitionID, blockNumberKey: bson.M{"$lt": blockNumber}}
+	opts := options.FindOne().SetSort(bson.D{{Key: blockNumberKey, Value: -1}})
+
+	var block domain.BlockInfo
+	err := s.db.Collection(blocksCollectionName).FindOne(ctx, filter, opts).Decode(&block)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query last block before block %d: %w", blockNumber, err)
+	}
+	return &block, nil
+}
+
+// lastBlockNumberBefore returns the number of the last stored block before "blockNumber".
+func (s *MongoBlockStore) lastBlockNumberBefore(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) (uint64, error) {
+	block, err := s.GetLastBlockBefore(ctx, partitionID, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	if block == nil {
+		// nothing left, continue syncing from the rolled back block
+		if blockNumber == 0 {
+			return 0, nil
+		}
+		return blockNumber - 1, nil
+	}
+	return block.BlockNumber, nil
+}
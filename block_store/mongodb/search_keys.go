@@ -0,0 +1,158 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrationBatchSize is the number of documents updated with a single bulk write by the search keys migration
+const migrationBatchSize = 1000
+
+type (
+	// txDocument is the stored transaction, search keys contain the lowercase hex encoded hashes and target
+	// units of the transaction. Binary values can't be matched by prefix, the indexed hex strings are used instead.
+	txDocument struct {
+		domain.TxInfo `bson:",inline"`
+		SearchKeys    []string `bson:"searchkeys"`
+	}
+
+	// blockDocument is the stored block, search keys contain the lowercase hex encoded block hash. The shard ID is
+	// stored as a bit string for the partition registry.
+	blockDocument struct {
+		domain.BlockInfo `bson:",inline"`
+		SearchKeys       []string `bson:"searchkeys"`
+		Shard            string   `bson:"shard"`
+	}
+)
+
+func newTxDocument(txInfo *domain.TxInfo) *txDocument {
+	return &txDocument{TxInfo: *txInfo, SearchKeys: txSearchKeys(txInfo)}
+}
+
+func newBlockDocument(blockInfo *domain.BlockInfo) *blockDocument {
+	return &blockDocument{BlockInfo: *blockInfo, SearchKeys: blockSearchKeys(blockInfo), Shard: blockInfo.ShardID.String()}
+}
+
+func txSearchKeys(txInfo *domain.TxInfo) []string {
+	keys := []string{hex.EncodeToString(txInfo.TxRecordHash), hex.EncodeToString(txInfo.TxOrderHash)}
+	if txInfo.Transaction != nil && txInfo.Transaction.ServerMetadata != nil {
+		for _, unitID := range txInfo.Transaction.ServerMetadata.TargetUnits {
+			keys = append(keys, hex.EncodeToString(unitID))
+		}
+	}
+	return keys
+}
+
+func blockSearchKeys(blockInfo *domain.BlockInfo) []string {
+	if len(blockInfo.BlockHash) == 0 {
+		return []string{}
+	}
+	return []string{hex.EncodeToString(blockInfo.BlockHash)}
+}
+
+// prefixFilter matches the documents having a search key starting with the lowercase hex prefix.
+func prefixFilter(prefix string, partitionIDs []types.PartitionID) bson.M {
+	filter := bson.M{searchKeysKey: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)}}
+	if len(partitionIDs) > 0 {
+		filter[partitionIDKey] = bson.M{"$in": partitionIDs}
+	}
+	return filter
+}
+
+/*
+FindTxsByPrefix returns up to "limit" latest transactions having the record hash, the order hash or a target
+unit starting with the lowercase hex prefix (without the 0x prefix).
+*/
+func (s *MongoBlockStore) FindTxsByPrefix(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*domain.TxInfo, error) {
+	txs, _, err := findPage[domain.TxInfo](ctx, s.db.Collection(txCollectionName), prefixFilter(prefix, partitionIDs), "_id", nil, false, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions by prefix: %w", err)
+	}
+	return txs, nil
+}
+
+// FindBlocksByPrefix returns up to "limit" latest blocks having the block hash starting with the lowercase hex prefix.
+func (s *MongoBlockStore) FindBlocksByPrefix(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*domain.BlockInfo, error) {
+	blocks, _, err := findPage[domain.BlockInfo](ctx, s.db.Collection(blocksCollectionName), prefixFilter(prefix, partitionIDs), blockNumberKey, nil, false, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocks by prefix: %w", err)
+	}
+	return blocks, nil
+}
+
+// MigrateSearchKeys adds the search keys to the transactions and blocks stored before the prefix search was added.
+// The migration is not part of the store initialization, it's run in the background as it may take a while and the
+// documents are only missing from the prefix search results until it completes.
+func (s *MongoBlockStore) MigrateSearchKeys(ctx context.Context) error {
+	txs, err := migrateSearchKeys(ctx, s.db.Collection(txCollectionName), func(tx *domain.TxInfo) (primitive.ObjectID, []string) {
+		return tx.ID, txSearchKeys(tx)
+	})
+	if err != nil {
+		return err
+	}
+	blocks, err := migrateSearchKeys(ctx, s.db.Collection(blocksCollectionName), func(doc *struct {
+		ID               primitive.ObjectID `bson:"_id"`
+		domain.BlockInfo `bson:",inline"`
+	}) (primitive.ObjectID, []string) {
+		return doc.ID, blockSearchKeys(&doc.BlockInfo)
+	})
+	if err != nil {
+		return err
+	}
+	if txs > 0 || blocks > 0 {
+		log.Info("Search keys migration complete", "txs", txs, "blocks", blocks)
+	}
+	return nil
+}
+
+func migrateSearchKeys[T any](ctx context.Context, coll *mongo.Collection, searchKeys func(*T) (primitive.ObjectID, []string)) (int64, error) {
+	cursor, err := coll.Find(ctx, bson.M{searchKeysKey: bson.M{"$exists": false}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s without search keys: %w", coll.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	var models []mongo.WriteModel
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+		res, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return fmt.Errorf("failed to set search keys of %s: %w", coll.Name(), err)
+		}
+		updated += res.ModifiedCount
+		models = models[:0]
+		return nil
+	}
+	for cursor.Next(ctx) {
+		var doc T
+		if err = cursor.Decode(&doc); err != nil {
+			return updated, fmt.Errorf("failed to decode %s document: %w", coll.Name(), err)
+		}
+		id, keys := searchKeys(&doc)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{searchKeysKey: keys}}))
+		if len(models) == migrationBatchSize {
+			if err = flush(); err != nil {
+				return updated, err
+			}
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		return updated, fmt.Errorf("cursor encountered an error: %w", err)
+	}
+	return updated, flush()
+}
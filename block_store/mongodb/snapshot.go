@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type snapshotCollection struct {
+	name string
+	// keys are the fields uniquely identifying a document in the collection
+	keys []string
+}
+
+// snapshotCollections lists the collections included in the database snapshots.
+// Metadata collection contains the sync cursors and must be the last one.
+var snapshotCollections = []snapshotCollection{
+	{name: blocksCollectionName, keys: []string{partitionIDKey, blockNumberKey}},
+	{name: txCollectionName, keys: []string{txRecordHashKey}},
+	{name: rawBlocksCollectionName, keys: []string{partitionIDKey, blockNumberKey}},
+	{name: tokenTextsCollectionName, keys: []string{partitionIDKey, unitIDKey}},
+	{name: billsCollectionName, keys: []string{partitionIDKey, unitIDKey}},
+	{name: balancesCollectionName, keys: []string{partitionIDKey, ownerIDKey}},
+	{name: billChangesCollectionName, keys: []string{partitionIDKey, blockNumberKey}},
+	{name: partitionsCollectionName, keys: []string{partitionIDKey}},
+	{name: metadataCollectionName, keys: []string{partitionIDKey}},
+}
+
+/*
+ExportSnapshot streams all documents of the snapshot collections to "write" encoded as
+canonical MongoDB extended JSON.
+
+The metadata collection (sync cursors) is read before and written after all the other
+collections so that the cursors never point past the exported blocks when the explorer
+is running during the export.
+*/
+func (s *MongoBlockStore) ExportSnapshot(ctx context.Context, write func(collection string, document []byte) error) error {
+	var metadata [][]byte
+	err := s.exportCollection(ctx, metadataCollectionName, func(document []byte) error {
+		metadata = append(metadata, document)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range snapshotCollections {
+		if collection.name == metadataCollectionName {
+			continue
+		}
+		err = s.exportCollection(ctx, collection.name, func(document []byte) error {
+			return write(collection.name, document)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, document := range metadata {
+		if err = write(metadataCollectionName, document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MongoBlockStore) exportCollection(ctx context.Context, collection string, write func(document []byte) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := s.db.Collection(collection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to query collection %s: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		document, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return fmt.Errorf("failed to encode document of collection %s: %w", collection, err)
+		}
+		if err = write(document); err != nil {
+			return err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return fmt.Errorf("cursor encountered an error: %w", err)
+	}
+	return nil
+}
+
+/*
+ImportSnapshotDocuments upserts extended JSON encoded documents into the collection.
+
+Documents are matched by the unique keys of the collection so importing the same documents
+multiple times, or into a database which already contains some of them, is safe. The _id
+of the document is preserved when the document is inserted (transactions are paged by _id).
+*/
+func (s *MongoBlockStore) ImportSnapshotDocuments(ctx context.Context, collection string, documents [][]byte) error {
+	idx := slices.IndexFunc(snapshotCollections, func(c snapshotCollection) bool { return c.name == collection })
+	if idx < 0 {
+		return fmt.Errorf("unknown snapshot collection %q", collection)
+	}
+	keys := snapshotCollections[idx].keys
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(documents))
+	for _, document := range documents {
+		// decode into bson.D to preserve the field order of (embedded) documents
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(document, true, &doc); err != nil {
+			return fmt.Errorf("failed to decode document of collection %s: %w", collection, err)
+		}
+		var (
+			filter = bson.D{}
+			fields = bson.D{}
+			update = bson.D{}
+		)
+		for _, e := range doc {
+			if e.Key == "_id" {
+				update = append(update, bson.E{Key: "$setOnInsert", Value: bson.D{e}})
+				continue
+			}
+			if slices.Contains(keys, e.Key) {
+				filter = append(filter, e)
+			}
+			fields = append(fields, e)
+		}
+		if len(filter) != len(keys) {
+			return fmt.Errorf("document of collection %s is missing some of the keys %v", collection, keys)
+		}
+		update = append(update, bson.E{Key: "$set", Value: fields})
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	_, err := s.db.Collection(collection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to import documents into collection %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetSubmittedTx stores the transaction order submitted through the explorer, the
+// submitted transactions are removed by the TTL index after submittedTxsTTL.
+func (s *MongoBlockStore) SetSubmittedTx(ctx context.Context, tx *domain.SubmittedTx) error {
+	filter := bson.M{txOrderHashKey: tx.TxOrderHash}
+	update := bson.M{"$set": tx}
+
+	_, err := s.db.Collection(submittedTxsCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert submitted transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoBlockStore) GetSubmittedTx(ctx context.Context, txOrderHash domain.TxHash) (*domain.SubmittedTx, error) {
+	var tx domain.SubmittedTx
+	err := s.db.Collection(submittedTxsCollectionName).FindOne(ctx, bson.M{txOrderHashKey: txOrderHash}).Decode(&tx)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query submitted transaction: %w", err)
+	}
+	return &tx, nil
+}
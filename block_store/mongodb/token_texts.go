@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxTokenTextCandidates is the number of the texts sharing a trigram with the query ranked by the text search
+const maxTokenTextCandidates = 1000
+
+// tokenTextDocument is the stored token text, trigrams of the symbol, the name and the URI are indexed for the
+// case-insensitive and fuzzy text search.
+type tokenTextDocument struct {
+	domain.TokenText `bson:",inline"`
+	Trigrams         []string `bson:"trigrams"`
+}
+
+func newTokenTextDocument(text *domain.TokenText) *tokenTextDocument {
+	return &tokenTextDocument{
+		TokenText: *text,
+		Trigrams:  util.Trigrams(strings.Join([]string{text.Symbol, text.Name, text.URI}, " ")),
+	}
+}
+
+// SetTokenTexts stores the token texts, the text of the unit already stored is replaced.
+func (s *MongoBlockStore) SetTokenTexts(ctx context.Context, texts []*domain.TokenText) error {
+	if len(texts) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(texts))
+	for _, text := range texts {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{partitionIDKey: text.PartitionID, unitIDKey: text.UnitID}).
+			SetReplacement(newTokenTextDocument(text)).
+			SetUpsert(true))
+	}
+	if _, err := s.db.Collection(tokenTextsCollectionName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to set token texts: %w", err)
+	}
+	return nil
+}
+
+/*
+FindTokenTexts returns up to "limit" token texts sharing the most trigrams with the query, the texts of the
+latest blocks first when the number of the shared trigrams is equal. The texts are the candidates of the
+fuzzy match, the caller decides whether the text is similar enough to the query. Only the first
+maxTokenTextCandidates texts sharing a trigram with the query are ranked.
+*/
+func (s *MongoBlockStore) FindTokenTexts(ctx context.Context, query string, partitionIDs []types.PartitionID, limit int) ([]*domain.TokenText, error) {
+	trigrams := util.Trigrams(query)
+	if len(trigrams) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{trigramsKey: bson.M{"$in": trigrams}}
+	if len(partitionIDs) > 0 {
+		filter[partitionIDKey] = bson.M{"$in": partitionIDs}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		// only the candidates are scored and sorted, common trigrams may be shared by most of the texts
+		{{Key: "$limit", Value: maxTokenTextCandidates}},
+		{{Key: "$addFields", Value: bson.M{
+			scoreKey: bson.M{"$size": bson.M{"$setIntersection": bson.A{"$" + trigramsKey, trigrams}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: scoreKey, Value: -1}, {Key: blockNumberKey, Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{trigramsKey: 0, scoreKey: 0}}},
+	}
+	cursor, err := s.db.Collection(tokenTextsCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token texts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var texts []*domain.TokenText
+	if err = cursor.All(ctx, &texts); err != nil {
+		return nil, fmt.Errorf("failed to decode token texts: %w", err)
+	}
+	return texts, nil
+}
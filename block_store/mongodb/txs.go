@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	"github.com/alphabill-org/alphabill-go-base/types"
@@ -14,7 +15,7 @@ import (
 
 func (s *MongoBlockStore) SetTxInfo(ctx context.Context, txInfo *domain.TxInfo) error {
 	filter := bson.M{txRecordHashKey: txInfo.TxRecordHash}
-	update := bson.M{"$set": txInfo}
+	update := bson.M{"$set": newTxDocument(txInfo)}
 
 	_, err := s.db.Collection(txCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
 	if err != nil {
@@ -49,12 +50,16 @@ func (s *MongoBlockStore) GetTxsByBlockNumber(ctx context.Context, blockNumber u
 		return nil, err
 	}
 	if blockMap == nil || blockMap[partitionID] == nil {
+		if err = s.checkPruned(ctx, partitionID, blockNumber); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("could not find block with number %d in partition %d", blockNumber, partitionID)
 	}
-	return s.getTxsByHashes(ctx, blockMap[partitionID].TxHashes)
+	return s.GetTxsByHashes(ctx, blockMap[partitionID].TxHashes)
 }
 
-func (s *MongoBlockStore) getTxsByHashes(ctx context.Context, hashes []domain.TxHash) ([]*domain.TxInfo, error) {
+// GetTxsByHashes returns the transactions with given transaction record hashes.
+func (s *MongoBlockStore) GetTxsByHashes(ctx context.Context, hashes []domain.TxHash) ([]*domain.TxInfo, error) {
 	filter := bson.M{txRecordHashKey: bson.M{"$in": hashes}}
 
 	cursor, err := s.db.Collection(txCollectionName).Find(ctx, filter)
@@ -104,54 +109,158 @@ func (s *MongoBlockStore) GetTxsByUnitID(ctx context.Context, unitID types.UnitI
 	return transactions, nil
 }
 
-// GetTxsPage retrieves a paginated list of transactions for a given partition, starting from the specified latestID.
-// Returns the transactions, the latest ID for the previous page, and any error encountered.
+// GetTxsPage returns a page of the transactions of the partition, latest transaction first.
+// The page key is the ID of the transaction document.
 func (s *MongoBlockStore) GetTxsPage(
 	ctx context.Context,
 	partitionID types.PartitionID,
-	latestID string,
-	limit int,
-) (transactions []*domain.TxInfo, previousID string, err error) {
-	filter := bson.M{partitionIDKey: partitionID}
-	if latestID != "" {
-		objectID, err := primitive.ObjectIDFromHex(latestID)
+	page domain.PageRequest[primitive.ObjectID],
+) ([]*domain.TxInfo, bool, error) {
+	return s.findTxsPage(ctx, bson.M{partitionIDKey: partitionID}, page)
+}
+
+// GetTxsPageByUnitID returns a page of the transactions targeting the unit, latest transaction first.
+// The page key is the ID of the transaction document.
+func (s *MongoBlockStore) GetTxsPageByUnitID(
+	ctx context.Context,
+	unitID types.UnitID,
+	page domain.PageRequest[primitive.ObjectID],
+) ([]*domain.TxInfo, bool, error) {
+	return s.findTxsPage(ctx, bson.M{targetUnitsKey: unitID}, page)
+}
+
+// GetTxsPages returns the pages of the transactions of the partitions, latest transaction first, with a query per
+// partition. The page key is the ID of the transaction document.
+func (s *MongoBlockStore) GetTxsPages(
+	ctx context.Context,
+	partitionIDs []types.PartitionID,
+	page domain.PageRequest[primitive.ObjectID],
+) (map[types.PartitionID][]*domain.TxInfo, error) {
+	pages := make(map[types.PartitionID][]*domain.TxInfo, len(partitionIDs))
+	for _, partitionID := range partitionIDs {
+		txs, _, err := s.GetTxsPage(ctx, partitionID, page)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid startID: %w", err)
+			return nil, fmt.Errorf("failed to get transactions of partition %d: %w", partitionID, err)
 		}
-		filter["_id"] = bson.M{"$lte": objectID}
+		pages[partitionID] = txs
 	}
+	return pages, nil
+}
 
-	opts := options.Find().
-		SetSort(bson.D{{Key: "_id", Value: -1}}).
-		SetLimit(int64(limit + 1)) // Fetch one extra to identify the previous ID
-
-	cursor, err := s.db.Collection(txCollectionName).Find(ctx, filter, opts)
+/*
+GetTxsPagesByUnitIDs returns the pages of the transactions targeting the units, latest transaction first, with a
+single query. The pages are keyed by the unit ID, the units without transactions are omitted. The page key is the
+ID of the transaction document.
+*/
+func (s *MongoBlockStore) GetTxsPagesByUnitIDs(
+	ctx context.Context,
+	unitIDs []types.UnitID,
+	page domain.PageRequest[primitive.ObjectID],
+) (map[string][]*domain.TxInfo, error) {
+	order, op := -1, "$lt"
+	if page.Backward {
+		order, op = 1, "$gt"
+	}
+	filter := bson.M{targetUnitsKey: bson.M{"$in": unitIDs}}
+	if page.Key != nil {
+		filter["_id"] = bson.M{op: *page.Key}
+	}
+	// the transaction is added to the page of every requested unit it targets
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: order}}}},
+		{{Key: "$addFields", Value: bson.M{unitIDKey: "$" + targetUnitsKey}}},
+		{{Key: "$unwind", Value: "$" + unitIDKey}},
+		{{Key: "$match", Value: bson.M{unitIDKey: bson.M{"$in": unitIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$" + unitIDKey,
+			"txs": bson.M{"$firstN": bson.M{"input": "$$ROOT", "n": page.Limit}},
+		}}},
+	}
+	cursor, err := s.db.Collection(txCollectionName).Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to query transactions: %w", err)
+		return nil, fmt.Errorf("failed to query transactions by unit IDs: %w", err)
+	}
+	var results []struct {
+		UnitID types.UnitID     `bson:"_id"`
+		Txs    []*domain.TxInfo `bson:"txs"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
 	}
-	defer cursor.Close(ctx)
-
-	count := 0
-	for cursor.Next(ctx) {
-		var tx domain.TxInfo
-		if err = cursor.Decode(&tx); err != nil {
-			return nil, "", fmt.Errorf("failed to decode transaction: %w", err)
-		}
 
-		if count == limit {
-			previousID = tx.ID.Hex()
-			break
+	pages := make(map[string][]*domain.TxInfo, len(results))
+	for _, r := range results {
+		if page.Backward {
+			slices.Reverse(r.Txs)
 		}
+		pages[string(r.UnitID)] = r.Txs
+	}
+	return pages, nil
+}
 
-		transactions = append(transactions, &tx)
-		count++
+func (s *MongoBlockStore) findTxsPage(ctx context.Context, filter bson.M, page domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error) {
+	var key any
+	if page.Key != nil {
+		key = *page.Key
 	}
+	return findPage[domain.TxInfo](ctx, s.db.Collection(txCollectionName), filter, "_id", key, page.Backward, page.Limit)
+}
 
-	if err = cursor.Err(); err != nil {
-		return nil, "", fmt.Errorf("cursor encountered an error: %w", err)
+// GetTxsPageByBlockNumber returns a page of the transactions of the block in the order they are in the block.
+// The page key is the transaction record hash.
+func (s *MongoBlockStore) GetTxsPageByBlockNumber(
+	ctx context.Context,
+	blockNumber uint64,
+	partitionID types.PartitionID,
+	page domain.PageRequest[domain.TxHash],
+) ([]*domain.TxInfo, bool, error) {
+	blockMap, err := s.GetBlock(ctx, blockNumber, []types.PartitionID{partitionID})
+	if err != nil {
+		return nil, false, err
+	}
+	if blockMap == nil || blockMap[partitionID] == nil {
+		if err = s.checkPruned(ctx, partitionID, blockNumber); err != nil {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("could not find block with number %d in partition %d: %w", blockNumber, partitionID, domain.ErrNotFound)
+	}
+	hashes := blockMap[partitionID].TxHashes
+
+	// the page is [start, end) of the transaction hashes of the block
+	start, end := 0, len(hashes)
+	if page.Key != nil {
+		idx := slices.IndexFunc(hashes, page.Key.Eq)
+		if idx < 0 {
+			return nil, false, fmt.Errorf("transaction %s is not in the block %d: %w", *page.Key, blockNumber, domain.ErrNotFound)
+		}
+		if page.Backward {
+			end = idx
+		} else {
+			start = idx + 1
+		}
+	}
+	hasMore := end-start > page.Limit
+	if page.Backward {
+		start = max(start, end-page.Limit)
+	} else {
+		end = min(end, start+page.Limit)
+	}
+	if start >= end {
+		return nil, false, nil
 	}
 
-	return transactions, previousID, nil
+	loaded, err := s.GetTxsByHashes(ctx, hashes[start:end])
+	if err != nil {
+		return nil, false, err
+	}
+	txs := make([]*domain.TxInfo, 0, len(loaded))
+	for _, hash := range hashes[start:end] {
+		if idx := slices.IndexFunc(loaded, func(tx *domain.TxInfo) bool { return hash.Eq(tx.TxRecordHash) }); idx >= 0 {
+			txs = append(txs, loaded[idx])
+		}
+	}
+	return txs, hasMore, nil
 }
 
 func (s *MongoBlockStore) FindTxs(ctx context.Context, searchKey []byte, partitionIDs []types.PartitionID) ([]*domain.TxInfo, error) {
@@ -191,3 +300,78 @@ func (s *MongoBlockStore) FindTxs(ctx context.Context, searchKey []byte, partiti
 
 	return transactions, nil
 }
+
+/*
+ExportTxs calls fn with the transactions matching the filter, oldest transaction first unless the filter is
+Descending. The transactions are read from the cursor one by one so the whole history is never loaded into
+memory, iteration is stopped when fn returns an error.
+*/
+func (s *MongoBlockStore) ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error {
+	match := bson.M{targetUnitsKey: bson.M{"$in": filter.UnitIDs}}
+	blockRange := bson.M{}
+	if filter.StartBlock > 0 {
+		blockRange["$gte"] = filter.StartBlock
+	}
+	if filter.EndBlock > 0 {
+		blockRange["$lte"] = filter.EndBlock
+	}
+	if len(blockRange) > 0 {
+		match[blockNumberKey] = blockRange
+	}
+
+	order := 1
+	if filter.Descending {
+		order = -1
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: order}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": blocksCollectionName,
+			"let":  bson.M{"pid": "$" + partitionIDKey, "bn": "$" + blockNumberKey},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$" + partitionIDKey, "$$pid"}},
+					bson.M{"$eq": bson.A{"$" + blockNumberKey, "$$bn"}},
+				}}}}},
+				{{Key: "$project", Value: bson.M{timestampKey: 1, partitionTypeIDKey: 1}}},
+			},
+			"as": "block",
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			timestampKey:       bson.M{"$arrayElemAt": bson.A{"$block." + timestampKey, 0}},
+			partitionTypeIDKey: bson.M{"$arrayElemAt": bson.A{"$block." + partitionTypeIDKey, 0}},
+		}}},
+		{{Key: "$project", Value: bson.M{"block": 0}}},
+	}
+	timeRange := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeRange["$gte"] = uint64(filter.StartTime.Unix())
+	}
+	if !filter.EndTime.IsZero() {
+		timeRange["$lte"] = uint64(filter.EndTime.Unix())
+	}
+	if len(timeRange) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{timestampKey: timeRange}}})
+	}
+
+	cursor, err := s.db.Collection(txCollectionName).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var tx domain.BlockTx
+		if err := cursor.Decode(&tx); err != nil {
+			return fmt.Errorf("failed to decode transaction: %w", err)
+		}
+		if err := fn(&tx); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor encountered an error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetProposerStats aggregates the stored blocks of the partition with the timestamp greater than or equal to
+// "fromTimestamp" by the proposer, the validators with the most proposed blocks first.
+func (s *MongoBlockStore) GetProposerStats(ctx context.Context, partitionID types.PartitionID, fromTimestamp uint64) ([]*domain.ProposerStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{partitionIDKey: partitionID, timestampKey: bson.M{"$gte": fromTimestamp}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":              "$" + proposerIDKey,
+			"blocksproposed":   bson.M{"$sum": 1},
+			"emptyblocks":      bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$" + txCountKey, 0}}, 1, 0}}},
+			"txcount":          bson.M{"$sum": "$" + txCountKey},
+			"fees":             bson.M{"$sum": "$" + feesKey},
+			"firstblocknumber": bson.M{"$min": "$" + blockNumberKey},
+			"lastblocknumber":  bson.M{"$max": "$" + blockNumberKey},
+			"lastproposedat":   bson.M{"$max": "$" + timestampKey},
+		}}},
+		{{Key: "$set", Value: bson.M{"nodeid": "$_id"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "blocksproposed", Value: -1}, {Key: "nodeid", Value: 1}}}},
+	}
+	cursor, err := s.db.Collection(blocksCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate proposer stats: %w", err)
+	}
+	var stats []*domain.ProposerStats
+	if err = cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode proposer stats: %w", err)
+	}
+	return stats, nil
+}
+
+// MigrateBlockFees sets the fees of the blocks stored before the fees of the blocks were recorded.
+func (s *MongoBlockStore) MigrateBlockFees(ctx context.Context) error {
+	blocks := s.db.Collection(blocksCollectionName)
+	filter := bson.M{feesKey: bson.M{"$exists": false}}
+	count, err := blocks.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to query blocks without fees: %w", err)
+	}
+	if count == 0 {
+		return nil
+	}
+	log.Info("Starting migration: Adding fees to existing blocks...")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{partitionIDKey: "$" + partitionIDKey, blockNumberKey: "$" + blockNumberKey},
+			feesKey: bson.M{"$sum": "$" + actualFeeKey},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			partitionIDKey: "$_id." + partitionIDKey,
+			blockNumberKey: "$_id." + blockNumberKey,
+			feesKey:        1,
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into": blocksCollectionName,
+			"on":   bson.A{blockNumberKey, partitionIDKey},
+			"whenMatched": bson.A{
+				bson.M{"$set": bson.M{feesKey: bson.M{"$ifNull": bson.A{"$" + feesKey, "$$new." + feesKey}}}},
+			},
+			"whenNotMatched": "discard",
+		}}},
+	}
+	cursor, err := s.db.Collection(txCollectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to migrate fees of blocks: %w", err)
+	}
+	if err = cursor.Close(ctx); err != nil {
+		return fmt.Errorf("failed to migrate fees of blocks: %w", err)
+	}
+	// the remaining blocks have no transactions
+	result, err := blocks.UpdateMany(ctx, filter, bson.M{"$set": bson.M{feesKey: 0}})
+	if err != nil {
+		return fmt.Errorf("failed to migrate fees of empty blocks: %w", err)
+	}
+
+	log.Info("Migration complete", "empty blocks", result.ModifiedCount)
+	return nil
+}
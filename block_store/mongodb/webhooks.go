@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (s *MongoBlockStore) CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	subscription.ID = primitive.NewObjectID()
+	if _, err := s.db.Collection(webhookSubscriptionsCollectionName).InsertOne(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookSubscriptions returns the subscriptions of the owner, all the subscriptions are returned when owner is empty.
+func (s *MongoBlockStore) GetWebhookSubscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	filter := bson.M{}
+	if owner != "" {
+		filter[ownerKey] = owner
+	}
+	cursor, err := s.db.Collection(webhookSubscriptionsCollectionName).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	var subscriptions []*domain.WebhookSubscription
+	if err = cursor.All(ctx, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *MongoBlockStore) GetWebhookSubscription(ctx context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	err := s.db.Collection(webhookSubscriptionsCollectionName).FindOne(ctx, bson.M{"_id": id}).Decode(&subscription)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// DeleteWebhookSubscription deletes the subscription and its deliveries.
+func (s *MongoBlockStore) DeleteWebhookSubscription(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := s.db.Collection(webhookSubscriptionsCollectionName).DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if _, err := s.db.Collection(webhookDeliveriesCollectionName).DeleteMany(ctx, bson.M{subscriptionIDKey: id}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// AddWebhookDeliveries inserts the deliveries, the deliveries which already exist are not modified.
+func (s *MongoBlockStore) AddWebhookDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	models := make([]mongo.WriteModel, 0, len(deliveries))
+	for _, d := range deliveries {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": d.ID}).
+			SetUpdate(bson.M{"$setOnInsert": d}).
+			SetUpsert(true))
+	}
+	_, err := s.db.Collection(webhookDeliveriesCollectionName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// GetDueWebhookDeliveries returns the pending deliveries which are due at "now", oldest first.
+func (s *MongoBlockStore) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	filter := bson.M{statusKey: domain.WebhookDeliveryPending, nextAttemptAtKey: bson.M{"$lte": now}}
+	opts := options.Find().SetSort(bson.D{{Key: nextAttemptAtKey, Value: 1}}).SetLimit(int64(limit))
+	return s.findWebhookDeliveries(ctx, filter, opts)
+}
+
+// GetWebhookDeliveries returns the latest deliveries of the subscription, all statuses are returned when status is empty.
+func (s *MongoBlockStore) GetWebhookDeliveries(
+	ctx context.Context, subscriptionID primitive.ObjectID, status domain.WebhookDeliveryStatus, limit int,
+) ([]*domain.WebhookDelivery, error) {
+	filter := bson.M{subscriptionIDKey: subscriptionID}
+	if status != "" {
+		filter[statusKey] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: eventCreatedAtKey, Value: -1}}).SetLimit(int64(limit))
+	return s.findWebhookDeliveries(ctx, filter, opts)
+}
+
+func (s *MongoBlockStore) findWebhookDeliveries(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.WebhookDelivery, error) {
+	cursor, err := s.db.Collection(webhookDeliveriesCollectionName).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	var deliveries []*domain.WebhookDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *MongoBlockStore) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := s.db.Collection(webhookDeliveriesCollectionName).ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ReplayWebhookDeliveries schedules the failed deliveries of the subscription for delivery at "now".
+func (s *MongoBlockStore) ReplayWebhookDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, now time.Time) (int64, error) {
+	filter := bson.M{subscriptionIDKey: subscriptionID, statusKey: domain.WebhookDeliveryFailed}
+	update := bson.M{"$set": bson.M{statusKey: domain.WebhookDeliveryPending, attemptsKey: 0, nextAttemptAtKey: now}}
+	res, err := s.db.Collection(webhookDeliveriesCollectionName).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replay webhook deliveries: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
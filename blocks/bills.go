@@ -0,0 +1,356 @@
+package blocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+type (
+	// GetUnitFunc returns the current state of the unit on the partition node, nil when the unit doesn't exist
+	GetUnitFunc func(ctx context.Context, unitID types.UnitID, includeStateProof bool) (*sdktypes.Unit[any], error)
+
+	// billReplay applies the transactions of the money partition block to the bills.
+	billReplay struct {
+		partitionID types.PartitionID
+		bills       map[string]*domain.TrackedBill // current state of the bills, nil when deleted
+		before      map[string]*domain.TrackedBill // state of the changed bills before the block
+		changed     []types.UnitID                 // changed bills in the order of the first change
+		counters    map[string]uint64              // counters of the changed bills after their last change
+		dust        int64
+		feeCredit   int64
+	}
+
+	moneyTx struct {
+		txr *types.TransactionRecord
+		txo *types.TransactionOrder
+	}
+)
+
+// saveBillChanges stores the changes of the bills and the supply made by the successful transactions of the money partition block.
+func (p *BlockProcessor) saveBillChanges(ctx context.Context, b *types.Block, blockNumber uint64) error {
+	var txs []*moneyTx
+	var unitIDs []types.UnitID
+	for _, txr := range b.Transactions {
+		if txr.TxStatus() != types.TxStatusSuccessful {
+			continue
+		}
+		txo, err := txr.GetTransactionOrderV1()
+		if err != nil {
+			continue
+		}
+		switch txo.Type {
+		case money.TransactionTypeTransfer, money.TransactionTypeSplit, money.TransactionTypeTransDC, money.TransactionTypeSwapDC,
+			money.TransactionTypeLock, money.TransactionTypeUnlock, fc.TransactionTypeTransferFeeCredit, fc.TransactionTypeReclaimFeeCredit:
+		default:
+			continue
+		}
+		txs = append(txs, &moneyTx{txr: txr, txo: txo})
+		unitIDs = append(unitIDs, txo.UnitID)
+		if txo.Type == money.TransactionTypeSplit && txr.ServerMetadata != nil {
+			unitIDs = append(unitIDs, txr.ServerMetadata.TargetUnits...)
+		}
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+
+	stored, err := p.store.GetTrackedBills(ctx, b.PartitionID(), unitIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load bills: %w", err)
+	}
+	r := newBillReplay(b.PartitionID(), stored)
+	for _, tx := range txs {
+		if err = r.apply(tx.txr, tx.txo); err != nil {
+			log.Warn("failed to decode money transaction attributes", "partition", b.PartitionID(), "round", blockNumber, "type", tx.txo.Type, "err", err)
+		}
+	}
+	if p.getUnit != nil {
+		for _, bill := range r.untracked() {
+			p.seedBill(ctx, r, bill, blockNumber)
+		}
+	}
+	changes := r.changes(blockNumber)
+	if changes == nil {
+		return nil
+	}
+	if err = p.store.ApplyBillChanges(ctx, changes); err != nil {
+		return fmt.Errorf("failed to save bill changes: %w", err)
+	}
+	return nil
+}
+
+func newBillReplay(partitionID types.PartitionID, stored []*domain.TrackedBill) *billReplay {
+	r := &billReplay{
+		partitionID: partitionID,
+		bills:       make(map[string]*domain.TrackedBill, len(stored)),
+		before:      make(map[string]*domain.TrackedBill),
+		counters:    make(map[string]uint64),
+	}
+	for _, bill := range stored {
+		r.bills[string(bill.UnitID)] = bill
+	}
+	return r
+}
+
+/*
+seedBill tracks the untracked bill, created before the first synced block, with the owner and the value of the bill
+on the money node. The state on the node is the state after the block only when the bill has not been changed since,
+the counter of the bill on the node must be the counter after its last transaction in the block. Otherwise the bill
+stays untracked until it's seen again.
+*/
+func (p *BlockProcessor) seedBill(ctx context.Context, r *billReplay, bill *domain.TrackedBill, blockNumber uint64) {
+	counter, ok := r.counters[string(bill.UnitID)]
+	if !ok {
+		return
+	}
+	unit, err := p.getUnit(ctx, bill.UnitID, false)
+	if err != nil {
+		log.Warn("failed to load bill from the node", "partition", r.partitionID, "round", blockNumber, "unit", bill.UnitID, "err", err)
+		return
+	}
+	if unit == nil {
+		return
+	}
+	data, err := billData(unit.Data)
+	if err != nil {
+		log.Warn("failed to decode bill of the node", "partition", r.partitionID, "round", blockNumber, "unit", bill.UnitID, "err", err)
+		return
+	}
+	if data.Counter != counter {
+		return
+	}
+	r.set(bill.UnitID, &domain.TrackedBill{PartitionID: r.partitionID, UnitID: bill.UnitID, OwnerID: ownerID(data.OwnerPredicate), Value: data.Value})
+}
+
+// billData decodes the unit data of the bill returned by the node as JSON.
+func billData(data any) (*money.BillData, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	bill := &money.BillData{}
+	if err = json.Unmarshal(b, bill); err != nil {
+		return nil, err
+	}
+	return bill, nil
+}
+
+/*
+apply applies the successful transaction to the bills. The bill which is not stored was created before the first
+synced block, it's tracked from the transfer setting its owner and value, the other transactions record it as
+untracked until it's seeded from the node. Every transaction increments the counter of the bill, the counter after
+the transaction is recorded for seeding.
+*/
+func (r *billReplay) apply(txr *types.TransactionRecord, txo *types.TransactionOrder) error {
+	switch txo.Type {
+	case money.TransactionTypeTransfer:
+		var attr money.TransferAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		r.set(txo.UnitID, &domain.TrackedBill{PartitionID: r.partitionID, UnitID: txo.UnitID, OwnerID: ownerID(attr.NewOwnerPredicate), Value: attr.TargetValue})
+		r.count(txo.UnitID, attr.Counter)
+	case money.TransactionTypeSplit:
+		var attr money.SplitAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		var amount uint64
+		for _, target := range attr.TargetUnits {
+			amount += target.Amount
+		}
+		r.update(txo.UnitID, func(bill *domain.TrackedBill) { subtractValue(bill, amount) })
+		r.count(txo.UnitID, attr.Counter)
+		for i, unitID := range newUnitIDs(txr, txo.UnitID) {
+			if i < len(attr.TargetUnits) {
+				target := attr.TargetUnits[i]
+				r.set(unitID, &domain.TrackedBill{PartitionID: r.partitionID, UnitID: unitID, OwnerID: ownerID(target.OwnerPredicate), Value: target.Amount})
+			}
+		}
+	case money.TransactionTypeTransDC:
+		var attr money.TransferDCAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		r.dust += int64(attr.Value)
+		r.set(txo.UnitID, nil)
+	case money.TransactionTypeSwapDC:
+		var attr money.SwapDCAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		var amount, counter uint64
+		for i, proof := range attr.DustTransferProofs {
+			var dcAttr money.TransferDCAttributes
+			if err := unmarshalProofAttributes(proof, &dcAttr); err != nil {
+				return err
+			}
+			amount += dcAttr.Value
+			if i == 0 {
+				counter = dcAttr.TargetUnitCounter
+			}
+		}
+		r.dust -= int64(amount)
+		r.update(txo.UnitID, func(bill *domain.TrackedBill) { addValue(bill, amount) })
+		if len(attr.DustTransferProofs) > 0 {
+			r.count(txo.UnitID, counter)
+		}
+	case money.TransactionTypeLock:
+		var attr money.LockAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		r.update(txo.UnitID, func(*domain.TrackedBill) {})
+		r.count(txo.UnitID, attr.Counter)
+	case money.TransactionTypeUnlock:
+		var attr money.UnlockAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		r.update(txo.UnitID, func(*domain.TrackedBill) {})
+		r.count(txo.UnitID, attr.Counter)
+	case fc.TransactionTypeTransferFeeCredit:
+		var attr fc.TransferFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		r.feeCredit += int64(attr.Amount)
+		r.update(txo.UnitID, func(bill *domain.TrackedBill) { subtractValue(bill, attr.Amount) })
+		r.count(txo.UnitID, attr.Counter)
+	case fc.TransactionTypeReclaimFeeCredit:
+		var attr fc.ReclaimFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		var closeAttr fc.CloseFeeCreditAttributes
+		if err := unmarshalProofAttributes(attr.CloseFeeCreditProof, &closeAttr); err != nil {
+			return err
+		}
+		r.feeCredit -= int64(closeAttr.Amount)
+		r.update(txo.UnitID, func(bill *domain.TrackedBill) {
+			if fee := txr.GetActualFee(); closeAttr.Amount >= fee {
+				addValue(bill, closeAttr.Amount-fee)
+			}
+		})
+		r.count(txo.UnitID, closeAttr.TargetUnitCounter)
+	}
+	return nil
+}
+
+// update applies fn to the copy of the bill, the bill which is not known is created as untracked.
+func (r *billReplay) update(unitID types.UnitID, fn func(bill *domain.TrackedBill)) {
+	bill := &domain.TrackedBill{PartitionID: r.partitionID, UnitID: unitID, Untracked: true}
+	if current := r.bills[string(unitID)]; current != nil {
+		*bill = *current
+	}
+	fn(bill)
+	r.set(unitID, bill)
+}
+
+func (r *billReplay) set(unitID types.UnitID, bill *domain.TrackedBill) {
+	key := string(unitID)
+	if _, ok := r.before[key]; !ok {
+		r.before[key] = r.bills[key]
+		r.changed = append(r.changed, unitID)
+	}
+	r.bills[key] = bill
+}
+
+// count records the counter of the bill after the transaction, "counter" is the counter before it.
+func (r *billReplay) count(unitID types.UnitID, counter uint64) {
+	r.counters[string(unitID)] = counter + 1
+}
+
+// untracked returns the changed bills which are untracked after the block.
+func (r *billReplay) untracked() []*domain.TrackedBill {
+	var bills []*domain.TrackedBill
+	for _, unitID := range r.changed {
+		if bill := r.bills[string(unitID)]; bill != nil && bill.Untracked {
+			bills = append(bills, bill)
+		}
+	}
+	return bills
+}
+
+// changes returns the net changes of the block, nil when the block did not change the bills nor the supply.
+func (r *billReplay) changes(blockNumber uint64) *domain.BillChanges {
+	changes := &domain.BillChanges{PartitionID: r.partitionID, BlockNumber: blockNumber, DustCollector: r.dust, FeeCredit: r.feeCredit}
+	for _, unitID := range r.changed {
+		before, after := r.before[string(unitID)], r.bills[string(unitID)]
+		if !sameBill(before, after) {
+			changes.Bills = append(changes.Bills, &domain.BillChange{UnitID: unitID, Before: before, After: after})
+		}
+	}
+	if len(changes.Bills) == 0 && changes.DustCollector == 0 && changes.FeeCredit == 0 {
+		return nil
+	}
+	return changes
+}
+
+func sameBill(a, b *domain.TrackedBill) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Value == b.Value && a.Untracked == b.Untracked && bytes.Equal(a.OwnerID, b.OwnerID)
+}
+
+func addValue(bill *domain.TrackedBill, amount uint64) {
+	if !bill.Untracked {
+		bill.Value += amount
+	}
+}
+
+// subtractValue subtracts the amount from the value of the bill, the bill becomes untracked when its value is less than the amount.
+func subtractValue(bill *domain.TrackedBill, amount uint64) {
+	if bill.Untracked {
+		return
+	}
+	if bill.Value < amount {
+		bill.Value, bill.Untracked = 0, true
+		return
+	}
+	bill.Value -= amount
+}
+
+func ownerID(ownerPredicate []byte) hex.Bytes {
+	if p := predicate.Decode(ownerPredicate); p != nil {
+		return p.OwnerID
+	}
+	return nil
+}
+
+// newUnitIDs returns the IDs of the units created by the transaction, the target units except the unit of the transaction.
+func newUnitIDs(txr *types.TransactionRecord, txUnitID types.UnitID) []types.UnitID {
+	if txr.ServerMetadata == nil {
+		return nil
+	}
+	var unitIDs []types.UnitID
+	for _, unitID := range txr.ServerMetadata.TargetUnits {
+		if !bytes.Equal(unitID, txUnitID) {
+			unitIDs = append(unitIDs, unitID)
+		}
+	}
+	return unitIDs
+}
+
+func unmarshalProofAttributes(proof *types.TxRecordProof, attr any) error {
+	if proof == nil || proof.TxRecord == nil {
+		return nil
+	}
+	txo, err := proof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return err
+	}
+	return txo.UnmarshalAttributes(attr)
+}
@@ -1,11 +1,16 @@
 package blocks
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
 )
 
@@ -15,15 +20,46 @@ type (
 		SetBlockNumber(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) error
 		SetTxInfo(ctx context.Context, txInfo *domain.TxInfo) error
 		SetBlockInfo(ctx context.Context, blockInfo *domain.BlockInfo) error
+		GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error)
+		GetLastBlockBefore(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) (*domain.BlockInfo, error)
+		RollbackBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error
+		SetTokenTexts(ctx context.Context, texts []*domain.TokenText) error
+		GetTrackedBills(ctx context.Context, partitionID types.PartitionID, unitIDs []types.UnitID) ([]*domain.TrackedBill, error)
+		ApplyBillChanges(ctx context.Context, changes *domain.BillChanges) error
+	}
+
+	// RawBlockStore archives the original CBOR encoded blocks
+	RawBlockStore interface {
+		SetRawBlock(ctx context.Context, partitionID types.PartitionID, blockNumber uint64, data []byte) error
+		DeleteRawBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error
+	}
+
+	// EventSink is notified about the transactions of the processed blocks
+	EventSink interface {
+		BlockProcessed(ctx context.Context, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID, blockNumber uint64, txs []*domain.TxInfo) error
 	}
 
 	BlockProcessor struct {
-		store Store
+		store    Store
+		archive  RawBlockStore
+		events   EventSink
+		getBlock GetBlockFunc
+		getUnit  GetUnitFunc
 	}
 )
 
-func NewBlockProcessor(store Store) (*BlockProcessor, error) {
-	return &BlockProcessor{store: store}, nil
+// ErrChainDiverged is returned when the received block does not extend the stored chain,
+// the stored blocks have been rolled back and the sync must be restarted from the new latest block.
+var ErrChainDiverged = errors.New("chain diverged")
+
+// NewBlockProcessor creates new block processor, when "archive" is nil raw blocks are not archived
+// and when "events" is nil no events are emitted. The blocks of the node are loaded with "getBlock"
+// to find the last stored block on the chain of the node when the chain diverges, when "getBlock"
+// is nil only the latest stored block is rolled back at a time. The bills of the money partition created
+// before the first synced block are seeded from the node with "getUnit", when it's nil they stay untracked
+// until transferred.
+func NewBlockProcessor(store Store, archive RawBlockStore, events EventSink, getBlock GetBlockFunc, getUnit GetUnitFunc) (*BlockProcessor, error) {
+	return &BlockProcessor{store: store, archive: archive, events: events, getBlock: getBlock, getUnit: getUnit}, nil
 }
 
 func (p *BlockProcessor) ProcessBlock(ctx context.Context, b *types.Block, partitionTypeID types.PartitionTypeID) error {
@@ -36,22 +72,162 @@ func (p *BlockProcessor) ProcessBlock(ctx context.Context, b *types.Block, parti
 	if err != nil {
 		return fmt.Errorf("failed to get last block number: %w", err)
 	}
+	blockHash, err := b.Hash(crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to calculate block hash: %w", err)
+	}
 	if lastBlockNumber >= roundNumber {
-		return fmt.Errorf("invalid block number. Received blockNumber %d current wallet blockNumber %d", roundNumber, lastBlockNumber)
+		stored, err := p.getStoredBlock(ctx, b.PartitionID(), roundNumber)
+		if err != nil {
+			return err
+		}
+		if stored == nil || len(stored.BlockHash) == 0 {
+			return fmt.Errorf("invalid block number. Received blockNumber %d current wallet blockNumber %d", roundNumber, lastBlockNumber)
+		}
+		if bytes.Equal(stored.BlockHash, blockHash) {
+			log.Debug("block already processed", "partition", b.PartitionID(), "round", roundNumber)
+			return nil
+		}
+		log.Warn("received block differs from the stored block, rolling back",
+			"partition", b.PartitionID(), "round", roundNumber, "latest", lastBlockNumber)
+		if err = p.rollback(ctx, b.PartitionID(), roundNumber); err != nil {
+			return err
+		}
 	}
+	if err = p.verifyPreviousBlock(ctx, b, roundNumber); err != nil {
+		return err
+	}
+	txs := make([]*domain.TxInfo, 0, len(b.Transactions))
 	for i, tx := range b.Transactions {
-		if err = p.processTx(ctx, tx, b, i); err != nil {
+		txInfo, err := p.processTx(ctx, tx, b, i)
+		if err != nil {
 			return fmt.Errorf("failed to process transaction: %w", err)
 		}
+		txs = append(txs, txInfo)
+	}
+	if partitionTypeID == tokens.PartitionTypeID {
+		if texts := tokenTexts(b, roundNumber); len(texts) > 0 {
+			if err = p.store.SetTokenTexts(ctx, texts); err != nil {
+				return fmt.Errorf("failed to save token texts: %w", err)
+			}
+		}
+	}
+	if partitionTypeID == money.PartitionTypeID {
+		if err = p.saveBillChanges(ctx, b, roundNumber); err != nil {
+			return err
+		}
 	}
 	err = p.saveBlock(ctx, b, partitionTypeID)
 	if err != nil {
 		return err
 	}
+	if err = p.archiveBlock(ctx, b, roundNumber); err != nil {
+		return err
+	}
+	// events are emitted before the block number is updated so that the block is processed again
+	// when emitting fails, the sink must not create duplicate events for the same transactions
+	if p.events != nil {
+		if err = p.events.BlockProcessed(ctx, b.PartitionID(), partitionTypeID, roundNumber, txs); err != nil {
+			return fmt.Errorf("failed to emit block events: %w", err)
+		}
+	}
 	return p.store.SetBlockNumber(ctx, b.PartitionID(), roundNumber)
 }
 
-func (p *BlockProcessor) processTx(ctx context.Context, txr *types.TransactionRecord, b *types.Block, txIdx int) error {
+/*
+verifyPreviousBlock checks that the block extends the latest stored block before it, whatever the
+number of the rounds without a block in between. When the hashes do not match the stored blocks are
+rolled back to the last block on the chain of the node and ErrChainDiverged is returned, restarting
+the sync continues from the common ancestor.
+*/
+func (p *BlockProcessor) verifyPreviousBlock(ctx context.Context, b *types.Block, roundNumber uint64) error {
+	previous, err := p.store.GetLastBlockBefore(ctx, b.PartitionID(), roundNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get previous stored block: %w", err)
+	}
+	// blocks stored before block hashes were recorded can't be verified
+	if previous == nil || len(previous.BlockHash) == 0 || b.Header == nil {
+		return nil
+	}
+	if bytes.Equal(previous.BlockHash, b.Header.PreviousBlockHash) {
+		return nil
+	}
+	ancestor, err := p.findCommonAncestor(ctx, b.PartitionID(), previous)
+	if err != nil {
+		return err
+	}
+	log.Warn("block does not extend the stored chain, rolling back",
+		"partition", b.PartitionID(), "round", roundNumber, "previous", previous.BlockNumber, "ancestor", ancestor)
+	if err = p.rollback(ctx, b.PartitionID(), ancestor+1); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: previous block hash of block %d does not match stored block %d of partition %d, rolled back to block %d",
+		ErrChainDiverged, roundNumber, previous.BlockNumber, b.PartitionID(), ancestor)
+}
+
+/*
+findCommonAncestor returns the number of the latest stored block, starting from the block "diverged",
+which is also on the chain of the node. When the diverged block itself is on the chain of the node the
+block of the node in between is missing from the store and nothing is rolled back. Zero is returned
+when none of the stored blocks is on the chain of the node. Without the block loader only the diverged
+block is rolled back.
+*/
+func (p *BlockProcessor) findCommonAncestor(ctx context.Context, partitionID types.PartitionID, diverged *domain.BlockInfo) (uint64, error) {
+	if p.getBlock == nil {
+		return diverged.BlockNumber - 1, nil
+	}
+	candidate := diverged
+	for candidate != nil {
+		// blocks stored before block hashes were recorded can't be compared
+		if len(candidate.BlockHash) == 0 {
+			return candidate.BlockNumber, nil
+		}
+		nodeBlock, err := p.getBlock(ctx, candidate.BlockNumber)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load block %d from the node: %w", candidate.BlockNumber, err)
+		}
+		if nodeBlock != nil {
+			hash, err := nodeBlock.Hash(crypto.SHA256)
+			if err != nil {
+				return 0, fmt.Errorf("failed to calculate block hash: %w", err)
+			}
+			if bytes.Equal(hash, candidate.BlockHash) {
+				return candidate.BlockNumber, nil
+			}
+		}
+		if candidate, err = p.store.GetLastBlockBefore(ctx, partitionID, candidate.BlockNumber); err != nil {
+			return 0, fmt.Errorf("failed to get previous stored block: %w", err)
+		}
+	}
+	return 0, nil
+}
+
+// getStoredBlock returns the stored block or nil when it's not stored.
+func (p *BlockProcessor) getStoredBlock(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) (*domain.BlockInfo, error) {
+	blocks, err := p.store.GetBlock(ctx, blockNumber, []types.PartitionID{partitionID})
+	if err != nil {
+		if errors.Is(err, domain.ErrPruned) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stored block: %w", err)
+	}
+	return blocks[partitionID], nil
+}
+
+// rollback deletes the stored blocks and archived raw blocks starting from "fromBlockNumber".
+func (p *BlockProcessor) rollback(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	if err := p.store.RollbackBlocks(ctx, partitionID, fromBlockNumber); err != nil {
+		return fmt.Errorf("failed to roll back blocks: %w", err)
+	}
+	if p.archive != nil {
+		if err := p.archive.DeleteRawBlocks(ctx, partitionID, fromBlockNumber); err != nil {
+			return fmt.Errorf("failed to roll back raw blocks: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *BlockProcessor) processTx(ctx context.Context, txr *types.TransactionRecord, b *types.Block, txIdx int) (*domain.TxInfo, error) {
 	/*txo := txr.TransactionOrder
 	txHash := txo.Hash(crypto.SHA256)
 	_ = txHash
@@ -64,21 +240,21 @@ func (p *BlockProcessor) processTx(ctx context.Context, txr *types.TransactionRe
 
 	roundNumber, err := b.GetRoundNumber()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	txInfo, err := domain.NewTxInfo(b.PartitionID(), roundNumber, txr)
 
 	if err != nil {
-		return fmt.Errorf("failed create new txInfo in ProcessBlock: %w", err)
+		return nil, fmt.Errorf("failed create new txInfo in ProcessBlock: %w", err)
 	}
 
 	err = p.saveTx(ctx, txInfo)
 	if err != nil {
-		return fmt.Errorf("failed to save tx in ProcessBlock: %w", err)
+		return nil, fmt.Errorf("failed to save tx in ProcessBlock: %w", err)
 	}
 
-	return nil
+	return txInfo, nil
 }
 
 func (p *BlockProcessor) saveTx(ctx context.Context, txInfo *domain.TxInfo) error {
@@ -106,3 +282,17 @@ func (p *BlockProcessor) saveBlock(ctx context.Context, b *types.Block, partitio
 	}
 	return nil
 }
+
+func (p *BlockProcessor) archiveBlock(ctx context.Context, b *types.Block, roundNumber uint64) error {
+	if p.archive == nil {
+		return nil
+	}
+	data, err := types.Cbor.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to encode raw block: %w", err)
+	}
+	if err = p.archive.SetRawBlock(ctx, b.PartitionID(), roundNumber, data); err != nil {
+		return fmt.Errorf("failed to archive raw block: %w", err)
+	}
+	return nil
+}
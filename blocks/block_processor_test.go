@@ -1,12 +1,22 @@
 package blocks
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/blocks"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/testutils"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -16,11 +26,12 @@ func TestBlockProcessor_Success(t *testing.T) {
 	partitionID := types.PartitionID(1)
 	partitionTypeID := types.PartitionTypeID(2)
 	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
 	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
 	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
 	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
 
-	blockProcessor, err := NewBlockProcessor(store)
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	txoBytes, err := (&types.TransactionOrder{}).MarshalCBOR()
@@ -51,7 +62,7 @@ func TestBlockProcessor_FailOnGetBlockNumber(t *testing.T) {
 	partitionTypeID := types.PartitionTypeID(2)
 	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(0), fmt.Errorf("some error"))
 
-	blockProcessor, err := NewBlockProcessor(store)
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	txoBytes, err := (&types.TransactionOrder{}).MarshalCBOR()
@@ -80,11 +91,12 @@ func TestBlockProcessor_FailOnSetBlockNumber(t *testing.T) {
 	partitionID := types.PartitionID(1)
 	partitionTypeID := types.PartitionTypeID(2)
 	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
 	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(fmt.Errorf("some error"))
 	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
 	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
 
-	blockProcessor, err := NewBlockProcessor(store)
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	txoBytes, err := (&types.TransactionOrder{}).MarshalCBOR()
@@ -108,3 +120,370 @@ func TestBlockProcessor_FailOnSetBlockNumber(t *testing.T) {
 
 	store.AssertExpectations(t)
 }
+
+func TestBlockProcessor_ArchivesRawBlock(t *testing.T) {
+	store := mocks.NewStore(t)
+	archive := mocks.NewRawBlockStore(t)
+	partitionID := types.PartitionID(1)
+	partitionTypeID := types.PartitionTypeID(2)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, archive, nil, nil, nil)
+	require.NoError(t, err)
+
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header:             &types.Header{PartitionID: partitionID},
+		UnicityCertificate: unicityCertificate,
+	}
+	blockBytes, err := types.Cbor.Marshal(block)
+	require.NoError(t, err)
+	archive.EXPECT().SetRawBlock(mock.Anything, partitionID, uint64(2), blockBytes).Return(nil)
+
+	err = blockProcessor.ProcessBlock(context.Background(), block, partitionTypeID)
+	require.NoError(t, err)
+}
+
+func TestBlockProcessor_FailOnArchiveRawBlock(t *testing.T) {
+	store := mocks.NewStore(t)
+	archive := mocks.NewRawBlockStore(t)
+	partitionID := types.PartitionID(1)
+	partitionTypeID := types.PartitionTypeID(2)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+	archive.EXPECT().SetRawBlock(mock.Anything, partitionID, uint64(2), mock.Anything).Return(fmt.Errorf("disk full"))
+
+	blockProcessor, err := NewBlockProcessor(store, archive, nil, nil, nil)
+	require.NoError(t, err)
+
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header:             &types.Header{PartitionID: partitionID},
+		UnicityCertificate: unicityCertificate,
+	}
+
+	err = blockProcessor.ProcessBlock(context.Background(), block, partitionTypeID)
+	require.ErrorContains(t, err, "disk full")
+}
+
+func TestBlockProcessor_AlreadyProcessed(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(2), nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	block := newTestBlock(t, partitionID, 2, nil)
+	store.EXPECT().GetBlock(mock.Anything, uint64(2), []types.PartitionID{partitionID}).Return(
+		map[types.PartitionID]*domain.BlockInfo{partitionID: {BlockHash: blockHash(t, block)}}, nil)
+
+	err = blockProcessor.ProcessBlock(context.Background(), block, types.PartitionTypeID(2))
+	require.NoError(t, err)
+}
+
+func TestBlockProcessor_DivergedStoredBlock(t *testing.T) {
+	store := mocks.NewStore(t)
+	archive := mocks.NewRawBlockStore(t)
+	partitionID := types.PartitionID(1)
+
+	blockProcessor, err := NewBlockProcessor(store, archive, nil, nil, nil)
+	require.NoError(t, err)
+
+	previousHash := []byte{1, 2, 3}
+	block := newTestBlock(t, partitionID, 2, previousHash)
+	// stored block 2 differs from the received one, it's rolled back and the received block is processed
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(3), nil).Once()
+	store.EXPECT().GetBlock(mock.Anything, uint64(2), []types.PartitionID{partitionID}).Return(
+		map[types.PartitionID]*domain.BlockInfo{partitionID: {BlockHash: []byte{4, 5, 6}}}, nil)
+	store.EXPECT().RollbackBlocks(mock.Anything, partitionID, uint64(2)).Return(nil)
+	archive.EXPECT().DeleteRawBlocks(mock.Anything, partitionID, uint64(2)).Return(nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(&domain.BlockInfo{BlockNumber: 1, BlockHash: previousHash}, nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+	archive.EXPECT().SetRawBlock(mock.Anything, partitionID, uint64(2), mock.Anything).Return(nil)
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+
+	err = blockProcessor.ProcessBlock(context.Background(), block, types.PartitionTypeID(2))
+	require.NoError(t, err)
+}
+
+func TestBlockProcessor_PreviousBlockHashMismatch(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(&domain.BlockInfo{BlockNumber: 1, BlockHash: []byte{4, 5, 6}}, nil)
+	store.EXPECT().RollbackBlocks(mock.Anything, partitionID, uint64(1)).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	err = blockProcessor.ProcessBlock(context.Background(), newTestBlock(t, partitionID, 2, []byte{1, 2, 3}), types.PartitionTypeID(2))
+	require.ErrorIs(t, err, ErrChainDiverged)
+}
+
+func TestBlockProcessor_PreviousBlockHashMismatchAfterEmptyRounds(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(1)
+	// rounds 3 and 4 have no blocks, block 5 is verified against the stored block 2
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(2), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(5)).Return(&domain.BlockInfo{BlockNumber: 2, BlockHash: []byte{4, 5, 6}}, nil)
+	store.EXPECT().RollbackBlocks(mock.Anything, partitionID, uint64(2)).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	err = blockProcessor.ProcessBlock(context.Background(), newTestBlock(t, partitionID, 5, []byte{1, 2, 3}), types.PartitionTypeID(2))
+	require.ErrorIs(t, err, ErrChainDiverged)
+}
+
+func TestBlockProcessor_RollbackToCommonAncestor(t *testing.T) {
+	store := mocks.NewStore(t)
+	archive := mocks.NewRawBlockStore(t)
+	partitionID := types.PartitionID(1)
+
+	// node chain: 1 <- 2' <- 4' <- 6', stored chain: 1 <- 2 <- 3 <- 5
+	node1 := newTestBlock(t, partitionID, 1, nil)
+	node2 := newTestBlock(t, partitionID, 2, blockHash(t, node1))
+	node4 := newTestBlock(t, partitionID, 4, blockHash(t, node2))
+	node6 := newTestBlock(t, partitionID, 6, blockHash(t, node4))
+	nodeBlocks := map[uint64]*types.Block{1: node1, 2: node2, 4: node4, 6: node6}
+	getBlock := func(_ context.Context, roundNumber uint64) (*types.Block, error) {
+		return nodeBlocks[roundNumber], nil
+	}
+	stored := map[uint64]*domain.BlockInfo{
+		1: {BlockNumber: 1, BlockHash: blockHash(t, node1)},
+		2: {BlockNumber: 2, BlockHash: []byte{2}},
+		3: {BlockNumber: 3, BlockHash: []byte{3}},
+		5: {BlockNumber: 5, BlockHash: []byte{5}},
+	}
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(5), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(6)).Return(stored[5], nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(5)).Return(stored[3], nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(3)).Return(stored[2], nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(stored[1], nil)
+	store.EXPECT().RollbackBlocks(mock.Anything, partitionID, uint64(2)).Return(nil).Once()
+	archive.EXPECT().DeleteRawBlocks(mock.Anything, partitionID, uint64(2)).Return(nil).Once()
+
+	blockProcessor, err := NewBlockProcessor(store, archive, nil, getBlock, nil)
+	require.NoError(t, err)
+
+	err = blockProcessor.ProcessBlock(context.Background(), node6, types.PartitionTypeID(2))
+	require.ErrorIs(t, err, ErrChainDiverged)
+	require.ErrorContains(t, err, "rolled back to block 1")
+}
+
+func newTestBlock(t *testing.T, partitionID types.PartitionID, roundNumber uint64, previousBlockHash []byte) *types.Block {
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: roundNumber}}).MarshalCBOR()
+	require.NoError(t, err)
+	return &types.Block{
+		Header:             &types.Header{PartitionID: partitionID, PreviousBlockHash: previousBlockHash},
+		UnicityCertificate: unicityCertificate,
+	}
+}
+
+func blockHash(t *testing.T, b *types.Block) []byte {
+	hash, err := b.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	return hash
+}
+
+func TestBlockProcessor_EmitsEvents(t *testing.T) {
+	store := mocks.NewStore(t)
+	events := mocks.NewEventSink(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, events, nil, nil)
+	require.NoError(t, err)
+
+	txoBytes, err := (&types.TransactionOrder{}).MarshalCBOR()
+	require.NoError(t, err)
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+	block := &types.Block{
+		Header:             &types.Header{PartitionID: partitionID},
+		Transactions:       []*types.TransactionRecord{{TransactionOrder: txoBytes}},
+		UnicityCertificate: unicityCertificate,
+	}
+
+	// block number is not updated when emitting the events fails
+	events.EXPECT().BlockProcessed(mock.Anything, partitionID, types.PartitionTypeID(2), uint64(2), mock.Anything).Return(fmt.Errorf("db error")).Once()
+	require.ErrorContains(t, blockProcessor.ProcessBlock(context.Background(), block, 2), "failed to emit block events: db error")
+
+	events.EXPECT().BlockProcessed(mock.Anything, partitionID, types.PartitionTypeID(2), uint64(2), mock.Anything).
+		RunAndReturn(func(_ context.Context, _ types.PartitionID, _ types.PartitionTypeID, _ uint64, txs []*domain.TxInfo) error {
+			require.Len(t, txs, 1)
+			require.EqualValues(t, 2, txs[0].BlockNumber)
+			return nil
+		}).Once()
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, 2))
+}
+
+func TestBlockProcessor_SavesTokenTexts(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(5)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	txRecord := func(txType uint16, unitID types.UnitID, attr any, status types.TxStatus) *types.TransactionRecord {
+		attrBytes, err := types.Cbor.Marshal(attr)
+		require.NoError(t, err)
+		txoBytes, err := (&types.TransactionOrder{Payload: types.Payload{Type: txType, UnitID: unitID, Attributes: attrBytes}}).MarshalCBOR()
+		require.NoError(t, err)
+		return &types.TransactionRecord{TransactionOrder: txoBytes, ServerMetadata: &types.ServerMetadata{SuccessIndicator: status}}
+	}
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+	block := &types.Block{
+		Header: &types.Header{PartitionID: partitionID},
+		Transactions: []*types.TransactionRecord{
+			txRecord(tokens.TransactionTypeDefineFT, types.UnitID{1}, &tokens.DefineFungibleTokenAttributes{Symbol: "ALPHA", Name: "Alpha coin"}, types.TxStatusSuccessful),
+			txRecord(tokens.TransactionTypeDefineNFT, types.UnitID{2}, &tokens.DefineNonFungibleTokenAttributes{Symbol: "CATS"}, types.TxStatusFailed),
+			txRecord(tokens.TransactionTypeMintNFT, types.UnitID{3}, &tokens.MintNonFungibleTokenAttributes{Name: "Cat #1", URI: "ipfs://cat1"}, types.TxStatusSuccessful),
+			txRecord(tokens.TransactionTypeTransferNFT, types.UnitID{3}, &tokens.MintNonFungibleTokenAttributes{Name: "not a mint"}, types.TxStatusSuccessful),
+			txRecord(tokens.TransactionTypeMintNFT, types.UnitID{4}, []byte{1}, types.TxStatusSuccessful),
+		},
+		UnicityCertificate: unicityCertificate,
+	}
+
+	store.EXPECT().SetTokenTexts(mock.Anything, []*domain.TokenText{
+		{UnitID: types.UnitID{1}, PartitionID: partitionID, BlockNumber: 2, Kind: domain.TokenTextFungibleTokenType, Symbol: "ALPHA", Name: "Alpha coin"},
+		{UnitID: types.UnitID{3}, PartitionID: partitionID, BlockNumber: 2, Kind: domain.TokenTextNonFungibleToken, Name: "Cat #1", URI: "ipfs://cat1"},
+	}).Return(nil).Once()
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, tokens.PartitionTypeID))
+
+	// texts are not extracted from the blocks of the other partitions
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, tokens.PartitionTypeID+1))
+}
+
+func TestBlockProcessor_SavesBillChanges(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	txRecord := func(txType uint16, unitID types.UnitID, attr any, status types.TxStatus, targetUnits ...types.UnitID) *types.TransactionRecord {
+		attrBytes, err := types.Cbor.Marshal(attr)
+		require.NoError(t, err)
+		txoBytes, err := (&types.TransactionOrder{Payload: types.Payload{Type: txType, UnitID: unitID, Attributes: attrBytes}}).MarshalCBOR()
+		require.NoError(t, err)
+		return &types.TransactionRecord{TransactionOrder: txoBytes, ServerMetadata: &types.ServerMetadata{SuccessIndicator: status, TargetUnits: targetUnits}}
+	}
+	ownerX, ownerY, ownerZ := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32), bytes.Repeat([]byte{3}, 32)
+	billA, billB, billC, billD := types.UnitID{0xA}, types.UnitID{0xB}, types.UnitID{0xC}, types.UnitID{0xD}
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+	block := &types.Block{
+		Header: &types.Header{PartitionID: partitionID},
+		Transactions: []*types.TransactionRecord{
+			txRecord(money.TransactionTypeSplit, billA, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{OwnerPredicate: predicate.P2PKH(ownerY), Amount: 30}}}, types.TxStatusSuccessful, billA, billB),
+			txRecord(money.TransactionTypeTransfer, billC, &money.TransferAttributes{NewOwnerPredicate: predicate.P2PKH(ownerZ), TargetValue: 50}, types.TxStatusSuccessful),
+			txRecord(money.TransactionTypeTransfer, billA, &money.TransferAttributes{NewOwnerPredicate: predicate.P2PKH(ownerZ), TargetValue: 70}, types.TxStatusFailed),
+			txRecord(money.TransactionTypeLock, billD, &money.LockAttributes{LockStatus: 1}, types.TxStatusSuccessful),
+			txRecord(fc.TransactionTypeTransferFeeCredit, billA, &fc.TransferFeeCreditAttributes{Amount: 20}, types.TxStatusSuccessful),
+			txRecord(money.TransactionTypeTransDC, billB, &money.TransferDCAttributes{Value: 30}, types.TxStatusSuccessful),
+		},
+		UnicityCertificate: unicityCertificate,
+	}
+
+	storedA := &domain.TrackedBill{PartitionID: partitionID, UnitID: billA, OwnerID: ownerX, Value: 100}
+	store.EXPECT().GetTrackedBills(mock.Anything, partitionID, mock.Anything).Return([]*domain.TrackedBill{storedA}, nil).Once()
+	store.EXPECT().ApplyBillChanges(mock.Anything, &domain.BillChanges{
+		PartitionID: partitionID,
+		BlockNumber: 2,
+		Bills: []*domain.BillChange{
+			{UnitID: billA, Before: storedA, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billA, OwnerID: ownerX, Value: 50}},
+			{UnitID: billC, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billC, OwnerID: ownerZ, Value: 50}},
+			{UnitID: billD, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billD, Untracked: true}},
+		},
+		DustCollector: 30,
+		FeeCredit:     20,
+	}).Return(nil).Once()
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, money.PartitionTypeID))
+
+	// bills are not tracked on the other partitions
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, money.PartitionTypeID+10))
+}
+
+func TestBlockProcessor_SeedsBills(t *testing.T) {
+	store := mocks.NewStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(1), nil)
+	store.EXPECT().GetLastBlockBefore(mock.Anything, partitionID, uint64(2)).Return(nil, nil)
+	store.EXPECT().SetTxInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockInfo(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().SetBlockNumber(mock.Anything, partitionID, uint64(2)).Return(nil)
+
+	ownerX, ownerY := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	billA, billB, billC, billD := types.UnitID{0xA}, types.UnitID{0xB}, types.UnitID{0xC}, types.UnitID{0xD}
+	// the node returns the unit data as JSON
+	nodeBill := func(value, counter uint64) map[string]any {
+		return map[string]any{"value": fmt.Sprint(value), "ownerPredicate": fmt.Sprintf("0x%x", predicate.P2PKH(ownerX)), "counter": fmt.Sprint(counter)}
+	}
+	getUnit := func(_ context.Context, unitID types.UnitID, _ bool) (*sdktypes.Unit[any], error) {
+		switch {
+		case bytes.Equal(unitID, billA):
+			return &sdktypes.Unit[any]{UnitID: unitID, Data: nodeBill(70, 8)}, nil
+		case bytes.Equal(unitID, billC):
+			// changed after the block
+			return &sdktypes.Unit[any]{UnitID: unitID, Data: nodeBill(10, 5)}, nil
+		case bytes.Equal(unitID, billD):
+			return nil, errors.New("boom")
+		}
+		return nil, nil
+	}
+	blockProcessor, err := NewBlockProcessor(store, nil, nil, nil, getUnit)
+	require.NoError(t, err)
+
+	unicityCertificate, err := (&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 2}}).MarshalCBOR()
+	require.NoError(t, err)
+	block := &types.Block{
+		Header: &types.Header{PartitionID: partitionID},
+		Transactions: []*types.TransactionRecord{
+			testutils.TxRecord(t, partitionID, money.TransactionTypeSplit, billA, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{OwnerPredicate: predicate.P2PKH(ownerY), Amount: 30}}, Counter: 6}, types.TxStatusSuccessful, billB),
+			testutils.TxRecord(t, partitionID, money.TransactionTypeLock, billA, &money.LockAttributes{LockStatus: 1, Counter: 7}, types.TxStatusSuccessful),
+			testutils.TxRecord(t, partitionID, money.TransactionTypeUnlock, billC, &money.UnlockAttributes{Counter: 3}, types.TxStatusSuccessful),
+			testutils.TxRecord(t, partitionID, fc.TransactionTypeTransferFeeCredit, billD, &fc.TransferFeeCreditAttributes{Amount: 20, Counter: 1}, types.TxStatusSuccessful),
+		},
+		UnicityCertificate: unicityCertificate,
+	}
+
+	store.EXPECT().GetTrackedBills(mock.Anything, partitionID, mock.Anything).Return(nil, nil).Once()
+	store.EXPECT().ApplyBillChanges(mock.Anything, &domain.BillChanges{
+		PartitionID: partitionID,
+		BlockNumber: 2,
+		Bills: []*domain.BillChange{
+			// the bill is not changed on the node after its last transaction in the block
+			{UnitID: billA, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billA, OwnerID: ownerX, Value: 70}},
+			{UnitID: billB, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billB, OwnerID: ownerY, Value: 30}},
+			{UnitID: billC, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billC, Untracked: true}},
+			{UnitID: billD, After: &domain.TrackedBill{PartitionID: partitionID, UnitID: billD, Untracked: true}},
+		},
+		FeeCredit: 20,
+	}).Return(nil).Once()
+	require.NoError(t, blockProcessor.ProcessBlock(context.Background(), block, money.PartitionTypeID))
+}
@@ -0,0 +1,201 @@
+package blocks
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+type (
+	// ResetPolicy defines what is done with the stored data of a partition when the partition has been reset.
+	ResetPolicy string
+
+	ResetStore interface {
+		GetBlockNumber(ctx context.Context, partitionID types.PartitionID) (uint64, error)
+		GetChainIdentity(ctx context.Context, partitionID types.PartitionID) (*domain.ChainIdentity, error)
+		SetChainIdentity(ctx context.Context, partitionID types.PartitionID, identity *domain.ChainIdentity) error
+		WipePartition(ctx context.Context, partitionID types.PartitionID) error
+		ArchivePartition(ctx context.Context, partitionID types.PartitionID, resetAt time.Time) error
+	}
+
+	// RawBlockResetter removes the archived raw blocks of the reset partitions
+	RawBlockResetter interface {
+		DeleteRawBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error
+		ArchiveRawBlocks(ctx context.Context, partitionID types.PartitionID, resetAt time.Time) error
+	}
+
+	// GetRoundNumberFunc returns the latest round number of the partition node
+	GetRoundNumberFunc func(ctx context.Context) (uint64, error)
+	// GetBlockFunc returns the block of the partition node, nil when the node doesn't have the block
+	GetBlockFunc func(ctx context.Context, roundNumber uint64) (*types.Block, error)
+
+	ResetDetector struct {
+		store   ResetStore
+		archive RawBlockResetter
+		policy  ResetPolicy
+
+		mu sync.Mutex
+		// number of consecutive checks the round number of the node has been lower than the latest stored block
+		regressions map[types.PartitionID]int
+	}
+)
+
+const (
+	ResetPolicyArchive ResetPolicy = "archive" // move the data into archive collections and sync the new chain
+	ResetPolicyWipe    ResetPolicy = "wipe"    // delete the data and sync the new chain
+	ResetPolicyFail    ResetPolicy = "fail"    // stop syncing the partition until the data is removed manually
+
+	genesisBlockNumber = 1
+	// regressionChecks is the number of consecutive checks the round number of the node must stay lower than the
+	// latest stored block before the partition is considered reset, a lagging or restarted node recovers before that
+	regressionChecks = 3
+)
+
+var (
+	// ErrPartitionReset is returned when the partition has been reset and the reset policy is ResetPolicyFail.
+	ErrPartitionReset = errors.New("partition reset detected")
+	// ErrResetUnconfirmed is returned when the round number of the node has regressed but not for long enough
+	// to consider the partition reset.
+	ErrResetUnconfirmed = errors.New("partition reset not confirmed")
+)
+
+// NewResetDetector creates new reset detector, empty policy defaults to ResetPolicyArchive and "archive" is optional.
+func NewResetDetector(store ResetStore, archive RawBlockResetter, policy ResetPolicy) (*ResetDetector, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	switch policy {
+	case "":
+		policy = ResetPolicyArchive
+	case ResetPolicyArchive, ResetPolicyWipe, ResetPolicyFail:
+	default:
+		return nil, fmt.Errorf("unknown reset policy %q", policy)
+	}
+	return &ResetDetector{store: store, archive: archive, policy: policy, regressions: make(map[types.PartitionID]int)}, nil
+}
+
+/*
+Check compares the chain served by the node with the stored data of the partition and applies
+the reset policy when the partition has been reset. Partition is considered reset when either
+  - the network ID of the node differs from the stored one;
+  - the genesis block hash of the node differs from the stored one;
+  - the round number of the node has been lower than the latest stored block number for regressionChecks
+    consecutive checks, ErrResetUnconfirmed is returned by the checks before that.
+
+The identity of the chain is stored on the first check and after the reset.
+*/
+func (d *ResetDetector) Check(ctx context.Context, partitionID types.PartitionID, networkID types.NetworkID, getRoundNumber GetRoundNumberFunc, getBlock GetBlockFunc) error {
+	identity, err := nodeChainIdentity(ctx, networkID, getBlock)
+	if err != nil {
+		return err
+	}
+	stored, err := d.store.GetChainIdentity(ctx, partitionID)
+	if err != nil {
+		return err
+	}
+	lastBlockNumber, err := d.store.GetBlockNumber(ctx, partitionID)
+	if err != nil {
+		return fmt.Errorf("failed to get last block number: %w", err)
+	}
+	roundNumber, err := getRoundNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get round number: %w", err)
+	}
+
+	reason := resetReason(stored, identity)
+	if reason == "" && roundNumber < lastBlockNumber {
+		regressions := d.regressed(partitionID)
+		if regressions < regressionChecks {
+			return fmt.Errorf("%w: round number %d is lower than latest stored block %d (check %d of %d)",
+				ErrResetUnconfirmed, roundNumber, lastBlockNumber, regressions, regressionChecks)
+		}
+		reason = fmt.Sprintf("round number %d has been lower than latest stored block %d for %d checks", roundNumber, lastBlockNumber, regressions)
+	}
+	d.clearRegressions(partitionID)
+	if reason == "" {
+		if stored == nil || len(stored.GenesisHash) == 0 && len(identity.GenesisHash) > 0 {
+			return d.store.SetChainIdentity(ctx, partitionID, identity)
+		}
+		return nil
+	}
+
+	log.Warn("partition reset detected", "partition", partitionID, "reason", reason, "policy", d.policy)
+	if err = d.reset(ctx, partitionID, time.Now()); err != nil {
+		return fmt.Errorf("failed to reset partition %d (%s): %w", partitionID, reason, err)
+	}
+	log.Info("partition data reset, syncing the new chain", "partition", partitionID, "policy", d.policy)
+	return d.store.SetChainIdentity(ctx, partitionID, identity)
+}
+
+func (d *ResetDetector) reset(ctx context.Context, partitionID types.PartitionID, resetAt time.Time) error {
+	switch d.policy {
+	case ResetPolicyWipe:
+		if err := d.store.WipePartition(ctx, partitionID); err != nil {
+			return err
+		}
+		if d.archive != nil {
+			return d.archive.DeleteRawBlocks(ctx, partitionID, 0)
+		}
+	case ResetPolicyArchive:
+		if err := d.store.ArchivePartition(ctx, partitionID, resetAt); err != nil {
+			return err
+		}
+		if d.archive != nil {
+			return d.archive.ArchiveRawBlocks(ctx, partitionID, resetAt)
+		}
+	default:
+		return ErrPartitionReset
+	}
+	return nil
+}
+
+// regressed counts the check with the regressed round number and returns the number of consecutive regressions.
+func (d *ResetDetector) regressed(partitionID types.PartitionID) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.regressions[partitionID]++
+	return d.regressions[partitionID]
+}
+
+func (d *ResetDetector) clearRegressions(partitionID types.PartitionID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.regressions, partitionID)
+}
+
+// resetReason returns the reason why the chain served by the node is not the stored one, empty string when it is.
+func resetReason(stored, identity *domain.ChainIdentity) string {
+	if stored == nil {
+		return ""
+	}
+	if stored.NetworkID != identity.NetworkID {
+		return fmt.Sprintf("network ID changed from %d to %d", stored.NetworkID, identity.NetworkID)
+	}
+	if len(stored.GenesisHash) > 0 && len(identity.GenesisHash) > 0 && !bytes.Equal(stored.GenesisHash, identity.GenesisHash) {
+		return fmt.Sprintf("genesis block hash changed from %X to %X", []byte(stored.GenesisHash), []byte(identity.GenesisHash))
+	}
+	return ""
+}
+
+func nodeChainIdentity(ctx context.Context, networkID types.NetworkID, getBlock GetBlockFunc) (*domain.ChainIdentity, error) {
+	identity := &domain.ChainIdentity{NetworkID: networkID}
+	genesis, err := getBlock(ctx, genesisBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genesis block: %w", err)
+	}
+	// genesis block may not be available on the node
+	if genesis != nil {
+		if identity.GenesisHash, err = genesis.Hash(crypto.SHA256); err != nil {
+			return nil, fmt.Errorf("failed to calculate genesis block hash: %w", err)
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,138 @@
+package blocks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	mocks "github.com/alphabill-org/alphabill-explorer-backend/internal/mocks/blocks"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetDetector_StoresChainIdentity(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	partitionID := types.PartitionID(1)
+	genesis := newTestBlock(t, partitionID, 1, nil)
+	identity := &domain.ChainIdentity{NetworkID: 3, GenesisHash: blockHash(t, genesis)}
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(nil, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(5), nil)
+	store.EXPECT().SetChainIdentity(mock.Anything, partitionID, identity).Return(nil)
+
+	detector, err := NewResetDetector(store, nil, "")
+	require.NoError(t, err)
+
+	err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(genesis))
+	require.NoError(t, err)
+}
+
+func TestResetDetector_NoReset(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	partitionID := types.PartitionID(1)
+	genesis := newTestBlock(t, partitionID, 1, nil)
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(
+		&domain.ChainIdentity{NetworkID: 3, GenesisHash: blockHash(t, genesis)}, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(10), nil)
+
+	detector, err := NewResetDetector(store, nil, ResetPolicyWipe)
+	require.NoError(t, err)
+
+	err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(genesis))
+	require.NoError(t, err)
+}
+
+func TestResetDetector_RoundNumberRegressed(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	archive := mocks.NewRawBlockResetter(t)
+	partitionID := types.PartitionID(1)
+	identity := &domain.ChainIdentity{NetworkID: 3}
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(identity, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(100), nil)
+	store.EXPECT().ArchivePartition(mock.Anything, partitionID, mock.Anything).Return(nil)
+	archive.EXPECT().ArchiveRawBlocks(mock.Anything, partitionID, mock.Anything).Return(nil)
+	store.EXPECT().SetChainIdentity(mock.Anything, partitionID, identity).Return(nil)
+
+	detector, err := NewResetDetector(store, archive, ResetPolicyArchive)
+	require.NoError(t, err)
+
+	for i := 1; i < regressionChecks; i++ {
+		err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(nil))
+		require.ErrorIs(t, err, ErrResetUnconfirmed)
+		require.ErrorContains(t, err, fmt.Sprintf("check %d of %d", i, regressionChecks))
+	}
+	err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(nil))
+	require.NoError(t, err)
+}
+
+func TestResetDetector_TransientRoundNumberRegression(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(&domain.ChainIdentity{NetworkID: 3}, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(100), nil)
+
+	detector, err := NewResetDetector(store, nil, ResetPolicyArchive)
+	require.NoError(t, err)
+
+	// node catches up before the regression is confirmed, the count starts over
+	for i := 1; i < regressionChecks; i++ {
+		err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(nil))
+		require.ErrorIs(t, err, ErrResetUnconfirmed)
+	}
+	require.NoError(t, detector.Check(context.Background(), partitionID, 3, roundNumber(100), getBlock(nil)))
+	err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(nil))
+	require.ErrorIs(t, err, ErrResetUnconfirmed)
+	require.ErrorContains(t, err, fmt.Sprintf("check 1 of %d", regressionChecks))
+}
+
+func TestResetDetector_GenesisHashChanged(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	archive := mocks.NewRawBlockResetter(t)
+	partitionID := types.PartitionID(1)
+	genesis := newTestBlock(t, partitionID, 1, nil)
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(
+		&domain.ChainIdentity{NetworkID: 3, GenesisHash: []byte{1, 2, 3}}, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(5), nil)
+	store.EXPECT().WipePartition(mock.Anything, partitionID).Return(nil)
+	archive.EXPECT().DeleteRawBlocks(mock.Anything, partitionID, uint64(0)).Return(nil)
+	store.EXPECT().SetChainIdentity(mock.Anything, partitionID,
+		&domain.ChainIdentity{NetworkID: 3, GenesisHash: blockHash(t, genesis)}).Return(nil)
+
+	detector, err := NewResetDetector(store, archive, ResetPolicyWipe)
+	require.NoError(t, err)
+
+	err = detector.Check(context.Background(), partitionID, 3, roundNumber(10), getBlock(genesis))
+	require.NoError(t, err)
+}
+
+func TestResetDetector_FailPolicy(t *testing.T) {
+	store := mocks.NewResetStore(t)
+	partitionID := types.PartitionID(1)
+	store.EXPECT().GetChainIdentity(mock.Anything, partitionID).Return(&domain.ChainIdentity{NetworkID: 3}, nil)
+	store.EXPECT().GetBlockNumber(mock.Anything, partitionID).Return(uint64(5), nil)
+
+	detector, err := NewResetDetector(store, nil, ResetPolicyFail)
+	require.NoError(t, err)
+
+	err = detector.Check(context.Background(), partitionID, 4, roundNumber(10), getBlock(nil))
+	require.ErrorIs(t, err, ErrPartitionReset)
+	require.ErrorContains(t, err, "network ID changed from 3 to 4")
+}
+
+func TestNewResetDetector_InvalidPolicy(t *testing.T) {
+	_, err := NewResetDetector(mocks.NewResetStore(t), nil, "drop")
+	require.ErrorContains(t, err, `unknown reset policy "drop"`)
+}
+
+func roundNumber(rn uint64) GetRoundNumberFunc {
+	return func(ctx context.Context) (uint64, error) {
+		return rn, nil
+	}
+}
+
+func getBlock(genesis *types.Block) GetBlockFunc {
+	return func(ctx context.Context, roundNumber uint64) (*types.Block, error) {
+		return genesis, nil
+	}
+}
@@ -0,0 +1,54 @@
+package blocks
+
+import (
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+/*
+tokenTexts returns the texts of the token types and the non-fungible tokens created by the successful
+transactions of the tokens partition block. Transactions with undecodable attributes are skipped.
+*/
+func tokenTexts(b *types.Block, blockNumber uint64) []*domain.TokenText {
+	var texts []*domain.TokenText
+	for _, txr := range b.Transactions {
+		if txr.TxStatus() != types.TxStatusSuccessful {
+			continue
+		}
+		txo, err := txr.GetTransactionOrderV1()
+		if err != nil {
+			continue
+		}
+		text := &domain.TokenText{UnitID: txo.UnitID, PartitionID: b.PartitionID(), BlockNumber: blockNumber}
+		switch txo.Type {
+		case tokens.TransactionTypeDefineFT:
+			var attr tokens.DefineFungibleTokenAttributes
+			if err = txo.UnmarshalAttributes(&attr); err == nil {
+				text.Kind, text.Symbol, text.Name = domain.TokenTextFungibleTokenType, attr.Symbol, attr.Name
+			}
+		case tokens.TransactionTypeDefineNFT:
+			var attr tokens.DefineNonFungibleTokenAttributes
+			if err = txo.UnmarshalAttributes(&attr); err == nil {
+				text.Kind, text.Symbol, text.Name = domain.TokenTextNonFungibleTokenType, attr.Symbol, attr.Name
+			}
+		case tokens.TransactionTypeMintNFT:
+			var attr tokens.MintNonFungibleTokenAttributes
+			if err = txo.UnmarshalAttributes(&attr); err == nil {
+				text.Kind, text.Name, text.URI = domain.TokenTextNonFungibleToken, attr.Name, attr.URI
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			log.Warn("failed to decode token attributes", "partition", b.PartitionID(), "round", blockNumber, "type", txo.Type, "err", err)
+			continue
+		}
+		if text.Symbol == "" && text.Name == "" && text.URI == "" {
+			continue
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
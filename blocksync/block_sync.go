@@ -110,6 +110,9 @@ func fetchBlocks(
 			roundNumber, err := getRoundNumber(ctx)
 			if err != nil {
 				log.Error("Failed to get latest round number", "err", err)
+			} else if roundNumber+1 < blockNumber {
+				// node is behind the already synced blocks, the partition has most likely been reset
+				return fmt.Errorf("%w: node round number %d is lower than last synced block %d", ErrRoundNumberRegressed, roundNumber, blockNumber-1)
 			} else if roundNumber > blockNumber {
 				log.Info("Could not get block after retries, skipping", "block", blockNumber, "retries", retries, "current_round", roundNumber)
 				blockNumber++
@@ -151,3 +154,6 @@ func loadUntilBlockNumber(maxBN uint64, f BlockLoaderFunc) BlockLoaderFunc {
 }
 
 var errMaxBlockReached = fmt.Errorf("max block number has been reached")
+
+// ErrRoundNumberRegressed is returned when the round number of the node drops below the already synced blocks.
+var ErrRoundNumberRegressed = errors.New("round number regressed")
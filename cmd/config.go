@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
 	"github.com/spf13/viper"
@@ -11,23 +12,104 @@ import (
 
 type (
 	Config struct {
-		Nodes  []Node `mapstructure:"nodes"`
-		DB     DB     `mapstructure:"db"`
-		Server Server `mapstructure:"server"`
-		Log    Log    `mapstructure:"log"`
+		Nodes      []Node     `mapstructure:"nodes"`
+		DB         DB         `mapstructure:"db"`
+		Server     Server     `mapstructure:"server"`
+		Log        Log        `mapstructure:"log"`
+		Archive    Archive    `mapstructure:"archive"`
+		Pruner     Pruner     `mapstructure:"pruner"`
+		RateLimit  RateLimit  `mapstructure:"rate_limit"`
+		Cache      Cache      `mapstructure:"cache"`
+		Webhooks   Webhooks   `mapstructure:"webhooks"`
+		Search     Search     `mapstructure:"search"`
+		Validators Validators `mapstructure:"validators"`
+		Registry   Registry   `mapstructure:"registry"`
+		// TrustBaseFile is the root trust base JSON file the unit state proofs are verified against, the proofs are
+		// not verified when empty
+		TrustBaseFile string `mapstructure:"trust_base_file"`
 	}
 
 	Node struct {
-		URL         string `mapstructure:"url"`
-		BlockNumber uint64 `mapstructure:"block_number"`
+		URL         string    `mapstructure:"url"`
+		BlockNumber uint64    `mapstructure:"block_number"`
+		Retention   Retention `mapstructure:"retention"`
+		OnReset     string    `mapstructure:"on_reset"` // "archive" (default), "wipe" or "fail"
+	}
+
+	// Retention defines how much data is kept for the partition, zero values mean unlimited
+	Retention struct {
+		Blocks uint64        `mapstructure:"blocks"` // number of latest blocks to keep
+		Age    time.Duration `mapstructure:"age"`    // maximum age of the blocks to keep, eg "336h"
+	}
+
+	Pruner struct {
+		Interval time.Duration `mapstructure:"interval"`
 	}
 
 	DB struct {
 		URL string `mapstructure:"url"`
 	}
 
+	// Archive configures the optional raw block archive, archiving is disabled when Type is empty
+	Archive struct {
+		Type string `mapstructure:"type"` // "mongodb" or "filesystem"
+		Path string `mapstructure:"path"` // root directory of the "filesystem" archive
+	}
+
+	// RateLimit configures the API rate limits, requests are not limited when no limits are configured
+	RateLimit struct {
+		Anonymous  RateLimitTier            `mapstructure:"anonymous"` // per IP limits of the requests without API key
+		Tiers      map[string]RateLimitTier `mapstructure:"tiers"`
+		APIKeys    []APIKey                 `mapstructure:"api_keys"`
+		TrustProxy bool                     `mapstructure:"trust_proxy"` // use X-Forwarded-For header as the client address
+	}
+
+	// RateLimitTier defines the token bucket limits, zero rate means unlimited and zero burst defaults to the rate
+	RateLimitTier struct {
+		Rate           float64 `mapstructure:"rate"` // requests per second
+		Burst          int     `mapstructure:"burst"`
+		PartitionRate  float64 `mapstructure:"partition_rate"` // requests per second to the endpoints calling partition nodes
+		PartitionBurst int     `mapstructure:"partition_burst"`
+	}
+
+	APIKey struct {
+		Key  string `mapstructure:"key"`
+		Tier string `mapstructure:"tier"`
+	}
+
+	// Cache configures the in-process cache of blocks and transactions, caching is disabled when Size is zero
+	Cache struct {
+		Size int           `mapstructure:"size"` // number of blocks and transactions to cache
+		TTL  time.Duration `mapstructure:"ttl"`
+	}
+
+	// Webhooks configures the webhook notifications, subscriptions can be created with the API keys of the rate limits
+	Webhooks struct {
+		Enabled     bool          `mapstructure:"enabled"`
+		Interval    time.Duration `mapstructure:"interval"` // how often the due deliveries are checked
+		Timeout     time.Duration `mapstructure:"timeout"`  // timeout of the webhook request
+		MaxAttempts int           `mapstructure:"max_attempts"`
+		BackoffBase time.Duration `mapstructure:"backoff_base"` // retry delay after the first failed attempt, doubled after every attempt
+		BackoffMax  time.Duration `mapstructure:"backoff_max"`
+	}
+
+	Search struct {
+		MinPrefixLength int `mapstructure:"min_prefix_length"` // minimum number of hex digits of the prefix search key
+	}
+
+	// Validators configures the windows the validator statistics can be requested for, the first window is the default
+	Validators struct {
+		Windows []time.Duration `mapstructure:"windows"`
+	}
+
+	// Registry configures how often the partition registry is refreshed from the partition nodes and the stored blocks
+	Registry struct {
+		Interval time.Duration `mapstructure:"interval"`
+	}
+
 	Server struct {
-		Address string `mapstructure:"address"`
+		Address      string `mapstructure:"address"`
+		AdminAddress string `mapstructure:"admin_address"` // address of the admin server publishing /debug/vars, disabled when empty
 	}
 
 	Log struct {
@@ -37,7 +119,28 @@ type (
 	}
 )
 
-const envPrefix = "BLOCK_EXPLORER"
+const (
+	envPrefix = "BLOCK_EXPLORER"
+
+	archiveTypeMongoDB    = "mongodb"
+	archiveTypeFilesystem = "filesystem"
+
+	defaultPrunerInterval = 10 * time.Minute
+	defaultCacheSize      = 10000
+	defaultCacheTTL       = time.Hour
+
+	defaultWebhookInterval    = 5 * time.Second
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 10
+	defaultWebhookBackoffBase = 10 * time.Second
+	defaultWebhookBackoffMax  = time.Hour
+
+	defaultSearchMinPrefixLength = 6
+
+	defaultRegistryInterval = 30 * time.Second
+)
+
+var defaultValidatorWindows = []time.Duration{24 * time.Hour, time.Hour, 7 * 24 * time.Hour}
 
 func LoadConfig(configFilePath string) (*Config, error) {
 	viper.AutomaticEnv()
@@ -65,6 +168,27 @@ func LoadConfig(configFilePath string) (*Config, error) {
 		log.Info("No config file provided, using environment variables only")
 	}
 
+	viper.SetDefault("pruner.interval", defaultPrunerInterval)
+	viper.SetDefault("cache.size", defaultCacheSize)
+	viper.SetDefault("cache.ttl", defaultCacheTTL)
+	viper.SetDefault("webhooks.enabled", false)
+	viper.SetDefault("webhooks.interval", defaultWebhookInterval)
+	viper.SetDefault("webhooks.timeout", defaultWebhookTimeout)
+	viper.SetDefault("webhooks.max_attempts", defaultWebhookMaxAttempts)
+	viper.SetDefault("webhooks.backoff_base", defaultWebhookBackoffBase)
+	viper.SetDefault("webhooks.backoff_max", defaultWebhookBackoffMax)
+	viper.SetDefault("search.min_prefix_length", defaultSearchMinPrefixLength)
+	viper.SetDefault("validators.windows", defaultValidatorWindows)
+	viper.SetDefault("registry.interval", defaultRegistryInterval)
+	viper.SetDefault("server.admin_address", "")
+	viper.SetDefault("trust_base_file", "")
+	// defaults make the anonymous limits configurable with environment variables
+	viper.SetDefault("rate_limit.trust_proxy", false)
+	viper.SetDefault("rate_limit.anonymous.rate", 0)
+	viper.SetDefault("rate_limit.anonymous.burst", 0)
+	viper.SetDefault("rate_limit.anonymous.partition_rate", 0)
+	viper.SetDefault("rate_limit.anonymous.partition_burst", 0)
+
 	// Build the nodes structure manually from environment variables
 	var nodes []map[string]interface{}
 	for i := 0; ; i++ {
@@ -76,6 +200,11 @@ func LoadConfig(configFilePath string) (*Config, error) {
 		nodes = append(nodes, map[string]interface{}{
 			"url":          url,
 			"block_number": blockNumber,
+			"on_reset":     viper.GetString(fmt.Sprintf("nodes.%d.on_reset", i)),
+			"retention": map[string]interface{}{
+				"blocks": viper.GetUint64(fmt.Sprintf("nodes.%d.retention.blocks", i)),
+				"age":    viper.GetDuration(fmt.Sprintf("nodes.%d.retention.age", i)),
+			},
 		})
 	}
 	viper.Set("nodes", nodes)
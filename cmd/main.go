@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -10,14 +12,20 @@ import (
 
 	"github.com/ainvaltin/httpsrv"
 	"github.com/alphabill-org/alphabill-explorer-backend/api"
+	"github.com/alphabill-org/alphabill-explorer-backend/block_store/filesystem"
 	"github.com/alphabill-org/alphabill-explorer-backend/block_store/mongodb"
 	"github.com/alphabill-org/alphabill-explorer-backend/blocks"
 	"github.com/alphabill-org/alphabill-explorer-backend/blocksync"
 	internalrpc "github.com/alphabill-org/alphabill-explorer-backend/client/rpc"
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/lifecycle"
 	moneyservice "github.com/alphabill-org/alphabill-explorer-backend/service/money"
 	"github.com/alphabill-org/alphabill-explorer-backend/service/partition"
 	"github.com/alphabill-org/alphabill-explorer-backend/service/search"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/txsubmit"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unitstate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/validator"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/webhook"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
@@ -27,10 +35,39 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+type rawBlockArchive interface {
+	blocks.RawBlockStore
+	blocks.RawBlockResetter
+	api.RawBlockService
+}
+
+/*
+Usage:
+
+	abexplorer [config]                      run the explorer
+	abexplorer export [config] <snapshot>    export the database into snapshot file
+	abexplorer import [config] <snapshot>    import the snapshot file into the database
+*/
 func main() {
+	args := os.Args[1:]
+	command := ""
+	if len(args) > 0 && (args[0] == cmdExport || args[0] == cmdImport) {
+		command = args[0]
+		args = args[1:]
+	}
+
+	snapshotPath := ""
+	if command != "" {
+		if len(args) == 0 {
+			panic(fmt.Errorf("snapshot file path is required: abexplorer %s [config] <snapshot>", command))
+		}
+		snapshotPath = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
 	configPath := ""
-	if len(os.Args) > 1 {
-		configPath = os.Args[1]
+	if len(args) > 0 {
+		configPath = args[0]
 		log.Info("reading config", "path", configPath)
 	}
 
@@ -49,7 +86,14 @@ func main() {
 
 	log.Info("loaded config: ", "config", config)
 
-	err = Run(context.Background(), config)
+	switch command {
+	case cmdExport:
+		err = Export(context.Background(), config, snapshotPath)
+	case cmdImport:
+		err = Import(context.Background(), config, snapshotPath)
+	default:
+		err = Run(context.Background(), config)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -63,34 +107,99 @@ func Run(ctx context.Context, config *Config) error {
 	}
 	log.Info("created store")
 
+	archive, err := createRawBlockArchive(config.Archive, store)
+	if err != nil {
+		return fmt.Errorf("failed to create raw block archive: %w", err)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	var moneyClient sdktypes.MoneyPartitionClient
-	partitionService, err := partition.NewPartitionService(make(map[types.PartitionID]*partition.Partition))
+	var moneyPartitionID types.PartitionID
+	partitionService, err := partition.NewPartitionService(make(map[types.PartitionID]*partition.Partition), store)
 	if err != nil {
 		return fmt.Errorf("failed to create partition service")
 	}
-	searchService, err := search.NewSearchService(store, make(map[types.PartitionID]search.PartitionClient))
+	searchService, err := search.NewSearchService(store, make(map[types.PartitionID]search.PartitionClient), config.Search.MinPrefixLength)
+	if err != nil {
+		return fmt.Errorf("failed to create search service: %w", err)
+	}
+	lifecycleService, err := lifecycle.NewService(store)
+	if err != nil {
+		return fmt.Errorf("failed to create lifecycle service: %w", err)
+	}
+	trustBase, err := loadTrustBase(config.TrustBaseFile)
+	if err != nil {
+		return fmt.Errorf("failed to load trust base: %w", err)
+	}
+	unitStateService, err := unitstate.NewService(store, make(map[types.PartitionID]unitstate.PartitionClient), trustBase)
+	if err != nil {
+		return fmt.Errorf("failed to create unit state service: %w", err)
+	}
+	validatorService, err := validator.NewValidatorService(store, config.Validators.Windows)
 	if err != nil {
-		return fmt.Errorf("failed to create search service")
+		return fmt.Errorf("failed to create validator service: %w", err)
+	}
+	txSubmitService, err := txsubmit.NewService(store, make(map[types.PartitionID]txsubmit.PartitionClient))
+	if err != nil {
+		return fmt.Errorf("failed to create tx submit service: %w", err)
 	}
 
+	// events are only created and delivered when webhooks are enabled, nil interfaces disable them
+	var eventSink blocks.EventSink
+	var webhookService api.WebhookService
+	if config.Webhooks.Enabled {
+		service, err := webhook.NewService(store)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook service: %w", err)
+		}
+		worker, err := webhook.NewWorker(store, webhook.WorkerConfig{
+			Interval:    config.Webhooks.Interval,
+			Timeout:     config.Webhooks.Timeout,
+			MaxAttempts: config.Webhooks.MaxAttempts,
+			BackoffBase: config.Webhooks.BackoffBase,
+			BackoffMax:  config.Webhooks.BackoffMax,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook worker: %w", err)
+		}
+		eventSink, webhookService = service, service
+		g.Go(func() error {
+			log.Info("starting webhook worker", "interval", config.Webhooks.Interval)
+			return worker.Run(ctx)
+		})
+	}
+
+	retentionPolicies := make(map[types.PartitionID]mongodb.RetentionPolicy)
 	for _, node := range config.Nodes {
 		partitionClient, nodeInfo, err := createPartitionClient(ctx, node)
 		if err != nil {
 			return fmt.Errorf("failed to create partition client: %w", err)
 		}
 
-		partitionService.AddPartition(partitionClient, nodeInfo.PartitionID, nodeInfo.PartitionTypeID)
+		policy := mongodb.RetentionPolicy{Blocks: node.Retention.Blocks, Age: node.Retention.Age}
+		if !policy.IsEmpty() {
+			retentionPolicies[nodeInfo.PartitionID] = policy
+		}
+
+		partitionService.AddPartition(partitionClient, nodeInfo.PartitionID, nodeInfo.PartitionTypeID, nodeInfo.NetworkID, node.URL)
 		searchService.AddPartitionClient(partitionClient, nodeInfo.PartitionID)
+		txSubmitService.AddPartitionClient(partitionClient, nodeInfo.PartitionID)
+		unitStateService.AddPartitionClient(partitionClient, nodeInfo.PartitionID, nodeInfo.PartitionTypeID)
 		if nodeInfo.PartitionTypeID == money.PartitionTypeID {
 			moneyClient, err = client.NewMoneyPartitionClient(ctx, args.BuildRpcUrl(node.URL))
 			if err != nil {
 				return fmt.Errorf("failed to create money partition client: %w", err)
 			}
+			moneyPartitionID = nodeInfo.PartitionID
+		}
+
+		resetDetector, err := blocks.NewResetDetector(store, archive, blocks.ResetPolicy(node.OnReset))
+		if err != nil {
+			return fmt.Errorf("failed to create reset detector: %w", err)
 		}
 
 		g.Go(func() error {
-			blockProcessor, err := blocks.NewBlockProcessor(store)
+			blockProcessor, err := blocks.NewBlockProcessor(store, archive, eventSink, partitionClient.GetBlock, partitionClient.GetUnit)
 			if err != nil {
 				return fmt.Errorf("failed to create block processor: %w", err)
 			}
@@ -118,9 +227,19 @@ func Run(ctx context.Context, config *Config) error {
 			// just retry in a loop until ctx is cancelled
 			for {
 				log.Info("starting block sync")
-				err := runBlockSync(ctx, partitionClient.GetBlock, getRoundNumber, getBlockNumber, 100,
-					blockProcessor.ProcessBlock, nodeInfo.PartitionID, nodeInfo.PartitionTypeID)
-				if err != nil {
+				err := resetDetector.Check(ctx, nodeInfo.PartitionID, nodeInfo.NetworkID, getRoundNumber, partitionClient.GetBlock)
+				if err == nil {
+					err = runBlockSync(ctx, partitionClient.GetBlock, getRoundNumber, getBlockNumber, 100,
+						blockProcessor.ProcessBlock, nodeInfo.PartitionID, nodeInfo.PartitionTypeID)
+				}
+				if errors.Is(err, blocks.ErrChainDiverged) || errors.Is(err, blocksync.ErrRoundNumberRegressed) {
+					// stored blocks were rolled back or the partition was reset, restart the sync right away
+					log.Warn("synchronizing blocks detected chain change", "err", err)
+					continue
+				}
+				if errors.Is(err, blocks.ErrResetUnconfirmed) {
+					log.Warn("node is behind the stored blocks, retrying later", "err", err)
+				} else if err != nil {
 					log.Error("synchronizing blocks returned error", "err", err)
 				}
 				select {
@@ -132,9 +251,61 @@ func Run(ctx context.Context, config *Config) error {
 		})
 	}
 
+	g.Go(func() error {
+		// the explorer serves the requests during the migration, failing it only affects the prefix search
+		if err := store.MigrateSearchKeys(ctx); err != nil && ctx.Err() == nil {
+			log.Error("search keys migration failed", "err", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		log.Info("starting partition registry", "interval", config.Registry.Interval)
+		return partitionService.Run(ctx, config.Registry.Interval)
+	})
+
+	if len(retentionPolicies) > 0 {
+		// filesystem archive is pruned separately, mongodb archive is pruned together with the blocks
+		rawBlockPruner, _ := archive.(mongodb.RawBlockPruner)
+		pruner, err := mongodb.NewPruner(store, rawBlockPruner, retentionPolicies, config.Pruner.Interval)
+		if err != nil {
+			return fmt.Errorf("failed to create pruner: %w", err)
+		}
+		g.Go(func() error {
+			log.Info("starting pruner", "interval", config.Pruner.Interval)
+			return pruner.Run(ctx)
+		})
+	}
+
+	if config.Server.AdminAddress != "" {
+		g.Go(func() error {
+			log.Info("admin server starting", "address", config.Server.AdminAddress)
+			return httpsrv.Run(
+				ctx,
+				&http.Server{
+					Addr:              config.Server.AdminAddress,
+					Handler:           api.AdminRouter(),
+					ReadTimeout:       3 * time.Second,
+					ReadHeaderTimeout: time.Second,
+					WriteTimeout:      5 * time.Second,
+					IdleTimeout:       30 * time.Second,
+				},
+				httpsrv.ShutdownTimeout(5*time.Second))
+		})
+	}
+
 	g.Go(func() error {
 		log.Info("block explorer REST server starting", "address", config.Server.Address)
-		controller, err := api.NewController(store, partitionService, moneyservice.NewMoneyService(moneyClient), searchService)
+		rateLimiter, err := createRateLimiter(config.RateLimit)
+		if err != nil {
+			return fmt.Errorf("failed to create rate limiter: %w", err)
+		}
+		var storage api.StorageService = store
+		if config.Cache.Size > 0 {
+			log.Info("caching blocks and transactions", "size", config.Cache.Size, "ttl", config.Cache.TTL)
+			storage = api.NewCachedStorageService(store, config.Cache.Size, config.Cache.TTL)
+		}
+		controller, err := api.NewController(storage, partitionService, moneyservice.NewMoneyService(moneyClient, moneyPartitionID, store), searchService, lifecycleService, unitStateService, validatorService, archive, txSubmitService, webhookService, rateLimiter)
 		if err != nil {
 			return fmt.Errorf("failed to create controller for rest API: %w", err)
 		}
@@ -155,6 +326,73 @@ func Run(ctx context.Context, config *Config) error {
 	return g.Wait()
 }
 
+// createRawBlockArchive returns nil when raw block archive is not configured.
+func createRawBlockArchive(config Archive, store *mongodb.MongoBlockStore) (rawBlockArchive, error) {
+	switch config.Type {
+	case "":
+		log.Info("raw block archive is disabled")
+		return nil, nil
+	case archiveTypeMongoDB:
+		log.Info("archiving raw blocks in mongodb")
+		return store, nil
+	case archiveTypeFilesystem:
+		log.Info("archiving raw blocks in filesystem", "path", config.Path)
+		return filesystem.NewRawBlockStore(config.Path)
+	default:
+		return nil, fmt.Errorf("unknown raw block archive type %q", config.Type)
+	}
+}
+
+// createRateLimiter returns nil when no rate limits are configured.
+func createRateLimiter(config RateLimit) (*api.RateLimiter, error) {
+	if config.Anonymous == (RateLimitTier{}) && len(config.APIKeys) == 0 {
+		log.Info("API rate limiting is disabled")
+		return nil, nil
+	}
+	cfg := api.RateLimitConfig{
+		Anonymous:  rateLimitTier(config.Anonymous),
+		Tiers:      make(map[string]api.RateLimitTier),
+		APIKeys:    make(map[string]string),
+		TrustProxy: config.TrustProxy,
+	}
+	for name, tier := range config.Tiers {
+		cfg.Tiers[name] = rateLimitTier(tier)
+	}
+	for _, key := range config.APIKeys {
+		if _, ok := cfg.APIKeys[key.Key]; ok {
+			return nil, fmt.Errorf("duplicate API key of the tier %q", key.Tier)
+		}
+		cfg.APIKeys[key.Key] = key.Tier
+	}
+	log.Info("API rate limiting is enabled", "tiers", len(cfg.Tiers), "keys", len(cfg.APIKeys))
+	return api.NewRateLimiter(cfg)
+}
+
+// loadTrustBase returns nil when the trust base file is not configured.
+func loadTrustBase(path string) (types.RootTrustBase, error) {
+	if path == "" {
+		log.Info("trust base is not configured, unit state proofs are not verified")
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trustBase := &types.RootTrustBaseV1{}
+	if err := json.Unmarshal(data, trustBase); err != nil {
+		return nil, fmt.Errorf("failed to decode trust base file %s: %w", path, err)
+	}
+	log.Info("loaded trust base", "path", path)
+	return trustBase, nil
+}
+
+func rateLimitTier(tier RateLimitTier) api.RateLimitTier {
+	return api.RateLimitTier{
+		Requests:          api.RateLimit{Rate: tier.Rate, Burst: tier.Burst},
+		PartitionRequests: api.RateLimit{Rate: tier.PartitionRate, Burst: tier.PartitionBurst},
+	}
+}
+
 func createPartitionClient(ctx context.Context, node Node) (*internalrpc.StateAPIClient, *sdktypes.NodeInfoResponse, error) {
 	log.Info("getting node info", "url", node.URL)
 	adminClient, err := rpc.NewAdminAPIClient(ctx, args.BuildRpcUrl(node.URL))
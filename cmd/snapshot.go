@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/block_store/mongodb"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/snapshot"
+)
+
+const (
+	cmdExport = "export"
+	cmdImport = "import"
+
+	importBatchSize = 500
+	// progressFileExt is appended to the snapshot file name to get the name of the import progress file
+	progressFileExt = ".progress"
+)
+
+// Export writes all the explorer data into the snapshot file.
+func Export(ctx context.Context, config *Config, path string) error {
+	store, err := mongodb.NewMongoBlockStore(ctx, config.DB.URL)
+	if err != nil {
+		return fmt.Errorf("failed to get storage: %w", err)
+	}
+
+	// write into temporary file first so that there is never an incomplete snapshot with the final name
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	w, err := snapshot.NewWriter(f)
+	if err != nil {
+		return err
+	}
+	log.Info("exporting snapshot", "path", path)
+	err = store.ExportSnapshot(ctx, func(collection string, document []byte) error {
+		if err := w.WriteRecord(collection, document); err != nil {
+			return err
+		}
+		if w.Count()%100000 == 0 {
+			log.Info("exporting snapshot", "records", w.Count())
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export snapshot: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+	log.Info("snapshot exported", "path", path, "records", w.Count())
+	return nil
+}
+
+type (
+	// snapshotImporter stores the imported snapshot documents
+	snapshotImporter interface {
+		ImportSnapshotDocuments(ctx context.Context, collection string, documents [][]byte) error
+	}
+
+	// importProgress is the content of the import progress file, the progress is tied to the checksum of the snapshot
+	// so that the import is not resumed with a different snapshot written to the same path.
+	importProgress struct {
+		Checksum string `json:"checksum"`
+		Imported uint64 `json:"imported"`
+	}
+)
+
+/*
+Import verifies the checksum of the snapshot file and imports it into the database.
+
+Import progress is saved into "<path>.progress" file after every batch, when the
+import is interrupted running it again continues from the last imported batch.
+*/
+func Import(ctx context.Context, config *Config, path string) error {
+	log.Info("verifying snapshot", "path", path)
+	trailer, err := verifySnapshot(path)
+	if err != nil {
+		return fmt.Errorf("snapshot verification failed: %w", err)
+	}
+	log.Info("snapshot verified", "records", trailer.Count)
+
+	store, err := mongodb.NewMongoBlockStore(ctx, config.DB.URL)
+	if err != nil {
+		return fmt.Errorf("failed to get storage: %w", err)
+	}
+	return importSnapshot(ctx, store, path, trailer)
+}
+
+// importSnapshot imports the verified snapshot, resuming the import recorded in the progress file of the snapshot.
+func importSnapshot(ctx context.Context, store snapshotImporter, path string, trailer *snapshot.Trailer) error {
+	progressPath := path + progressFileExt
+	imported, err := readImportProgress(progressPath, trailer.Checksum)
+	if err != nil {
+		return err
+	}
+	if imported > 0 {
+		log.Info("resuming snapshot import", "imported", imported, "records", trailer.Count)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	r, err := snapshot.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var (
+		read       uint64
+		collection string
+		batch      [][]byte
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := store.ImportSnapshotDocuments(ctx, collection, batch); err != nil {
+			return err
+		}
+		imported += uint64(len(batch))
+		batch = batch[:0]
+		if err := writeImportProgress(progressPath, trailer.Checksum, imported); err != nil {
+			return err
+		}
+		if imported%100000 < importBatchSize {
+			log.Info("importing snapshot", "imported", imported, "records", trailer.Count)
+		}
+		return nil
+	}
+
+	for {
+		record, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		read++
+		if read <= imported {
+			continue
+		}
+		if record.Collection != collection || len(batch) == importBatchSize {
+			if err = flush(); err != nil {
+				return err
+			}
+			collection = record.Collection
+		}
+		batch = append(batch, record.Document)
+	}
+	if err = flush(); err != nil {
+		return err
+	}
+
+	if err = os.Remove(progressPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn("failed to remove snapshot import progress file", "path", progressPath, "err", err)
+	}
+	log.Info("snapshot imported", "path", path, "records", imported)
+	return nil
+}
+
+func verifySnapshot(path string) (*snapshot.Trailer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer f.Close()
+	return snapshot.Verify(f)
+}
+
+// readImportProgress returns the number of the imported records of the snapshot with the given checksum, the import
+// is not resumed when the progress file belongs to a different snapshot.
+func readImportProgress(path, checksum string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read import progress: %w", err)
+	}
+	var progress importProgress
+	if err = json.Unmarshal(data, &progress); err != nil {
+		return 0, fmt.Errorf("invalid import progress file %s: %w", path, err)
+	}
+	if progress.Checksum != checksum {
+		return 0, fmt.Errorf("import progress file %s belongs to a different snapshot (checksum %s), remove it to import the snapshot from the start",
+			path, progress.Checksum)
+	}
+	return progress.Imported, nil
+}
+
+func writeImportProgress(path, checksum string, imported uint64) error {
+	data, err := json.Marshal(importProgress{Checksum: checksum, Imported: imported})
+	if err != nil {
+		return fmt.Errorf("failed to encode import progress: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save import progress: %w", err)
+	}
+	return nil
+}
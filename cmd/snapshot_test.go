@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+type importerStub struct {
+	documents []string
+	failAfter int // fail when the given number of documents has been imported, zero means never
+}
+
+func (s *importerStub) ImportSnapshotDocuments(_ context.Context, collection string, documents [][]byte) error {
+	if s.failAfter > 0 && len(s.documents) >= s.failAfter {
+		return errors.New("import failed")
+	}
+	for _, doc := range documents {
+		s.documents = append(s.documents, collection+":"+string(doc))
+	}
+	return nil
+}
+
+func TestImportSnapshot(t *testing.T) {
+	path, trailer := writeTestSnapshot(t, "snapshot", importBatchSize+10)
+
+	store := &importerStub{}
+	require.NoError(t, importSnapshot(context.Background(), store, path, trailer))
+	require.Len(t, store.documents, importBatchSize+11)
+	require.Equal(t, `blocks:{"blocknumber":0}`, store.documents[0])
+	require.Equal(t, `metadata:{"partitionid":1}`, store.documents[importBatchSize+10])
+	require.NoFileExists(t, path+progressFileExt)
+}
+
+func TestImportSnapshot_Resume(t *testing.T) {
+	path, trailer := writeTestSnapshot(t, "snapshot", importBatchSize+10)
+
+	// first batch is imported and the second one fails
+	store := &importerStub{failAfter: importBatchSize}
+	require.ErrorContains(t, importSnapshot(context.Background(), store, path, trailer), "import failed")
+	imported, err := readImportProgress(path+progressFileExt, trailer.Checksum)
+	require.NoError(t, err)
+	require.EqualValues(t, importBatchSize, imported)
+
+	store.failAfter = 0
+	require.NoError(t, importSnapshot(context.Background(), store, path, trailer))
+	require.Len(t, store.documents, importBatchSize+11)
+	require.Equal(t, `blocks:{"blocknumber":500}`, store.documents[importBatchSize])
+	require.NoFileExists(t, path+progressFileExt)
+}
+
+func TestImportSnapshot_ProgressOfDifferentSnapshot(t *testing.T) {
+	path, trailer := writeTestSnapshot(t, "snapshot", 10)
+	require.NoError(t, writeImportProgress(path+progressFileExt, "other", 5))
+
+	store := &importerStub{}
+	err := importSnapshot(context.Background(), store, path, trailer)
+	require.ErrorContains(t, err, "belongs to a different snapshot (checksum other)")
+	require.Empty(t, store.documents)
+
+	// progress files of the earlier versions contain only the number of imported records
+	require.NoError(t, os.WriteFile(path+progressFileExt, []byte("5"), 0o644))
+	_, err = readImportProgress(path+progressFileExt, trailer.Checksum)
+	require.ErrorContains(t, err, "invalid import progress file")
+}
+
+// writeTestSnapshot writes a snapshot with the given number of blocks and a metadata record
+func writeTestSnapshot(t *testing.T, name string, blocks int) (string, *snapshot.Trailer) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	w, err := snapshot.NewWriter(f)
+	require.NoError(t, err)
+	for i := range blocks {
+		doc, err := json.Marshal(map[string]int{"blocknumber": i})
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRecord("blocks", doc))
+	}
+	require.NoError(t, w.WriteRecord("metadata", json.RawMessage(`{"partitionid":1}`)))
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	trailer, err := verifySnapshot(path)
+	require.NoError(t, err)
+	return path, trailer
+}
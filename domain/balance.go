@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+)
+
+type (
+	// TrackedBill is the owner and the value of the bill of the money partition tracked for the balances. The bills
+	// created before the first synced block are untracked until they are transferred or seeded from the money node,
+	// the value of the untracked bill is not known and it's not included in the balances.
+	TrackedBill struct {
+		PartitionID types.PartitionID
+		UnitID      types.UnitID
+		OwnerID     hex.Bytes
+		Value       uint64
+		Untracked   bool
+	}
+
+	// BillChange is the state of the bill before and after the block, nil when the bill did not exist or was deleted.
+	BillChange struct {
+		UnitID types.UnitID
+		Before *TrackedBill
+		After  *TrackedBill
+	}
+
+	// BillChanges are the changes of the bills and the supply made by the money partition block, stored for reverting
+	// the block when it's rolled back.
+	BillChanges struct {
+		PartitionID   types.PartitionID
+		BlockNumber   uint64
+		Bills         []*BillChange
+		DustCollector int64 // change of the value of the dust transferred bills waiting for the swap
+		FeeCredit     int64 // change of the value transferred to the fee credit records
+	}
+
+	// Balance is the total value of the tracked bills of the owner.
+	Balance struct {
+		PartitionID types.PartitionID
+		OwnerID     hex.Bytes
+		Balance     uint64
+		BillCount   uint64
+	}
+
+	// BalanceBucket is the number of the owners with the balance in the range [Min, Max), Max is 0 for the last bucket.
+	BalanceBucket struct {
+		Min         uint64
+		Max         uint64 `json:",omitempty"`
+		HolderCount uint64
+		Balance     uint64 // total balance of the owners in the bucket
+	}
+
+	// MoneySupply is the supply of the money partition after the block. The total supply is the state summary value
+	// reported by the partition, the value of all the bills including the bills created before the first synced block,
+	// the other values are counted from the synced blocks.
+	MoneySupply struct {
+		PartitionID    types.PartitionID
+		BlockNumber    uint64
+		TotalSupply    uint64
+		BillsValue     uint64 // value of the tracked bills
+		DustCollector  uint64
+		FeeCredit      uint64 // transferred to the fee credit records and not reclaimed, includes the fees spent
+		HolderCount    uint64
+		UntrackedBills uint64
+	}
+)
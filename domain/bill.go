@@ -1,6 +1,9 @@
 package domain
 
-import "github.com/alphabill-org/alphabill-go-base/types"
+import (
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
 
 type Bill struct {
 	NetworkID   types.NetworkID
@@ -9,4 +12,6 @@ type Bill struct {
 	Value       uint64
 	LockStatus  uint64
 	Counter     uint64
+	// OwnerPredicate is the owner predicate of the address the bills were requested for
+	OwnerPredicate *predicate.Predicate `json:",omitempty"`
 }
@@ -9,6 +9,7 @@ import (
 )
 
 type BlockInfo struct {
+	BlockHash          hex.Bytes
 	PartitionID        types.PartitionID
 	PartitionTypeID    types.PartitionTypeID
 	ShardID            types.ShardID
@@ -18,6 +19,8 @@ type BlockInfo struct {
 	UnicityCertificate types.TaggedCBOR
 	BlockNumber        uint64
 	TxCount            int
+	Fees               uint64 // sum of the actual fees of the transactions
+	Timestamp          uint64 // unicity seal timestamp (seconds since Unix epoch)
 }
 
 func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*BlockInfo, error) {
@@ -25,6 +28,7 @@ func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*Block
 		return nil, fmt.Errorf("block is nil")
 	}
 	txHashes := make([]TxHash, 0, len(b.Transactions))
+	var fees uint64
 
 	for _, tx := range b.Transactions {
 		hash, err := tx.Hash(crypto.SHA256)
@@ -32,6 +36,7 @@ func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*Block
 			return nil, err
 		}
 		txHashes = append(txHashes, hash)
+		fees += tx.GetActualFee()
 	}
 
 	roundNumber, err := b.GetRoundNumber()
@@ -39,6 +44,11 @@ func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*Block
 		return nil, fmt.Errorf("failed to get round number from block: %w", err)
 	}
 
+	blockHash, err := b.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate block hash: %w", err)
+	}
+
 	var (
 		shardID           types.ShardID
 		previousBlockHash hex.Bytes
@@ -48,7 +58,17 @@ func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*Block
 		previousBlockHash = b.Header.PreviousBlockHash
 	}
 
+	uc := &types.UnicityCertificate{}
+	if err = types.Cbor.Unmarshal(b.UnicityCertificate, uc); err != nil {
+		return nil, fmt.Errorf("failed to decode unicity certificate: %w", err)
+	}
+	var timestamp uint64
+	if uc.UnicitySeal != nil {
+		timestamp = uc.UnicitySeal.Timestamp
+	}
+
 	return &BlockInfo{
+		BlockHash:          blockHash,
 		PartitionID:        b.PartitionID(),
 		PartitionTypeID:    partitionTypeID,
 		ShardID:            shardID,
@@ -58,5 +78,7 @@ func NewBlockInfo(b *types.Block, partitionTypeID types.PartitionTypeID) (*Block
 		UnicityCertificate: b.UnicityCertificate,
 		BlockNumber:        roundNumber,
 		TxCount:            len(txHashes),
+		Fees:               fees,
+		Timestamp:          timestamp,
 	}, nil
 }
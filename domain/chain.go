@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+)
+
+// ChainIdentity identifies the chain the stored partition data belongs to.
+type ChainIdentity struct {
+	NetworkID   types.NetworkID
+	GenesisHash hex.Bytes // hash of the genesis block, empty when the node didn't return it
+}
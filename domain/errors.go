@@ -6,4 +6,5 @@ var (
 	ErrNotFound          = errors.New("not found")
 	ErrNilArgument       = errors.New("nil argument")
 	ErrFailedToDecodeHex = errors.New("failed to decode hex")
+	ErrPruned            = errors.New("pruned")
 )
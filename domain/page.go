@@ -0,0 +1,10 @@
+package domain
+
+// PageRequest requests a page of an ordered list. The page contains up to Limit items following
+// the item with the Key, or preceding it when Backward is set. The item with the Key is not included
+// in the page and the first page of the list is requested with nil Key.
+type PageRequest[K any] struct {
+	Key      *K
+	Backward bool
+	Limit    int
+}
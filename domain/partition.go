@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+type (
+	// PartitionInfo is the registry entry of the partition, refreshed periodically from the partition node and the
+	// stored blocks and kept when the node is not available.
+	PartitionInfo struct {
+		PartitionID       types.PartitionID
+		PartitionTypeID   types.PartitionTypeID
+		TypeName          string // "money", "tokens", "orchestration" or "unknown"
+		NetworkID         types.NetworkID
+		Shards            []string // shard IDs of the latest stored blocks, empty string is the shard of an unsharded partition
+		Nodes             []*NodeStatus
+		FirstBlockNumber  uint64 // first stored block, older blocks are pruned or not indexed
+		LatestBlockNumber uint64 // latest indexed block
+		RoundNumber       uint64 // latest round number reported by the nodes
+		EpochNumber       uint64
+		SyncLag           uint64 // rounds the indexed blocks are behind the node
+		BlockCount        uint64
+		TxCount           uint64
+		UpdatedAt         time.Time
+	}
+
+	// NodeStatus is the result of the latest health check of the partition node.
+	NodeStatus struct {
+		URL           string // without credentials and query
+		Healthy       bool
+		RoundNumber   uint64 // latest round number reported by the node
+		Error         string `json:",omitempty"`
+		CheckedAt     time.Time
+		LastHealthyAt *time.Time `json:",omitempty"`
+	}
+
+	// PartitionStats are the statistics of the stored blocks and transactions of the partition.
+	PartitionStats struct {
+		FirstBlockNumber  uint64
+		LatestBlockNumber uint64
+		BlockCount        uint64
+		TxCount           uint64
+		Shards            []string
+	}
+)
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const (
+	TokenTextFungibleTokenType    TokenTextKind = "fungibleTokenType"
+	TokenTextNonFungibleTokenType TokenTextKind = "nonFungibleTokenType"
+	TokenTextNonFungibleToken     TokenTextKind = "nonFungibleToken"
+)
+
+// TokenTextKind is the kind of the unit the token text belongs to.
+type TokenTextKind string
+
+// TokenText is the searchable text of the token type or the non-fungible token, set when the unit is created.
+type TokenText struct {
+	UnitID      types.UnitID
+	PartitionID types.PartitionID
+	BlockNumber uint64
+	Kind        TokenTextKind
+	Symbol      string // symbol of the token type
+	Name        string
+	URI         string // URI of the non-fungible token
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"crypto"
 	"fmt"
+	"time"
 
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -44,3 +45,28 @@ func NewTxInfo(PartitionID types.PartitionID, blockNo uint64, txRecord *types.Tr
 	}
 	return txInfo, nil
 }
+
+// SubmittedTx is a transaction order submitted to the partition node through the explorer.
+type SubmittedTx struct {
+	TxOrderHash TxHash
+	PartitionID types.PartitionID
+	Timeout     uint64
+	SubmittedAt time.Time
+}
+
+// TxExportFilter selects the exported transactions, zero values of the ranges mean unbounded.
+type TxExportFilter struct {
+	UnitIDs    []types.UnitID // transactions targeting any of the units
+	StartBlock uint64
+	EndBlock   uint64
+	StartTime  time.Time
+	EndTime    time.Time
+	Descending bool // newest transactions first
+}
+
+// BlockTx is the transaction together with the data of its block.
+type BlockTx struct {
+	TxInfo          `bson:",inline"`
+	PartitionTypeID types.PartitionTypeID
+	Timestamp       uint64 // unicity seal timestamp of the block (seconds since Unix epoch)
+}
@@ -0,0 +1,15 @@
+package domain
+
+type (
+	// ProposerStats are the statistics of the stored blocks proposed by the validator.
+	ProposerStats struct {
+		NodeID           string
+		BlocksProposed   uint64
+		EmptyBlocks      uint64
+		TxCount          uint64
+		Fees             uint64 // sum of the actual fees of the transactions in the proposed blocks
+		FirstBlockNumber uint64
+		LastBlockNumber  uint64
+		LastProposedAt   uint64 // unicity seal timestamp of the last proposed block
+	}
+)
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // dead-lettered after all the retries failed
+)
+
+type (
+	// WebhookSubscription is a webhook URL notified about the transactions matching the filter.
+	WebhookSubscription struct {
+		ID        primitive.ObjectID `bson:"_id,omitempty"`
+		Owner     string             `json:"-"` // hash of the API key which created the subscription
+		URL       string
+		Secret    hex.Bytes `json:",omitempty"` // HMAC key of the payload signatures
+		Filter    WebhookFilter
+		CreatedAt time.Time
+	}
+
+	// WebhookFilter matches the transactions, all the set fields must match.
+	WebhookFilter struct {
+		// transactions which transfer units to the owner, ie the attributes contain the pubkey hash of the P2PKH predicate
+		OwnerPubKeyHash hex.Bytes          `json:",omitempty"`
+		UnitID          types.UnitID       `json:",omitempty"` // transactions targeting the unit
+		PartitionID     *types.PartitionID `json:",omitempty"`
+		TxType          *uint16            `json:",omitempty"`
+	}
+
+	WebhookEvent struct {
+		ID             string // unique and stable across the retries, subscribers should use it for deduplication
+		SubscriptionID primitive.ObjectID
+		PartitionID    types.PartitionID
+		BlockNumber    uint64
+		TxRecordHash   TxHash
+		TxOrderHash    TxHash
+		TxType         uint16
+		UnitID         types.UnitID
+		TargetUnits    []types.UnitID
+		Successful     bool
+		CreatedAt      time.Time
+	}
+
+	WebhookDeliveryStatus string
+
+	// WebhookDelivery is the delivery state of the event to the subscription.
+	WebhookDelivery struct {
+		ID             string `bson:"_id"` // event ID
+		SubscriptionID primitive.ObjectID
+		Event          *WebhookEvent
+		Status         WebhookDeliveryStatus
+		Attempts       int
+		NextAttemptAt  time.Time
+		LastError      string     `json:",omitempty"`
+		ExpireAt       *time.Time `json:"-" bson:",omitempty"` // delivered events are removed after this time
+	}
+)
+
+func (f *WebhookFilter) IsEmpty() bool {
+	return len(f.OwnerPubKeyHash) == 0 && len(f.UnitID) == 0 && f.PartitionID == nil && f.TxType == nil
+}
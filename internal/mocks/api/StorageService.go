@@ -9,6 +9,7 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	types "github.com/alphabill-org/alphabill-go-base/types"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // StorageService is an autogenerated mock type for the StorageService type
@@ -24,6 +25,54 @@ func (_m *StorageService) EXPECT() *StorageService_Expecter {
 	return &StorageService_Expecter{mock: &_m.Mock}
 }
 
+// ExportTxs provides a mock function with given fields: ctx, filter, fn
+func (_m *StorageService) ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportTxs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TxExportFilter, func(tx *domain.BlockTx) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageService_ExportTxs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportTxs'
+type StorageService_ExportTxs_Call struct {
+	*mock.Call
+}
+
+// ExportTxs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.TxExportFilter
+//   - fn func(tx *domain.BlockTx) error
+func (_e *StorageService_Expecter) ExportTxs(ctx interface{}, filter interface{}, fn interface{}) *StorageService_ExportTxs_Call {
+	return &StorageService_ExportTxs_Call{Call: _e.mock.On("ExportTxs", ctx, filter, fn)}
+}
+
+func (_c *StorageService_ExportTxs_Call) Run(run func(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error)) *StorageService_ExportTxs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.TxExportFilter), args[2].(func(tx *domain.BlockTx) error))
+	})
+	return _c
+}
+
+func (_c *StorageService_ExportTxs_Call) Return(_a0 error) *StorageService_ExportTxs_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageService_ExportTxs_Call) RunAndReturn(run func(context.Context, domain.TxExportFilter, func(tx *domain.BlockTx) error) error) *StorageService_ExportTxs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindTxs provides a mock function with given fields: ctx, searchKey, partitionIDs
 func (_m *StorageService) FindTxs(ctx context.Context, searchKey []byte, partitionIDs []types.PartitionID) ([]*domain.TxInfo, error) {
 	ret := _m.Called(ctx, searchKey, partitionIDs)
@@ -144,36 +193,155 @@ func (_c *StorageService_GetBlock_Call) RunAndReturn(run func(context.Context, u
 	return _c
 }
 
-// GetBlocksInRange provides a mock function with given fields: ctx, partitionID, dbStartBlock, count, includeEmpty
-func (_m *StorageService) GetBlocksInRange(ctx context.Context, partitionID types.PartitionID, dbStartBlock uint64, count int, includeEmpty bool) ([]*domain.BlockInfo, uint64, error) {
-	ret := _m.Called(ctx, partitionID, dbStartBlock, count, includeEmpty)
+// GetBlockByHash provides a mock function with given fields: ctx, blockHash
+func (_m *StorageService) GetBlockByHash(ctx context.Context, blockHash []byte) (*domain.BlockInfo, error) {
+	ret := _m.Called(ctx, blockHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockByHash")
+	}
+
+	var r0 *domain.BlockInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*domain.BlockInfo, error)); ok {
+		return rf(ctx, blockHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *domain.BlockInfo); ok {
+		r0 = rf(ctx, blockHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BlockInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, blockHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageService_GetBlockByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlockByHash'
+type StorageService_GetBlockByHash_Call struct {
+	*mock.Call
+}
+
+// GetBlockByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockHash []byte
+func (_e *StorageService_Expecter) GetBlockByHash(ctx interface{}, blockHash interface{}) *StorageService_GetBlockByHash_Call {
+	return &StorageService_GetBlockByHash_Call{Call: _e.mock.On("GetBlockByHash", ctx, blockHash)}
+}
+
+func (_c *StorageService_GetBlockByHash_Call) Run(run func(ctx context.Context, blockHash []byte)) *StorageService_GetBlockByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]byte))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetBlockByHash_Call) Return(_a0 *domain.BlockInfo, _a1 error) *StorageService_GetBlockByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageService_GetBlockByHash_Call) RunAndReturn(run func(context.Context, []byte) (*domain.BlockInfo, error)) *StorageService_GetBlockByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlocksByNumbers provides a mock function with given fields: ctx, partitionID, blockNumbers
+func (_m *StorageService) GetBlocksByNumbers(ctx context.Context, partitionID types.PartitionID, blockNumbers []uint64) ([]*domain.BlockInfo, error) {
+	ret := _m.Called(ctx, partitionID, blockNumbers)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBlocksInRange")
+		panic("no return value specified for GetBlocksByNumbers")
 	}
 
 	var r0 []*domain.BlockInfo
-	var r1 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, []uint64) ([]*domain.BlockInfo, error)); ok {
+		return rf(ctx, partitionID, blockNumbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, []uint64) []*domain.BlockInfo); ok {
+		r0 = rf(ctx, partitionID, blockNumbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.BlockInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, []uint64) error); ok {
+		r1 = rf(ctx, partitionID, blockNumbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageService_GetBlocksByNumbers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksByNumbers'
+type StorageService_GetBlocksByNumbers_Call struct {
+	*mock.Call
+}
+
+// GetBlocksByNumbers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionID types.PartitionID
+//   - blockNumbers []uint64
+func (_e *StorageService_Expecter) GetBlocksByNumbers(ctx interface{}, partitionID interface{}, blockNumbers interface{}) *StorageService_GetBlocksByNumbers_Call {
+	return &StorageService_GetBlocksByNumbers_Call{Call: _e.mock.On("GetBlocksByNumbers", ctx, partitionID, blockNumbers)}
+}
+
+func (_c *StorageService_GetBlocksByNumbers_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, blockNumbers []uint64)) *StorageService_GetBlocksByNumbers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].([]uint64))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetBlocksByNumbers_Call) Return(_a0 []*domain.BlockInfo, _a1 error) *StorageService_GetBlocksByNumbers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageService_GetBlocksByNumbers_Call) RunAndReturn(run func(context.Context, types.PartitionID, []uint64) ([]*domain.BlockInfo, error)) *StorageService_GetBlocksByNumbers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlocksPage provides a mock function with given fields: ctx, partitionID, page, includeEmpty
+func (_m *StorageService) GetBlocksPage(ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool) ([]*domain.BlockInfo, bool, error) {
+	ret := _m.Called(ctx, partitionID, page, includeEmpty)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlocksPage")
+	}
+
+	var r0 []*domain.BlockInfo
+	var r1 bool
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, uint64, int, bool) ([]*domain.BlockInfo, uint64, error)); ok {
-		return rf(ctx, partitionID, dbStartBlock, count, includeEmpty)
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) ([]*domain.BlockInfo, bool, error)); ok {
+		return rf(ctx, partitionID, page, includeEmpty)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, uint64, int, bool) []*domain.BlockInfo); ok {
-		r0 = rf(ctx, partitionID, dbStartBlock, count, includeEmpty)
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) []*domain.BlockInfo); ok {
+		r0 = rf(ctx, partitionID, page, includeEmpty)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.BlockInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, uint64, int, bool) uint64); ok {
-		r1 = rf(ctx, partitionID, dbStartBlock, count, includeEmpty)
+	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) bool); ok {
+		r1 = rf(ctx, partitionID, page, includeEmpty)
 	} else {
-		r1 = ret.Get(1).(uint64)
+		r1 = ret.Get(1).(bool)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, types.PartitionID, uint64, int, bool) error); ok {
-		r2 = rf(ctx, partitionID, dbStartBlock, count, includeEmpty)
+	if rf, ok := ret.Get(2).(func(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) error); ok {
+		r2 = rf(ctx, partitionID, page, includeEmpty)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -181,34 +349,94 @@ func (_m *StorageService) GetBlocksInRange(ctx context.Context, partitionID type
 	return r0, r1, r2
 }
 
-// StorageService_GetBlocksInRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksInRange'
-type StorageService_GetBlocksInRange_Call struct {
+// StorageService_GetBlocksPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksPage'
+type StorageService_GetBlocksPage_Call struct {
 	*mock.Call
 }
 
-// GetBlocksInRange is a helper method to define mock.On call
+// GetBlocksPage is a helper method to define mock.On call
 //   - ctx context.Context
 //   - partitionID types.PartitionID
-//   - dbStartBlock uint64
-//   - count int
+//   - page domain.PageRequest[uint64]
+//   - includeEmpty bool
+func (_e *StorageService_Expecter) GetBlocksPage(ctx interface{}, partitionID interface{}, page interface{}, includeEmpty interface{}) *StorageService_GetBlocksPage_Call {
+	return &StorageService_GetBlocksPage_Call{Call: _e.mock.On("GetBlocksPage", ctx, partitionID, page, includeEmpty)}
+}
+
+func (_c *StorageService_GetBlocksPage_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool)) *StorageService_GetBlocksPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(domain.PageRequest[uint64]), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetBlocksPage_Call) Return(blocks []*domain.BlockInfo, hasMore bool, err error) *StorageService_GetBlocksPage_Call {
+	_c.Call.Return(blocks, hasMore, err)
+	return _c
+}
+
+func (_c *StorageService_GetBlocksPage_Call) RunAndReturn(run func(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) ([]*domain.BlockInfo, bool, error)) *StorageService_GetBlocksPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlocksPages provides a mock function with given fields: ctx, partitionIDs, page, includeEmpty
+func (_m *StorageService) GetBlocksPages(ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool) (map[types.PartitionID][]*domain.BlockInfo, error) {
+	ret := _m.Called(ctx, partitionIDs, page, includeEmpty)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlocksPages")
+	}
+
+	var r0 map[types.PartitionID][]*domain.BlockInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.PartitionID, domain.PageRequest[uint64], bool) (map[types.PartitionID][]*domain.BlockInfo, error)); ok {
+		return rf(ctx, partitionIDs, page, includeEmpty)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []types.PartitionID, domain.PageRequest[uint64], bool) map[types.PartitionID][]*domain.BlockInfo); ok {
+		r0 = rf(ctx, partitionIDs, page, includeEmpty)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[types.PartitionID][]*domain.BlockInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []types.PartitionID, domain.PageRequest[uint64], bool) error); ok {
+		r1 = rf(ctx, partitionIDs, page, includeEmpty)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageService_GetBlocksPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksPages'
+type StorageService_GetBlocksPages_Call struct {
+	*mock.Call
+}
+
+// GetBlocksPages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionIDs []types.PartitionID
+//   - page domain.PageRequest[uint64]
 //   - includeEmpty bool
-func (_e *StorageService_Expecter) GetBlocksInRange(ctx interface{}, partitionID interface{}, dbStartBlock interface{}, count interface{}, includeEmpty interface{}) *StorageService_GetBlocksInRange_Call {
-	return &StorageService_GetBlocksInRange_Call{Call: _e.mock.On("GetBlocksInRange", ctx, partitionID, dbStartBlock, count, includeEmpty)}
+func (_e *StorageService_Expecter) GetBlocksPages(ctx interface{}, partitionIDs interface{}, page interface{}, includeEmpty interface{}) *StorageService_GetBlocksPages_Call {
+	return &StorageService_GetBlocksPages_Call{Call: _e.mock.On("GetBlocksPages", ctx, partitionIDs, page, includeEmpty)}
 }
 
-func (_c *StorageService_GetBlocksInRange_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, dbStartBlock uint64, count int, includeEmpty bool)) *StorageService_GetBlocksInRange_Call {
+func (_c *StorageService_GetBlocksPages_Call) Run(run func(ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool)) *StorageService_GetBlocksPages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(uint64), args[3].(int), args[4].(bool))
+		run(args[0].(context.Context), args[1].([]types.PartitionID), args[2].(domain.PageRequest[uint64]), args[3].(bool))
 	})
 	return _c
 }
 
-func (_c *StorageService_GetBlocksInRange_Call) Return(res []*domain.BlockInfo, prevBlockNumber uint64, err error) *StorageService_GetBlocksInRange_Call {
-	_c.Call.Return(res, prevBlockNumber, err)
+func (_c *StorageService_GetBlocksPages_Call) Return(_a0 map[types.PartitionID][]*domain.BlockInfo, _a1 error) *StorageService_GetBlocksPages_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *StorageService_GetBlocksInRange_Call) RunAndReturn(run func(context.Context, types.PartitionID, uint64, int, bool) ([]*domain.BlockInfo, uint64, error)) *StorageService_GetBlocksInRange_Call {
+func (_c *StorageService_GetBlocksPages_Call) RunAndReturn(run func(context.Context, []types.PartitionID, domain.PageRequest[uint64], bool) (map[types.PartitionID][]*domain.BlockInfo, error)) *StorageService_GetBlocksPages_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -333,29 +561,29 @@ func (_c *StorageService_GetTxByHash_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
-// GetTxsByBlockNumber provides a mock function with given fields: ctx, blockNumber, partitionID
-func (_m *StorageService) GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, partitionID types.PartitionID) ([]*domain.TxInfo, error) {
-	ret := _m.Called(ctx, blockNumber, partitionID)
+// GetTxsByHashes provides a mock function with given fields: ctx, txHashes
+func (_m *StorageService) GetTxsByHashes(ctx context.Context, txHashes []domain.TxHash) ([]*domain.TxInfo, error) {
+	ret := _m.Called(ctx, txHashes)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTxsByBlockNumber")
+		panic("no return value specified for GetTxsByHashes")
 	}
 
 	var r0 []*domain.TxInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, types.PartitionID) ([]*domain.TxInfo, error)); ok {
-		return rf(ctx, blockNumber, partitionID)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TxHash) ([]*domain.TxInfo, error)); ok {
+		return rf(ctx, txHashes)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, types.PartitionID) []*domain.TxInfo); ok {
-		r0 = rf(ctx, blockNumber, partitionID)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TxHash) []*domain.TxInfo); ok {
+		r0 = rf(ctx, txHashes)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.TxInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, types.PartitionID) error); ok {
-		r1 = rf(ctx, blockNumber, partitionID)
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.TxHash) error); ok {
+		r1 = rf(ctx, txHashes)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -363,125 +591,132 @@ func (_m *StorageService) GetTxsByBlockNumber(ctx context.Context, blockNumber u
 	return r0, r1
 }
 
-// StorageService_GetTxsByBlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByBlockNumber'
-type StorageService_GetTxsByBlockNumber_Call struct {
+// StorageService_GetTxsByHashes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByHashes'
+type StorageService_GetTxsByHashes_Call struct {
 	*mock.Call
 }
 
-// GetTxsByBlockNumber is a helper method to define mock.On call
+// GetTxsByHashes is a helper method to define mock.On call
 //   - ctx context.Context
-//   - blockNumber uint64
-//   - partitionID types.PartitionID
-func (_e *StorageService_Expecter) GetTxsByBlockNumber(ctx interface{}, blockNumber interface{}, partitionID interface{}) *StorageService_GetTxsByBlockNumber_Call {
-	return &StorageService_GetTxsByBlockNumber_Call{Call: _e.mock.On("GetTxsByBlockNumber", ctx, blockNumber, partitionID)}
+//   - txHashes []domain.TxHash
+func (_e *StorageService_Expecter) GetTxsByHashes(ctx interface{}, txHashes interface{}) *StorageService_GetTxsByHashes_Call {
+	return &StorageService_GetTxsByHashes_Call{Call: _e.mock.On("GetTxsByHashes", ctx, txHashes)}
 }
 
-func (_c *StorageService_GetTxsByBlockNumber_Call) Run(run func(ctx context.Context, blockNumber uint64, partitionID types.PartitionID)) *StorageService_GetTxsByBlockNumber_Call {
+func (_c *StorageService_GetTxsByHashes_Call) Run(run func(ctx context.Context, txHashes []domain.TxHash)) *StorageService_GetTxsByHashes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(types.PartitionID))
+		run(args[0].(context.Context), args[1].([]domain.TxHash))
 	})
 	return _c
 }
 
-func (_c *StorageService_GetTxsByBlockNumber_Call) Return(_a0 []*domain.TxInfo, _a1 error) *StorageService_GetTxsByBlockNumber_Call {
+func (_c *StorageService_GetTxsByHashes_Call) Return(_a0 []*domain.TxInfo, _a1 error) *StorageService_GetTxsByHashes_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *StorageService_GetTxsByBlockNumber_Call) RunAndReturn(run func(context.Context, uint64, types.PartitionID) ([]*domain.TxInfo, error)) *StorageService_GetTxsByBlockNumber_Call {
+func (_c *StorageService_GetTxsByHashes_Call) RunAndReturn(run func(context.Context, []domain.TxHash) ([]*domain.TxInfo, error)) *StorageService_GetTxsByHashes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTxsByUnitID provides a mock function with given fields: ctx, unitID
-func (_m *StorageService) GetTxsByUnitID(ctx context.Context, unitID types.UnitID) ([]*domain.TxInfo, error) {
-	ret := _m.Called(ctx, unitID)
+// GetTxsPage provides a mock function with given fields: ctx, partitionID, page
+func (_m *StorageService) GetTxsPage(ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error) {
+	ret := _m.Called(ctx, partitionID, page)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTxsByUnitID")
+		panic("no return value specified for GetTxsPage")
 	}
 
 	var r0 []*domain.TxInfo
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.UnitID) ([]*domain.TxInfo, error)); ok {
-		return rf(ctx, unitID)
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error)); ok {
+		return rf(ctx, partitionID, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.UnitID) []*domain.TxInfo); ok {
-		r0 = rf(ctx, unitID)
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, domain.PageRequest[primitive.ObjectID]) []*domain.TxInfo); ok {
+		r0 = rf(ctx, partitionID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.TxInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.UnitID) error); ok {
-		r1 = rf(ctx, unitID)
+	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, domain.PageRequest[primitive.ObjectID]) bool); ok {
+		r1 = rf(ctx, partitionID, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, types.PartitionID, domain.PageRequest[primitive.ObjectID]) error); ok {
+		r2 = rf(ctx, partitionID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// StorageService_GetTxsByUnitID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsByUnitID'
-type StorageService_GetTxsByUnitID_Call struct {
+// StorageService_GetTxsPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPage'
+type StorageService_GetTxsPage_Call struct {
 	*mock.Call
 }
 
-// GetTxsByUnitID is a helper method to define mock.On call
+// GetTxsPage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - unitID types.UnitID
-func (_e *StorageService_Expecter) GetTxsByUnitID(ctx interface{}, unitID interface{}) *StorageService_GetTxsByUnitID_Call {
-	return &StorageService_GetTxsByUnitID_Call{Call: _e.mock.On("GetTxsByUnitID", ctx, unitID)}
+//   - partitionID types.PartitionID
+//   - page domain.PageRequest[primitive.ObjectID]
+func (_e *StorageService_Expecter) GetTxsPage(ctx interface{}, partitionID interface{}, page interface{}) *StorageService_GetTxsPage_Call {
+	return &StorageService_GetTxsPage_Call{Call: _e.mock.On("GetTxsPage", ctx, partitionID, page)}
 }
 
-func (_c *StorageService_GetTxsByUnitID_Call) Run(run func(ctx context.Context, unitID types.UnitID)) *StorageService_GetTxsByUnitID_Call {
+func (_c *StorageService_GetTxsPage_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[primitive.ObjectID])) *StorageService_GetTxsPage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.UnitID))
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(domain.PageRequest[primitive.ObjectID]))
 	})
 	return _c
 }
 
-func (_c *StorageService_GetTxsByUnitID_Call) Return(_a0 []*domain.TxInfo, _a1 error) *StorageService_GetTxsByUnitID_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *StorageService_GetTxsPage_Call) Return(transactions []*domain.TxInfo, hasMore bool, err error) *StorageService_GetTxsPage_Call {
+	_c.Call.Return(transactions, hasMore, err)
 	return _c
 }
 
-func (_c *StorageService_GetTxsByUnitID_Call) RunAndReturn(run func(context.Context, types.UnitID) ([]*domain.TxInfo, error)) *StorageService_GetTxsByUnitID_Call {
+func (_c *StorageService_GetTxsPage_Call) RunAndReturn(run func(context.Context, types.PartitionID, domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error)) *StorageService_GetTxsPage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTxsPage provides a mock function with given fields: ctx, partitionID, startID, limit
-func (_m *StorageService) GetTxsPage(ctx context.Context, partitionID types.PartitionID, startID string, limit int) ([]*domain.TxInfo, string, error) {
-	ret := _m.Called(ctx, partitionID, startID, limit)
+// GetTxsPageByBlockNumber provides a mock function with given fields: ctx, blockNumber, partitionID, page
+func (_m *StorageService) GetTxsPageByBlockNumber(ctx context.Context, blockNumber uint64, partitionID types.PartitionID, page domain.PageRequest[domain.TxHash]) ([]*domain.TxInfo, bool, error) {
+	ret := _m.Called(ctx, blockNumber, partitionID, page)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTxsPage")
+		panic("no return value specified for GetTxsPageByBlockNumber")
 	}
 
 	var r0 []*domain.TxInfo
-	var r1 string
+	var r1 bool
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, string, int) ([]*domain.TxInfo, string, error)); ok {
-		return rf(ctx, partitionID, startID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, types.PartitionID, domain.PageRequest[domain.TxHash]) ([]*domain.TxInfo, bool, error)); ok {
+		return rf(ctx, blockNumber, partitionID, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, string, int) []*domain.TxInfo); ok {
-		r0 = rf(ctx, partitionID, startID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, types.PartitionID, domain.PageRequest[domain.TxHash]) []*domain.TxInfo); ok {
+		r0 = rf(ctx, blockNumber, partitionID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.TxInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, string, int) string); ok {
-		r1 = rf(ctx, partitionID, startID, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, types.PartitionID, domain.PageRequest[domain.TxHash]) bool); ok {
+		r1 = rf(ctx, blockNumber, partitionID, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(bool)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, types.PartitionID, string, int) error); ok {
-		r2 = rf(ctx, partitionID, startID, limit)
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, types.PartitionID, domain.PageRequest[domain.TxHash]) error); ok {
+		r2 = rf(ctx, blockNumber, partitionID, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -489,33 +724,220 @@ func (_m *StorageService) GetTxsPage(ctx context.Context, partitionID types.Part
 	return r0, r1, r2
 }
 
-// StorageService_GetTxsPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPage'
-type StorageService_GetTxsPage_Call struct {
+// StorageService_GetTxsPageByBlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPageByBlockNumber'
+type StorageService_GetTxsPageByBlockNumber_Call struct {
 	*mock.Call
 }
 
-// GetTxsPage is a helper method to define mock.On call
+// GetTxsPageByBlockNumber is a helper method to define mock.On call
 //   - ctx context.Context
+//   - blockNumber uint64
 //   - partitionID types.PartitionID
-//   - startID string
-//   - limit int
-func (_e *StorageService_Expecter) GetTxsPage(ctx interface{}, partitionID interface{}, startID interface{}, limit interface{}) *StorageService_GetTxsPage_Call {
-	return &StorageService_GetTxsPage_Call{Call: _e.mock.On("GetTxsPage", ctx, partitionID, startID, limit)}
+//   - page domain.PageRequest[domain.TxHash]
+func (_e *StorageService_Expecter) GetTxsPageByBlockNumber(ctx interface{}, blockNumber interface{}, partitionID interface{}, page interface{}) *StorageService_GetTxsPageByBlockNumber_Call {
+	return &StorageService_GetTxsPageByBlockNumber_Call{Call: _e.mock.On("GetTxsPageByBlockNumber", ctx, blockNumber, partitionID, page)}
+}
+
+func (_c *StorageService_GetTxsPageByBlockNumber_Call) Run(run func(ctx context.Context, blockNumber uint64, partitionID types.PartitionID, page domain.PageRequest[domain.TxHash])) *StorageService_GetTxsPageByBlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(types.PartitionID), args[3].(domain.PageRequest[domain.TxHash]))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetTxsPageByBlockNumber_Call) Return(transactions []*domain.TxInfo, hasMore bool, err error) *StorageService_GetTxsPageByBlockNumber_Call {
+	_c.Call.Return(transactions, hasMore, err)
+	return _c
+}
+
+func (_c *StorageService_GetTxsPageByBlockNumber_Call) RunAndReturn(run func(context.Context, uint64, types.PartitionID, domain.PageRequest[domain.TxHash]) ([]*domain.TxInfo, bool, error)) *StorageService_GetTxsPageByBlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsPageByUnitID provides a mock function with given fields: ctx, unitID, page
+func (_m *StorageService) GetTxsPageByUnitID(ctx context.Context, unitID types.UnitID, page domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error) {
+	ret := _m.Called(ctx, unitID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsPageByUnitID")
+	}
+
+	var r0 []*domain.TxInfo
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error)); ok {
+		return rf(ctx, unitID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) []*domain.TxInfo); ok {
+		r0 = rf(ctx, unitID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TxInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) bool); ok {
+		r1 = rf(ctx, unitID, page)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) error); ok {
+		r2 = rf(ctx, unitID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// StorageService_GetTxsPageByUnitID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPageByUnitID'
+type StorageService_GetTxsPageByUnitID_Call struct {
+	*mock.Call
+}
+
+// GetTxsPageByUnitID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - unitID types.UnitID
+//   - page domain.PageRequest[primitive.ObjectID]
+func (_e *StorageService_Expecter) GetTxsPageByUnitID(ctx interface{}, unitID interface{}, page interface{}) *StorageService_GetTxsPageByUnitID_Call {
+	return &StorageService_GetTxsPageByUnitID_Call{Call: _e.mock.On("GetTxsPageByUnitID", ctx, unitID, page)}
 }
 
-func (_c *StorageService_GetTxsPage_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, startID string, limit int)) *StorageService_GetTxsPage_Call {
+func (_c *StorageService_GetTxsPageByUnitID_Call) Run(run func(ctx context.Context, unitID types.UnitID, page domain.PageRequest[primitive.ObjectID])) *StorageService_GetTxsPageByUnitID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(string), args[3].(int))
+		run(args[0].(context.Context), args[1].(types.UnitID), args[2].(domain.PageRequest[primitive.ObjectID]))
 	})
 	return _c
 }
 
-func (_c *StorageService_GetTxsPage_Call) Return(transactions []*domain.TxInfo, previousID string, err error) *StorageService_GetTxsPage_Call {
-	_c.Call.Return(transactions, previousID, err)
+func (_c *StorageService_GetTxsPageByUnitID_Call) Return(transactions []*domain.TxInfo, hasMore bool, err error) *StorageService_GetTxsPageByUnitID_Call {
+	_c.Call.Return(transactions, hasMore, err)
+	return _c
+}
+
+func (_c *StorageService_GetTxsPageByUnitID_Call) RunAndReturn(run func(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error)) *StorageService_GetTxsPageByUnitID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsPages provides a mock function with given fields: ctx, partitionIDs, page
+func (_m *StorageService) GetTxsPages(ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[primitive.ObjectID]) (map[types.PartitionID][]*domain.TxInfo, error) {
+	ret := _m.Called(ctx, partitionIDs, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsPages")
+	}
+
+	var r0 map[types.PartitionID][]*domain.TxInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.PartitionID, domain.PageRequest[primitive.ObjectID]) (map[types.PartitionID][]*domain.TxInfo, error)); ok {
+		return rf(ctx, partitionIDs, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []types.PartitionID, domain.PageRequest[primitive.ObjectID]) map[types.PartitionID][]*domain.TxInfo); ok {
+		r0 = rf(ctx, partitionIDs, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[types.PartitionID][]*domain.TxInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []types.PartitionID, domain.PageRequest[primitive.ObjectID]) error); ok {
+		r1 = rf(ctx, partitionIDs, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageService_GetTxsPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPages'
+type StorageService_GetTxsPages_Call struct {
+	*mock.Call
+}
+
+// GetTxsPages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionIDs []types.PartitionID
+//   - page domain.PageRequest[primitive.ObjectID]
+func (_e *StorageService_Expecter) GetTxsPages(ctx interface{}, partitionIDs interface{}, page interface{}) *StorageService_GetTxsPages_Call {
+	return &StorageService_GetTxsPages_Call{Call: _e.mock.On("GetTxsPages", ctx, partitionIDs, page)}
+}
+
+func (_c *StorageService_GetTxsPages_Call) Run(run func(ctx context.Context, partitionIDs []types.PartitionID, page domain.PageRequest[primitive.ObjectID])) *StorageService_GetTxsPages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]types.PartitionID), args[2].(domain.PageRequest[primitive.ObjectID]))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetTxsPages_Call) Return(_a0 map[types.PartitionID][]*domain.TxInfo, _a1 error) *StorageService_GetTxsPages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageService_GetTxsPages_Call) RunAndReturn(run func(context.Context, []types.PartitionID, domain.PageRequest[primitive.ObjectID]) (map[types.PartitionID][]*domain.TxInfo, error)) *StorageService_GetTxsPages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsPagesByUnitIDs provides a mock function with given fields: ctx, unitIDs, page
+func (_m *StorageService) GetTxsPagesByUnitIDs(ctx context.Context, unitIDs []types.UnitID, page domain.PageRequest[primitive.ObjectID]) (map[string][]*domain.TxInfo, error) {
+	ret := _m.Called(ctx, unitIDs, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsPagesByUnitIDs")
+	}
+
+	var r0 map[string][]*domain.TxInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.UnitID, domain.PageRequest[primitive.ObjectID]) (map[string][]*domain.TxInfo, error)); ok {
+		return rf(ctx, unitIDs, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []types.UnitID, domain.PageRequest[primitive.ObjectID]) map[string][]*domain.TxInfo); ok {
+		r0 = rf(ctx, unitIDs, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]*domain.TxInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []types.UnitID, domain.PageRequest[primitive.ObjectID]) error); ok {
+		r1 = rf(ctx, unitIDs, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageService_GetTxsPagesByUnitIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsPagesByUnitIDs'
+type StorageService_GetTxsPagesByUnitIDs_Call struct {
+	*mock.Call
+}
+
+// GetTxsPagesByUnitIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - unitIDs []types.UnitID
+//   - page domain.PageRequest[primitive.ObjectID]
+func (_e *StorageService_Expecter) GetTxsPagesByUnitIDs(ctx interface{}, unitIDs interface{}, page interface{}) *StorageService_GetTxsPagesByUnitIDs_Call {
+	return &StorageService_GetTxsPagesByUnitIDs_Call{Call: _e.mock.On("GetTxsPagesByUnitIDs", ctx, unitIDs, page)}
+}
+
+func (_c *StorageService_GetTxsPagesByUnitIDs_Call) Run(run func(ctx context.Context, unitIDs []types.UnitID, page domain.PageRequest[primitive.ObjectID])) *StorageService_GetTxsPagesByUnitIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]types.UnitID), args[2].(domain.PageRequest[primitive.ObjectID]))
+	})
+	return _c
+}
+
+func (_c *StorageService_GetTxsPagesByUnitIDs_Call) Return(_a0 map[string][]*domain.TxInfo, _a1 error) *StorageService_GetTxsPagesByUnitIDs_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *StorageService_GetTxsPage_Call) RunAndReturn(run func(context.Context, types.PartitionID, string, int) ([]*domain.TxInfo, string, error)) *StorageService_GetTxsPage_Call {
+func (_c *StorageService_GetTxsPagesByUnitIDs_Call) RunAndReturn(run func(context.Context, []types.UnitID, domain.PageRequest[primitive.ObjectID]) (map[string][]*domain.TxInfo, error)) *StorageService_GetTxsPagesByUnitIDs_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,6 +1,6 @@
 // Code generated by mockery v2.53.0. DO NOT EDIT.
 
-package blocks_mocks
+package blocks
 
 import (
 	context "context"
@@ -24,6 +24,233 @@ func (_m *Store) EXPECT() *Store_Expecter {
 	return &Store_Expecter{mock: &_m.Mock}
 }
 
+// ApplyBillChanges provides a mock function with given fields: ctx, changes
+func (_m *Store) ApplyBillChanges(ctx context.Context, changes *domain.BillChanges) error {
+	ret := _m.Called(ctx, changes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBillChanges")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BillChanges) error); ok {
+		r0 = rf(ctx, changes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_ApplyBillChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyBillChanges'
+type Store_ApplyBillChanges_Call struct {
+	*mock.Call
+}
+
+// ApplyBillChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - changes *domain.BillChanges
+func (_e *Store_Expecter) ApplyBillChanges(ctx interface{}, changes interface{}) *Store_ApplyBillChanges_Call {
+	return &Store_ApplyBillChanges_Call{Call: _e.mock.On("ApplyBillChanges", ctx, changes)}
+}
+
+func (_c *Store_ApplyBillChanges_Call) Run(run func(ctx context.Context, changes *domain.BillChanges)) *Store_ApplyBillChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.BillChanges))
+	})
+	return _c
+}
+
+func (_c *Store_ApplyBillChanges_Call) Return(_a0 error) *Store_ApplyBillChanges_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_ApplyBillChanges_Call) RunAndReturn(run func(context.Context, *domain.BillChanges) error) *Store_ApplyBillChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastBlockBefore provides a mock function with given fields: ctx, partitionID, blockNumber
+func (_m *Store) GetLastBlockBefore(ctx context.Context, partitionID types.PartitionID, blockNumber uint64) (*domain.BlockInfo, error) {
+	ret := _m.Called(ctx, partitionID, blockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastBlockBefore")
+	}
+
+	var r0 *domain.BlockInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, uint64) (*domain.BlockInfo, error)); ok {
+		return rf(ctx, partitionID, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, uint64) *domain.BlockInfo); ok {
+		r0 = rf(ctx, partitionID, blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BlockInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, uint64) error); ok {
+		r1 = rf(ctx, partitionID, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_GetLastBlockBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastBlockBefore'
+type Store_GetLastBlockBefore_Call struct {
+	*mock.Call
+}
+
+// GetLastBlockBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionID types.PartitionID
+//   - blockNumber uint64
+func (_e *Store_Expecter) GetLastBlockBefore(ctx interface{}, partitionID interface{}, blockNumber interface{}) *Store_GetLastBlockBefore_Call {
+	return &Store_GetLastBlockBefore_Call{Call: _e.mock.On("GetLastBlockBefore", ctx, partitionID, blockNumber)}
+}
+
+func (_c *Store_GetLastBlockBefore_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, blockNumber uint64)) *Store_GetLastBlockBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *Store_GetLastBlockBefore_Call) Return(_a0 *domain.BlockInfo, _a1 error) *Store_GetLastBlockBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_GetLastBlockBefore_Call) RunAndReturn(run func(context.Context, types.PartitionID, uint64) (*domain.BlockInfo, error)) *Store_GetLastBlockBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTrackedBills provides a mock function with given fields: ctx, partitionID, unitIDs
+func (_m *Store) GetTrackedBills(ctx context.Context, partitionID types.PartitionID, unitIDs []types.UnitID) ([]*domain.TrackedBill, error) {
+	ret := _m.Called(ctx, partitionID, unitIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrackedBills")
+	}
+
+	var r0 []*domain.TrackedBill
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, []types.UnitID) ([]*domain.TrackedBill, error)); ok {
+		return rf(ctx, partitionID, unitIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, []types.UnitID) []*domain.TrackedBill); ok {
+		r0 = rf(ctx, partitionID, unitIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TrackedBill)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.PartitionID, []types.UnitID) error); ok {
+		r1 = rf(ctx, partitionID, unitIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_GetTrackedBills_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTrackedBills'
+type Store_GetTrackedBills_Call struct {
+	*mock.Call
+}
+
+// GetTrackedBills is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionID types.PartitionID
+//   - unitIDs []types.UnitID
+func (_e *Store_Expecter) GetTrackedBills(ctx interface{}, partitionID interface{}, unitIDs interface{}) *Store_GetTrackedBills_Call {
+	return &Store_GetTrackedBills_Call{Call: _e.mock.On("GetTrackedBills", ctx, partitionID, unitIDs)}
+}
+
+func (_c *Store_GetTrackedBills_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, unitIDs []types.UnitID)) *Store_GetTrackedBills_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].([]types.UnitID))
+	})
+	return _c
+}
+
+func (_c *Store_GetTrackedBills_Call) Return(_a0 []*domain.TrackedBill, _a1 error) *Store_GetTrackedBills_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_GetTrackedBills_Call) RunAndReturn(run func(context.Context, types.PartitionID, []types.UnitID) ([]*domain.TrackedBill, error)) *Store_GetTrackedBills_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlock provides a mock function with given fields: ctx, blockNumber, partitionIDs
+func (_m *Store) GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error) {
+	ret := _m.Called(ctx, blockNumber, partitionIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlock")
+	}
+
+	var r0 map[types.PartitionID]*domain.BlockInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error)); ok {
+		return rf(ctx, blockNumber, partitionIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []types.PartitionID) map[types.PartitionID]*domain.BlockInfo); ok {
+		r0 = rf(ctx, blockNumber, partitionIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[types.PartitionID]*domain.BlockInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []types.PartitionID) error); ok {
+		r1 = rf(ctx, blockNumber, partitionIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_GetBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlock'
+type Store_GetBlock_Call struct {
+	*mock.Call
+}
+
+// GetBlock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockNumber uint64
+//   - partitionIDs []types.PartitionID
+func (_e *Store_Expecter) GetBlock(ctx interface{}, blockNumber interface{}, partitionIDs interface{}) *Store_GetBlock_Call {
+	return &Store_GetBlock_Call{Call: _e.mock.On("GetBlock", ctx, blockNumber, partitionIDs)}
+}
+
+func (_c *Store_GetBlock_Call) Run(run func(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID)) *Store_GetBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].([]types.PartitionID))
+	})
+	return _c
+}
+
+func (_c *Store_GetBlock_Call) Return(_a0 map[types.PartitionID]*domain.BlockInfo, _a1 error) *Store_GetBlock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_GetBlock_Call) RunAndReturn(run func(context.Context, uint64, []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error)) *Store_GetBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBlockNumber provides a mock function with given fields: ctx, partitionID
 func (_m *Store) GetBlockNumber(ctx context.Context, partitionID types.PartitionID) (uint64, error) {
 	ret := _m.Called(ctx, partitionID)
@@ -81,6 +308,54 @@ func (_c *Store_GetBlockNumber_Call) RunAndReturn(run func(context.Context, type
 	return _c
 }
 
+// RollbackBlocks provides a mock function with given fields: ctx, partitionID, fromBlockNumber
+func (_m *Store) RollbackBlocks(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64) error {
+	ret := _m.Called(ctx, partitionID, fromBlockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackBlocks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.PartitionID, uint64) error); ok {
+		r0 = rf(ctx, partitionID, fromBlockNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_RollbackBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackBlocks'
+type Store_RollbackBlocks_Call struct {
+	*mock.Call
+}
+
+// RollbackBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partitionID types.PartitionID
+//   - fromBlockNumber uint64
+func (_e *Store_Expecter) RollbackBlocks(ctx interface{}, partitionID interface{}, fromBlockNumber interface{}) *Store_RollbackBlocks_Call {
+	return &Store_RollbackBlocks_Call{Call: _e.mock.On("RollbackBlocks", ctx, partitionID, fromBlockNumber)}
+}
+
+func (_c *Store_RollbackBlocks_Call) Run(run func(ctx context.Context, partitionID types.PartitionID, fromBlockNumber uint64)) *Store_RollbackBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.PartitionID), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *Store_RollbackBlocks_Call) Return(_a0 error) *Store_RollbackBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_RollbackBlocks_Call) RunAndReturn(run func(context.Context, types.PartitionID, uint64) error) *Store_RollbackBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetBlockInfo provides a mock function with given fields: ctx, blockInfo
 func (_m *Store) SetBlockInfo(ctx context.Context, blockInfo *domain.BlockInfo) error {
 	ret := _m.Called(ctx, blockInfo)
@@ -176,6 +451,53 @@ func (_c *Store_SetBlockNumber_Call) RunAndReturn(run func(context.Context, type
 	return _c
 }
 
+// SetTokenTexts provides a mock function with given fields: ctx, texts
+func (_m *Store) SetTokenTexts(ctx context.Context, texts []*domain.TokenText) error {
+	ret := _m.Called(ctx, texts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTokenTexts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.TokenText) error); ok {
+		r0 = rf(ctx, texts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SetTokenTexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTokenTexts'
+type Store_SetTokenTexts_Call struct {
+	*mock.Call
+}
+
+// SetTokenTexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - texts []*domain.TokenText
+func (_e *Store_Expecter) SetTokenTexts(ctx interface{}, texts interface{}) *Store_SetTokenTexts_Call {
+	return &Store_SetTokenTexts_Call{Call: _e.mock.On("SetTokenTexts", ctx, texts)}
+}
+
+func (_c *Store_SetTokenTexts_Call) Run(run func(ctx context.Context, texts []*domain.TokenText)) *Store_SetTokenTexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*domain.TokenText))
+	})
+	return _c
+}
+
+func (_c *Store_SetTokenTexts_Call) Return(_a0 error) *Store_SetTokenTexts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SetTokenTexts_Call) RunAndReturn(run func(context.Context, []*domain.TokenText) error) *Store_SetTokenTexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetTxInfo provides a mock function with given fields: ctx, txInfo
 func (_m *Store) SetTxInfo(ctx context.Context, txInfo *domain.TxInfo) error {
 	ret := _m.Called(ctx, txInfo)
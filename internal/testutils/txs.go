@@ -0,0 +1,64 @@
+// Package testutils contains the transaction fixtures shared by the tests of the services.
+package testutils
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TxStore serves the transactions in the given order, reversed when the filter is descending. The filter of the
+// last call is recorded.
+type TxStore struct {
+	Txs    []*domain.BlockTx
+	Err    error
+	Filter domain.TxExportFilter
+}
+
+func (s *TxStore) ExportTxs(_ context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error {
+	s.Filter = filter
+	if s.Err != nil {
+		return s.Err
+	}
+	txs := slices.Clone(s.Txs)
+	if filter.Descending {
+		slices.Reverse(txs)
+	}
+	for _, tx := range txs {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TxRecord returns the transaction record of the transaction targeting the unit and the target units.
+func TxRecord(t *testing.T, partitionID types.PartitionID, txType uint16, unitID types.UnitID, attr any, status types.TxStatus, targetUnits ...types.UnitID) *types.TransactionRecord {
+	attrBytes, err := types.Cbor.Marshal(attr)
+	require.NoError(t, err)
+	txoBytes, err := (&types.TransactionOrder{Payload: types.Payload{PartitionID: partitionID, Type: txType, UnitID: unitID, Attributes: attrBytes}}).MarshalCBOR()
+	require.NoError(t, err)
+	return &types.TransactionRecord{
+		TransactionOrder: txoBytes,
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: status, TargetUnits: append([]types.UnitID{unitID}, targetUnits...)},
+	}
+}
+
+// BlockTx returns the transaction of the block, the hash of the transaction is the block number.
+func BlockTx(partitionTypeID types.PartitionTypeID, partitionID types.PartitionID, blockNumber uint64, txr *types.TransactionRecord) *domain.BlockTx {
+	return &domain.BlockTx{
+		TxInfo: domain.TxInfo{
+			TxRecordHash: binary.BigEndian.AppendUint64(nil, blockNumber),
+			BlockNumber:  blockNumber,
+			Transaction:  txr,
+			PartitionID:  partitionID,
+		},
+		PartitionTypeID: partitionTypeID,
+		Timestamp:       1000 + blockNumber,
+	}
+}
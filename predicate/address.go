@@ -0,0 +1,80 @@
+package predicate
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+)
+
+const (
+	AddressPubKey     AddressKind = "pubKey"
+	AddressPubKeyHash AddressKind = "pubKeyHash"
+	AddressPredicate  AddressKind = "predicate"
+
+	pubKeyLength = 33
+)
+
+var (
+	ErrInvalidAddress = errors.New("address is not a public key, a public key hash or an owner predicate")
+
+	base64Encodings = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}
+)
+
+type (
+	// AddressKind tells how the owner was given.
+	AddressKind string
+
+	// Address is the owner of the units.
+	Address struct {
+		Kind      AddressKind
+		OwnerID   []byte     // ID the units of the owner are indexed by
+		Predicate *Predicate // the decoded owner predicate of the AddressPredicate
+	}
+)
+
+/*
+ParseAddress parses the owner given as the compressed public key, the public key hash or the encoded owner
+predicate. The bytes are hex encoded with the optional 0x prefix or base64 encoded (standard or URL alphabet,
+with or without the padding). Only the recognized predicates are accepted, see Decode.
+*/
+func ParseAddress(s string) (*Address, error) {
+	if b, err := util.DecodeHex(s); err == nil {
+		if address := addressOf(b); address != nil {
+			return address, nil
+		}
+		return nil, ErrInvalidAddress
+	}
+	for _, enc := range base64Encodings {
+		if b, err := enc.DecodeString(s); err == nil {
+			if address := addressOf(b); address != nil {
+				return address, nil
+			}
+		}
+	}
+	return nil, ErrInvalidAddress
+}
+
+// OwnerPredicate returns the owner predicate of the address, the P2PKH predicate of the public key hash unless
+// the address is the owner predicate.
+func (a *Address) OwnerPredicate() *Predicate {
+	if a.Predicate != nil {
+		return a.Predicate
+	}
+	return Decode(P2PKH(a.OwnerID))
+}
+
+func addressOf(b []byte) *Address {
+	switch len(b) {
+	case pubKeyLength:
+		hash := sha256.Sum256(b)
+		return &Address{Kind: AddressPubKey, OwnerID: hash[:]}
+	case pubKeyHashLength:
+		return &Address{Kind: AddressPubKeyHash, OwnerID: b}
+	}
+	if p := Decode(b); p != nil && p.Type != TypeUnknown {
+		return &Address{Kind: AddressPredicate, OwnerID: p.OwnerID, Predicate: p}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package predicate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	pubKey := bytes.Repeat([]byte{2}, 33)
+	pubKeyHash := sha256.Sum256(pubKey)
+	p2pkh := P2PKH(pubKeyHash[:])
+	alwaysTrue := encode(t, templateEngineTag, []byte{alwaysTrueID}, nil)
+	alwaysTrueHash := sha256.Sum256(alwaysTrue)
+
+	tests := []struct {
+		name    string
+		address string
+		kind    AddressKind
+		ownerID []byte
+	}{
+		{name: "hex public key", address: "0x" + hex.EncodeToString(pubKey), kind: AddressPubKey, ownerID: pubKeyHash[:]},
+		{name: "hex public key without prefix", address: hex.EncodeToString(pubKey), kind: AddressPubKey, ownerID: pubKeyHash[:]},
+		{name: "hex public key hash", address: "0x" + hex.EncodeToString(pubKeyHash[:]), kind: AddressPubKeyHash, ownerID: pubKeyHash[:]},
+		{name: "hex p2pkh predicate", address: "0x" + hex.EncodeToString(p2pkh), kind: AddressPredicate, ownerID: pubKeyHash[:]},
+		{name: "hex always true predicate", address: hex.EncodeToString(alwaysTrue), kind: AddressPredicate, ownerID: alwaysTrueHash[:]},
+		{name: "base64 public key", address: base64.StdEncoding.EncodeToString(pubKey), kind: AddressPubKey, ownerID: pubKeyHash[:]},
+		{name: "base64url public key hash", address: base64.RawURLEncoding.EncodeToString(pubKeyHash[:]), kind: AddressPubKeyHash, ownerID: pubKeyHash[:]},
+		{name: "base64url p2pkh predicate", address: base64.RawURLEncoding.EncodeToString(p2pkh), kind: AddressPredicate, ownerID: pubKeyHash[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			require.Equal(t, tt.kind, address.Kind)
+			require.EqualValues(t, tt.ownerID, address.OwnerID)
+			require.Equal(t, tt.kind == AddressPredicate, address.Predicate != nil)
+			require.EqualValues(t, tt.ownerID, address.OwnerPredicate().OwnerID)
+		})
+	}
+
+	for _, s := range []string{"", "0x0102", "0x" + hex.EncodeToString(encode(t, 7, nil, nil)), "not an address!"} {
+		_, err := ParseAddress(s)
+		require.ErrorIs(t, err, ErrInvalidAddress, s)
+	}
+}
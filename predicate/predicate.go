@@ -0,0 +1,133 @@
+package predicate
+
+import (
+	"crypto/sha256"
+	"maps"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/util"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+)
+
+const (
+	TypeP2PKH       Type = "p2pkh"
+	TypeAlwaysTrue  Type = "alwaysTrue"
+	TypeAlwaysFalse Type = "alwaysFalse"
+	TypeWASM        Type = "wasm"
+	TypeUnknown     Type = "unknown"
+
+	// engine tags of the encoded predicate
+	templateEngineTag = 0
+	wasmEngineTag     = 1
+
+	// IDs of the standard templates of the template engine
+	alwaysFalseID = 0
+	alwaysTrueID  = 1
+	p2pkh256ID    = 2
+
+	pubKeyHashLength = 32
+
+	// ownerPredicateField is the field of the owner predicate in the unit data of the partition node
+	ownerPredicateField = "ownerPredicate"
+)
+
+type (
+	// Type is the type of the predicate recognized by the explorer.
+	Type string
+
+	// Predicate is the decoded owner predicate, the fields of the other types are empty.
+	Predicate struct {
+		Type       Type
+		PubKeyHash hex.Bytes `json:",omitempty"` // public key hash of the P2PKH predicate
+		CodeHash   hex.Bytes `json:",omitempty"` // SHA256 hash of the WASM module
+		CodeSize   int       `json:",omitempty"` // size of the WASM module in bytes
+		Params     hex.Bytes `json:",omitempty"` // parameters of the WASM predicate
+		OwnerID    hex.Bytes // ID the units of the owner are indexed by
+		Bytes      hex.Bytes // the encoded predicate
+	}
+
+	// encodedPredicate is the CBOR encoding of the predicate, the code is the template ID for the template engine.
+	encodedPredicate struct {
+		_      struct{} `cbor:",toarray"`
+		Tag    uint64
+		Code   []byte
+		Params []byte
+	}
+)
+
+// Decode decodes the predicate, predicates which are not recognized are returned with TypeUnknown.
+// Returns nil for the empty predicate.
+func Decode(b []byte) *Predicate {
+	if len(b) == 0 {
+		return nil
+	}
+	p := &Predicate{Type: TypeUnknown, Bytes: b}
+	var enc encodedPredicate
+	if err := types.Cbor.Unmarshal(b, &enc); err == nil {
+		switch enc.Tag {
+		case templateEngineTag:
+			if len(enc.Code) != 1 {
+				break
+			}
+			switch enc.Code[0] {
+			case alwaysFalseID:
+				p.Type = TypeAlwaysFalse
+			case alwaysTrueID:
+				p.Type = TypeAlwaysTrue
+			case p2pkh256ID:
+				if len(enc.Params) == pubKeyHashLength {
+					p.Type, p.PubKeyHash = TypeP2PKH, enc.Params
+				}
+			}
+		case wasmEngineTag:
+			codeHash := sha256.Sum256(enc.Code)
+			p.Type, p.CodeHash, p.CodeSize, p.Params = TypeWASM, codeHash[:], len(enc.Code), enc.Params
+		}
+	}
+	p.OwnerID = OwnerID(p)
+	return p
+}
+
+/*
+OwnerID returns the ID the units of the predicate are indexed by: the public key hash of the P2PKH predicate
+and the SHA256 hash of the encoded predicate for the other predicates.
+*/
+func OwnerID(p *Predicate) []byte {
+	if p.Type == TypeP2PKH {
+		return p.PubKeyHash
+	}
+	hash := sha256.Sum256(p.Bytes)
+	return hash[:]
+}
+
+// P2PKH returns the encoded P2PKH predicate of the public key hash.
+func P2PKH(pubKeyHash []byte) []byte {
+	b, err := types.Cbor.Marshal(&encodedPredicate{Tag: templateEngineTag, Code: []byte{p2pkh256ID}, Params: pubKeyHash})
+	if err != nil {
+		// encoding the struct of byte slices does not fail
+		panic(err)
+	}
+	return b
+}
+
+/*
+DecodeUnitData returns the unit data returned by the partition node as JSON with the hex encoded "ownerPredicate"
+field replaced by the decoded predicate. Other data is returned as is, the given data is not modified.
+*/
+func DecodeUnitData(data any) any {
+	fields, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	s, ok := fields[ownerPredicateField].(string)
+	if !ok {
+		return data
+	}
+	b, err := util.DecodeHex(s)
+	if err != nil {
+		return data
+	}
+	res := maps.Clone(fields)
+	res[ownerPredicateField] = Decode(b)
+	return res
+}
@@ -0,0 +1,78 @@
+package predicate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, tag uint64, code, params []byte) []byte {
+	t.Helper()
+	b, err := types.Cbor.Marshal(&encodedPredicate{Tag: tag, Code: code, Params: params})
+	require.NoError(t, err)
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	pubKeyHash := bytes.Repeat([]byte{1}, 32)
+	hashOf := func(b []byte) []byte {
+		hash := sha256.Sum256(b)
+		return hash[:]
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		require.Nil(t, Decode(nil))
+	})
+
+	t.Run("p2pkh", func(t *testing.T) {
+		b := P2PKH(pubKeyHash)
+		p := Decode(b)
+		require.Equal(t, TypeP2PKH, p.Type)
+		require.EqualValues(t, pubKeyHash, p.PubKeyHash)
+		require.EqualValues(t, pubKeyHash, p.OwnerID)
+		require.EqualValues(t, b, p.Bytes)
+	})
+
+	t.Run("always true and false", func(t *testing.T) {
+		b := encode(t, templateEngineTag, []byte{alwaysTrueID}, nil)
+		require.Equal(t, &Predicate{Type: TypeAlwaysTrue, OwnerID: hashOf(b), Bytes: b}, Decode(b))
+
+		b = encode(t, templateEngineTag, []byte{alwaysFalseID}, nil)
+		require.Equal(t, &Predicate{Type: TypeAlwaysFalse, OwnerID: hashOf(b), Bytes: b}, Decode(b))
+	})
+
+	t.Run("wasm", func(t *testing.T) {
+		code := []byte{0, 0x61, 0x73, 0x6d}
+		b := encode(t, wasmEngineTag, code, []byte{5})
+		require.Equal(t, &Predicate{Type: TypeWASM, CodeHash: hashOf(code), CodeSize: 4, Params: []byte{5}, OwnerID: hashOf(b), Bytes: b}, Decode(b))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		for _, b := range [][]byte{
+			{0x83},
+			encode(t, templateEngineTag, []byte{p2pkh256ID}, []byte{1, 2}),
+			encode(t, templateEngineTag, []byte{9}, nil),
+			encode(t, 7, nil, nil),
+		} {
+			require.Equal(t, &Predicate{Type: TypeUnknown, OwnerID: hashOf(b), Bytes: b}, Decode(b))
+		}
+	})
+}
+
+func TestDecodeUnitData(t *testing.T) {
+	p2pkh := P2PKH(bytes.Repeat([]byte{1}, 32))
+	data := map[string]any{"value": "100", "ownerPredicate": "0x" + hex.EncodeToString(p2pkh)}
+
+	decoded := DecodeUnitData(data)
+	require.Equal(t, map[string]any{"value": "100", "ownerPredicate": Decode(p2pkh)}, decoded)
+	// the data of the node is not modified
+	require.Equal(t, "0x"+hex.EncodeToString(p2pkh), data["ownerPredicate"])
+
+	for _, data := range []any{nil, "data", map[string]any{"value": "100"}, map[string]any{"ownerPredicate": "not hex"}} {
+		require.Equal(t, data, DecodeUnitData(data))
+	}
+}
@@ -0,0 +1,342 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unittx"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const (
+	EventCreated              EventKind = "created"
+	EventTransferred          EventKind = "transferred"
+	EventSplit                EventKind = "split"
+	EventDustTransferred      EventKind = "dustTransferred"
+	EventSwapped              EventKind = "swapped"
+	EventBurned               EventKind = "burned"
+	EventJoined               EventKind = "joined"
+	EventUpdated              EventKind = "updated"
+	EventLocked               EventKind = "locked"
+	EventUnlocked             EventKind = "unlocked"
+	EventFeeCreditTransferred EventKind = "feeCreditTransferred"
+	EventFeeCreditAdded       EventKind = "feeCreditAdded"
+	EventFeeCreditClosed      EventKind = "feeCreditClosed"
+	EventFeeCreditReclaimed   EventKind = "feeCreditReclaimed"
+	EventTargeted             EventKind = "targeted" // the unit is targeted by the transaction of another unit
+
+	RelationSplitTarget     Relation = "splitTarget"     // unit split off from the unit
+	RelationSplitSource     Relation = "splitSource"     // unit the unit was split off from
+	RelationSwapTarget      Relation = "swapTarget"      // unit the value of the dust transfer or the burn is swapped or joined into
+	RelationSwapInput       Relation = "swapInput"       // dust bill or burned token swapped or joined into the unit
+	RelationFeeCreditRecord Relation = "feeCreditRecord" // fee credit record funded by the unit or reclaimed into the unit
+	RelationFeeCreditSource Relation = "feeCreditSource" // unit the fee credit was transferred from
+	RelationReclaimTarget   Relation = "reclaimTarget"   // unit the closed fee credit is reclaimed into
+	RelationTxUnit          Relation = "txUnit"          // unit of the transaction targeting the unit
+
+	StateActive  State = "active"
+	StateLocked  State = "locked"
+	StateDeleted State = "deleted" // the value of the unit was transferred to another unit by the dust transfer or the burn
+)
+
+type (
+	// Service assembles the lifecycles of the units from the stored transactions.
+	Service struct {
+		store Store
+	}
+
+	Store interface {
+		ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error
+	}
+
+	// EventKind is the change of the unit made by the transaction.
+	EventKind string
+
+	// Relation tells how the related unit is related to the unit.
+	Relation string
+
+	// State is the state of the unit after its last stored transaction.
+	State string
+
+	// Lifecycle is the history of the unit assembled from its successful transactions.
+	Lifecycle struct {
+		UnitID          types.UnitID
+		PartitionID     types.PartitionID
+		PartitionTypeID types.PartitionTypeID
+		Created         *Event         `json:",omitempty"` // nil when the unit was created before the first stored block
+		Events          []*Event       // oldest event first, the creation included
+		RelatedUnits    []*RelatedUnit // related units of all the events
+		State           State
+		Truncated       bool `json:",omitempty"` // only the first and the latest events were loaded, the events between them are missing
+	}
+
+	// Event is the successful transaction changing the state of the unit.
+	Event struct {
+		Kind         EventKind
+		TxType       uint16
+		TxRecordHash domain.TxHash
+		BlockNumber  uint64
+		Timestamp    uint64         // unicity seal timestamp of the block (seconds since Unix epoch)
+		RelatedUnits []*RelatedUnit `json:",omitempty"`
+	}
+
+	// RelatedUnit is the unit the transaction relates the unit to, the partition ID is zero when the
+	// transaction does not tell the partition of the related unit.
+	RelatedUnit struct {
+		Relation    Relation
+		PartitionID types.PartitionID `json:",omitempty"`
+		UnitID      types.UnitID
+	}
+)
+
+func NewService(store Store) (*Service, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	return &Service{store: store}, nil
+}
+
+/*
+GetLifecycle returns the lifecycle of the unit assembled from the successful transactions targeting it.
+The transactions of the partition of the oldest transaction are used when the unit ID is targeted on
+several partitions. The creation and the latest events are kept when the unit has more than unittx.MaxTxs
+transactions. Returns domain.ErrNotFound when no successful transaction targets the unit.
+*/
+func (s *Service) GetLifecycle(ctx context.Context, unitID types.UnitID) (*Lifecycle, error) {
+	txs, truncated, err := unittx.Load(ctx, s.store, unittx.Filter{UnitID: unitID})
+	if err != nil {
+		return nil, err
+	}
+	lc := &Lifecycle{
+		UnitID:          unitID,
+		PartitionID:     txs[0].PartitionID,
+		PartitionTypeID: txs[0].PartitionTypeID,
+		State:           StateActive,
+		Truncated:       truncated,
+	}
+	for _, tx := range txs {
+		lc.add(tx)
+	}
+	return lc, nil
+}
+
+func (lc *Lifecycle) add(tx *domain.BlockTx) {
+	txo, err := tx.Transaction.GetTransactionOrderV1()
+	if err != nil {
+		log.Warn("failed to decode transaction order", "partition", tx.PartitionID, "round", tx.BlockNumber, "err", err)
+		return
+	}
+	event := &Event{
+		TxType:       txo.Type,
+		TxRecordHash: tx.TxRecordHash,
+		BlockNumber:  tx.BlockNumber,
+		Timestamp:    tx.Timestamp,
+	}
+	if bytes.Equal(txo.UnitID, lc.UnitID) {
+		err = event.decode(tx, txo)
+	} else {
+		event.decodeTargeted(tx, txo)
+	}
+	if err != nil {
+		log.Warn("failed to decode transaction attributes", "partition", tx.PartitionID, "round", tx.BlockNumber, "type", txo.Type, "err", err)
+	}
+
+	switch event.Kind {
+	case EventCreated:
+		lc.State = StateActive
+		if lc.Created == nil {
+			lc.Created = event
+		}
+	case EventFeeCreditAdded:
+		// the first addition creates the fee credit record
+		if len(lc.Events) == 0 {
+			lc.Created = event
+		}
+	case EventLocked:
+		lc.State = StateLocked
+	case EventUnlocked:
+		lc.State = StateActive
+	case EventDustTransferred, EventBurned:
+		lc.State = StateDeleted
+	}
+	lc.Events = append(lc.Events, event)
+	for _, unit := range event.RelatedUnits {
+		if !lc.hasRelatedUnit(unit) {
+			lc.RelatedUnits = append(lc.RelatedUnits, unit)
+		}
+	}
+}
+
+func (lc *Lifecycle) hasRelatedUnit(unit *RelatedUnit) bool {
+	for _, u := range lc.RelatedUnits {
+		if u.Relation == unit.Relation && u.PartitionID == unit.PartitionID && bytes.Equal(u.UnitID, unit.UnitID) {
+			return true
+		}
+	}
+	return false
+}
+
+// decode sets the kind and the related units of the event of the transaction of the unit.
+func (e *Event) decode(tx *domain.BlockTx, txo *types.TransactionOrder) error {
+	e.Kind = EventUpdated
+	switch txo.Type {
+	case fc.TransactionTypeTransferFeeCredit:
+		e.Kind = EventFeeCreditTransferred
+		var attr fc.TransferFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		e.relate(RelationFeeCreditRecord, attr.TargetPartitionID, attr.TargetRecordID)
+		return nil
+	case fc.TransactionTypeAddFeeCredit:
+		e.Kind = EventFeeCreditAdded
+		var attr fc.AddFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		return e.relateProofs(RelationFeeCreditSource, attr.FeeCreditTransferProof)
+	case fc.TransactionTypeCloseFeeCredit:
+		e.Kind = EventFeeCreditClosed
+		var attr fc.CloseFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		e.relate(RelationReclaimTarget, 0, attr.TargetUnitID)
+		return nil
+	case fc.TransactionTypeReclaimFeeCredit:
+		e.Kind = EventFeeCreditReclaimed
+		var attr fc.ReclaimFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		return e.relateProofs(RelationFeeCreditRecord, attr.CloseFeeCreditProof)
+	case fc.TransactionTypeLockFeeCredit:
+		e.Kind = EventLocked
+		return nil
+	case fc.TransactionTypeUnlockFeeCredit:
+		e.Kind = EventUnlocked
+		return nil
+	}
+
+	switch tx.PartitionTypeID {
+	case money.PartitionTypeID:
+		return e.decodeMoney(tx, txo)
+	case tokens.PartitionTypeID:
+		return e.decodeTokens(tx, txo)
+	}
+	return nil
+}
+
+func (e *Event) decodeMoney(tx *domain.BlockTx, txo *types.TransactionOrder) error {
+	switch txo.Type {
+	case money.TransactionTypeTransfer:
+		e.Kind = EventTransferred
+	case money.TransactionTypeSplit:
+		e.Kind = EventSplit
+		e.relateSplitTargets(tx, txo)
+	case money.TransactionTypeTransDC:
+		e.Kind = EventDustTransferred
+		var attr money.TransferDCAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		e.relate(RelationSwapTarget, tx.PartitionID, attr.TargetUnitID)
+	case money.TransactionTypeSwapDC:
+		e.Kind = EventSwapped
+		var attr money.SwapDCAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		return e.relateProofs(RelationSwapInput, attr.DustTransferProofs...)
+	case money.TransactionTypeLock:
+		e.Kind = EventLocked
+	case money.TransactionTypeUnlock:
+		e.Kind = EventUnlocked
+	}
+	return nil
+}
+
+func (e *Event) decodeTokens(tx *domain.BlockTx, txo *types.TransactionOrder) error {
+	switch txo.Type {
+	case tokens.TransactionTypeDefineFT, tokens.TransactionTypeDefineNFT, tokens.TransactionTypeMintFT, tokens.TransactionTypeMintNFT:
+		e.Kind = EventCreated
+	case tokens.TransactionTypeTransferFT, tokens.TransactionTypeTransferNFT:
+		e.Kind = EventTransferred
+	case tokens.TransactionTypeSplitFT:
+		e.Kind = EventSplit
+		e.relateSplitTargets(tx, txo)
+	case tokens.TransactionTypeBurnFT:
+		e.Kind = EventBurned
+		var attr tokens.BurnFungibleTokenAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		e.relate(RelationSwapTarget, tx.PartitionID, attr.TargetTokenID)
+	case tokens.TransactionTypeJoinFT:
+		e.Kind = EventJoined
+		var attr tokens.JoinFungibleTokenAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return err
+		}
+		return e.relateProofs(RelationSwapInput, attr.BurnTokenProofs...)
+	case tokens.TransactionTypeLockToken:
+		e.Kind = EventLocked
+	case tokens.TransactionTypeUnlockToken:
+		e.Kind = EventUnlocked
+	}
+	return nil
+}
+
+// decodeTargeted sets the kind and the related units of the event of the transaction of another unit,
+// the unit split off is created by the split transaction.
+func (e *Event) decodeTargeted(tx *domain.BlockTx, txo *types.TransactionOrder) {
+	if (tx.PartitionTypeID == money.PartitionTypeID && txo.Type == money.TransactionTypeSplit) ||
+		(tx.PartitionTypeID == tokens.PartitionTypeID && txo.Type == tokens.TransactionTypeSplitFT) {
+		e.Kind = EventCreated
+		e.relate(RelationSplitSource, tx.PartitionID, txo.UnitID)
+		return
+	}
+	e.Kind = EventTargeted
+	e.relate(RelationTxUnit, tx.PartitionID, txo.UnitID)
+}
+
+func (e *Event) relate(relation Relation, partitionID types.PartitionID, unitID types.UnitID) {
+	if len(unitID) == 0 {
+		return
+	}
+	e.RelatedUnits = append(e.RelatedUnits, &RelatedUnit{Relation: relation, PartitionID: partitionID, UnitID: unitID})
+}
+
+// relateSplitTargets relates the units targeted by the split transaction, other than the unit split.
+func (e *Event) relateSplitTargets(tx *domain.BlockTx, txo *types.TransactionOrder) {
+	if tx.Transaction.ServerMetadata == nil {
+		return
+	}
+	for _, unitID := range tx.Transaction.ServerMetadata.TargetUnits {
+		if !bytes.Equal(unitID, txo.UnitID) {
+			e.relate(RelationSplitTarget, tx.PartitionID, unitID)
+		}
+	}
+}
+
+// relateProofs relates the units of the transactions of the proofs.
+func (e *Event) relateProofs(relation Relation, proofs ...*types.TxRecordProof) error {
+	for _, proof := range proofs {
+		if proof == nil || proof.TxRecord == nil {
+			continue
+		}
+		txo, err := proof.TxRecord.GetTransactionOrderV1()
+		if err != nil {
+			return fmt.Errorf("failed to decode proof transaction: %w", err)
+		}
+		e.relate(relation, txo.PartitionID, txo.UnitID)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/testutils"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unittx"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewService(t *testing.T) {
+	_, err := NewService(nil)
+	require.EqualError(t, err, "store is nil")
+}
+
+func TestService_GetLifecycle(t *testing.T) {
+	ctx := context.Background()
+	bill := types.UnitID{5}
+	moneyTx := func(blockNumber uint64, txType uint16, unitID types.UnitID, attr any, status types.TxStatus, targetUnits ...types.UnitID) *domain.BlockTx {
+		return testutils.BlockTx(money.PartitionTypeID, 1, blockNumber, testutils.TxRecord(t, 1, txType, unitID, attr, status, targetUnits...))
+	}
+	kinds := func(lc *Lifecycle) []EventKind {
+		var res []EventKind
+		for _, e := range lc.Events {
+			res = append(res, e.Kind)
+		}
+		return res
+	}
+
+	t.Run("bill", func(t *testing.T) {
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeSplit, types.UnitID{1}, &money.SplitAttributes{}, types.TxStatusSuccessful, bill),
+			moneyTx(2, money.TransactionTypeTransfer, bill, &money.TransferAttributes{}, types.TxStatusFailed),
+			moneyTx(3, money.TransactionTypeTransfer, bill, &money.TransferAttributes{}, types.TxStatusSuccessful),
+			moneyTx(4, money.TransactionTypeSplit, bill, &money.SplitAttributes{}, types.TxStatusSuccessful, types.UnitID{6}),
+			moneyTx(5, money.TransactionTypeLock, bill, &money.TransferAttributes{}, types.TxStatusSuccessful),
+			moneyTx(6, money.TransactionTypeUnlock, bill, &money.TransferAttributes{}, types.TxStatusSuccessful),
+			moneyTx(7, fc.TransactionTypeTransferFeeCredit, bill, &fc.TransferFeeCreditAttributes{TargetPartitionID: 2, TargetRecordID: types.UnitID{9}}, types.TxStatusSuccessful),
+			moneyTx(8, money.TransactionTypeTransDC, bill, &money.TransferDCAttributes{TargetUnitID: types.UnitID{7}}, types.TxStatusSuccessful),
+		}}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, bill)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{bill}, store.Filter.UnitIDs)
+		require.Equal(t, bill, lc.UnitID)
+		require.EqualValues(t, 1, lc.PartitionID)
+		require.Equal(t, money.PartitionTypeID, lc.PartitionTypeID)
+		require.Equal(t, []EventKind{EventCreated, EventTransferred, EventSplit, EventLocked, EventUnlocked, EventFeeCreditTransferred, EventDustTransferred}, kinds(lc))
+		require.Same(t, lc.Events[0], lc.Created)
+		require.EqualValues(t, 1001, lc.Created.Timestamp)
+		require.Equal(t, StateDeleted, lc.State)
+		require.False(t, lc.Truncated)
+		require.Equal(t, []*RelatedUnit{
+			{Relation: RelationSplitSource, PartitionID: 1, UnitID: types.UnitID{1}},
+			{Relation: RelationSplitTarget, PartitionID: 1, UnitID: types.UnitID{6}},
+			{Relation: RelationFeeCreditRecord, PartitionID: 2, UnitID: types.UnitID{9}},
+			{Relation: RelationSwapTarget, PartitionID: 1, UnitID: types.UnitID{7}},
+		}, lc.RelatedUnits)
+	})
+
+	t.Run("swap", func(t *testing.T) {
+		proof := func(unitID types.UnitID) *types.TxRecordProof {
+			return &types.TxRecordProof{TxRecord: testutils.TxRecord(t, 1, money.TransactionTypeTransDC, unitID, &money.TransferDCAttributes{TargetUnitID: bill}, types.TxStatusSuccessful)}
+		}
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(3, money.TransactionTypeLock, bill, &money.TransferAttributes{}, types.TxStatusSuccessful),
+			moneyTx(4, money.TransactionTypeSwapDC, bill, &money.SwapDCAttributes{DustTransferProofs: []*types.TxRecordProof{proof(types.UnitID{2}), proof(types.UnitID{3})}}, types.TxStatusSuccessful),
+		}}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, bill)
+		require.NoError(t, err)
+		require.Nil(t, lc.Created)
+		require.Equal(t, StateLocked, lc.State)
+		require.Equal(t, []EventKind{EventLocked, EventSwapped}, kinds(lc))
+		require.Equal(t, []*RelatedUnit{
+			{Relation: RelationSwapInput, PartitionID: 1, UnitID: types.UnitID{2}},
+			{Relation: RelationSwapInput, PartitionID: 1, UnitID: types.UnitID{3}},
+		}, lc.Events[1].RelatedUnits)
+	})
+
+	t.Run("fee credit record", func(t *testing.T) {
+		record := types.UnitID{9}
+		transferProof := &types.TxRecordProof{TxRecord: testutils.TxRecord(t, 1, fc.TransactionTypeTransferFeeCredit, bill, &fc.TransferFeeCreditAttributes{TargetPartitionID: 2, TargetRecordID: record}, types.TxStatusSuccessful)}
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 10, testutils.TxRecord(t, 2, fc.TransactionTypeAddFeeCredit, record, &fc.AddFeeCreditAttributes{FeeCreditTransferProof: transferProof}, types.TxStatusSuccessful)),
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 11, testutils.TxRecord(t, 2, fc.TransactionTypeCloseFeeCredit, record, &fc.CloseFeeCreditAttributes{TargetUnitID: bill}, types.TxStatusSuccessful)),
+			// the same unit ID on another partition is ignored
+			moneyTx(12, money.TransactionTypeTransfer, record, &money.TransferAttributes{}, types.TxStatusSuccessful),
+		}}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, record)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, lc.PartitionID)
+		require.Equal(t, []EventKind{EventFeeCreditAdded, EventFeeCreditClosed}, kinds(lc))
+		require.Same(t, lc.Events[0], lc.Created)
+		require.Equal(t, StateActive, lc.State)
+		require.Equal(t, []*RelatedUnit{
+			{Relation: RelationFeeCreditSource, PartitionID: 1, UnitID: bill},
+			{Relation: RelationReclaimTarget, UnitID: bill},
+		}, lc.RelatedUnits)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		token := types.UnitID{3}
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 1, testutils.TxRecord(t, 2, tokens.TransactionTypeMintFT, token, &tokens.MintNonFungibleTokenAttributes{}, types.TxStatusSuccessful)),
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 2, testutils.TxRecord(t, 2, tokens.TransactionTypeTransferFT, types.UnitID{4}, &tokens.MintNonFungibleTokenAttributes{}, types.TxStatusSuccessful, token)),
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 3, testutils.TxRecord(t, 2, tokens.TransactionTypeBurnFT, token, &tokens.BurnFungibleTokenAttributes{TargetTokenID: types.UnitID{8}}, types.TxStatusSuccessful)),
+		}}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, []EventKind{EventCreated, EventTargeted, EventBurned}, kinds(lc))
+		require.Same(t, lc.Events[0], lc.Created)
+		require.Equal(t, StateDeleted, lc.State)
+		require.Equal(t, []*RelatedUnit{
+			{Relation: RelationTxUnit, PartitionID: 2, UnitID: types.UnitID{4}},
+			{Relation: RelationSwapTarget, PartitionID: 2, UnitID: types.UnitID{8}},
+		}, lc.RelatedUnits)
+	})
+
+	t.Run("undecodable attributes", func(t *testing.T) {
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeTransDC, bill, "not attributes", types.TxStatusSuccessful),
+		}}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, bill)
+		require.NoError(t, err)
+		require.Equal(t, []EventKind{EventDustTransferred}, kinds(lc))
+		require.Empty(t, lc.RelatedUnits)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		store := &testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeSplit, types.UnitID{1}, &money.SplitAttributes{}, types.TxStatusSuccessful, bill),
+		}}
+		for i := range unittx.MaxTxs {
+			store.Txs = append(store.Txs, moneyTx(uint64(i+2), money.TransactionTypeTransfer, bill, &money.TransferAttributes{}, types.TxStatusSuccessful))
+		}
+		store.Txs = append(store.Txs, moneyTx(unittx.MaxTxs+2, money.TransactionTypeLock, bill, &money.TransferAttributes{}, types.TxStatusSuccessful))
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, bill)
+		require.NoError(t, err)
+		require.True(t, lc.Truncated)
+		require.Len(t, lc.Events, unittx.MaxTxs)
+		// the creation and the latest events are kept
+		require.Same(t, lc.Events[0], lc.Created)
+		require.EqualValues(t, 1, lc.Created.BlockNumber)
+		require.EqualValues(t, 4, lc.Events[1].BlockNumber)
+		require.EqualValues(t, unittx.MaxTxs+2, lc.Events[unittx.MaxTxs-1].BlockNumber)
+		require.Equal(t, StateLocked, lc.State)
+	})
+
+	t.Run("not truncated", func(t *testing.T) {
+		store := &testutils.TxStore{}
+		for i := range unittx.MaxTxs {
+			store.Txs = append(store.Txs, moneyTx(uint64(i), money.TransactionTypeTransfer, bill, &money.TransferAttributes{}, types.TxStatusSuccessful))
+		}
+		service, err := NewService(store)
+		require.NoError(t, err)
+
+		lc, err := service.GetLifecycle(ctx, bill)
+		require.NoError(t, err)
+		require.False(t, lc.Truncated)
+		require.Len(t, lc.Events, unittx.MaxTxs)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		service, err := NewService(&testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeTransfer, bill, &money.TransferAttributes{}, types.TxStatusFailed),
+		}})
+		require.NoError(t, err)
+
+		_, err = service.GetLifecycle(ctx, bill)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		service, err := NewService(&testutils.TxStore{Err: errors.New("boom")})
+		require.NoError(t, err)
+
+		_, err = service.GetLifecycle(ctx, bill)
+		require.EqualError(t, err, "failed to load transactions: boom")
+	})
+}
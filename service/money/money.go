@@ -1,10 +1,15 @@
 package money
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/types/hex"
 	"github.com/alphabill-org/alphabill-wallet/client/types"
 )
@@ -12,11 +17,58 @@ import (
 type (
 	Service struct {
 		moneyClient types.MoneyPartitionClient
+		partitionID abtypes.PartitionID
+		store       Store
+	}
+
+	// Store provides the balances of the owners maintained from the money partition blocks.
+	Store interface {
+		GetTopHolders(ctx context.Context, partitionID abtypes.PartitionID, limit int) ([]*domain.Balance, error)
+		GetBalanceDistribution(ctx context.Context, partitionID abtypes.PartitionID, boundaries []uint64) ([]*domain.BalanceBucket, error)
+		GetMoneySupply(ctx context.Context, partitionID abtypes.PartitionID) (*domain.MoneySupply, error)
+		GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []abtypes.PartitionID) (map[abtypes.PartitionID]*domain.BlockInfo, error)
+		GetOwnerBillIDs(ctx context.Context, partitionID abtypes.PartitionID, ownerID hex.Bytes) ([]abtypes.UnitID, error)
+	}
+
+	// TopHolders is the rich list of the money partition.
+	TopHolders struct {
+		PartitionID abtypes.PartitionID
+		BlockNumber uint64
+		HolderCount uint64
+		TotalSupply uint64
+		Holders     []*Holder
+	}
+
+	Holder struct {
+		Rank      int
+		OwnerID   hex.Bytes
+		Balance   uint64
+		BillCount uint64
+		Share     float64 // share of the total supply in percent
+	}
+
+	// Distribution is the number of the owners by the balance bucket, the empty buckets are omitted.
+	Distribution struct {
+		PartitionID abtypes.PartitionID
+		BlockNumber uint64
+		HolderCount uint64
+		Buckets     []*domain.BalanceBucket
 	}
 )
 
-func NewMoneyService(moneyClient types.MoneyPartitionClient) *Service {
-	return &Service{moneyClient: moneyClient}
+// distributionBoundaries are the lower bounds of the balance buckets: zero and the powers of ten.
+var distributionBoundaries = func() []uint64 {
+	boundaries := []uint64{0}
+	for b := uint64(1); b <= 1e18; b *= 10 {
+		boundaries = append(boundaries, b)
+	}
+	return boundaries
+}()
+
+// NewMoneyService creates the money service, the balances are not available when the money partition is not
+// configured (moneyClient is nil).
+func NewMoneyService(moneyClient types.MoneyPartitionClient, partitionID abtypes.PartitionID, store Store) *Service {
+	return &Service{moneyClient: moneyClient, partitionID: partitionID, store: store}
 }
 
 func (m *Service) GetBillsByPubKeyHash(ctx context.Context, ownerID hex.Bytes) ([]*types.Bill, error) {
@@ -29,3 +81,113 @@ func (m *Service) GetBillsByPubKeyHash(ctx context.Context, ownerID hex.Bytes) (
 	}
 	return bills, nil
 }
+
+/*
+GetOwnerBillIDs returns the IDs of the bills the owner owns or has owned since the oldest stored block. The stored
+bills are completed with the bills the money node returns, the bills created before the first synced block are not
+stored until they are changed.
+*/
+func (m *Service) GetOwnerBillIDs(ctx context.Context, ownerID hex.Bytes) ([]abtypes.UnitID, error) {
+	bills, err := m.GetBillsByPubKeyHash(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	unitIDs, err := m.store.GetOwnerBillIDs(ctx, m.partitionID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bills of the owner: %w", err)
+	}
+	for _, bill := range bills {
+		if !slices.ContainsFunc(unitIDs, func(id abtypes.UnitID) bool { return bytes.Equal(id, bill.ID) }) {
+			unitIDs = append(unitIDs, bill.ID)
+		}
+	}
+	return unitIDs, nil
+}
+
+// GetTopHolders returns up to "limit" owners with the largest balances and their share of the total supply.
+func (m *Service) GetTopHolders(ctx context.Context, limit int) (*TopHolders, error) {
+	supply, err := m.GetMoneySupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := m.store.GetTopHolders(ctx, m.partitionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top holders: %w", err)
+	}
+	result := &TopHolders{
+		PartitionID: supply.PartitionID,
+		BlockNumber: supply.BlockNumber,
+		HolderCount: supply.HolderCount,
+		TotalSupply: supply.TotalSupply,
+		Holders:     make([]*Holder, 0, len(balances)),
+	}
+	for i, balance := range balances {
+		holder := &Holder{Rank: i + 1, OwnerID: balance.OwnerID, Balance: balance.Balance, BillCount: balance.BillCount}
+		if supply.TotalSupply > 0 {
+			holder.Share = float64(balance.Balance) / float64(supply.TotalSupply) * 100
+		}
+		result.Holders = append(result.Holders, holder)
+	}
+	return result, nil
+}
+
+// GetBalanceDistribution returns the number of the owners and their total balance by the balance bucket,
+// the buckets start at zero and the powers of ten.
+func (m *Service) GetBalanceDistribution(ctx context.Context) (*Distribution, error) {
+	supply, err := m.GetMoneySupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := m.store.GetBalanceDistribution(ctx, m.partitionID, distributionBoundaries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balance distribution: %w", err)
+	}
+	return &Distribution{
+		PartitionID: supply.PartitionID,
+		BlockNumber: supply.BlockNumber,
+		HolderCount: supply.HolderCount,
+		Buckets:     buckets,
+	}, nil
+}
+
+/*
+GetMoneySupply returns the supply of the money partition, domain.ErrNotFound when the money partition is not configured.
+The total supply is reported by the partition in the unicity certificate of the latest synced block.
+*/
+func (m *Service) GetMoneySupply(ctx context.Context) (*domain.MoneySupply, error) {
+	if m.moneyClient == nil {
+		return nil, fmt.Errorf("money partition not configured: %w", domain.ErrNotFound)
+	}
+	supply, err := m.store.GetMoneySupply(ctx, m.partitionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load money supply: %w", err)
+	}
+	if supply.BlockNumber == 0 {
+		return supply, nil
+	}
+	blocks, err := m.store.GetBlock(ctx, supply.BlockNumber, []abtypes.PartitionID{m.partitionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block %d: %w", supply.BlockNumber, err)
+	}
+	if block := blocks[m.partitionID]; block != nil {
+		if supply.TotalSupply, err = summaryValue(block); err != nil {
+			return nil, fmt.Errorf("failed to read total supply of block %d: %w", supply.BlockNumber, err)
+		}
+	}
+	return supply, nil
+}
+
+// summaryValue returns the state summary value of the unicity certificate of the block, the value of all the bills.
+func summaryValue(block *domain.BlockInfo) (uint64, error) {
+	uc := &abtypes.UnicityCertificate{}
+	if err := abtypes.Cbor.Unmarshal(block.UnicityCertificate, uc); err != nil {
+		return 0, fmt.Errorf("failed to decode unicity certificate: %w", err)
+	}
+	if uc.InputRecord == nil {
+		return 0, errors.New("unicity certificate has no input record")
+	}
+	if len(uc.InputRecord.SummaryValue) != 8 {
+		return 0, fmt.Errorf("invalid summary value length %d", len(uc.InputRecord.SummaryValue))
+	}
+	return binary.BigEndian.Uint64(uc.InputRecord.SummaryValue), nil
+}
@@ -0,0 +1,151 @@
+package money
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	storeStub struct {
+		balances   []*domain.Balance
+		buckets    []*domain.BalanceBucket
+		supply     *domain.MoneySupply
+		blocks     map[abtypes.PartitionID]*domain.BlockInfo
+		billIDs    []abtypes.UnitID
+		limit      int
+		boundaries []uint64
+	}
+
+	// moneyClientStub is the configured money partition, the client is not called by the balance queries.
+	moneyClientStub struct {
+		types.MoneyPartitionClient
+		bills []*types.Bill
+	}
+)
+
+func (c *moneyClientStub) GetBills(context.Context, hex.Bytes) ([]*types.Bill, error) {
+	return c.bills, nil
+}
+
+func (s *storeStub) GetTopHolders(_ context.Context, _ abtypes.PartitionID, limit int) ([]*domain.Balance, error) {
+	s.limit = limit
+	return s.balances, nil
+}
+
+func (s *storeStub) GetBalanceDistribution(_ context.Context, _ abtypes.PartitionID, boundaries []uint64) ([]*domain.BalanceBucket, error) {
+	s.boundaries = boundaries
+	return s.buckets, nil
+}
+
+func (s *storeStub) GetMoneySupply(context.Context, abtypes.PartitionID) (*domain.MoneySupply, error) {
+	supply := *s.supply
+	return &supply, nil
+}
+
+func (s *storeStub) GetOwnerBillIDs(context.Context, abtypes.PartitionID, hex.Bytes) ([]abtypes.UnitID, error) {
+	return s.billIDs, nil
+}
+
+func (s *storeStub) GetBlock(context.Context, uint64, []abtypes.PartitionID) (map[abtypes.PartitionID]*domain.BlockInfo, error) {
+	return s.blocks, nil
+}
+
+// blockWithSummary returns the block of the partition with the state summary value in its unicity certificate.
+func blockWithSummary(t *testing.T, summaryValue []byte) map[abtypes.PartitionID]*domain.BlockInfo {
+	uc, err := (&abtypes.UnicityCertificate{InputRecord: &abtypes.InputRecord{RoundNumber: 5, SummaryValue: summaryValue}}).MarshalCBOR()
+	require.NoError(t, err)
+	return map[abtypes.PartitionID]*domain.BlockInfo{1: {PartitionID: 1, BlockNumber: 5, UnicityCertificate: uc}}
+}
+
+func TestService_GetTopHolders(t *testing.T) {
+	store := &storeStub{
+		balances: []*domain.Balance{{OwnerID: []byte{1}, Balance: 75, BillCount: 2}, {OwnerID: []byte{2}, Balance: 20, BillCount: 1}},
+		supply:   &domain.MoneySupply{PartitionID: 1, BlockNumber: 5, HolderCount: 2},
+		blocks:   blockWithSummary(t, binary.BigEndian.AppendUint64(nil, 100)),
+	}
+	s := NewMoneyService(&moneyClientStub{}, 1, store)
+
+	holders, err := s.GetTopHolders(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 10, store.limit)
+	require.EqualValues(t, 5, holders.BlockNumber)
+	require.EqualValues(t, 2, holders.HolderCount)
+	require.Equal(t, []*Holder{
+		{Rank: 1, OwnerID: []byte{1}, Balance: 75, BillCount: 2, Share: 75},
+		{Rank: 2, OwnerID: []byte{2}, Balance: 20, BillCount: 1, Share: 20},
+	}, holders.Holders)
+
+	// no supply yet
+	store.blocks = nil
+	holders, err = s.GetTopHolders(context.Background(), 10)
+	require.NoError(t, err)
+	require.Zero(t, holders.Holders[0].Share)
+}
+
+func TestService_GetBalanceDistribution(t *testing.T) {
+	store := &storeStub{
+		buckets: []*domain.BalanceBucket{{Min: 10, Max: 100, HolderCount: 1, Balance: 20}},
+		supply:  &domain.MoneySupply{BlockNumber: 5, HolderCount: 1},
+	}
+	s := NewMoneyService(&moneyClientStub{}, 1, store)
+
+	distribution, err := s.GetBalanceDistribution(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, store.buckets, distribution.Buckets)
+	require.Len(t, store.boundaries, 20)
+	require.EqualValues(t, 0, store.boundaries[0])
+	require.EqualValues(t, 1, store.boundaries[1])
+	require.EqualValues(t, uint64(1e18), store.boundaries[19])
+}
+
+func TestService_GetMoneySupply(t *testing.T) {
+	store := &storeStub{
+		supply: &domain.MoneySupply{PartitionID: 1, BlockNumber: 5, BillsValue: 40, DustCollector: 5, UntrackedBills: 1},
+		blocks: blockWithSummary(t, binary.BigEndian.AppendUint64(nil, 1000)),
+	}
+	s := NewMoneyService(&moneyClientStub{}, 1, store)
+
+	// the total supply includes the bills created before the first synced block
+	supply, err := s.GetMoneySupply(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, supply.TotalSupply)
+	require.EqualValues(t, 40, supply.BillsValue)
+
+	store.blocks = blockWithSummary(t, []byte{1, 2})
+	_, err = s.GetMoneySupply(context.Background())
+	require.EqualError(t, err, "failed to read total supply of block 5: invalid summary value length 2")
+
+	// nothing synced yet
+	store.supply.BlockNumber = 0
+	supply, err = s.GetMoneySupply(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, supply.TotalSupply)
+}
+
+func TestService_GetOwnerBillIDs(t *testing.T) {
+	// the bill 3 was transferred away, the bill 1 is not stored as it was created before the first synced block
+	store := &storeStub{billIDs: []abtypes.UnitID{{2}, {3}}}
+	s := NewMoneyService(&moneyClientStub{bills: []*types.Bill{{ID: abtypes.UnitID{1}}, {ID: abtypes.UnitID{2}}}}, 1, store)
+
+	unitIDs, err := s.GetOwnerBillIDs(context.Background(), []byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []abtypes.UnitID{{2}, {3}, {1}}, unitIDs)
+}
+
+func TestService_NotConfigured(t *testing.T) {
+	s := NewMoneyService(nil, 0, &storeStub{})
+
+	_, err := s.GetMoneySupply(context.Background())
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = s.GetTopHolders(context.Background(), 10)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = s.GetBalanceDistribution(context.Background())
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
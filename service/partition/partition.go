@@ -2,24 +2,50 @@ package partition
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/orchestration"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	wallettypes "github.com/alphabill-org/alphabill-wallet/client/types"
 )
 
+// nodeCheckTimeout is the timeout of the health check of the partition node
+const nodeCheckTimeout = 10 * time.Second
+
 type (
 	Service struct {
 		partitions map[types.PartitionID]*Partition
+		store      Store
 		sync.RWMutex
 	}
 
+	Store interface {
+		GetLowestBlockNumbers(ctx context.Context, partitionIDs []types.PartitionID) (map[types.PartitionID]uint64, error)
+		GetPartitionStats(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionStats, error)
+		SetPartitionInfo(ctx context.Context, info *domain.PartitionInfo) error
+		GetPartitionInfos(ctx context.Context) ([]*domain.PartitionInfo, error)
+		GetPartitionInfo(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionInfo, error)
+	}
+
 	Partition struct {
 		RoundInfoClient
 		partitionID     types.PartitionID
 		partitionTypeID types.PartitionTypeID
+		networkID       types.NetworkID
+		url             string
 	}
 
 	RoundInfoClient interface {
@@ -27,19 +53,21 @@ type (
 	}
 
 	RoundInfo struct {
-		PartitionID     types.PartitionID
-		PartitionTypeID types.PartitionTypeID
-		RoundNumber     uint64
-		EpochNumber     uint64
+		PartitionID       types.PartitionID
+		PartitionTypeID   types.PartitionTypeID
+		RoundNumber       uint64
+		EpochNumber       uint64
+		LowestBlockNumber uint64 // the first block available in the explorer, older blocks are pruned or not indexed
 	}
 )
 
-func NewPartitionService(clients map[types.PartitionID]*Partition) (*Service, error) {
-	if clients == nil {
+func NewPartitionService(clients map[types.PartitionID]*Partition, store Store) (*Service, error) {
+	if clients == nil || store == nil {
 		return nil, domain.ErrNilArgument
 	}
 	return &Service{
 		partitions: clients,
+		store:      store,
 		RWMutex:    sync.RWMutex{},
 	}, nil
 }
@@ -49,6 +77,15 @@ func (p *Service) GetRoundNumber(ctx context.Context) ([]RoundInfo, error) {
 	p.RLock()
 	defer p.RUnlock()
 
+	partitionIDs := make([]types.PartitionID, 0, len(p.partitions))
+	for partitionID := range p.partitions {
+		partitionIDs = append(partitionIDs, partitionID)
+	}
+	lowestBlockNumbers, err := p.store.GetLowestBlockNumbers(ctx, partitionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lowest block numbers: %w", err)
+	}
+
 	var result []RoundInfo
 	for _, client := range p.partitions {
 		info, err := client.GetRoundInfo(ctx)
@@ -56,17 +93,19 @@ func (p *Service) GetRoundNumber(ctx context.Context) ([]RoundInfo, error) {
 			return nil, fmt.Errorf("failed to get round info for partition %d: %w", client.partitionID, err)
 		}
 		result = append(result, RoundInfo{
-			PartitionID:     client.partitionID,
-			PartitionTypeID: client.partitionTypeID,
-			RoundNumber:     info.RoundNumber,
-			EpochNumber:     info.Epoch,
+			PartitionID:       client.partitionID,
+			PartitionTypeID:   client.partitionTypeID,
+			RoundNumber:       info.RoundNumber,
+			EpochNumber:       info.Epoch,
+			LowestBlockNumber: lowestBlockNumbers[client.partitionID],
 		})
 	}
 	return result, nil
 }
 
+// AddPartition adds the partition served by the node at the given URL, the URL is only used in the registry.
 func (p *Service) AddPartition(
-	client RoundInfoClient, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID,
+	client RoundInfoClient, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID, networkID types.NetworkID, url string,
 ) {
 	p.Lock()
 	defer p.Unlock()
@@ -74,5 +113,147 @@ func (p *Service) AddPartition(
 		RoundInfoClient: client,
 		partitionID:     partitionID,
 		partitionTypeID: partitionTypeID,
+		networkID:       networkID,
+		url:             url,
+	}
+}
+
+// GetPartitions returns the registry entries of all the partitions ordered by the partition ID. The entries are
+// loaded from the store and include the partitions which nodes are not available.
+func (p *Service) GetPartitions(ctx context.Context) ([]*domain.PartitionInfo, error) {
+	return p.store.GetPartitionInfos(ctx)
+}
+
+// GetPartition returns the registry entry of the partition, domain.ErrNotFound if the partition is not registered.
+func (p *Service) GetPartition(ctx context.Context, partitionID types.PartitionID) (*domain.PartitionInfo, error) {
+	return p.store.GetPartitionInfo(ctx, partitionID)
+}
+
+// Run refreshes the registry entries of the partitions with the given interval until the context is cancelled.
+func (p *Service) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			log.Error("failed to refresh partition registry", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Refresh checks the nodes of the partitions and updates the registry entries of the partitions with the node status
+// and the statistics of the stored blocks. The entry of the partition which node is not available keeps the latest
+// round number reported by the node.
+func (p *Service) Refresh(ctx context.Context) error {
+	p.RLock()
+	partitions := slices.Collect(maps.Values(p.partitions))
+	p.RUnlock()
+
+	var errs []error
+	for _, partition := range partitions {
+		if err := p.refreshPartition(ctx, partition, time.Now().UTC()); err != nil {
+			errs = append(errs, fmt.Errorf("partition %d: %w", partition.partitionID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Service) refreshPartition(ctx context.Context, partition *Partition, now time.Time) error {
+	info, err := p.store.GetPartitionInfo(ctx, partition.partitionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		info = &domain.PartitionInfo{}
+	} else if err != nil {
+		return fmt.Errorf("failed to load partition info: %w", err)
+	}
+	stats, err := p.store.GetPartitionStats(ctx, partition.partitionID)
+	if err != nil {
+		return fmt.Errorf("failed to load partition stats: %w", err)
+	}
+
+	node := &domain.NodeStatus{URL: redactURL(partition.url), CheckedAt: now}
+	if idx := slices.IndexFunc(info.Nodes, func(n *domain.NodeStatus) bool { return n.URL == node.URL }); idx >= 0 {
+		node.RoundNumber = info.Nodes[idx].RoundNumber
+		node.LastHealthyAt = info.Nodes[idx].LastHealthyAt
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, nodeCheckTimeout)
+	roundInfo, err := partition.GetRoundInfo(checkCtx)
+	cancel()
+	if err != nil {
+		// the error of the client may contain the URL of the node with the API key, the registry only has the status
+		node.Error = nodeError(err)
+		log.Warn("partition node check failed", "partition", partition.partitionID, "err", err)
+	} else {
+		node.Healthy = true
+		node.RoundNumber = roundInfo.RoundNumber
+		node.LastHealthyAt = &now
+		info.RoundNumber = roundInfo.RoundNumber
+		info.EpochNumber = roundInfo.Epoch
+	}
+
+	info.PartitionID = partition.partitionID
+	info.PartitionTypeID = partition.partitionTypeID
+	info.TypeName = TypeName(partition.partitionTypeID)
+	info.NetworkID = partition.networkID
+	info.Shards = stats.Shards
+	info.Nodes = []*domain.NodeStatus{node}
+	info.FirstBlockNumber = stats.FirstBlockNumber
+	info.LatestBlockNumber = stats.LatestBlockNumber
+	info.SyncLag = 0
+	if info.RoundNumber > stats.LatestBlockNumber {
+		info.SyncLag = info.RoundNumber - stats.LatestBlockNumber
+	}
+	info.BlockCount = stats.BlockCount
+	info.TxCount = stats.TxCount
+	info.UpdatedAt = now
+	if err = p.store.SetPartitionInfo(ctx, info); err != nil {
+		return fmt.Errorf("failed to store partition info: %w", err)
+	}
+	return nil
+}
+
+// TypeName returns the name of the partition type, "unknown" for the types not supported by the explorer.
+func TypeName(partitionTypeID types.PartitionTypeID) string {
+	switch partitionTypeID {
+	case money.PartitionTypeID:
+		return "money"
+	case tokens.PartitionTypeID:
+		return "tokens"
+	case orchestration.PartitionTypeID:
+		return "orchestration"
+	default:
+		return "unknown"
+	}
+}
+
+// nodeError returns the status of the failed health check of the node stored in the registry.
+func nodeError(err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	msg := err.Error()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, syscall.ECONNREFUSED), strings.Contains(msg, "connection refused"):
+		return "connection refused"
+	case errors.Is(err, syscall.ECONNRESET), strings.Contains(msg, "connection reset"):
+		return "connection reset"
+	case errors.As(err, &dnsErr):
+		return "host not found"
+	default:
+		return "node not available"
+	}
+}
+
+// redactURL removes the credentials and the query of the node URL, these may contain API keys of the node provider.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
 	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
 }
@@ -1,24 +1,105 @@
 package search
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/alphabill-org/alphabill-explorer-backend/domain"
 	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
 	"github.com/alphabill-org/alphabill-explorer-backend/util"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/types/hex"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 )
 
+const (
+	HitBlock     HitType = "block"
+	HitTx        HitType = "tx"
+	HitUnit      HitType = "unit"
+	HitOwner     HitType = "owner"
+	HitTokenType HitType = "tokenType"
+
+	MatchBlockNumber     MatchKind = "blockNumber"
+	MatchBlockHash       MatchKind = "blockHash"
+	MatchTxRecordHash    MatchKind = "txRecordHash"
+	MatchTxOrderHash     MatchKind = "txOrderHash"
+	MatchUnitID          MatchKind = "unitID"
+	MatchOwnerPubKey     MatchKind = "ownerPubKey"
+	MatchOwnerPubKeyHash MatchKind = "ownerPubKeyHash"
+	MatchOwnerPredicate  MatchKind = "ownerPredicate"
+	MatchTargetUnit      MatchKind = "targetUnit" // the search key is one of the units the transaction targets
+
+	// the search key is a hex prefix of the value
+	MatchTxRecordHashPrefix MatchKind = "txRecordHashPrefix"
+	MatchTxOrderHashPrefix  MatchKind = "txOrderHashPrefix"
+	MatchUnitIDPrefix       MatchKind = "unitIDPrefix"
+	MatchBlockHashPrefix    MatchKind = "blockHashPrefix"
+
+	// the search key matches the text of the token type or the non-fungible token, case-insensitively
+	MatchTokenSymbol MatchKind = "tokenSymbol" // equal to the symbol
+	MatchTokenName   MatchKind = "tokenName"   // equal to the name
+	MatchTokenText   MatchKind = "tokenText"   // part of the symbol, the name or the URI
+	MatchTokenFuzzy  MatchKind = "tokenFuzzy"  // similar to the symbol or the name
+
+	lookupBlocks        = "blocks"
+	lookupBlockHash     = "block hash"
+	lookupTxs           = "txs"
+	lookupUnit          = "unit"
+	lookupOwnerUnits    = "owner units"
+	lookupTxPrefix      = "tx prefix"
+	lookupBlockPrefix   = "block prefix"
+	lookupTokenTexts    = "token texts"
+	maxPrefixLookupHits = 20 // number of transactions and blocks loaded by the prefix lookups of the search
+	maxTextLookupHits   = 20 // number of token texts loaded by the text lookup of the search
+	// minTextQueryLength is the number of letters and digits the search key must have for the text lookup, the
+	// trigrams of the shorter keys are shared by too many texts
+	minTextQueryLength = 3
+
+	// minTokenTextSimilarity is the trigram similarity of the search key and the token text required for the fuzzy match
+	minTokenTextSimilarity = 0.3
+)
+
+// ErrPrefixTooShort is returned when the prefix is shorter than the minimum prefix length.
+var ErrPrefixTooShort = errors.New("prefix is too short")
+
+// matchRanks orders the hits, exact identifiers of the entities come before the related entities.
+var matchRanks = map[MatchKind]int{
+	MatchBlockNumber:     0,
+	MatchBlockHash:       0,
+	MatchTxRecordHash:    0,
+	MatchTxOrderHash:     1,
+	MatchUnitID:          2,
+	MatchOwnerPubKey:     3,
+	MatchOwnerPubKeyHash: 3,
+	MatchOwnerPredicate:  3,
+	MatchTargetUnit:      4,
+
+	MatchTxRecordHashPrefix: 5,
+	MatchTxOrderHashPrefix:  5,
+	MatchUnitIDPrefix:       5,
+	MatchBlockHashPrefix:    5,
+
+	MatchTokenSymbol: 5,
+	MatchTokenName:   5,
+	MatchTokenText:   6,
+	MatchTokenFuzzy:  7,
+}
+
+var hitTypes = []HitType{HitBlock, HitTx, HitUnit, HitOwner, HitTokenType}
+
 type (
 	Service struct {
 		store            BlockStore
 		partitionClients map[types.PartitionID]PartitionClient
+		minPrefixLength  int // minimum number of hex digits of the prefix search key
 	}
 
 	PartitionClient interface {
@@ -28,181 +109,624 @@ type (
 
 	BlockStore interface {
 		GetBlock(ctx context.Context, blockNumber uint64, partitionIDs []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error)
+		GetBlockByHash(ctx context.Context, blockHash []byte) (*domain.BlockInfo, error)
 		FindTxs(ctx context.Context, searchKey []byte, partitionIDs []types.PartitionID) ([]*domain.TxInfo, error)
+		FindTxsByPrefix(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*domain.TxInfo, error)
+		FindBlocksByPrefix(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*domain.BlockInfo, error)
+		FindTokenTexts(ctx context.Context, query string, partitionIDs []types.PartitionID, limit int) ([]*domain.TokenText, error)
+	}
+
+	// HitType is the type of the entity found.
+	HitType string
+
+	// MatchKind tells how the search key matched the entity.
+	MatchKind string
+
+	// Hit is the entity matching the search key, only the field of the hit type is set.
+	Hit struct {
+		Type        HitType
+		Match       MatchKind
+		PartitionID types.PartitionID
+		Block       *domain.BlockInfo
+		Tx          *domain.TxInfo
+		Unit        *sdktypes.Unit[any] // unit and token type, the owner predicate of the unit data is decoded
+		UnitIDs     []types.UnitID      // units of the owner, the unit matching the prefix or the text
+		Token       *domain.TokenText   // text of the token type or the non-fungible token matching the search key
+		Similarity  float64             // trigram similarity of the search key and the token text
+	}
+
+	// Suggestion is the identifier starting with the prefix.
+	Suggestion struct {
+		Type        HitType
+		Match       MatchKind
+		PartitionID types.PartitionID
+		Key         hex.Bytes
+	}
+
+	// Failure is the lookup which failed, the partition ID is nil when the lookup was not partition specific.
+	Failure struct {
+		PartitionID *types.PartitionID
+		Lookup      string
+		Error       string
 	}
 
 	Result struct {
+		Hits     []*Hit // ranked, best match first
+		Failures []*Failure
+
+		// hits grouped by the type, kept for the clients of the original search response
 		Blocks  map[types.PartitionID]*domain.BlockInfo
 		Txs     []*domain.TxInfo
 		UnitIDs map[types.PartitionID][]types.UnitID
 		Unit    *sdktypes.Unit[any]
+
+		mu sync.Mutex
 	}
 )
 
-func NewSearchService(store BlockStore, partitionClients map[types.PartitionID]PartitionClient) (*Service, error) {
+func NewSearchService(store BlockStore, partitionClients map[types.PartitionID]PartitionClient, minPrefixLength int) (*Service, error) {
 	if store == nil {
 		return nil, errors.New("store is nil")
 	}
 	if partitionClients == nil {
 		return nil, errors.New("partitionClients is nil")
 	}
+	if minPrefixLength < 1 {
+		return nil, fmt.Errorf("invalid minimum prefix length %d", minPrefixLength)
+	}
 	return &Service{
 		store:            store,
 		partitionClients: partitionClients,
+		minPrefixLength:  minPrefixLength,
 	}, nil
 }
 
-func (s *Service) Search(ctx context.Context, searchKey string, partitionIDs []types.PartitionID) (*Result, error) {
-	blockNumber, err := strconv.ParseUint(searchKey, 10, 64)
-	if err == nil {
-		blockMap, err := s.store.GetBlock(ctx, blockNumber, partitionIDs)
-		if err != nil || len(blockMap) == 0 {
-			return nil, domain.ErrNotFound
+// ParseHitType returns the hit type of the string, error when the type is unknown.
+func ParseHitType(s string) (HitType, error) {
+	if t := HitType(s); slices.Contains(hitTypes, t) {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown hit type %q", s)
+}
+
+/*
+Search finds the entities matching the search key in the given partitions (all partitions when empty) and
+returns the hits of the given types (all types when empty) ranked by the match kind. Decimal search key is
+looked up as a block number, hex encoded key as a block hash, transaction hash, unit ID and owner public key (hash).
+Hex key of at least the minimum prefix length (odd number of digits is allowed) is also looked up as a prefix
+of the transaction hashes, unit IDs and block hashes, prefix matches are ranked after the exact matches.
+Any key is looked up in the symbols and names of the token types and the names and URIs of the non-fungible
+tokens, case-insensitively and fuzzily, the text matches are ranked with the prefix matches and after them.
+Failed lookups don't fail the search, they are reported in the Failures of the result.
+*/
+func (s *Service) Search(ctx context.Context, searchKey string, partitionIDs []types.PartitionID, hitTypeFilter []HitType) (*Result, error) {
+	result := &Result{}
+	wanted := func(t HitType) bool { return len(hitTypeFilter) == 0 || slices.Contains(hitTypeFilter, t) }
+
+	var wg sync.WaitGroup
+	if wanted(HitTokenType) || wanted(HitUnit) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.findTokenTexts(ctx, result, searchKey, partitionIDs, wanted)
+		}()
+	}
+	s.findByKey(ctx, result, searchKey, partitionIDs, wanted)
+	wg.Wait()
+
+	result.finalize()
+	return result, nil
+}
+
+/*
+findByKey looks up the decimal search key as a block number and the hex search key as an identifier or a prefix.
+The decimal search key long enough for the prefix search is also looked up as a prefix, digits are hex digits too.
+*/
+func (s *Service) findByKey(ctx context.Context, result *Result, searchKey string, partitionIDs []types.PartitionID, wanted func(HitType) bool) {
+	if blockNumber, err := strconv.ParseUint(searchKey, 10, 64); err == nil {
+		var wg sync.WaitGroup
+		if len(searchKey) >= s.minPrefixLength {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.findByPrefix(ctx, result, searchKey, partitionIDs, wanted)
+			}()
 		}
-		return &Result{Blocks: blockMap}, nil
+		if wanted(HitBlock) {
+			s.findBlocks(ctx, result, blockNumber, partitionIDs)
+		}
+		wg.Wait()
+		return
 	}
 
+	prefix, err := util.NormalizeHexPrefix(searchKey)
+	if err != nil {
+		return
+	}
+	prefixSearch := len(prefix) >= s.minPrefixLength
 	searchKeyBytes, err := util.DecodeHex(searchKey)
 	if err != nil {
-		return nil, domain.ErrFailedToDecodeHex
+		// odd number of digits can only be a prefix
+		if prefixSearch {
+			s.findByPrefix(ctx, result, prefix, partitionIDs, wanted)
+		}
+		return
 	}
+	partitionsToSearch := s.partitionsToSearch(partitionIDs)
 
-	var (
-		wg                 sync.WaitGroup
-		partitionsToSearch []types.PartitionID
-		txs                []*domain.TxInfo
-		units              = make(map[types.PartitionID][]types.UnitID)
-		unit               *sdktypes.Unit[any]
-	)
-	if len(partitionIDs) > 0 {
-		for _, partitionID := range partitionIDs {
-			if _, exists := s.partitionClients[partitionID]; exists {
-				partitionsToSearch = append(partitionsToSearch, partitionID)
-			} else {
-				log.Warn("Partition does not exist in search service", "id", partitionID)
-			}
+	var wg sync.WaitGroup
+	if prefixSearch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.findByPrefix(ctx, result, prefix, partitionIDs, wanted)
+		}()
+	}
+	if address, _ := predicate.ParseAddress(searchKey); address != nil && wanted(HitOwner) {
+		match := MatchOwnerPubKeyHash
+		switch address.Kind {
+		case predicate.AddressPubKey:
+			match = MatchOwnerPubKey
+		case predicate.AddressPredicate:
+			match = MatchOwnerPredicate
 		}
-	} else {
-		for partitionID, _ := range s.partitionClients {
-			partitionsToSearch = append(partitionsToSearch, partitionID)
+		for _, partitionID := range partitionsToSearch {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.findUnitsByOwner(ctx, result, partitionID, address.OwnerID, match)
+			}()
 		}
 	}
-
-	pubKeyHash, _ := util.PubKeyHash(searchKey)
-	if pubKeyHash != nil {
+	if wanted(HitUnit) || wanted(HitTokenType) {
+		for _, partitionID := range partitionsToSearch {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.findUnit(ctx, result, partitionID, searchKeyBytes, wanted)
+			}()
+		}
+	}
+	if wanted(HitTx) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			units = s.findUnitsByOwnerPubKey(ctx, pubKeyHash, partitionsToSearch)
+			s.findTxs(ctx, result, searchKeyBytes, partitionIDs)
 		}()
 	}
+	if wanted(HitBlock) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.findBlockByHash(ctx, result, searchKeyBytes, partitionIDs)
+		}()
+	}
+	wg.Wait()
+}
 
-	wg.Add(1)
+/*
+Suggest returns up to "limit" transaction hashes, unit IDs and block hashes starting with the hex prefix for the
+autocomplete of the search key. Returns ErrPrefixTooShort when the prefix is shorter than the minimum prefix length.
+*/
+func (s *Service) Suggest(ctx context.Context, prefix string, partitionIDs []types.PartitionID, limit int) ([]*Suggestion, error) {
+	prefix, err := util.NormalizeHexPrefix(prefix)
+	if err != nil {
+		return nil, domain.ErrFailedToDecodeHex
+	}
+	if len(prefix) < s.minPrefixLength {
+		return nil, fmt.Errorf("%w, minimum length is %d hex digits", ErrPrefixTooShort, s.minPrefixLength)
+	}
+
+	var (
+		txs       []*domain.TxInfo
+		blocks    []*domain.BlockInfo
+		txsErr    error
+		blocksErr error
+		wg        sync.WaitGroup
+	)
+	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		unit = s.findUnit(ctx, searchKeyBytes, partitionsToSearch)
+		txs, txsErr = s.store.FindTxsByPrefix(ctx, prefix, partitionIDs, limit)
 	}()
-
-	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var txsErr error
-		txs, txsErr = s.store.FindTxs(ctx, searchKeyBytes, partitionIDs)
-		if txsErr != nil && txsErr != domain.ErrNotFound {
-			fmt.Println("Tx search failed: ", txsErr)
-		}
+		blocks, blocksErr = s.store.FindBlocksByPrefix(ctx, prefix, partitionIDs, limit)
 	}()
 	wg.Wait()
+	if err = errors.Join(txsErr, blocksErr); err != nil {
+		return nil, fmt.Errorf("failed to find suggestions: %w", err)
+	}
 
-	return &Result{
-		Txs:     txs,
-		UnitIDs: units,
-		Unit:    unit,
-	}, nil
+	var suggestions []*Suggestion
+	add := func(hitType HitType, match MatchKind, partitionID types.PartitionID, key []byte) {
+		if !slices.ContainsFunc(suggestions, func(s *Suggestion) bool {
+			return s.Type == hitType && s.PartitionID == partitionID && bytes.Equal(s.Key, key)
+		}) {
+			suggestions = append(suggestions, &Suggestion{Type: hitType, Match: match, PartitionID: partitionID, Key: key})
+		}
+	}
+	for _, tx := range txs {
+		if hasHexPrefix(tx.TxRecordHash, prefix) {
+			add(HitTx, MatchTxRecordHashPrefix, tx.PartitionID, tx.TxRecordHash)
+		}
+		if hasHexPrefix(tx.TxOrderHash, prefix) {
+			add(HitTx, MatchTxOrderHashPrefix, tx.PartitionID, tx.TxOrderHash)
+		}
+		for _, unitID := range matchingTargetUnits(tx, prefix) {
+			add(HitUnit, MatchUnitIDPrefix, tx.PartitionID, unitID)
+		}
+	}
+	for _, block := range blocks {
+		add(HitBlock, MatchBlockHashPrefix, block.PartitionID, block.BlockHash)
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b *Suggestion) int {
+		return bytes.Compare(a.Key, b.Key)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
 }
 
-func (s *Service) findUnit(ctx context.Context, unitID types.UnitID, partitionIDs []types.PartitionID) *sdktypes.Unit[any] {
+func (s *Service) partitionsToSearch(partitionIDs []types.PartitionID) []types.PartitionID {
 	if len(partitionIDs) == 0 {
-		return nil
+		partitionIDs := make([]types.PartitionID, 0, len(s.partitionClients))
+		for partitionID := range s.partitionClients {
+			partitionIDs = append(partitionIDs, partitionID)
+		}
+		return partitionIDs
 	}
-	var (
-		wg                    sync.WaitGroup
-		unitResultChan        = make(chan *sdktypes.Unit[any], 1)
-		ctxWithCancel, cancel = context.WithCancel(ctx)
-	)
-	defer cancel()
-
+	var partitionsToSearch []types.PartitionID
 	for _, partitionID := range partitionIDs {
-		client, exists := s.partitionClients[partitionID]
-		if !exists {
-			log.Info("Skipping unknown partition", "id", partitionID)
-			continue
+		if _, exists := s.partitionClients[partitionID]; exists {
+			partitionsToSearch = append(partitionsToSearch, partitionID)
+		} else {
+			log.Warn("Partition does not exist in search service", "id", partitionID)
 		}
-		wg.Add(1)
-		go func(c PartitionClient) {
-			defer wg.Done()
-			unit, err := c.GetUnit(ctxWithCancel, unitID, false)
-			if unit != nil {
-				cancel() // Cancel other goroutines if we found a result
-				select {
-				case unitResultChan <- unit:
-				default:
-					// channel full, do nothing
-				}
-			}
-			if err != nil && !errors.Is(err, context.Canceled) {
-				log.Error("Failed to get unit", "err", err)
-			}
-		}(client)
 	}
-	wg.Wait()
+	return partitionsToSearch
+}
 
-	select {
-	case unit := <-unitResultChan:
-		return unit
-	default:
-		return nil
+func (s *Service) findBlocks(ctx context.Context, result *Result, blockNumber uint64, partitionIDs []types.PartitionID) {
+	blockMap, err := s.store.GetBlock(ctx, blockNumber, partitionIDs)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			result.addFailure(nil, lookupBlocks, err)
+		}
+		return
+	}
+	for partitionID, block := range blockMap {
+		result.addHit(&Hit{Type: HitBlock, Match: MatchBlockNumber, PartitionID: partitionID, Block: block})
 	}
 }
 
-func (s *Service) findUnitsByOwnerPubKey(ctx context.Context, pubKey []byte, partitionIDs []types.PartitionID) map[types.PartitionID][]types.UnitID {
-	if len(partitionIDs) == 0 {
-		return make(map[types.PartitionID][]types.UnitID)
+func (s *Service) findBlockByHash(ctx context.Context, result *Result, blockHash []byte, partitionIDs []types.PartitionID) {
+	block, err := s.store.GetBlockByHash(ctx, blockHash)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			result.addFailure(nil, lookupBlockHash, err)
+		}
+		return
 	}
-	var (
-		wg              sync.WaitGroup
-		unitResultsChan = make(chan map[types.PartitionID][]types.UnitID, len(partitionIDs))
-		units           = make(map[types.PartitionID][]types.UnitID)
-	)
+	if len(partitionIDs) == 0 || slices.Contains(partitionIDs, block.PartitionID) {
+		result.addHit(&Hit{Type: HitBlock, Match: MatchBlockHash, PartitionID: block.PartitionID, Block: block})
+	}
+}
 
-	for _, partitionID := range partitionIDs {
-		client, exists := s.partitionClients[partitionID]
-		if !exists {
-			log.Info("Skipping unknown partition", "id", partitionID)
-			continue
-		}
+func (s *Service) findByPrefix(ctx context.Context, result *Result, prefix string, partitionIDs []types.PartitionID, wanted func(HitType) bool) {
+	var wg sync.WaitGroup
+	if wanted(HitTx) || wanted(HitUnit) {
 		wg.Add(1)
-		go func(pid types.PartitionID, c PartitionClient) {
+		go func() {
 			defer wg.Done()
-			unitIDs, err := c.GetUnitsByOwnerID(ctx, pubKey)
-			unitResultsChan <- map[types.PartitionID][]types.UnitID{pid: unitIDs}
+			txs, err := s.store.FindTxsByPrefix(ctx, prefix, partitionIDs, maxPrefixLookupHits)
 			if err != nil {
-				log.Error("Failed to get units by owner ID", "err", err)
+				result.addFailure(nil, lookupTxPrefix, err)
+				return
 			}
-		}(partitionID, client)
+			for _, tx := range txs {
+				if wanted(HitTx) {
+					switch {
+					case hasHexPrefix(tx.TxRecordHash, prefix):
+						result.addHit(&Hit{Type: HitTx, Match: MatchTxRecordHashPrefix, PartitionID: tx.PartitionID, Tx: tx})
+					case hasHexPrefix(tx.TxOrderHash, prefix):
+						result.addHit(&Hit{Type: HitTx, Match: MatchTxOrderHashPrefix, PartitionID: tx.PartitionID, Tx: tx})
+					}
+				}
+				if wanted(HitUnit) {
+					for _, unitID := range matchingTargetUnits(tx, prefix) {
+						result.addHit(&Hit{Type: HitUnit, Match: MatchUnitIDPrefix, PartitionID: tx.PartitionID, UnitIDs: []types.UnitID{unitID}})
+					}
+				}
+			}
+		}()
+	}
+	if wanted(HitBlock) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blocks, err := s.store.FindBlocksByPrefix(ctx, prefix, partitionIDs, maxPrefixLookupHits)
+			if err != nil {
+				result.addFailure(nil, lookupBlockPrefix, err)
+				return
+			}
+			for _, block := range blocks {
+				result.addHit(&Hit{Type: HitBlock, Match: MatchBlockHashPrefix, PartitionID: block.PartitionID, Block: block})
+			}
+		}()
 	}
 	wg.Wait()
-	close(unitResultsChan)
+}
 
-	for kv := range unitResultsChan {
-		for partitionID, unitIDs := range kv {
-			units[partitionID] = unitIDs
+func (s *Service) findTokenTexts(ctx context.Context, result *Result, query string, partitionIDs []types.PartitionID, wanted func(HitType) bool) {
+	if textLength(query) < minTextQueryLength {
+		return
+	}
+	texts, err := s.store.FindTokenTexts(ctx, query, partitionIDs, maxTextLookupHits)
+	if err != nil {
+		result.addFailure(nil, lookupTokenTexts, err)
+		return
+	}
+	for _, text := range texts {
+		hitType := HitTokenType
+		if text.Kind == domain.TokenTextNonFungibleToken {
+			hitType = HitUnit
+		}
+		if !wanted(hitType) {
+			continue
+		}
+		if match, similarity := matchTokenText(text, query); match != "" {
+			result.addHit(&Hit{
+				Type:        hitType,
+				Match:       match,
+				PartitionID: text.PartitionID,
+				UnitIDs:     []types.UnitID{text.UnitID},
+				Token:       text,
+				Similarity:  similarity,
+			})
+		}
+	}
+}
+
+func (s *Service) findTxs(ctx context.Context, result *Result, searchKey []byte, partitionIDs []types.PartitionID) {
+	txs, err := s.store.FindTxs(ctx, searchKey, partitionIDs)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			result.addFailure(nil, lookupTxs, err)
+		}
+		return
+	}
+	for _, tx := range txs {
+		match := MatchTargetUnit
+		switch {
+		case bytes.Equal(tx.TxRecordHash, searchKey):
+			match = MatchTxRecordHash
+		case bytes.Equal(tx.TxOrderHash, searchKey):
+			match = MatchTxOrderHash
 		}
+		result.addHit(&Hit{Type: HitTx, Match: match, PartitionID: tx.PartitionID, Tx: tx})
+	}
+}
+
+func (s *Service) findUnit(ctx context.Context, result *Result, partitionID types.PartitionID, unitID types.UnitID, wanted func(HitType) bool) {
+	unit, err := s.partitionClients[partitionID].GetUnit(ctx, unitID, false)
+	if err != nil {
+		result.addFailure(&partitionID, lookupUnit, err)
+		return
+	}
+	if unit == nil {
+		return
 	}
+	hitType := HitUnit
+	if isTokenType(unit) {
+		hitType = HitTokenType
+	}
+	if wanted(hitType) {
+		decoded := *unit
+		decoded.Data = predicate.DecodeUnitData(unit.Data)
+		result.addHit(&Hit{Type: hitType, Match: MatchUnitID, PartitionID: partitionID, Unit: &decoded})
+	}
+}
 
-	return units
+func (s *Service) findUnitsByOwner(ctx context.Context, result *Result, partitionID types.PartitionID, pubKeyHash []byte, match MatchKind) {
+	unitIDs, err := s.partitionClients[partitionID].GetUnitsByOwnerID(ctx, pubKeyHash)
+	if err != nil {
+		result.addFailure(&partitionID, lookupOwnerUnits, err)
+		return
+	}
+	if len(unitIDs) > 0 {
+		result.addHit(&Hit{Type: HitOwner, Match: match, PartitionID: partitionID, UnitIDs: unitIDs})
+	}
 }
 
 func (s *Service) AddPartitionClient(client PartitionClient, partitionID types.PartitionID) {
 	s.partitionClients[partitionID] = client
 }
+
+/*
+isTokenType tells whether the unit is a fungible or non-fungible token type, the node returns the unit data
+as JSON object and only the token types have the symbol and the sub type creation predicate.
+*/
+func isTokenType(unit *sdktypes.Unit[any]) bool {
+	data, ok := unit.Data.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, hasSymbol := data["symbol"]
+	_, hasSubTypePredicate := data["subTypeCreationPredicate"]
+	return hasSymbol && hasSubTypePredicate
+}
+
+/*
+matchTokenText tells how the query matches the token text, empty match kind when the text is not similar enough
+to the query. The texts are compared case-insensitively, the similarity is the best trigram similarity of the
+query and the symbol, the name or the URI.
+*/
+func matchTokenText(text *domain.TokenText, query string) (MatchKind, float64) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return "", 0
+	}
+	symbol, name, uri := strings.ToLower(text.Symbol), strings.ToLower(text.Name), strings.ToLower(text.URI)
+	similarity := max(util.TrigramSimilarity(q, symbol), util.TrigramSimilarity(q, name), util.TrigramSimilarity(q, uri))
+	switch {
+	case q == symbol:
+		return MatchTokenSymbol, similarity
+	case q == name:
+		return MatchTokenName, similarity
+	case strings.Contains(symbol, q) || strings.Contains(name, q) || strings.Contains(uri, q):
+		return MatchTokenText, similarity
+	case similarity >= minTokenTextSimilarity:
+		return MatchTokenFuzzy, similarity
+	}
+	return "", 0
+}
+
+// textLength returns the number of letters and digits of the text.
+func textLength(text string) int {
+	n := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// hasHexPrefix tells whether the hex encoding of the value starts with the lowercase hex prefix.
+func hasHexPrefix(value []byte, prefix string) bool {
+	return strings.HasPrefix(fmt.Sprintf("%x", value), prefix)
+}
+
+func matchingTargetUnits(tx *domain.TxInfo, prefix string) []types.UnitID {
+	if tx.Transaction == nil || tx.Transaction.ServerMetadata == nil {
+		return nil
+	}
+	var unitIDs []types.UnitID
+	for _, unitID := range tx.Transaction.ServerMetadata.TargetUnits {
+		if hasHexPrefix(unitID, prefix) {
+			unitIDs = append(unitIDs, unitID)
+		}
+	}
+	return unitIDs
+}
+
+// hitKey identifies the entity of the hit, the entity found by the exact and the prefix lookups is returned once.
+func hitKey(hit *Hit) string {
+	switch hit.Type {
+	case HitBlock:
+		return fmt.Sprintf("block/%d/%d", hit.PartitionID, hit.Block.BlockNumber)
+	case HitTx:
+		return fmt.Sprintf("tx/%x", []byte(hit.Tx.TxRecordHash))
+	case HitUnit, HitTokenType:
+		unitID := hit.UnitIDs
+		if hit.Unit != nil {
+			unitID = []types.UnitID{hit.Unit.UnitID}
+		}
+		if len(unitID) == 1 {
+			return fmt.Sprintf("unit/%d/%x", hit.PartitionID, []byte(unitID[0]))
+		}
+	}
+	return ""
+}
+
+func (r *Result) addHit(hit *Hit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Hits = append(r.Hits, hit)
+}
+
+func (r *Result) addFailure(partitionID *types.PartitionID, lookup string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	log.Warn("Search lookup failed", "lookup", lookup, "partition", partitionID, "err", err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Failures = append(r.Failures, &Failure{PartitionID: partitionID, Lookup: lookup, Error: err.Error()})
+}
+
+/*
+finalize ranks the hits by the match kind, then by the partition ID, the hit type, the similarity of the token text
+and the latest block first, removes the duplicate hits of the lower rank and groups the hits by the type.
+*/
+func (r *Result) finalize() {
+	slices.SortStableFunc(r.Hits, func(a, b *Hit) int {
+		if c := matchRanks[a.Match] - matchRanks[b.Match]; c != 0 {
+			return c
+		}
+		if a.PartitionID != b.PartitionID {
+			if a.PartitionID < b.PartitionID {
+				return -1
+			}
+			return 1
+		}
+		if c := slices.Index(hitTypes, a.Type) - slices.Index(hitTypes, b.Type); c != 0 {
+			return c
+		}
+		if a.Similarity != b.Similarity {
+			if a.Similarity > b.Similarity {
+				return -1
+			}
+			return 1
+		}
+		if a.Tx != nil && b.Tx != nil && a.Tx.BlockNumber != b.Tx.BlockNumber {
+			if a.Tx.BlockNumber > b.Tx.BlockNumber {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	seen := make(map[string]bool)
+	r.Hits = slices.DeleteFunc(r.Hits, func(hit *Hit) bool {
+		key := hitKey(hit)
+		if key == "" {
+			return false
+		}
+		duplicate := seen[key]
+		seen[key] = true
+		return duplicate
+	})
+	for _, hit := range r.Hits {
+		switch hit.Type {
+		case HitBlock:
+			if r.Blocks == nil {
+				r.Blocks = make(map[types.PartitionID]*domain.BlockInfo)
+			}
+			if _, ok := r.Blocks[hit.PartitionID]; !ok {
+				r.Blocks[hit.PartitionID] = hit.Block
+			}
+		case HitTx:
+			r.Txs = append(r.Txs, hit.Tx)
+		case HitUnit, HitTokenType:
+			if r.Unit == nil {
+				r.Unit = hit.Unit
+			}
+		case HitOwner:
+			if r.UnitIDs == nil {
+				r.UnitIDs = make(map[types.PartitionID][]types.UnitID)
+			}
+			r.UnitIDs[hit.PartitionID] = hit.UnitIDs
+		}
+	}
+
+	slices.SortStableFunc(r.Failures, func(a, b *Failure) int {
+		switch {
+		case a.PartitionID == nil && b.PartitionID == nil:
+			return strings.Compare(a.Lookup, b.Lookup)
+		case a.PartitionID == nil:
+			return -1
+		case b.PartitionID == nil:
+			return 1
+		case *a.PartitionID < *b.PartitionID:
+			return -1
+		case *a.PartitionID > *b.PartitionID:
+			return 1
+		}
+		return 0
+	})
+}
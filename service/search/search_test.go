@@ -0,0 +1,369 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	storeStub struct {
+		blocks       map[types.PartitionID]*domain.BlockInfo
+		txs          []*domain.TxInfo
+		txsErr       error
+		findTxs      int
+		prefixTxs    []*domain.TxInfo
+		prefixBlocks []*domain.BlockInfo
+		prefixErr    error
+		texts        []*domain.TokenText
+		textsErr     error
+		blockByHash  *domain.BlockInfo
+	}
+
+	clientStub struct {
+		unit       *sdktypes.Unit[any]
+		unitErr    error
+		ownerUnits []types.UnitID
+		ownerErr   error
+	}
+)
+
+func (s *storeStub) GetBlock(context.Context, uint64, []types.PartitionID) (map[types.PartitionID]*domain.BlockInfo, error) {
+	if len(s.blocks) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return s.blocks, nil
+}
+
+func (s *storeStub) GetBlockByHash(context.Context, []byte) (*domain.BlockInfo, error) {
+	if s.blockByHash == nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.blockByHash, nil
+}
+
+func (s *storeStub) FindTxs(context.Context, []byte, []types.PartitionID) ([]*domain.TxInfo, error) {
+	s.findTxs++
+	return s.txs, s.txsErr
+}
+
+func (s *storeStub) FindTxsByPrefix(context.Context, string, []types.PartitionID, int) ([]*domain.TxInfo, error) {
+	return s.prefixTxs, s.prefixErr
+}
+
+func (s *storeStub) FindBlocksByPrefix(context.Context, string, []types.PartitionID, int) ([]*domain.BlockInfo, error) {
+	return s.prefixBlocks, s.prefixErr
+}
+
+func (s *storeStub) FindTokenTexts(context.Context, string, []types.PartitionID, int) ([]*domain.TokenText, error) {
+	return s.texts, s.textsErr
+}
+
+func (c *clientStub) GetUnitsByOwnerID(context.Context, hex.Bytes) ([]types.UnitID, error) {
+	return c.ownerUnits, c.ownerErr
+}
+
+func (c *clientStub) GetUnit(context.Context, types.UnitID, bool) (*sdktypes.Unit[any], error) {
+	return c.unit, c.unitErr
+}
+
+func TestParseHitType(t *testing.T) {
+	hitType, err := ParseHitType("tokenType")
+	require.NoError(t, err)
+	require.Equal(t, HitTokenType, hitType)
+
+	_, err = ParseHitType("bill")
+	require.ErrorContains(t, err, `unknown hit type "bill"`)
+}
+
+func TestService_Search(t *testing.T) {
+	ctx := context.Background()
+	key, keyHex := types.UnitID{1, 2, 3}, "0x010203"
+	pubKey := "0x" + strings.Repeat("02", 33)
+
+	newService := func(store *storeStub, clients map[types.PartitionID]PartitionClient) *Service {
+		s, err := NewSearchService(store, clients, 6)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("block number", func(t *testing.T) {
+		store := &storeStub{blocks: map[types.PartitionID]*domain.BlockInfo{2: {BlockNumber: 5}, 1: {BlockNumber: 5}}}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "5", nil, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 2)
+		require.Equal(t, &Hit{Type: HitBlock, Match: MatchBlockNumber, PartitionID: 1, Block: store.blocks[1]}, result.Hits[0])
+		require.Equal(t, types.PartitionID(2), result.Hits[1].PartitionID)
+		require.Len(t, result.Blocks, 2)
+		require.Zero(t, store.findTxs)
+	})
+
+	t.Run("block number is also a prefix", func(t *testing.T) {
+		block := &domain.BlockInfo{BlockNumber: 123456, PartitionID: 1}
+		tx := &domain.TxInfo{TxRecordHash: []byte{0x12, 0x34, 0x56, 0xab}, TxOrderHash: []byte{1}, PartitionID: 1}
+		store := &storeStub{blocks: map[types.PartitionID]*domain.BlockInfo{1: block}, prefixTxs: []*domain.TxInfo{tx}}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "123456", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{
+			{Type: HitBlock, Match: MatchBlockNumber, PartitionID: 1, Block: block},
+			{Type: HitTx, Match: MatchTxRecordHashPrefix, PartitionID: 1, Tx: tx},
+		}, result.Hits)
+
+		// the number shorter than the minimum prefix length is only a block number
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "12345", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{{Type: HitBlock, Match: MatchBlockNumber, PartitionID: 1, Block: block}}, result.Hits)
+	})
+
+	t.Run("block number not found", func(t *testing.T) {
+		result, err := newService(&storeStub{}, map[types.PartitionID]PartitionClient{}).Search(ctx, "5", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		require.Empty(t, result.Failures)
+	})
+
+	t.Run("invalid hex is only looked up as text", func(t *testing.T) {
+		result, err := newService(&storeStub{}, map[types.PartitionID]PartitionClient{}).Search(ctx, "xyz", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		require.Empty(t, result.Failures)
+	})
+
+	t.Run("ranked hits", func(t *testing.T) {
+		store := &storeStub{txs: []*domain.TxInfo{
+			{TxRecordHash: []byte{9}, TxOrderHash: []byte{8}, BlockNumber: 1, PartitionID: 1},
+			{TxRecordHash: []byte{7}, TxOrderHash: domain.TxHash(key), BlockNumber: 2, PartitionID: 1},
+			{TxRecordHash: []byte{6}, TxOrderHash: []byte{5}, BlockNumber: 3, PartitionID: 1},
+			{TxRecordHash: domain.TxHash(key), TxOrderHash: []byte{4}, BlockNumber: 4, PartitionID: 2},
+		}}
+		owner := predicate.P2PKH(bytes.Repeat([]byte{1}, 32))
+		unit := &sdktypes.Unit[any]{UnitID: key, Data: map[string]any{"ownerPredicate": fmt.Sprintf("0x%x", owner)}}
+		tokenType := &sdktypes.Unit[any]{UnitID: key, Data: map[string]any{"symbol": "AB", "subTypeCreationPredicate": "0x"}}
+		clients := map[types.PartitionID]PartitionClient{1: &clientStub{unit: unit}, 2: &clientStub{unit: tokenType}}
+
+		result, err := newService(store, clients).Search(ctx, keyHex, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Failures)
+
+		var matches []MatchKind
+		for _, hit := range result.Hits {
+			matches = append(matches, hit.Match)
+		}
+		require.Equal(t, []MatchKind{MatchTxRecordHash, MatchTxOrderHash, MatchUnitID, MatchUnitID, MatchTargetUnit, MatchTargetUnit}, matches)
+		require.Equal(t, store.txs[3], result.Hits[0].Tx)
+		require.Equal(t, store.txs[1], result.Hits[1].Tx)
+		require.Equal(t, HitUnit, result.Hits[2].Type)
+		require.Equal(t, map[string]any{"ownerPredicate": predicate.Decode(owner)}, result.Hits[2].Unit.Data)
+		require.Equal(t, HitTokenType, result.Hits[3].Type)
+		require.Equal(t, store.txs[2], result.Hits[4].Tx, "latest target unit transaction first")
+		require.Len(t, result.Txs, 4)
+	})
+
+	t.Run("block hash", func(t *testing.T) {
+		block := &domain.BlockInfo{BlockHash: []byte(key), PartitionID: 2, BlockNumber: 7}
+		store := &storeStub{blockByHash: block}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, keyHex, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{{Type: HitBlock, Match: MatchBlockHash, PartitionID: 2, Block: block}}, result.Hits)
+		require.Equal(t, map[types.PartitionID]*domain.BlockInfo{2: block}, result.Blocks)
+
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, keyHex, []types.PartitionID{1}, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits, "block of another partition")
+	})
+
+	t.Run("type filter", func(t *testing.T) {
+		store := &storeStub{txs: []*domain.TxInfo{{TxRecordHash: domain.TxHash(key)}}}
+		tokenType := &sdktypes.Unit[any]{UnitID: key, Data: map[string]any{"symbol": "AB", "subTypeCreationPredicate": "0x"}}
+		clients := map[types.PartitionID]PartitionClient{1: &clientStub{unit: tokenType}}
+
+		result, err := newService(store, clients).Search(ctx, keyHex, nil, []HitType{HitUnit})
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		require.Zero(t, store.findTxs)
+
+		result, err = newService(store, clients).Search(ctx, keyHex, nil, []HitType{HitTokenType})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, HitTokenType, result.Hits[0].Type)
+		require.Equal(t, tokenType, result.Unit)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		clients := map[types.PartitionID]PartitionClient{
+			1: &clientStub{ownerUnits: []types.UnitID{{1}}},
+			2: &clientStub{},
+		}
+		result, err := newService(&storeStub{}, clients).Search(ctx, pubKey, nil, []HitType{HitOwner})
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{{Type: HitOwner, Match: MatchOwnerPubKey, PartitionID: 1, UnitIDs: []types.UnitID{{1}}}}, result.Hits)
+		require.Equal(t, map[types.PartitionID][]types.UnitID{1: {{1}}}, result.UnitIDs)
+
+		ownerPredicate := fmt.Sprintf("0x%x", predicate.P2PKH(make([]byte, 32)))
+		result, err = newService(&storeStub{}, clients).Search(ctx, ownerPredicate, nil, []HitType{HitOwner})
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{{Type: HitOwner, Match: MatchOwnerPredicate, PartitionID: 1, UnitIDs: []types.UnitID{{1}}}}, result.Hits)
+	})
+
+	t.Run("partial failures", func(t *testing.T) {
+		store := &storeStub{txsErr: errors.New("db error")}
+		clients := map[types.PartitionID]PartitionClient{
+			1: &clientStub{unit: &sdktypes.Unit[any]{UnitID: key}},
+			2: &clientStub{unitErr: errors.New("node unavailable")},
+			3: &clientStub{unitErr: context.Canceled},
+		}
+		result, err := newService(store, clients).Search(ctx, keyHex, []types.PartitionID{1, 2, 3, 4}, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, types.PartitionID(1), result.Hits[0].PartitionID)
+
+		partitionID := types.PartitionID(2)
+		require.Equal(t, []*Failure{
+			{Lookup: lookupTxs, Error: "db error"},
+			{PartitionID: &partitionID, Lookup: lookupUnit, Error: "node unavailable"},
+		}, result.Failures)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		recordHashTx := &domain.TxInfo{TxRecordHash: []byte{0xab, 0xcd, 0xef, 0x12}, TxOrderHash: []byte{1}, PartitionID: 2}
+		targetUnitTx := &domain.TxInfo{TxRecordHash: []byte{2}, TxOrderHash: []byte{3}, PartitionID: 1, Transaction: &types.TransactionRecord{
+			ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{{1}, {0xab, 0xcd, 0xef, 0x1f}}},
+		}}
+		block := &domain.BlockInfo{BlockHash: []byte{0xab, 0xcd, 0xef, 0x10}, PartitionID: 1, BlockNumber: 7}
+		store := &storeStub{prefixTxs: []*domain.TxInfo{recordHashTx, targetUnitTx}, prefixBlocks: []*domain.BlockInfo{block}}
+
+		// odd number of digits is only looked up as a prefix
+		result, err := newService(store, map[types.PartitionID]PartitionClient{1: &clientStub{}}).Search(ctx, "0xABCDEF1", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Failures)
+		require.Equal(t, []*Hit{
+			{Type: HitBlock, Match: MatchBlockHashPrefix, PartitionID: 1, Block: block},
+			{Type: HitUnit, Match: MatchUnitIDPrefix, PartitionID: 1, UnitIDs: []types.UnitID{{0xab, 0xcd, 0xef, 0x1f}}},
+			{Type: HitTx, Match: MatchTxRecordHashPrefix, PartitionID: 2, Tx: recordHashTx},
+		}, result.Hits)
+		require.Equal(t, []*domain.TxInfo{recordHashTx}, result.Txs)
+		require.Zero(t, store.findTxs)
+
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "0xabcdef1", nil, []HitType{HitBlock})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, MatchBlockHashPrefix, result.Hits[0].Match)
+
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "0xabcde", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits, "too short odd length prefix")
+	})
+
+	t.Run("exact match is not repeated as prefix match", func(t *testing.T) {
+		tx := &domain.TxInfo{TxRecordHash: domain.TxHash(key), TxOrderHash: []byte{1}, PartitionID: 1}
+		store := &storeStub{txs: []*domain.TxInfo{tx}, prefixTxs: []*domain.TxInfo{{TxRecordHash: domain.TxHash(key), TxOrderHash: []byte{1}, PartitionID: 1}}}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, keyHex, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*Hit{{Type: HitTx, Match: MatchTxRecordHash, PartitionID: 1, Tx: tx}}, result.Hits)
+		require.Len(t, result.Txs, 1)
+	})
+
+	t.Run("prefix lookup failure", func(t *testing.T) {
+		store := &storeStub{prefixErr: errors.New("db error")}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "0xabcdef1", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		require.Equal(t, []*Failure{{Lookup: lookupBlockPrefix, Error: "db error"}, {Lookup: lookupTxPrefix, Error: "db error"}}, result.Failures)
+	})
+
+	t.Run("token texts", func(t *testing.T) {
+		fungibleType := &domain.TokenText{UnitID: types.UnitID{1}, PartitionID: 2, Kind: domain.TokenTextFungibleTokenType, Symbol: "ALPHA", Name: "Alpha coin"}
+		nftType := &domain.TokenText{UnitID: types.UnitID{2}, PartitionID: 2, Kind: domain.TokenTextNonFungibleTokenType, Symbol: "ALPHACATS", Name: "Cats"}
+		nft := &domain.TokenText{UnitID: types.UnitID{3}, PartitionID: 2, Kind: domain.TokenTextNonFungibleToken, Name: "Alpa", URI: "ipfs://alpa"}
+		unrelated := &domain.TokenText{UnitID: types.UnitID{4}, PartitionID: 2, Kind: domain.TokenTextNonFungibleToken, Name: "Dog"}
+		store := &storeStub{texts: []*domain.TokenText{nft, unrelated, nftType, fungibleType}}
+
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "alpha", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Failures)
+		var matches []MatchKind
+		for _, hit := range result.Hits {
+			matches = append(matches, hit.Match)
+		}
+		require.Equal(t, []MatchKind{MatchTokenSymbol, MatchTokenText, MatchTokenFuzzy}, matches)
+		require.Equal(t, &Hit{Type: HitTokenType, Match: MatchTokenSymbol, PartitionID: 2, UnitIDs: []types.UnitID{{1}}, Token: fungibleType, Similarity: 1}, result.Hits[0])
+		require.Equal(t, nftType, result.Hits[1].Token)
+		require.Equal(t, HitUnit, result.Hits[2].Type)
+		require.Equal(t, nft, result.Hits[2].Token)
+		require.Less(t, result.Hits[2].Similarity, 1.0)
+
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "CATS", nil, []HitType{HitTokenType})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, MatchTokenName, result.Hits[0].Match)
+
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "alpha", nil, []HitType{HitTx})
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+
+		// the keys shorter than the minimum text length are not looked up as text
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "al", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		result, err = newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "a-l ", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+	})
+
+	t.Run("token texts lookup failure", func(t *testing.T) {
+		store := &storeStub{textsErr: errors.New("db error")}
+		result, err := newService(store, map[types.PartitionID]PartitionClient{}).Search(ctx, "alpha", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+		require.Equal(t, []*Failure{{Lookup: lookupTokenTexts, Error: "db error"}}, result.Failures)
+	})
+}
+
+func TestService_Suggest(t *testing.T) {
+	ctx := context.Background()
+	store := &storeStub{
+		prefixTxs: []*domain.TxInfo{
+			{TxRecordHash: []byte{0xab, 0xcd, 0xef, 0x02}, TxOrderHash: []byte{0xab, 0xcd, 0xef, 0x03}, PartitionID: 1, Transaction: &types.TransactionRecord{
+				ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{{0xab, 0xcd, 0xef, 0x01}}},
+			}},
+			{TxRecordHash: []byte{1}, TxOrderHash: []byte{2}, PartitionID: 1, Transaction: &types.TransactionRecord{
+				ServerMetadata: &types.ServerMetadata{TargetUnits: []types.UnitID{{0xab, 0xcd, 0xef, 0x01}}},
+			}},
+		},
+		prefixBlocks: []*domain.BlockInfo{{BlockHash: []byte{0xab, 0xcd, 0xef, 0x00}, PartitionID: 2}},
+	}
+	s, err := NewSearchService(store, map[types.PartitionID]PartitionClient{}, 6)
+	require.NoError(t, err)
+
+	suggestions, err := s.Suggest(ctx, "0xABCDEF", nil, 10)
+	require.NoError(t, err)
+	require.Equal(t, []*Suggestion{
+		{Type: HitBlock, Match: MatchBlockHashPrefix, PartitionID: 2, Key: []byte{0xab, 0xcd, 0xef, 0x00}},
+		{Type: HitUnit, Match: MatchUnitIDPrefix, PartitionID: 1, Key: []byte{0xab, 0xcd, 0xef, 0x01}},
+		{Type: HitTx, Match: MatchTxRecordHashPrefix, PartitionID: 1, Key: []byte{0xab, 0xcd, 0xef, 0x02}},
+		{Type: HitTx, Match: MatchTxOrderHashPrefix, PartitionID: 1, Key: []byte{0xab, 0xcd, 0xef, 0x03}},
+	}, suggestions)
+
+	suggestions, err = s.Suggest(ctx, "abcdef", nil, 2)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+
+	_, err = s.Suggest(ctx, "0xabcde", nil, 10)
+	require.ErrorIs(t, err, ErrPrefixTooShort)
+	_, err = s.Suggest(ctx, "0xabcdefgh", nil, 10)
+	require.ErrorIs(t, err, domain.ErrFailedToDecodeHex)
+
+	store.prefixErr = errors.New("db error")
+	_, err = s.Suggest(ctx, "0xabcdef", nil, 10)
+	require.ErrorContains(t, err, "db error")
+}
@@ -0,0 +1,161 @@
+package txsubmit
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const (
+	StatusPending  Status = "pending"
+	StatusIncluded Status = "included"
+	StatusExpired  Status = "expired"
+)
+
+// ErrTxRejected is returned when the transaction is invalid or the partition node rejected it.
+var ErrTxRejected = errors.New("transaction rejected")
+
+type (
+	Status string
+
+	// Service forwards the transactions to the partition nodes and tracks their status.
+	Service struct {
+		store            Store
+		partitionClients map[types.PartitionID]PartitionClient
+		mu               sync.RWMutex
+	}
+
+	PartitionClient interface {
+		SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
+	}
+
+	Store interface {
+		GetTxByHash(ctx context.Context, txHash domain.TxHash) (*domain.TxInfo, error)
+		GetBlockNumber(ctx context.Context, partitionID types.PartitionID) (uint64, error)
+		SetSubmittedTx(ctx context.Context, tx *domain.SubmittedTx) error
+		GetSubmittedTx(ctx context.Context, txOrderHash domain.TxHash) (*domain.SubmittedTx, error)
+	}
+
+	TxStatus struct {
+		TxOrderHash  domain.TxHash
+		PartitionID  types.PartitionID
+		Status       Status
+		Timeout      uint64        `json:",omitempty"` // round number after which the transaction can't be included in a block
+		TxRecordHash domain.TxHash `json:",omitempty"`
+		BlockNumber  uint64        `json:",omitempty"`
+		Successful   bool          `json:",omitempty"` // whether the included transaction was executed successfully
+	}
+
+	// rpcError is implemented by the errors returned by the partition node.
+	rpcError interface {
+		ErrorCode() int
+	}
+)
+
+func NewService(store Store, partitionClients map[types.PartitionID]PartitionClient) (*Service, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if partitionClients == nil {
+		return nil, errors.New("partitionClients is nil")
+	}
+	return &Service{
+		store:            store,
+		partitionClients: partitionClients,
+	}, nil
+}
+
+func (s *Service) AddPartitionClient(client PartitionClient, partitionID types.PartitionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partitionClients[partitionID] = client
+}
+
+/*
+SubmitTx forwards the transaction order to the node of the partition and remembers it so that
+its status can be tracked until it's included in a block or expires. ErrNotFound is returned
+for unknown partition.
+*/
+func (s *Service) SubmitTx(ctx context.Context, partitionID types.PartitionID, tx *types.TransactionOrder) (*TxStatus, error) {
+	s.mu.RLock()
+	client, ok := s.partitionClients[partitionID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("partition %d: %w", partitionID, domain.ErrNotFound)
+	}
+	if tx.PartitionID != partitionID {
+		return nil, fmt.Errorf("%w: transaction is for partition %d", ErrTxRejected, tx.PartitionID)
+	}
+
+	txOrderHash, err := tx.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash transaction order: %w", err)
+	}
+	if _, err = client.SendTransaction(ctx, tx); err != nil {
+		var rpcErr rpcError
+		if errors.As(err, &rpcErr) {
+			return nil, fmt.Errorf("%w: %w", ErrTxRejected, err)
+		}
+		return nil, fmt.Errorf("failed to send transaction to partition %d: %w", partitionID, err)
+	}
+
+	submitted := &domain.SubmittedTx{
+		TxOrderHash: txOrderHash,
+		PartitionID: partitionID,
+		Timeout:     tx.Timeout(),
+		SubmittedAt: time.Now(),
+	}
+	if err = s.store.SetSubmittedTx(ctx, submitted); err != nil {
+		return nil, fmt.Errorf("failed to store submitted transaction: %w", err)
+	}
+	return &TxStatus{TxOrderHash: txOrderHash, PartitionID: partitionID, Status: StatusPending, Timeout: submitted.Timeout}, nil
+}
+
+/*
+GetTxStatus returns the status of the transaction, the transaction is "included" when it's found in
+the indexed blocks, "expired" when it was submitted through the explorer but the blocks up to its
+timeout round have been indexed without it and "pending" otherwise. ErrNotFound is returned when the
+transaction is neither indexed nor submitted through the explorer.
+*/
+func (s *Service) GetTxStatus(ctx context.Context, txOrderHash domain.TxHash) (*TxStatus, error) {
+	txInfo, err := s.store.GetTxByHash(ctx, txOrderHash)
+	if err == nil {
+		return &TxStatus{
+			TxOrderHash:  txInfo.TxOrderHash,
+			PartitionID:  txInfo.PartitionID,
+			Status:       StatusIncluded,
+			TxRecordHash: txInfo.TxRecordHash,
+			BlockNumber:  txInfo.BlockNumber,
+			Successful:   txInfo.Transaction != nil && txInfo.Transaction.TxStatus() == types.TxStatusSuccessful,
+		}, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load transaction: %w", err)
+	}
+
+	submitted, err := s.store.GetSubmittedTx(ctx, txOrderHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load submitted transaction: %w", err)
+	}
+	status := &TxStatus{
+		TxOrderHash: submitted.TxOrderHash,
+		PartitionID: submitted.PartitionID,
+		Status:      StatusPending,
+		Timeout:     submitted.Timeout,
+	}
+	blockNumber, err := s.store.GetBlockNumber(ctx, submitted.PartitionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block number of partition %d: %w", submitted.PartitionID, err)
+	}
+	// transaction can only be included in a block of the round before its timeout
+	if blockNumber+1 >= submitted.Timeout {
+		status.Status = StatusExpired
+	}
+	return status, nil
+}
@@ -0,0 +1,114 @@
+package txsubmit
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	storeStub struct {
+		txs         map[string]*domain.TxInfo
+		submitted   map[string]*domain.SubmittedTx
+		blockNumber uint64
+	}
+
+	clientStub struct {
+		err error
+	}
+
+	rpcErrorStub struct{}
+)
+
+func (s *storeStub) GetTxByHash(_ context.Context, txHash domain.TxHash) (*domain.TxInfo, error) {
+	if tx, ok := s.txs[string(txHash)]; ok {
+		return tx, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *storeStub) GetBlockNumber(context.Context, types.PartitionID) (uint64, error) {
+	return s.blockNumber, nil
+}
+
+func (s *storeStub) SetSubmittedTx(_ context.Context, tx *domain.SubmittedTx) error {
+	s.submitted[string(tx.TxOrderHash)] = tx
+	return nil
+}
+
+func (s *storeStub) GetSubmittedTx(_ context.Context, txOrderHash domain.TxHash) (*domain.SubmittedTx, error) {
+	if tx, ok := s.submitted[string(txOrderHash)]; ok {
+		return tx, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (c *clientStub) SendTransaction(context.Context, *types.TransactionOrder) ([]byte, error) {
+	return nil, c.err
+}
+
+func (rpcErrorStub) Error() string  { return "invalid transaction" }
+func (rpcErrorStub) ErrorCode() int { return -32000 }
+
+func TestService_SubmitTx(t *testing.T) {
+	ctx := context.Background()
+	store := &storeStub{txs: map[string]*domain.TxInfo{}, submitted: map[string]*domain.SubmittedTx{}, blockNumber: 10}
+	client := &clientStub{}
+	s, err := NewService(store, map[types.PartitionID]PartitionClient{1: client})
+	require.NoError(t, err)
+
+	tx := &types.TransactionOrder{Payload: types.Payload{PartitionID: 1, ClientMetadata: &types.ClientMetadata{Timeout: 15}}}
+	txOrderHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+
+	_, err = s.SubmitTx(ctx, 2, tx)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = s.GetTxStatus(ctx, txOrderHash)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	client.err = rpcErrorStub{}
+	_, err = s.SubmitTx(ctx, 1, tx)
+	require.ErrorIs(t, err, ErrTxRejected)
+	client.err = errors.New("connection refused")
+	_, err = s.SubmitTx(ctx, 1, tx)
+	require.NotErrorIs(t, err, ErrTxRejected)
+	client.err = nil
+
+	status, err := s.SubmitTx(ctx, 1, tx)
+	require.NoError(t, err)
+	require.Equal(t, &TxStatus{TxOrderHash: txOrderHash, PartitionID: 1, Status: StatusPending, Timeout: 15}, status)
+
+	status, err = s.GetTxStatus(ctx, txOrderHash)
+	require.NoError(t, err)
+	require.Equal(t, StatusPending, status.Status)
+
+	// blocks up to the timeout round have been processed
+	store.blockNumber = 14
+	status, err = s.GetTxStatus(ctx, txOrderHash)
+	require.NoError(t, err)
+	require.Equal(t, StatusExpired, status.Status)
+
+	store.txs[string(txOrderHash)] = &domain.TxInfo{
+		TxRecordHash: domain.TxHash{1},
+		TxOrderHash:  txOrderHash,
+		BlockNumber:  12,
+		PartitionID:  1,
+		Transaction:  &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful}},
+	}
+	status, err = s.GetTxStatus(ctx, txOrderHash)
+	require.NoError(t, err)
+	require.Equal(t, &TxStatus{
+		TxOrderHash:  txOrderHash,
+		PartitionID:  1,
+		Status:       StatusIncluded,
+		TxRecordHash: domain.TxHash{1},
+		BlockNumber:  12,
+		Successful:   true,
+	}, status)
+}
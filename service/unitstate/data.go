@@ -0,0 +1,356 @@
+package unitstate
+
+import (
+	"bytes"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+)
+
+const (
+	KindBill                 UnitKind = "bill"
+	KindFungibleToken        UnitKind = "fungibleToken"
+	KindNonFungibleToken     UnitKind = "nonFungibleToken"
+	KindFungibleTokenType    UnitKind = "fungibleTokenType"
+	KindNonFungibleTokenType UnitKind = "nonFungibleTokenType"
+	KindFeeCreditRecord      UnitKind = "feeCreditRecord"
+)
+
+type (
+	// UnitKind is the kind of the unit, known from the transactions replayed.
+	UnitKind string
+
+	// UnitData is the data of the unit reconstructed from the transactions, the fields not set by the replayed
+	// transactions are left empty. Value and Counter are nil when they can't be derived from the transactions.
+	UnitData struct {
+		Kind           UnitKind             `json:",omitempty"`
+		Value          *uint64              `json:",omitempty"` // value of the bill or the fungible token
+		OwnerPredicate *predicate.Predicate `json:",omitempty"`
+		Counter        *uint64              `json:",omitempty"`
+		Locked         bool                 `json:",omitempty"`
+		TypeID         types.UnitID         `json:",omitempty"` // type of the token, parent type of the token type
+		Symbol         string               `json:",omitempty"` // symbol of the token type
+		Name           string               `json:",omitempty"`
+		URI            string               `json:",omitempty"` // URI of the non-fungible token
+		TokenData      hex.Bytes            `json:",omitempty"` // data of the non-fungible token
+		DecimalPlaces  uint32               `json:",omitempty"` // decimal places of the fungible token type
+	}
+)
+
+/*
+apply updates the data with the successful transaction targeting the unit, created is true when the transaction
+created the unit and deleted when it deleted the unit. The value and the counter are forgotten when the attributes
+of the transaction can't be decoded.
+*/
+func (d *UnitData) apply(tx *domain.BlockTx, unitID types.UnitID) (created, deleted bool) {
+	txo, err := tx.Transaction.GetTransactionOrderV1()
+	if err != nil {
+		log.Warn("failed to decode transaction order", "partition", tx.PartitionID, "round", tx.BlockNumber, "err", err)
+		d.Value, d.Counter = nil, nil
+		return false, false
+	}
+	if bytes.Equal(txo.UnitID, unitID) {
+		deleted, err = d.applyOwn(tx, txo)
+	} else {
+		created, err = d.applyTargeted(tx, txo, unitID)
+	}
+	if err != nil {
+		log.Warn("failed to decode transaction attributes", "partition", tx.PartitionID, "round", tx.BlockNumber, "type", txo.Type, "err", err)
+		d.Value, d.Counter = nil, nil
+		return false, deleted
+	}
+	if !created {
+		created = isCreation(tx.PartitionTypeID, txo.Type)
+	}
+	return created, deleted
+}
+
+func isCreation(partitionTypeID types.PartitionTypeID, txType uint16) bool {
+	if partitionTypeID != tokens.PartitionTypeID {
+		return false
+	}
+	switch txType {
+	case tokens.TransactionTypeDefineFT, tokens.TransactionTypeDefineNFT, tokens.TransactionTypeMintFT, tokens.TransactionTypeMintNFT:
+		return true
+	}
+	return false
+}
+
+// applyOwn applies the transaction of the unit.
+func (d *UnitData) applyOwn(tx *domain.BlockTx, txo *types.TransactionOrder) (deleted bool, err error) {
+	switch txo.Type {
+	case fc.TransactionTypeTransferFeeCredit:
+		var attr fc.TransferFeeCreditAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.subtractValue(attr.Amount)
+		d.setCounter(attr.Counter)
+		return false, nil
+	case fc.TransactionTypeReclaimFeeCredit:
+		// the reclaimed amount is reduced by the fees which are not known
+		d.Value = nil
+		d.incrementCounter()
+		return false, nil
+	case fc.TransactionTypeAddFeeCredit:
+		var attr fc.AddFeeCreditAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.OwnerPredicate, d.Value = KindFeeCreditRecord, predicate.Decode(attr.FeeCreditOwnerPredicate), nil
+		return false, nil
+	case fc.TransactionTypeCloseFeeCredit:
+		d.Kind, d.Value = KindFeeCreditRecord, nil
+		return false, nil
+	case fc.TransactionTypeLockFeeCredit:
+		d.Kind, d.Locked = KindFeeCreditRecord, true
+		return false, nil
+	case fc.TransactionTypeUnlockFeeCredit:
+		d.Kind, d.Locked = KindFeeCreditRecord, false
+		return false, nil
+	}
+
+	switch tx.PartitionTypeID {
+	case money.PartitionTypeID:
+		return d.applyMoney(txo)
+	case tokens.PartitionTypeID:
+		return d.applyTokens(txo)
+	}
+	return false, nil
+}
+
+func (d *UnitData) applyMoney(txo *types.TransactionOrder) (deleted bool, err error) {
+	d.Kind = KindBill
+	switch txo.Type {
+	case money.TransactionTypeTransfer:
+		var attr money.TransferAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.setValue(attr.TargetValue)
+		d.OwnerPredicate = predicate.Decode(attr.NewOwnerPredicate)
+		d.setCounter(attr.Counter)
+	case money.TransactionTypeSplit:
+		var attr money.SplitAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		for _, target := range attr.TargetUnits {
+			d.subtractValue(target.Amount)
+		}
+		d.setCounter(attr.Counter)
+	case money.TransactionTypeTransDC:
+		var attr money.TransferDCAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.setCounter(attr.Counter)
+		return true, nil
+	case money.TransactionTypeSwapDC:
+		var attr money.SwapDCAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		for _, proof := range attr.DustTransferProofs {
+			var dcAttr money.TransferDCAttributes
+			if err = unmarshalProofAttributes(proof, &dcAttr); err != nil {
+				return false, err
+			}
+			d.addValue(dcAttr.Value)
+		}
+		d.incrementCounter()
+	case money.TransactionTypeLock:
+		d.Locked = true
+		d.incrementCounter()
+	case money.TransactionTypeUnlock:
+		d.Locked = false
+		d.incrementCounter()
+	default:
+		d.incrementCounter()
+	}
+	return false, nil
+}
+
+func (d *UnitData) applyTokens(txo *types.TransactionOrder) (deleted bool, err error) {
+	switch txo.Type {
+	case tokens.TransactionTypeDefineFT:
+		var attr tokens.DefineFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		*d = UnitData{Kind: KindFungibleTokenType, TypeID: attr.ParentTypeID, Symbol: attr.Symbol, Name: attr.Name, DecimalPlaces: attr.DecimalPlaces}
+	case tokens.TransactionTypeDefineNFT:
+		var attr tokens.DefineNonFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		*d = UnitData{Kind: KindNonFungibleTokenType, TypeID: attr.ParentTypeID, Symbol: attr.Symbol, Name: attr.Name}
+	case tokens.TransactionTypeMintFT:
+		var attr tokens.MintFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		*d = UnitData{Kind: KindFungibleToken, TypeID: attr.TypeID, OwnerPredicate: predicate.Decode(attr.OwnerPredicate)}
+		d.setValue(attr.Value)
+		d.Counter = new(uint64)
+	case tokens.TransactionTypeMintNFT:
+		var attr tokens.MintNonFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		*d = UnitData{Kind: KindNonFungibleToken, TypeID: attr.TypeID, OwnerPredicate: predicate.Decode(attr.OwnerPredicate), Name: attr.Name, URI: attr.URI, TokenData: attr.Data}
+		d.Counter = new(uint64)
+	case tokens.TransactionTypeTransferFT:
+		var attr tokens.TransferFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.TypeID, d.OwnerPredicate = KindFungibleToken, attr.TypeID, predicate.Decode(attr.NewOwnerPredicate)
+		d.setValue(attr.Value)
+		d.setCounter(attr.Counter)
+	case tokens.TransactionTypeTransferNFT:
+		var attr tokens.TransferNonFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.TypeID, d.OwnerPredicate = KindNonFungibleToken, attr.TypeID, predicate.Decode(attr.NewOwnerPredicate)
+		d.setCounter(attr.Counter)
+	case tokens.TransactionTypeSplitFT:
+		var attr tokens.SplitFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.TypeID = KindFungibleToken, attr.TypeID
+		d.subtractValue(attr.TargetValue)
+		d.setCounter(attr.Counter)
+	case tokens.TransactionTypeBurnFT:
+		var attr tokens.BurnFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.TypeID = KindFungibleToken, attr.TypeID
+		d.setCounter(attr.Counter)
+		return true, nil
+	case tokens.TransactionTypeJoinFT:
+		var attr tokens.JoinFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		for _, proof := range attr.BurnTokenProofs {
+			var burnAttr tokens.BurnFungibleTokenAttributes
+			if err = unmarshalProofAttributes(proof, &burnAttr); err != nil {
+				return false, err
+			}
+			d.addValue(burnAttr.Value)
+		}
+		d.Kind = KindFungibleToken
+		d.incrementCounter()
+	case tokens.TransactionTypeUpdateNFT:
+		var attr tokens.UpdateNonFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		d.Kind, d.TokenData = KindNonFungibleToken, attr.Data
+		d.setCounter(attr.Counter)
+	case tokens.TransactionTypeLockToken:
+		d.Locked = true
+		d.incrementCounter()
+	case tokens.TransactionTypeUnlockToken:
+		d.Locked = false
+		d.incrementCounter()
+	default:
+		d.incrementCounter()
+	}
+	return false, nil
+}
+
+// applyTargeted applies the transaction of another unit, the unit split off is created by the split transaction.
+func (d *UnitData) applyTargeted(tx *domain.BlockTx, txo *types.TransactionOrder, unitID types.UnitID) (created bool, err error) {
+	switch {
+	case tx.PartitionTypeID == money.PartitionTypeID && txo.Type == money.TransactionTypeSplit:
+		var attr money.SplitAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		// the new units follow the unit split in the target units, in the order of the split targets
+		i := newUnitIndex(tx.Transaction, txo.UnitID, unitID)
+		if i < 0 || i >= len(attr.TargetUnits) {
+			return false, nil
+		}
+		*d = UnitData{Kind: KindBill, OwnerPredicate: predicate.Decode(attr.TargetUnits[i].OwnerPredicate), Counter: new(uint64)}
+		d.setValue(attr.TargetUnits[i].Amount)
+		return true, nil
+	case tx.PartitionTypeID == tokens.PartitionTypeID && txo.Type == tokens.TransactionTypeSplitFT:
+		var attr tokens.SplitFungibleTokenAttributes
+		if err = txo.UnmarshalAttributes(&attr); err != nil {
+			return false, err
+		}
+		*d = UnitData{Kind: KindFungibleToken, TypeID: attr.TypeID, OwnerPredicate: predicate.Decode(attr.NewOwnerPredicate), Counter: new(uint64)}
+		d.setValue(attr.TargetValue)
+		return true, nil
+	}
+	return false, nil
+}
+
+// newUnitIndex returns the index of the unit among the units created by the transaction of the unit txUnitID.
+func newUnitIndex(txr *types.TransactionRecord, txUnitID, unitID types.UnitID) int {
+	if txr.ServerMetadata == nil {
+		return -1
+	}
+	i := 0
+	for _, id := range txr.ServerMetadata.TargetUnits {
+		if bytes.Equal(id, txUnitID) {
+			continue
+		}
+		if bytes.Equal(id, unitID) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+func unmarshalProofAttributes(proof *types.TxRecordProof, attr any) error {
+	if proof == nil || proof.TxRecord == nil {
+		return nil
+	}
+	txo, err := proof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return err
+	}
+	return txo.UnmarshalAttributes(attr)
+}
+
+func (d *UnitData) setValue(value uint64) {
+	d.Value = &value
+}
+
+func (d *UnitData) addValue(value uint64) {
+	if d.Value != nil {
+		d.setValue(*d.Value + value)
+	}
+}
+
+func (d *UnitData) subtractValue(value uint64) {
+	if d.Value != nil {
+		d.setValue(*d.Value - value)
+	}
+}
+
+// setCounter sets the counter after the transaction with the counter attribute.
+func (d *UnitData) setCounter(counter uint64) {
+	counter++
+	d.Counter = &counter
+}
+
+func (d *UnitData) incrementCounter() {
+	if d.Counter != nil {
+		d.setCounter(*d.Counter)
+	}
+}
@@ -0,0 +1,104 @@
+package unitstate
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+/*
+verifyStateProof verifies that the state proof is for the unit, that the unit data of the node hashes to the state
+root of the unicity certificate of the proof and that the unicity certificate is signed by the root chain of the
+trust base. The proof carries its own unicity certificate, the round of the proof does not need to be synced.
+*/
+func (s *Service) verifyStateProof(partitionID types.PartitionID, unitID types.UnitID, unit *sdktypes.Unit[any]) *ProofVerification {
+	proof := unit.StateProof
+	if proof == nil {
+		return &ProofVerification{Error: "node returned no state proof"}
+	}
+	if !bytes.Equal(proof.UnitID, unitID) {
+		return &ProofVerification{Error: fmt.Sprintf("state proof is for unit %s", proof.UnitID)}
+	}
+	uc := &types.UnicityCertificate{}
+	if err := types.Cbor.Unmarshal(proof.UnicityCertificate, uc); err != nil {
+		return &ProofVerification{Error: fmt.Sprintf("failed to decode unicity certificate: %v", err)}
+	}
+	verification := &ProofVerification{RoundNumber: uc.GetRoundNumber()}
+	if s.trustBase == nil {
+		verification.Error = "trust base is not configured"
+		return verification
+	}
+	s.mu.RLock()
+	partitionTypeID := s.partitionTypes[partitionID]
+	s.mu.RUnlock()
+	unitData, err := stateUnitData(partitionTypeID, unitID, unit.Data)
+	if err != nil {
+		verification.Error = fmt.Sprintf("failed to encode unit data: %v", err)
+		return verification
+	}
+	if err := s.verifyProof(proof, crypto.SHA256, unitData, s.trustBase); err != nil {
+		verification.Error = fmt.Sprintf("state proof is not valid: %v", err)
+		return verification
+	}
+	verification.Verified = true
+	return verification
+}
+
+// stateUnitData encodes the unit data returned by the partition node as the unit data hashed into the unit tree.
+func stateUnitData(partitionTypeID types.PartitionTypeID, unitID types.UnitID, data any) (*types.StateUnitData, error) {
+	unitData, err := newUnitData(partitionTypeID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	// the node returns the unit data as JSON, decoded into the unit data type of the unit for the CBOR encoding
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, unitData); err != nil {
+		return nil, err
+	}
+	cborData, err := types.Cbor.Marshal(unitData)
+	if err != nil {
+		return nil, err
+	}
+	return &types.StateUnitData{Data: cborData}, nil
+}
+
+// newUnitData returns the unit data of the unit type, the type of the unit is the last byte of the unit ID.
+func newUnitData(partitionTypeID types.PartitionTypeID, unitID types.UnitID) (any, error) {
+	if len(unitID) == 0 {
+		return nil, fmt.Errorf("unit ID is empty")
+	}
+	unitType := unitID[len(unitID)-1]
+	switch partitionTypeID {
+	case money.PartitionTypeID:
+		switch unitType {
+		case money.BillUnitType:
+			return &money.BillData{}, nil
+		case money.FeeCreditRecordUnitType:
+			return &fc.FeeCreditRecord{}, nil
+		}
+	case tokens.PartitionTypeID:
+		switch unitType {
+		case tokens.FungibleTokenTypeUnitType:
+			return &tokens.FungibleTokenTypeData{}, nil
+		case tokens.NonFungibleTokenTypeUnitType:
+			return &tokens.NonFungibleTokenTypeData{}, nil
+		case tokens.FungibleTokenUnitType:
+			return &tokens.FungibleTokenData{}, nil
+		case tokens.NonFungibleTokenUnitType:
+			return &tokens.NonFungibleTokenData{}, nil
+		case tokens.FeeCreditRecordUnitType:
+			return &fc.FeeCreditRecord{}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown unit type %d of partition type %d", unitType, partitionTypeID)
+}
@@ -0,0 +1,174 @@
+package unitstate
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unittx"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SourceHistory Source = "history" // reconstructed by replaying the stored transactions
+	SourceNode    Source = "node"    // loaded from the partition node
+)
+
+type (
+	Source string
+
+	// Service returns the current state of the units from the partition nodes and reconstructs
+	// the past states from the stored transactions.
+	Service struct {
+		store            Store
+		partitionClients map[types.PartitionID]PartitionClient
+		partitionTypes   map[types.PartitionID]types.PartitionTypeID
+		trustBase        types.RootTrustBase
+		verifyProof      func(proof *types.UnitStateProof, algorithm crypto.Hash, unitData *types.StateUnitData, tb types.RootTrustBase) error
+		mu               sync.RWMutex
+	}
+
+	Store interface {
+		ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error
+		GetTxsPageByUnitID(
+			ctx context.Context, unitID types.UnitID, page domain.PageRequest[primitive.ObjectID],
+		) (transactions []*domain.TxInfo, hasMore bool, err error)
+	}
+
+	PartitionClient interface {
+		GetUnit(ctx context.Context, unitID types.UnitID, includeStateProof bool) (*sdktypes.Unit[any], error)
+	}
+
+	// UnitState is the state of the unit at the block, reconstructed from the stored transactions or loaded from the
+	// partition node.
+	UnitState struct {
+		UnitID      types.UnitID
+		PartitionID types.PartitionID
+		Source      Source
+		BlockNumber uint64                `json:",omitempty"` // block the state is reconstructed at, round of the state proof of the node
+		LastTxBlock uint64                `json:",omitempty"` // block of the last replayed transaction
+		Complete    bool                  `json:",omitempty"` // the creation of the unit was replayed, otherwise only the fields set by the replayed transactions are known
+		Deleted     bool                  `json:",omitempty"` // the unit was deleted by the dust transfer or the burn at or before the block
+		Truncated   bool                  `json:",omitempty"` // the transactions between the oldest and the latest replayed transactions were left out, the fields set only by them may be outdated
+		Data        any                   // *UnitData when reconstructed, the unit data of the node with the decoded owner predicate otherwise
+		StateProof  *types.UnitStateProof `json:",omitempty"`
+		Proof       *ProofVerification    `json:",omitempty"` // set when the state proof was requested
+	}
+
+	// ProofVerification is the result of verifying the state proof of the node against the trust base.
+	ProofVerification struct {
+		Verified    bool
+		RoundNumber uint64 `json:",omitempty"` // round of the unicity certificate of the proof
+		Error       string `json:",omitempty"` // why the proof was not verified
+	}
+)
+
+// NewService returns the unit state service, the state proofs are not verified when the trust base is nil.
+func NewService(store Store, partitionClients map[types.PartitionID]PartitionClient, trustBase types.RootTrustBase) (*Service, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if partitionClients == nil {
+		return nil, errors.New("partitionClients is nil")
+	}
+	return &Service{
+		store:            store,
+		partitionClients: partitionClients,
+		partitionTypes:   make(map[types.PartitionID]types.PartitionTypeID),
+		trustBase:        trustBase,
+		verifyProof:      types.VerifyUnitStateProof,
+	}, nil
+}
+
+func (s *Service) AddPartitionClient(client PartitionClient, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partitionClients[partitionID] = client
+	s.partitionTypes[partitionID] = partitionTypeID
+}
+
+/*
+GetUnitStateAt reconstructs the state of the unit after the transactions of the blocks up to the given block by
+replaying the successful transactions targeting the unit, oldest first. Without the partition ID the transactions
+of the partition of the oldest transaction are used when the unit ID is targeted on several partitions. Only the
+oldest and the latest transactions are replayed when the unit has more than unittx.MaxTxs transactions. Returns
+domain.ErrNotFound when no successful transaction targeted the unit at or before the block.
+*/
+func (s *Service) GetUnitStateAt(ctx context.Context, unitID types.UnitID, partitionID *types.PartitionID, blockNumber uint64) (*UnitState, error) {
+	txs, truncated, err := unittx.Load(ctx, s.store, unittx.Filter{UnitID: unitID, PartitionID: partitionID, EndBlock: blockNumber})
+	if err != nil {
+		return nil, err
+	}
+	data := &UnitData{}
+	state := &UnitState{
+		UnitID:      unitID,
+		PartitionID: txs[0].PartitionID,
+		Source:      SourceHistory,
+		BlockNumber: blockNumber,
+		Truncated:   truncated,
+		Data:        data,
+	}
+	for i, tx := range txs {
+		state.LastTxBlock = tx.BlockNumber
+		created, deleted := data.apply(tx, unitID)
+		if i == 0 && created {
+			state.Complete = true
+		}
+		if deleted {
+			state.Deleted = true
+		}
+	}
+	return state, nil
+}
+
+/*
+GetUnitState loads the current state of the unit from the partition node. The partition of the latest stored
+transaction of the unit is used when the partition ID is nil. With includeStateProof the state proof of the node
+is verified against the unit data and the trust base. Returns domain.ErrNotFound when the partition is not
+configured or the unit does not exist.
+*/
+func (s *Service) GetUnitState(ctx context.Context, unitID types.UnitID, partitionID *types.PartitionID, includeStateProof bool) (*UnitState, error) {
+	if partitionID == nil {
+		txs, _, err := s.store.GetTxsPageByUnitID(ctx, unitID, domain.PageRequest[primitive.ObjectID]{Limit: 1})
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load transactions: %w", err)
+		}
+		if len(txs) == 0 {
+			return nil, fmt.Errorf("partition of unit %s: %w", unitID, domain.ErrNotFound)
+		}
+		partitionID = &txs[0].PartitionID
+	}
+
+	s.mu.RLock()
+	client, ok := s.partitionClients[*partitionID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("partition %d: %w", *partitionID, domain.ErrNotFound)
+	}
+	unit, err := client.GetUnit(ctx, unitID, includeStateProof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load unit from partition %d: %w", *partitionID, err)
+	}
+	if unit == nil {
+		return nil, fmt.Errorf("unit %s: %w", unitID, domain.ErrNotFound)
+	}
+
+	state := &UnitState{
+		UnitID:      unitID,
+		PartitionID: *partitionID,
+		Source:      SourceNode,
+		Data:        predicate.DecodeUnitData(unit.Data),
+		StateProof:  unit.StateProof,
+	}
+	if includeStateProof {
+		state.Proof = s.verifyStateProof(*partitionID, unitID, unit)
+		state.BlockNumber = state.Proof.RoundNumber
+	}
+	return state, nil
+}
@@ -0,0 +1,316 @@
+package unitstate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/testutils"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-explorer-backend/service/unittx"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	storeStub struct {
+		testutils.TxStore
+		unitTxs []*domain.TxInfo
+	}
+
+	clientStub struct {
+		unit         *sdktypes.Unit[any]
+		err          error
+		stateProofed bool
+	}
+)
+
+func (s *storeStub) GetTxsPageByUnitID(context.Context, types.UnitID, domain.PageRequest[primitive.ObjectID]) ([]*domain.TxInfo, bool, error) {
+	return s.unitTxs, false, nil
+}
+
+func (c *clientStub) GetUnit(_ context.Context, _ types.UnitID, includeStateProof bool) (*sdktypes.Unit[any], error) {
+	c.stateProofed = includeStateProof
+	return c.unit, c.err
+}
+
+func ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestNewService(t *testing.T) {
+	_, err := NewService(nil, map[types.PartitionID]PartitionClient{}, nil)
+	require.EqualError(t, err, "store is nil")
+	_, err = NewService(&storeStub{}, nil, nil)
+	require.EqualError(t, err, "partitionClients is nil")
+}
+
+func TestService_GetUnitStateAt(t *testing.T) {
+	ctx := context.Background()
+	bill := types.UnitID{5}
+	moneyTx := func(blockNumber uint64, txType uint16, unitID types.UnitID, attr any, targetUnits ...types.UnitID) *domain.BlockTx {
+		return testutils.BlockTx(money.PartitionTypeID, 1, blockNumber, testutils.TxRecord(t, 1, txType, unitID, attr, types.TxStatusSuccessful, targetUnits...))
+	}
+	getState := func(t *testing.T, store *storeStub, partitionID *types.PartitionID, blockNumber uint64) (*UnitState, *UnitData) {
+		service, err := NewService(store, map[types.PartitionID]PartitionClient{}, nil)
+		require.NoError(t, err)
+		state, err := service.GetUnitStateAt(ctx, bill, partitionID, blockNumber)
+		require.NoError(t, err)
+		require.Equal(t, SourceHistory, state.Source)
+		require.Equal(t, blockNumber, state.BlockNumber)
+		return state, state.Data.(*UnitData)
+	}
+
+	t.Run("bill", func(t *testing.T) {
+		dustProof := func(value uint64) *types.TxRecordProof {
+			return &types.TxRecordProof{TxRecord: testutils.TxRecord(t, 1, money.TransactionTypeTransDC, types.UnitID{9}, &money.TransferDCAttributes{Value: value, TargetUnitID: bill}, types.TxStatusSuccessful)}
+		}
+		pubKeyHash := bytes.Repeat([]byte{3}, 32)
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeSplit, types.UnitID{1}, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{
+				{OwnerPredicate: []byte{1}, Amount: 10},
+				{OwnerPredicate: []byte{2}, Amount: 100},
+			}}, types.UnitID{4}, bill),
+			testutils.BlockTx(money.PartitionTypeID, 1, 2, testutils.TxRecord(t, 1, money.TransactionTypeTransfer, bill, &money.TransferAttributes{NewOwnerPredicate: []byte{7}}, types.TxStatusFailed)),
+			moneyTx(3, money.TransactionTypeSplit, bill, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{Amount: 30}}, Counter: 0}, types.UnitID{6}),
+			moneyTx(4, money.TransactionTypeLock, bill, &money.TransferAttributes{}),
+			moneyTx(5, money.TransactionTypeSwapDC, bill, &money.SwapDCAttributes{DustTransferProofs: []*types.TxRecordProof{dustProof(5), dustProof(6)}}),
+			moneyTx(6, money.TransactionTypeTransfer, bill, &money.TransferAttributes{NewOwnerPredicate: predicate.P2PKH(pubKeyHash), TargetValue: 81, Counter: 3}),
+			moneyTx(7, money.TransactionTypeTransDC, bill, &money.TransferDCAttributes{Value: 81, TargetUnitID: types.UnitID{8}, Counter: 4}),
+		}}}
+
+		state, data := getState(t, store, nil, 1)
+		require.True(t, state.Complete)
+		require.EqualValues(t, 1, state.LastTxBlock)
+		require.Equal(t, &UnitData{Kind: KindBill, Value: ptr(100), OwnerPredicate: predicate.Decode([]byte{2}), Counter: ptr(0)}, data)
+
+		state, data = getState(t, store, nil, 5)
+		require.True(t, state.Complete)
+		require.EqualValues(t, 5, state.LastTxBlock)
+		require.Equal(t, &UnitData{Kind: KindBill, Value: ptr(81), OwnerPredicate: predicate.Decode([]byte{2}), Counter: ptr(3), Locked: true}, data)
+
+		state, data = getState(t, store, nil, 7)
+		require.True(t, state.Deleted)
+		require.Equal(t, &UnitData{Kind: KindBill, Value: ptr(81), OwnerPredicate: predicate.Decode(predicate.P2PKH(pubKeyHash)), Counter: ptr(5), Locked: true}, data)
+		require.Equal(t, predicate.TypeP2PKH, data.OwnerPredicate.Type)
+		require.EqualValues(t, pubKeyHash, data.OwnerPredicate.PubKeyHash)
+	})
+
+	t.Run("created before the first stored block", func(t *testing.T) {
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(10, money.TransactionTypeSplit, bill, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{Amount: 30}}, Counter: 4}, types.UnitID{6}),
+			moneyTx(11, money.TransactionTypeTransfer, bill, &money.TransferAttributes{NewOwnerPredicate: []byte{3}, TargetValue: 70, Counter: 5}),
+		}}}
+
+		state, data := getState(t, store, nil, 10)
+		require.False(t, state.Complete)
+		require.Equal(t, &UnitData{Kind: KindBill, Counter: ptr(5)}, data)
+
+		_, data = getState(t, store, nil, 11)
+		require.Equal(t, &UnitData{Kind: KindBill, Value: ptr(70), OwnerPredicate: predicate.Decode([]byte{3}), Counter: ptr(6)}, data)
+	})
+
+	t.Run("undecodable attributes", func(t *testing.T) {
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeTransfer, bill, &money.TransferAttributes{TargetValue: 70, Counter: 5}),
+			moneyTx(2, money.TransactionTypeSplit, bill, "not attributes"),
+		}}}
+
+		_, data := getState(t, store, nil, 2)
+		require.Equal(t, &UnitData{Kind: KindBill}, data)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		tokenTx := func(blockNumber uint64, txType uint16, unitID types.UnitID, attr any, targetUnits ...types.UnitID) *domain.BlockTx {
+			return testutils.BlockTx(tokens.PartitionTypeID, 2, blockNumber, testutils.TxRecord(t, 2, txType, unitID, attr, types.TxStatusSuccessful, targetUnits...))
+		}
+		burnProof := &types.TxRecordProof{TxRecord: testutils.TxRecord(t, 2, tokens.TransactionTypeBurnFT, types.UnitID{9}, &tokens.BurnFungibleTokenAttributes{Value: 7, TargetTokenID: bill}, types.TxStatusSuccessful)}
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			tokenTx(1, tokens.TransactionTypeMintFT, bill, &tokens.MintFungibleTokenAttributes{TypeID: types.UnitID{0x20}, Value: 50, OwnerPredicate: []byte{1}}),
+			tokenTx(2, tokens.TransactionTypeSplitFT, bill, &tokens.SplitFungibleTokenAttributes{TypeID: types.UnitID{0x20}, TargetValue: 20, Counter: 0}, types.UnitID{6}),
+			tokenTx(3, tokens.TransactionTypeJoinFT, bill, &tokens.JoinFungibleTokenAttributes{BurnTokenProofs: []*types.TxRecordProof{burnProof}}),
+			// the same unit ID on another partition
+			moneyTx(4, money.TransactionTypeTransfer, bill, &money.TransferAttributes{TargetValue: 1}),
+		}}}
+
+		state, data := getState(t, store, nil, 4)
+		require.True(t, state.Complete)
+		require.EqualValues(t, 2, state.PartitionID)
+		require.Equal(t, &UnitData{Kind: KindFungibleToken, TypeID: types.UnitID{0x20}, Value: ptr(37), OwnerPredicate: predicate.Decode([]byte{1}), Counter: ptr(2)}, data)
+
+		partitionID := types.PartitionID(1)
+		state, data = getState(t, store, &partitionID, 4)
+		require.EqualValues(t, 1, state.PartitionID)
+		require.Equal(t, &UnitData{Kind: KindBill, Value: ptr(1), Counter: ptr(1)}, data)
+	})
+
+	t.Run("split token", func(t *testing.T) {
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			testutils.BlockTx(tokens.PartitionTypeID, 2, 2, testutils.TxRecord(t, 2, tokens.TransactionTypeSplitFT, types.UnitID{1}, &tokens.SplitFungibleTokenAttributes{
+				TypeID: types.UnitID{0x20}, TargetValue: 20, NewOwnerPredicate: []byte{4},
+			}, types.TxStatusSuccessful, bill)),
+		}}}
+
+		state, data := getState(t, store, nil, 2)
+		require.True(t, state.Complete)
+		require.Equal(t, &UnitData{Kind: KindFungibleToken, TypeID: types.UnitID{0x20}, Value: ptr(20), OwnerPredicate: predicate.Decode([]byte{4}), Counter: ptr(0)}, data)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		store := &storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(1, money.TransactionTypeSplit, types.UnitID{1}, &money.SplitAttributes{TargetUnits: []*money.TargetUnit{{Amount: 10}}}, bill),
+		}}}
+		for i := range unittx.MaxTxs {
+			store.Txs = append(store.Txs, moneyTx(uint64(i+2), money.TransactionTypeTransfer, bill, &money.TransferAttributes{NewOwnerPredicate: []byte{byte(i)}}))
+		}
+
+		state, data := getState(t, store, nil, unittx.MaxTxs+1)
+		require.True(t, state.Truncated)
+		require.True(t, state.Complete)
+		require.EqualValues(t, unittx.MaxTxs+1, state.LastTxBlock)
+		require.Equal(t, predicate.Decode([]byte{byte((unittx.MaxTxs - 1) % 256)}), data.OwnerPredicate)
+
+		state, data = getState(t, store, nil, 10)
+		require.False(t, state.Truncated)
+		require.EqualValues(t, 10, state.LastTxBlock)
+		require.Equal(t, predicate.Decode([]byte{8}), data.OwnerPredicate)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		service, err := NewService(&storeStub{TxStore: testutils.TxStore{Txs: []*domain.BlockTx{
+			moneyTx(5, money.TransactionTypeTransfer, bill, &money.TransferAttributes{}),
+		}}}, map[types.PartitionID]PartitionClient{}, nil)
+		require.NoError(t, err)
+
+		_, err = service.GetUnitStateAt(ctx, bill, nil, 4)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		service, err := NewService(&storeStub{TxStore: testutils.TxStore{Err: errors.New("boom")}}, map[types.PartitionID]PartitionClient{}, nil)
+		require.NoError(t, err)
+
+		_, err = service.GetUnitStateAt(ctx, bill, nil, 4)
+		require.EqualError(t, err, "failed to load transactions: boom")
+	})
+}
+
+func TestService_GetUnitState(t *testing.T) {
+	ctx := context.Background()
+	unitID := types.UnitID{5, money.BillUnitType}
+	ucBytes, err := types.Cbor.Marshal(&types.UnicityCertificate{InputRecord: &types.InputRecord{RoundNumber: 7}})
+	require.NoError(t, err)
+	proof := &types.UnitStateProof{UnitID: unitID, UnicityCertificate: ucBytes}
+	bill := &money.BillData{Value: 100, OwnerPredicate: []byte{1, 2}, Counter: 3}
+	billCbor, err := types.Cbor.Marshal(bill)
+	require.NoError(t, err)
+	// the node returns the unit data as JSON
+	billJSON, err := json.Marshal(bill)
+	require.NoError(t, err)
+	var data any
+	require.NoError(t, json.Unmarshal(billJSON, &data))
+	trustBase := &types.RootTrustBaseV1{}
+	newService := func(store *storeStub, client *clientStub, trustBase types.RootTrustBase) *Service {
+		service, err := NewService(store, map[types.PartitionID]PartitionClient{}, trustBase)
+		require.NoError(t, err)
+		service.AddPartitionClient(client, 1, money.PartitionTypeID)
+		return service
+	}
+
+	t.Run("partition of the stored transactions", func(t *testing.T) {
+		client := &clientStub{unit: &sdktypes.Unit[any]{UnitID: unitID, Data: "data"}}
+		state, err := newService(&storeStub{unitTxs: []*domain.TxInfo{{PartitionID: 1}}}, client, nil).GetUnitState(ctx, unitID, nil, false)
+		require.NoError(t, err)
+		require.Equal(t, &UnitState{UnitID: unitID, PartitionID: 1, Source: SourceNode, Data: "data"}, state)
+		require.False(t, client.stateProofed)
+	})
+
+	t.Run("verified state proof", func(t *testing.T) {
+		client := &clientStub{unit: &sdktypes.Unit[any]{UnitID: unitID, Data: data, StateProof: proof}}
+		service := newService(&storeStub{}, client, trustBase)
+		var verifiedData *types.StateUnitData
+		service.verifyProof = func(p *types.UnitStateProof, algorithm crypto.Hash, unitData *types.StateUnitData, tb types.RootTrustBase) error {
+			require.Equal(t, proof, p)
+			require.Equal(t, crypto.SHA256, algorithm)
+			require.Same(t, trustBase, tb)
+			verifiedData = unitData
+			return nil
+		}
+		partitionID := types.PartitionID(1)
+		state, err := service.GetUnitState(ctx, unitID, &partitionID, true)
+		require.NoError(t, err)
+		require.True(t, client.stateProofed)
+		require.Equal(t, proof, state.StateProof)
+		require.Equal(t, &ProofVerification{Verified: true, RoundNumber: 7}, state.Proof)
+		require.EqualValues(t, 7, state.BlockNumber)
+		// the proof is verified against the unit data returned by the node
+		require.Equal(t, &types.StateUnitData{Data: billCbor}, verifiedData)
+		require.Equal(t, predicate.Decode(bill.OwnerPredicate), state.Data.(map[string]any)["ownerPredicate"])
+	})
+
+	t.Run("unverified state proof", func(t *testing.T) {
+		partitionID := types.PartitionID(1)
+		otherTypeID := types.UnitID{5, 0xEE}
+		tests := []struct {
+			name      string
+			unitID    types.UnitID
+			proof     *types.UnitStateProof
+			trustBase types.RootTrustBase
+			verifyErr error
+			want      *ProofVerification
+		}{
+			{name: "no proof", want: &ProofVerification{Error: "node returned no state proof"}},
+			{name: "other unit", proof: &types.UnitStateProof{UnitID: types.UnitID{6}},
+				want: &ProofVerification{Error: "state proof is for unit 06"}},
+			{name: "no trust base", proof: proof,
+				want: &ProofVerification{RoundNumber: 7, Error: "trust base is not configured"}},
+			{name: "unknown unit type", unitID: otherTypeID, proof: &types.UnitStateProof{UnitID: otherTypeID, UnicityCertificate: ucBytes}, trustBase: trustBase,
+				want: &ProofVerification{RoundNumber: 7, Error: "failed to encode unit data: unknown unit type 238 of partition type 1"}},
+			{name: "invalid proof", proof: proof, trustBase: trustBase, verifyErr: errors.New("unit data hash does not match"),
+				want: &ProofVerification{RoundNumber: 7, Error: "state proof is not valid: unit data hash does not match"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				id := unitID
+				if tt.unitID != nil {
+					id = tt.unitID
+				}
+				client := &clientStub{unit: &sdktypes.Unit[any]{UnitID: id, Data: data, StateProof: tt.proof}}
+				service := newService(&storeStub{}, client, tt.trustBase)
+				service.verifyProof = func(*types.UnitStateProof, crypto.Hash, *types.StateUnitData, types.RootTrustBase) error {
+					return tt.verifyErr
+				}
+				state, err := service.GetUnitState(ctx, id, &partitionID, true)
+				require.NoError(t, err)
+				require.False(t, state.Proof.Verified)
+				require.Equal(t, tt.want, state.Proof)
+			})
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		partitionID, unknownPartitionID := types.PartitionID(1), types.PartitionID(2)
+		service := newService(&storeStub{}, &clientStub{}, nil)
+
+		_, err := service.GetUnitState(ctx, unitID, nil, false)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.GetUnitState(ctx, unitID, &unknownPartitionID, false)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.GetUnitState(ctx, unitID, &partitionID, false)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("node error", func(t *testing.T) {
+		partitionID := types.PartitionID(1)
+		_, err := newService(&storeStub{}, &clientStub{err: errors.New("timeout")}, nil).GetUnitState(ctx, unitID, &partitionID, false)
+		require.EqualError(t, err, "failed to load unit from partition 1: timeout")
+	})
+}
@@ -0,0 +1,91 @@
+package unittx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+// MaxTxs is the number of the transactions of the unit loaded for the lifecycle and the state reconstruction.
+const MaxTxs = 1000
+
+var (
+	errTruncated = errors.New("transactions truncated")
+	// errStop stops the iteration of the transactions
+	errStop = errors.New("stop")
+)
+
+type (
+	Store interface {
+		ExportTxs(ctx context.Context, filter domain.TxExportFilter, fn func(tx *domain.BlockTx) error) error
+	}
+
+	// Filter selects the transactions of the unit.
+	Filter struct {
+		UnitID      types.UnitID
+		PartitionID *types.PartitionID // partition of the oldest transaction when nil
+		EndBlock    uint64             // not applied when zero
+	}
+)
+
+/*
+Load returns the successful transactions targeting the unit, oldest first. Only the transactions of the partition
+of the oldest transaction are returned when the partition ID is not set and the unit ID is targeted on several
+partitions. When there are more than MaxTxs transactions the oldest transaction and the latest MaxTxs-1
+transactions are returned and truncated is set. Returns domain.ErrNotFound when no successful transaction
+targets the unit.
+*/
+func Load(ctx context.Context, store Store, filter Filter) (txs []*domain.BlockTx, truncated bool, err error) {
+	match := func(tx *domain.BlockTx) bool {
+		if tx.Transaction == nil || tx.Transaction.TxStatus() != types.TxStatusSuccessful {
+			return false
+		}
+		if filter.PartitionID != nil && tx.PartitionID != *filter.PartitionID {
+			return false
+		}
+		// the end block filter of the store is not applied when zero
+		return filter.EndBlock == 0 || tx.BlockNumber <= filter.EndBlock
+	}
+	exportFilter := domain.TxExportFilter{UnitIDs: []types.UnitID{filter.UnitID}, EndBlock: filter.EndBlock}
+
+	var first *domain.BlockTx
+	err = store.ExportTxs(ctx, exportFilter, func(tx *domain.BlockTx) error {
+		if !match(tx) {
+			return nil
+		}
+		first = tx
+		return errStop
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return nil, false, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	if first == nil {
+		return nil, false, domain.ErrNotFound
+	}
+
+	exportFilter.Descending = true
+	err = store.ExportTxs(ctx, exportFilter, func(tx *domain.BlockTx) error {
+		if !match(tx) || tx.PartitionID != first.PartitionID {
+			return nil
+		}
+		if bytes.Equal(tx.TxRecordHash, first.TxRecordHash) {
+			return errStop
+		}
+		if len(txs) == MaxTxs-1 {
+			return errTruncated
+		}
+		txs = append(txs, tx)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) && !errors.Is(err, errTruncated) {
+		return nil, false, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	txs = append(txs, first)
+	slices.Reverse(txs)
+	return txs, errors.Is(err, errTruncated), nil
+}
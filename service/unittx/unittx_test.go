@@ -0,0 +1,91 @@
+package unittx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/testutils"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	unitID := types.UnitID{5}
+	tx := func(partitionID types.PartitionID, blockNumber uint64, status types.TxStatus) *domain.BlockTx {
+		partitionTypeID := money.PartitionTypeID
+		if partitionID == 2 {
+			partitionTypeID = tokens.PartitionTypeID
+		}
+		return testutils.BlockTx(partitionTypeID, partitionID, blockNumber, testutils.TxRecord(t, partitionID, money.TransactionTypeTransfer, unitID, &money.TransferAttributes{}, status))
+	}
+	blockNumbers := func(txs []*domain.BlockTx) []uint64 {
+		var res []uint64
+		for _, tx := range txs {
+			res = append(res, tx.BlockNumber)
+		}
+		return res
+	}
+	store := &testutils.TxStore{Txs: []*domain.BlockTx{
+		tx(1, 1, types.TxStatusFailed),
+		tx(2, 2, types.TxStatusSuccessful),
+		tx(1, 3, types.TxStatusSuccessful),
+		tx(2, 4, types.TxStatusSuccessful),
+		tx(2, 5, types.TxStatusSuccessful),
+	}}
+
+	t.Run("partition of the oldest transaction", func(t *testing.T) {
+		txs, truncated, err := Load(ctx, store, Filter{UnitID: unitID})
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Equal(t, []uint64{2, 4, 5}, blockNumbers(txs))
+		require.Equal(t, []types.UnitID{unitID}, store.Filter.UnitIDs)
+	})
+
+	t.Run("partition and end block", func(t *testing.T) {
+		partitionID := types.PartitionID(2)
+		txs, _, err := Load(ctx, store, Filter{UnitID: unitID, PartitionID: &partitionID, EndBlock: 4})
+		require.NoError(t, err)
+		require.Equal(t, []uint64{2, 4}, blockNumbers(txs))
+		require.EqualValues(t, 4, store.Filter.EndBlock)
+
+		partitionID = 1
+		txs, _, err = Load(ctx, store, Filter{UnitID: unitID, PartitionID: &partitionID})
+		require.NoError(t, err)
+		require.Equal(t, []uint64{3}, blockNumbers(txs))
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		store := &testutils.TxStore{}
+		for i := range MaxTxs + 1 {
+			store.Txs = append(store.Txs, tx(1, uint64(i+1), types.TxStatusSuccessful))
+		}
+		txs, truncated, err := Load(ctx, store, Filter{UnitID: unitID})
+		require.NoError(t, err)
+		require.True(t, truncated)
+		require.Len(t, txs, MaxTxs)
+		require.EqualValues(t, 1, txs[0].BlockNumber)
+		require.EqualValues(t, 3, txs[1].BlockNumber)
+		require.EqualValues(t, MaxTxs+1, txs[MaxTxs-1].BlockNumber)
+
+		store.Txs = store.Txs[:MaxTxs]
+		txs, truncated, err = Load(ctx, store, Filter{UnitID: unitID})
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Len(t, txs, MaxTxs)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := Load(ctx, store, Filter{UnitID: unitID, EndBlock: 1})
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		_, _, err := Load(ctx, &testutils.TxStore{Err: errors.New("boom")}, Filter{UnitID: unitID})
+		require.EqualError(t, err, "failed to load transactions: boom")
+	})
+}
@@ -0,0 +1,216 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+var ErrInvalidWindow = errors.New("invalid window")
+
+// statsMaxAge is how long the aggregated statistics are reused when new blocks are stored.
+const statsMaxAge = 10 * time.Second
+
+type (
+	// Service aggregates the statistics of the validators from the proposers of the stored blocks. The statistics of
+	// the window are cached, they are aggregated again when a new block is stored and the cached statistics are older
+	// than statsMaxAge.
+	Service struct {
+		store   Store
+		windows []time.Duration
+		now     func() time.Time
+
+		mu    sync.Mutex
+		cache map[statsKey]*cachedStats
+	}
+
+	statsKey struct {
+		partitionID types.PartitionID
+		window      time.Duration
+	}
+
+	cachedStats struct {
+		blockNumber  uint64 // latest stored block the statistics are aggregated up to
+		aggregatedAt time.Time
+		window       *Window
+		stats        []*Stats
+	}
+
+	Store interface {
+		GetBlocksPage(ctx context.Context, partitionID types.PartitionID, page domain.PageRequest[uint64], includeEmpty bool) ([]*domain.BlockInfo, bool, error)
+		GetProposerStats(ctx context.Context, partitionID types.PartitionID, fromTimestamp uint64) ([]*domain.ProposerStats, error)
+	}
+
+	// Window is the summary of the blocks of the partition in the window ending with the latest stored block. The
+	// rounds without a block between the first and the last block of the window are counted as missed, the leader
+	// of the missed round is not recorded and the missed rounds are not attributed to the validators.
+	Window struct {
+		Duration         string
+		From             uint64 // unicity seal timestamps of the window
+		To               uint64
+		FirstBlockNumber uint64
+		LastBlockNumber  uint64
+		Blocks           uint64
+		EmptyBlocks      uint64
+		MissedRounds     uint64
+		TxCount          uint64
+		Fees             uint64
+	}
+
+	// Validators are the statistics of the validators which proposed blocks in the window.
+	Validators struct {
+		PartitionID types.PartitionID
+		Window      *Window
+		Validators  []*Stats // the validators with the most proposed blocks first
+	}
+
+	// Validator is the statistics of the validator in the window.
+	Validator struct {
+		PartitionID types.PartitionID
+		Window      *Window
+		Validator   *Stats
+	}
+
+	Stats struct {
+		NodeID           string
+		BlocksProposed   uint64
+		EmptyBlocks      uint64
+		NonEmptyBlocks   uint64
+		TxCount          uint64
+		Fees             uint64  // sum of the actual fees of the transactions in the proposed blocks
+		Share            float64 // share of the blocks of the window in percent
+		FirstBlockNumber uint64
+		LastBlockNumber  uint64
+		LastProposedAt   uint64
+	}
+)
+
+// NewValidatorService creates the validator service, the statistics can be requested for the given windows, the first
+// window is the default.
+func NewValidatorService(store Store, windows []time.Duration) (*Service, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if len(windows) == 0 {
+		return nil, errors.New("no windows configured")
+	}
+	for _, window := range windows {
+		if window < time.Second {
+			return nil, fmt.Errorf("invalid window %s", window)
+		}
+	}
+	return &Service{store: store, windows: windows, now: time.Now, cache: make(map[statsKey]*cachedStats)}, nil
+}
+
+// Windows returns the windows the statistics can be requested for.
+func (s *Service) Windows() []time.Duration {
+	return slices.Clone(s.windows)
+}
+
+// GetValidators returns the statistics of all the validators which proposed blocks of the partition in the window,
+// zero window means the default window.
+func (s *Service) GetValidators(ctx context.Context, partitionID types.PartitionID, window time.Duration) (*Validators, error) {
+	w, stats, err := s.getStats(ctx, partitionID, window)
+	if err != nil {
+		return nil, err
+	}
+	return &Validators{PartitionID: partitionID, Window: w, Validators: stats}, nil
+}
+
+// GetValidator returns the statistics of the validator, domain.ErrNotFound when the validator did not propose any
+// blocks of the partition in the window.
+func (s *Service) GetValidator(ctx context.Context, partitionID types.PartitionID, nodeID string, window time.Duration) (*Validator, error) {
+	w, stats, err := s.getStats(ctx, partitionID, window)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(stats, func(st *Stats) bool { return st.NodeID == nodeID })
+	if idx < 0 {
+		return nil, fmt.Errorf("no blocks proposed by validator %s in the window %s: %w", nodeID, w.Duration, domain.ErrNotFound)
+	}
+	return &Validator{PartitionID: partitionID, Window: w, Validator: stats[idx]}, nil
+}
+
+func (s *Service) getStats(ctx context.Context, partitionID types.PartitionID, window time.Duration) (*Window, []*Stats, error) {
+	if window == 0 {
+		window = s.windows[0]
+	}
+	if !slices.Contains(s.windows, window) {
+		return nil, nil, fmt.Errorf("%w %s, available windows: %v", ErrInvalidWindow, window, s.windows)
+	}
+	key := statsKey{partitionID: partitionID, window: window}
+	now := s.now()
+	s.mu.Lock()
+	cached := s.cache[key]
+	s.mu.Unlock()
+	if cached != nil && now.Sub(cached.aggregatedAt) < statsMaxAge {
+		return cached.window, cached.stats, nil
+	}
+
+	latest, _, err := s.store.GetBlocksPage(ctx, partitionID, domain.PageRequest[uint64]{Limit: 1}, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load latest block: %w", err)
+	}
+	if len(latest) == 0 {
+		return nil, nil, fmt.Errorf("no blocks of partition %d: %w", partitionID, domain.ErrNotFound)
+	}
+	if cached != nil && cached.blockNumber == latest[0].BlockNumber {
+		return cached.window, cached.stats, nil
+	}
+	w, stats, err := s.aggregate(ctx, partitionID, window, latest[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	s.cache[key] = &cachedStats{blockNumber: latest[0].BlockNumber, aggregatedAt: now, window: w, stats: stats}
+	s.mu.Unlock()
+	return w, stats, nil
+}
+
+// aggregate aggregates the statistics of the proposers of the blocks in the window ending with the latest block.
+func (s *Service) aggregate(ctx context.Context, partitionID types.PartitionID, window time.Duration, latest *domain.BlockInfo) (*Window, []*Stats, error) {
+	w := &Window{Duration: window.String(), To: latest.Timestamp}
+	if seconds := uint64(window / time.Second); w.To > seconds {
+		w.From = w.To - seconds
+	}
+	proposers, err := s.store.GetProposerStats(ctx, partitionID, w.From)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load proposer stats: %w", err)
+	}
+
+	stats := make([]*Stats, 0, len(proposers))
+	for _, p := range proposers {
+		if w.Blocks == 0 || p.FirstBlockNumber < w.FirstBlockNumber {
+			w.FirstBlockNumber = p.FirstBlockNumber
+		}
+		w.LastBlockNumber = max(w.LastBlockNumber, p.LastBlockNumber)
+		w.Blocks += p.BlocksProposed
+		w.EmptyBlocks += p.EmptyBlocks
+		w.TxCount += p.TxCount
+		w.Fees += p.Fees
+		stats = append(stats, &Stats{
+			NodeID:           p.NodeID,
+			BlocksProposed:   p.BlocksProposed,
+			EmptyBlocks:      p.EmptyBlocks,
+			NonEmptyBlocks:   p.BlocksProposed - p.EmptyBlocks,
+			TxCount:          p.TxCount,
+			Fees:             p.Fees,
+			FirstBlockNumber: p.FirstBlockNumber,
+			LastBlockNumber:  p.LastBlockNumber,
+			LastProposedAt:   p.LastProposedAt,
+		})
+	}
+	if w.Blocks > 0 {
+		w.MissedRounds = w.LastBlockNumber - w.FirstBlockNumber + 1 - w.Blocks
+		for _, st := range stats {
+			st.Share = float64(st.BlocksProposed) / float64(w.Blocks) * 100
+		}
+	}
+	return w, stats, nil
+}
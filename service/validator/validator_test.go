@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+type storeStub struct {
+	latest        []*domain.BlockInfo
+	proposers     []*domain.ProposerStats
+	fromTimestamp uint64
+}
+
+func (s *storeStub) GetBlocksPage(context.Context, types.PartitionID, domain.PageRequest[uint64], bool) ([]*domain.BlockInfo, bool, error) {
+	return s.latest, false, nil
+}
+
+func (s *storeStub) GetProposerStats(_ context.Context, _ types.PartitionID, fromTimestamp uint64) ([]*domain.ProposerStats, error) {
+	s.fromTimestamp = fromTimestamp
+	return s.proposers, nil
+}
+
+func TestNewValidatorService(t *testing.T) {
+	_, err := NewValidatorService(nil, []time.Duration{time.Hour})
+	require.ErrorContains(t, err, "store is nil")
+	_, err = NewValidatorService(&storeStub{}, nil)
+	require.ErrorContains(t, err, "no windows configured")
+	_, err = NewValidatorService(&storeStub{}, []time.Duration{time.Millisecond})
+	require.ErrorContains(t, err, "invalid window 1ms")
+}
+
+func TestService_GetValidators(t *testing.T) {
+	store := &storeStub{
+		latest: []*domain.BlockInfo{{BlockNumber: 20, Timestamp: 10000}},
+		proposers: []*domain.ProposerStats{
+			{NodeID: "node1", BlocksProposed: 6, EmptyBlocks: 2, TxCount: 10, Fees: 5, FirstBlockNumber: 11, LastBlockNumber: 20, LastProposedAt: 10000},
+			{NodeID: "node2", BlocksProposed: 2, EmptyBlocks: 2, FirstBlockNumber: 10, LastBlockNumber: 18, LastProposedAt: 9990},
+		},
+	}
+	s, err := NewValidatorService(store, []time.Duration{time.Hour, 24 * time.Hour})
+	require.NoError(t, err)
+
+	validators, err := s.GetValidators(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 10000-3600, store.fromTimestamp)
+	require.Equal(t, &Window{
+		Duration:         "1h0m0s",
+		From:             10000 - 3600,
+		To:               10000,
+		FirstBlockNumber: 10,
+		LastBlockNumber:  20,
+		Blocks:           8,
+		EmptyBlocks:      4,
+		MissedRounds:     3,
+		TxCount:          10,
+		Fees:             5,
+	}, validators.Window)
+	require.Len(t, validators.Validators, 2)
+	require.Equal(t, &Stats{NodeID: "node1", BlocksProposed: 6, EmptyBlocks: 2, NonEmptyBlocks: 4, TxCount: 10, Fees: 5, Share: 75,
+		FirstBlockNumber: 11, LastBlockNumber: 20, LastProposedAt: 10000}, validators.Validators[0])
+	require.Equal(t, float64(25), validators.Validators[1].Share)
+
+	// window longer than the stored chain
+	_, err = s.GetValidators(context.Background(), 1, 24*time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, store.fromTimestamp)
+
+	_, err = s.GetValidators(context.Background(), 1, 2*time.Hour)
+	require.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestService_GetValidator(t *testing.T) {
+	store := &storeStub{
+		latest:    []*domain.BlockInfo{{BlockNumber: 20, Timestamp: 10000}},
+		proposers: []*domain.ProposerStats{{NodeID: "node1", BlocksProposed: 1, FirstBlockNumber: 20, LastBlockNumber: 20}},
+	}
+	s, err := NewValidatorService(store, []time.Duration{time.Hour})
+	require.NoError(t, err)
+
+	validator, err := s.GetValidator(context.Background(), 1, "node1", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, "node1", validator.Validator.NodeID)
+	require.Equal(t, float64(100), validator.Validator.Share)
+	require.Zero(t, validator.Window.MissedRounds)
+
+	_, err = s.GetValidator(context.Background(), 1, "node2", time.Hour)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	store.latest = nil
+	_, err = s.GetValidators(context.Background(), 2, 0)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestService_CachesStats(t *testing.T) {
+	store := &storeStub{
+		latest:    []*domain.BlockInfo{{BlockNumber: 20, Timestamp: 10000}},
+		proposers: []*domain.ProposerStats{{NodeID: "node1", BlocksProposed: 1, FirstBlockNumber: 20, LastBlockNumber: 20}},
+	}
+	s, err := NewValidatorService(store, []time.Duration{time.Hour})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	validators, err := s.GetValidators(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, validators.Window.Blocks)
+
+	// new block, the cached statistics are reused until they are too old
+	store.latest = []*domain.BlockInfo{{BlockNumber: 21, Timestamp: 10001}}
+	store.proposers = []*domain.ProposerStats{{NodeID: "node1", BlocksProposed: 2, FirstBlockNumber: 20, LastBlockNumber: 21}}
+	validators, err = s.GetValidators(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, validators.Window.Blocks)
+
+	now = now.Add(statsMaxAge)
+	validators, err = s.GetValidators(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, validators.Window.Blocks)
+
+	// no new blocks, the statistics are not aggregated again
+	store.proposers = nil
+	now = now.Add(statsMaxAge)
+	validators, err = s.GetValidators(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, validators.Window.Blocks)
+}
@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/internal/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	HeaderWebhookID        = "X-Webhook-ID"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+
+	deliveryBatchSize   = 100
+	deliveryConcurrency = 8
+	// how long the delivered events are kept for the deliveries API
+	deliveredTTL = 7 * 24 * time.Hour
+)
+
+// errAddressNotAllowed is returned when the webhook host resolves to a non-public address.
+var errAddressNotAllowed = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598) which netip doesn't classify as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+type (
+	WorkerConfig struct {
+		Interval    time.Duration // how often the due deliveries are checked
+		Timeout     time.Duration // timeout of the webhook request
+		MaxAttempts int           // the delivery is dead-lettered after this many failed attempts
+		BackoffBase time.Duration // delay after the first failed attempt, doubled after every attempt
+		BackoffMax  time.Duration
+	}
+
+	/*
+		Worker posts the events to the webhook URLs. Payload is the JSON encoded event signed with the
+		subscription secret: X-Webhook-Signature header is "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<payload>">".
+		Failed deliveries are retried with exponential backoff and dead-lettered after MaxAttempts.
+	*/
+	Worker struct {
+		store  Store
+		config WorkerConfig
+		client *http.Client
+		now    func() time.Time
+		// allowAddr reports whether the webhook requests may be sent to the address
+		allowAddr func(netip.Addr) bool
+	}
+
+	// statusError is returned when the webhook responds with a non-2xx status.
+	statusError struct {
+		status string
+	}
+)
+
+func NewWorker(store Store, config WorkerConfig) (*Worker, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if config.Interval <= 0 || config.Timeout <= 0 || config.MaxAttempts <= 0 || config.BackoffBase <= 0 || config.BackoffMax < config.BackoffBase {
+		return nil, fmt.Errorf("invalid webhook worker config: %+v", config)
+	}
+	w := &Worker{
+		store:     store,
+		config:    config,
+		now:       time.Now,
+		allowAddr: isPublicAddr,
+	}
+	// the address is checked when connecting so that the host can't resolve to a public
+	// address when the subscription is created and to an internal one when the event is posted
+	dialer := &net.Dialer{Timeout: config.Timeout, Control: w.checkAddr}
+	w.client = &http.Client{
+		Timeout:   config.Timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, ForceAttemptHTTP2: true},
+		// redirects are not followed, the subscriber must register the final URL
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return w, nil
+}
+
+// checkAddr is the net.Dialer Control function rejecting the connections to the addresses which are not allowed.
+func (w *Worker) checkAddr(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if !w.allowAddr(addrPort.Addr().Unmap()) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, addrPort.Addr())
+	}
+	return nil
+}
+
+// isPublicAddr returns false for the loopback, private, link-local, multicast and unspecified addresses.
+func isPublicAddr(addr netip.Addr) bool {
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// Run delivers the due events until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := w.deliverDue(ctx)
+			if err != nil {
+				log.Error("failed to deliver webhook events", "err", err)
+			}
+			// full batch, there may be more due deliveries
+			if err != nil || n < deliveryBatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue makes delivery attempts of the due events and returns the number of attempts made.
+func (w *Worker) deliverDue(ctx context.Context) (int, error) {
+	deliveries, err := w.store.GetDueWebhookDeliveries(ctx, w.now(), deliveryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due deliveries: %w", err)
+	}
+
+	subscriptions := make(map[primitive.ObjectID]*domain.WebhookSubscription)
+	for _, d := range deliveries {
+		if _, ok := subscriptions[d.SubscriptionID]; ok {
+			continue
+		}
+		subscription, err := w.store.GetWebhookSubscription(ctx, d.SubscriptionID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return 0, fmt.Errorf("failed to load subscription: %w", err)
+		}
+		subscriptions[d.SubscriptionID] = subscription
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(deliveryConcurrency)
+	for _, d := range deliveries {
+		subscription := subscriptions[d.SubscriptionID]
+		if subscription == nil {
+			// subscription was deleted after the deliveries were loaded
+			continue
+		}
+		g.Go(func() error {
+			w.attempt(ctx, subscription, d)
+			if err := w.store.UpdateWebhookDelivery(ctx, d); err != nil {
+				return fmt.Errorf("failed to update delivery %s: %w", d.ID, err)
+			}
+			return nil
+		})
+	}
+	return len(deliveries), g.Wait()
+}
+
+// attempt posts the event and updates the delivery state according to the result.
+func (w *Worker) attempt(ctx context.Context, subscription *domain.WebhookSubscription, d *domain.WebhookDelivery) {
+	d.Attempts++
+	err := w.post(ctx, subscription, d.Event)
+	now := w.now()
+	if err == nil {
+		expireAt := now.Add(deliveredTTL)
+		d.Status = domain.WebhookDeliveryDelivered
+		d.LastError = ""
+		d.ExpireAt = &expireAt
+		return
+	}
+
+	// the error of the transport may reveal details of the network, the subscriber only sees a summary
+	d.LastError = deliveryError(err)
+	log.Debug("webhook delivery attempt failed", "event", d.ID, "attempts", d.Attempts, "err", err)
+	if d.Attempts >= w.config.MaxAttempts {
+		log.Warn("webhook delivery failed, dead-lettering the event", "event", d.ID, "attempts", d.Attempts, "err", err)
+		d.Status = domain.WebhookDeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(w.backoff(d.Attempts))
+}
+
+// backoff returns the delay after the failed attempt, ie BackoffBase * 2^(attempts-1) capped to BackoffMax.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.config.BackoffBase
+	for i := 1; i < attempts && delay < w.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, w.config.BackoffMax)
+}
+
+func (w *Worker) post(ctx context.Context, subscription *domain.WebhookSubscription, event *domain.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookID, event.ID)
+	req.Header.Set(HeaderWebhookSignature, Signature(subscription.Secret, w.now(), payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &statusError{status: res.Status}
+	}
+	return nil
+}
+
+func (e *statusError) Error() string {
+	return "webhook responded with status " + e.status
+}
+
+// deliveryError returns the error of the failed attempt shown to the subscriber.
+func deliveryError(err error) string {
+	var se *statusError
+	var ne net.Error
+	switch {
+	case errors.As(err, &se):
+		return se.Error()
+	case errors.Is(err, errAddressNotAllowed):
+		return errAddressNotAllowed.Error()
+	case errors.As(err, &ne) && ne.Timeout():
+		return "webhook request timed out"
+	default:
+		return "failed to post event"
+	}
+}
+
+// Signature returns the X-Webhook-Signature header value of the payload.
+func Signature(secret []byte, timestamp time.Time, payload []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,296 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	secretLength     = 32
+	pubKeyHashLength = 32
+)
+
+// ErrInvalidSubscription is returned when the subscription URL or filter is invalid.
+var ErrInvalidSubscription = errors.New("invalid subscription")
+
+type (
+	/*
+		Service manages the webhook subscriptions and creates the events for the transactions
+		matching the subscriptions when a block is processed. The events are delivered by the Worker.
+	*/
+	Service struct {
+		store Store
+	}
+
+	Store interface {
+		CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+		GetWebhookSubscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error)
+		GetWebhookSubscription(ctx context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error)
+		DeleteWebhookSubscription(ctx context.Context, id primitive.ObjectID) error
+		AddWebhookDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+		GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+		UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+		GetWebhookDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+		ReplayWebhookDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, now time.Time) (int64, error)
+	}
+)
+
+func NewService(store Store) (*Service, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	return &Service{store: store}, nil
+}
+
+// CreateSubscription creates the subscription of the "owner", the returned subscription contains the secret
+// the payloads are signed with.
+func (s *Service) CreateSubscription(ctx context.Context, owner, webhookURL string, filter domain.WebhookFilter) (*domain.WebhookSubscription, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: URL must be an absolute http(s) URL", ErrInvalidSubscription)
+	}
+	if filter.IsEmpty() {
+		return nil, fmt.Errorf("%w: filter is empty", ErrInvalidSubscription)
+	}
+	if len(filter.OwnerPubKeyHash) > 0 && len(filter.OwnerPubKeyHash) != pubKeyHashLength {
+		return nil, fmt.Errorf("%w: owner pubkey hash must be %d bytes", ErrInvalidSubscription, pubKeyHashLength)
+	}
+	secret := make([]byte, secretLength)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	subscription := &domain.WebhookSubscription{
+		Owner:     owner,
+		URL:       webhookURL,
+		Secret:    secret,
+		Filter:    filter,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err = s.store.CreateWebhookSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to store subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions returns the subscriptions of the "owner" without the secrets.
+func (s *Service) GetSubscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	subscriptions, err := s.store.GetWebhookSubscriptions(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	for _, subscription := range subscriptions {
+		subscription.Secret = nil
+	}
+	return subscriptions, nil
+}
+
+// GetSubscription returns the subscription without the secret, ErrNotFound is returned
+// when the subscription doesn't exist or belongs to another owner.
+func (s *Service) GetSubscription(ctx context.Context, owner string, id primitive.ObjectID) (*domain.WebhookSubscription, error) {
+	subscription, err := s.store.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Owner != owner {
+		return nil, domain.ErrNotFound
+	}
+	subscription.Secret = nil
+	return subscription, nil
+}
+
+// DeleteSubscription deletes the subscription and its deliveries.
+func (s *Service) DeleteSubscription(ctx context.Context, owner string, id primitive.ObjectID) error {
+	if _, err := s.GetSubscription(ctx, owner, id); err != nil {
+		return err
+	}
+	return s.store.DeleteWebhookSubscription(ctx, id)
+}
+
+// GetDeliveries returns the latest deliveries of the subscription, all statuses are returned when "status" is empty.
+func (s *Service) GetDeliveries(
+	ctx context.Context, owner string, id primitive.ObjectID, status domain.WebhookDeliveryStatus, limit int,
+) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, owner, id); err != nil {
+		return nil, err
+	}
+	return s.store.GetWebhookDeliveries(ctx, id, status, limit)
+}
+
+// ReplayDeliveries schedules the failed (dead-lettered) deliveries of the subscription
+// for delivery again and returns the number of deliveries scheduled.
+func (s *Service) ReplayDeliveries(ctx context.Context, owner string, id primitive.ObjectID) (int64, error) {
+	if _, err := s.GetSubscription(ctx, owner, id); err != nil {
+		return 0, err
+	}
+	return s.store.ReplayWebhookDeliveries(ctx, id, time.Now())
+}
+
+/*
+BlockProcessed creates the events of the transactions matching the subscriptions. Event IDs are
+derived from the subscription and the transaction so processing the block again doesn't create
+duplicate events.
+*/
+func (s *Service) BlockProcessed(
+	ctx context.Context, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID, blockNumber uint64, txs []*domain.TxInfo,
+) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	subscriptions, err := s.store.GetWebhookSubscriptions(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var deliveries []*domain.WebhookDelivery
+	for _, tx := range txs {
+		txo, err := tx.Transaction.GetTransactionOrderV1()
+		if err != nil {
+			return fmt.Errorf("failed to decode transaction order: %w", err)
+		}
+		for _, subscription := range subscriptions {
+			if !matches(&subscription.Filter, partitionID, partitionTypeID, tx, txo) {
+				continue
+			}
+			event := &domain.WebhookEvent{
+				ID:             fmt.Sprintf("%s-%x", subscription.ID.Hex(), []byte(tx.TxRecordHash)),
+				SubscriptionID: subscription.ID,
+				PartitionID:    partitionID,
+				BlockNumber:    blockNumber,
+				TxRecordHash:   tx.TxRecordHash,
+				TxOrderHash:    tx.TxOrderHash,
+				TxType:         txo.Type,
+				UnitID:         txo.UnitID,
+				TargetUnits:    targetUnits(tx),
+				Successful:     tx.Transaction.TxStatus() == types.TxStatusSuccessful,
+				CreatedAt:      now,
+			}
+			deliveries = append(deliveries, &domain.WebhookDelivery{
+				ID:             event.ID,
+				SubscriptionID: subscription.ID,
+				Event:          event,
+				Status:         domain.WebhookDeliveryPending,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err = s.store.AddWebhookDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to store webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func matches(f *domain.WebhookFilter, partitionID types.PartitionID, partitionTypeID types.PartitionTypeID, tx *domain.TxInfo, txo *types.TransactionOrder) bool {
+	if f.PartitionID != nil && *f.PartitionID != partitionID {
+		return false
+	}
+	if f.TxType != nil && *f.TxType != txo.Type {
+		return false
+	}
+	if len(f.UnitID) > 0 && !bytes.Equal(f.UnitID, txo.UnitID) &&
+		!slices.ContainsFunc(targetUnits(tx), func(id types.UnitID) bool { return bytes.Equal(id, f.UnitID) }) {
+		return false
+	}
+	if len(f.OwnerPubKeyHash) > 0 && !slices.ContainsFunc(ownerPredicates(partitionTypeID, txo), func(b []byte) bool {
+		p := predicate.Decode(b)
+		return p != nil && p.Type == predicate.TypeP2PKH && bytes.Equal(p.PubKeyHash, f.OwnerPubKeyHash)
+	}) {
+		return false
+	}
+	return true
+}
+
+// ownerPredicates returns the owner predicates the transaction assigns to its units, nil when the
+// transaction doesn't change the owner or its attributes can't be decoded.
+func ownerPredicates(partitionTypeID types.PartitionTypeID, txo *types.TransactionOrder) [][]byte {
+	if txo.Type == fc.TransactionTypeAddFeeCredit {
+		var attr fc.AddFeeCreditAttributes
+		if err := txo.UnmarshalAttributes(&attr); err != nil {
+			return nil
+		}
+		return [][]byte{attr.FeeCreditOwnerPredicate}
+	}
+
+	switch partitionTypeID {
+	case money.PartitionTypeID:
+		switch txo.Type {
+		case money.TransactionTypeTransfer:
+			var attr money.TransferAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.NewOwnerPredicate}
+		case money.TransactionTypeSplit:
+			var attr money.SplitAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			var owners [][]byte
+			for _, target := range attr.TargetUnits {
+				owners = append(owners, target.OwnerPredicate)
+			}
+			return owners
+		}
+	case tokens.PartitionTypeID:
+		switch txo.Type {
+		case tokens.TransactionTypeMintFT:
+			var attr tokens.MintFungibleTokenAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.OwnerPredicate}
+		case tokens.TransactionTypeMintNFT:
+			var attr tokens.MintNonFungibleTokenAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.OwnerPredicate}
+		case tokens.TransactionTypeTransferFT:
+			var attr tokens.TransferFungibleTokenAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.NewOwnerPredicate}
+		case tokens.TransactionTypeTransferNFT:
+			var attr tokens.TransferNonFungibleTokenAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.NewOwnerPredicate}
+		case tokens.TransactionTypeSplitFT:
+			var attr tokens.SplitFungibleTokenAttributes
+			if err := txo.UnmarshalAttributes(&attr); err != nil {
+				return nil
+			}
+			return [][]byte{attr.NewOwnerPredicate}
+		}
+	}
+	return nil
+}
+
+func targetUnits(tx *domain.TxInfo) []types.UnitID {
+	if tx.Transaction.ServerMetadata == nil {
+		return nil
+	}
+	return tx.Transaction.ServerMetadata.TargetUnits
+}
@@ -0,0 +1,384 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-explorer-backend/domain"
+	"github.com/alphabill-org/alphabill-explorer-backend/predicate"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type storeStub struct {
+	mu            sync.Mutex
+	subscriptions map[primitive.ObjectID]*domain.WebhookSubscription
+	deliveries    map[string]*domain.WebhookDelivery
+}
+
+func newStoreStub() *storeStub {
+	return &storeStub{
+		subscriptions: map[primitive.ObjectID]*domain.WebhookSubscription{},
+		deliveries:    map[string]*domain.WebhookDelivery{},
+	}
+}
+
+func (s *storeStub) CreateWebhookSubscription(_ context.Context, subscription *domain.WebhookSubscription) error {
+	subscription.ID = primitive.NewObjectID()
+	cp := *subscription
+	s.subscriptions[subscription.ID] = &cp
+	return nil
+}
+
+func (s *storeStub) GetWebhookSubscriptions(_ context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	var res []*domain.WebhookSubscription
+	for _, subscription := range s.subscriptions {
+		if owner == "" || subscription.Owner == owner {
+			cp := *subscription
+			res = append(res, &cp)
+		}
+	}
+	return res, nil
+}
+
+func (s *storeStub) GetWebhookSubscription(_ context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error) {
+	subscription, ok := s.subscriptions[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *subscription
+	return &cp, nil
+}
+
+func (s *storeStub) DeleteWebhookSubscription(_ context.Context, id primitive.ObjectID) error {
+	delete(s.subscriptions, id)
+	return nil
+}
+
+func (s *storeStub) AddWebhookDeliveries(_ context.Context, deliveries []*domain.WebhookDelivery) error {
+	for _, d := range deliveries {
+		if _, ok := s.deliveries[d.ID]; !ok {
+			s.deliveries[d.ID] = d
+		}
+	}
+	return nil
+}
+
+func (s *storeStub) GetDueWebhookDeliveries(_ context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	var res []*domain.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(now) && len(res) < limit {
+			cp := *d
+			res = append(res, &cp)
+		}
+	}
+	return res, nil
+}
+
+func (s *storeStub) UpdateWebhookDelivery(_ context.Context, delivery *domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (s *storeStub) GetWebhookDeliveries(_ context.Context, subscriptionID primitive.ObjectID, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	var res []*domain.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.SubscriptionID == subscriptionID && (status == "" || d.Status == status) && len(res) < limit {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+func (s *storeStub) ReplayWebhookDeliveries(_ context.Context, subscriptionID primitive.ObjectID, now time.Time) (int64, error) {
+	var n int64
+	for _, d := range s.deliveries {
+		if d.SubscriptionID == subscriptionID && d.Status == domain.WebhookDeliveryFailed {
+			d.Status = domain.WebhookDeliveryPending
+			d.Attempts = 0
+			d.NextAttemptAt = now
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newTx(t *testing.T, txo *types.TransactionOrder, targetUnits ...types.UnitID) *domain.TxInfo {
+	txoBytes, err := txo.MarshalCBOR()
+	require.NoError(t, err)
+	return &domain.TxInfo{
+		TxRecordHash: domain.TxHash(txo.UnitID),
+		Transaction: &types.TransactionRecord{
+			TransactionOrder: txoBytes,
+			ServerMetadata:   &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful, TargetUnits: targetUnits},
+		},
+	}
+}
+
+func TestService_CreateSubscription(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewService(newStoreStub())
+	require.NoError(t, err)
+
+	_, err = s.CreateSubscription(ctx, "owner", "ftp://example.com", domain.WebhookFilter{UnitID: []byte{1}})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+	_, err = s.CreateSubscription(ctx, "owner", "/hook", domain.WebhookFilter{UnitID: []byte{1}})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+	_, err = s.CreateSubscription(ctx, "owner", "https://example.com/hook", domain.WebhookFilter{})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+	_, err = s.CreateSubscription(ctx, "owner", "https://example.com/hook", domain.WebhookFilter{OwnerPubKeyHash: []byte{0xAA, 0xBB}})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+
+	subscription, err := s.CreateSubscription(ctx, "owner", "https://example.com/hook", domain.WebhookFilter{UnitID: []byte{1}})
+	require.NoError(t, err)
+	require.Len(t, subscription.Secret, secretLength)
+
+	// secret is only returned on create, other owners don't see the subscription
+	stored, err := s.GetSubscription(ctx, "owner", subscription.ID)
+	require.NoError(t, err)
+	require.Nil(t, stored.Secret)
+	_, err = s.GetSubscription(ctx, "other", subscription.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.ErrorIs(t, s.DeleteSubscription(ctx, "other", subscription.ID), domain.ErrNotFound)
+
+	subscriptions, err := s.GetSubscriptions(ctx, "owner")
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Nil(t, subscriptions[0].Secret)
+
+	require.NoError(t, s.DeleteSubscription(ctx, "owner", subscription.ID))
+	_, err = s.GetSubscription(ctx, "owner", subscription.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestMatches(t *testing.T) {
+	partitionID := types.PartitionID(2)
+	txType := money.TransactionTypeTransfer
+	pubKeyHash := bytes.Repeat([]byte{0xAA}, 32)
+	attr, err := types.Cbor.Marshal(&money.TransferAttributes{NewOwnerPredicate: predicate.P2PKH(pubKeyHash), TargetValue: 10})
+	require.NoError(t, err)
+	txo := &types.TransactionOrder{Payload: types.Payload{
+		PartitionID: partitionID,
+		Type:        txType,
+		UnitID:      []byte{1},
+		Attributes:  attr,
+	}}
+	tx := newTx(t, txo, []byte{1}, []byte{5})
+
+	otherPartition := types.PartitionID(1)
+	otherType := uint16(4)
+	tests := []struct {
+		name   string
+		filter domain.WebhookFilter
+		match  bool
+	}{
+		{name: "unit", filter: domain.WebhookFilter{UnitID: []byte{1}}, match: true},
+		{name: "target unit", filter: domain.WebhookFilter{UnitID: []byte{5}}, match: true},
+		{name: "other unit", filter: domain.WebhookFilter{UnitID: []byte{6}}, match: false},
+		{name: "owner", filter: domain.WebhookFilter{OwnerPubKeyHash: pubKeyHash}, match: true},
+		{name: "other owner", filter: domain.WebhookFilter{OwnerPubKeyHash: bytes.Repeat([]byte{0xAB}, 32)}, match: false},
+		// the pubkey hash of the filter is compared to the decoded predicate, not searched from the attributes
+		{name: "part of the owner", filter: domain.WebhookFilter{OwnerPubKeyHash: pubKeyHash[1:]}, match: false},
+		{name: "partition and type", filter: domain.WebhookFilter{PartitionID: &partitionID, TxType: &txType}, match: true},
+		{name: "other partition", filter: domain.WebhookFilter{PartitionID: &otherPartition}, match: false},
+		{name: "other type", filter: domain.WebhookFilter{UnitID: []byte{1}, TxType: &otherType}, match: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.match, matches(&tt.filter, partitionID, money.PartitionTypeID, tx, txo))
+		})
+	}
+
+	// the transaction types of the other partition types have other attributes
+	require.False(t, matches(&domain.WebhookFilter{OwnerPubKeyHash: pubKeyHash}, partitionID, tokens.PartitionTypeID, tx, txo))
+}
+
+func TestService_BlockProcessed(t *testing.T) {
+	ctx := context.Background()
+	store := newStoreStub()
+	s, err := NewService(store)
+	require.NoError(t, err)
+	subscription, err := s.CreateSubscription(ctx, "owner", "https://example.com/hook", domain.WebhookFilter{UnitID: []byte{1}})
+	require.NoError(t, err)
+
+	txs := []*domain.TxInfo{
+		newTx(t, &types.TransactionOrder{Payload: types.Payload{UnitID: []byte{1}}}),
+		newTx(t, &types.TransactionOrder{Payload: types.Payload{UnitID: []byte{2}}}),
+	}
+	require.NoError(t, s.BlockProcessed(ctx, 1, money.PartitionTypeID, 10, txs))
+	require.Len(t, store.deliveries, 1)
+	// processing the block again doesn't duplicate the events
+	require.NoError(t, s.BlockProcessed(ctx, 1, money.PartitionTypeID, 10, txs))
+	require.Len(t, store.deliveries, 1)
+
+	deliveries, err := s.GetDeliveries(ctx, "owner", subscription.ID, domain.WebhookDeliveryPending, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	event := deliveries[0].Event
+	require.Equal(t, deliveries[0].ID, event.ID)
+	require.EqualValues(t, 10, event.BlockNumber)
+	require.EqualValues(t, []byte{1}, event.UnitID)
+	require.True(t, event.Successful)
+}
+
+func TestWorker_Deliver(t *testing.T) {
+	ctx := context.Background()
+	store := newStoreStub()
+	s, err := NewService(store)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	status := http.StatusInternalServerError
+	var received []*http.Request
+	var payloads [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r)
+		payloads = append(payloads, body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	subscription, err := s.CreateSubscription(ctx, "owner", srv.URL, domain.WebhookFilter{UnitID: []byte{1}})
+	require.NoError(t, err)
+	require.NoError(t, s.BlockProcessed(ctx, 1, money.PartitionTypeID, 10, []*domain.TxInfo{newTx(t, &types.TransactionOrder{Payload: types.Payload{UnitID: []byte{1}}})}))
+
+	worker, err := NewWorker(store, WorkerConfig{Interval: time.Second, Timeout: time.Second, MaxAttempts: 2, BackoffBase: time.Minute, BackoffMax: time.Hour})
+	require.NoError(t, err)
+	now := time.Now()
+	worker.now = func() time.Time { return now }
+	// the test server listens on the loopback address
+	worker.allowAddr = func(netip.Addr) bool { return true }
+
+	// failed attempt is retried after the backoff
+	n, err := worker.deliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	var delivery *domain.WebhookDelivery
+	for _, d := range store.deliveries {
+		delivery = d
+	}
+	require.Equal(t, domain.WebhookDeliveryPending, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+	require.Equal(t, "webhook responded with status 500 Internal Server Error", delivery.LastError)
+	n, err = worker.deliverDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// dead-lettered after MaxAttempts
+	now = now.Add(time.Minute)
+	_, err = worker.deliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, domain.WebhookDeliveryFailed, store.deliveries[delivery.ID].Status)
+
+	// replayed delivery is delivered
+	replayed, err := s.ReplayDeliveries(ctx, "owner", subscription.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, replayed)
+	status = http.StatusNoContent
+	_, err = worker.deliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, domain.WebhookDeliveryDelivered, store.deliveries[delivery.ID].Status)
+	require.NotNil(t, store.deliveries[delivery.ID].ExpireAt)
+
+	require.Len(t, received, 3)
+	last := received[2]
+	require.Equal(t, delivery.ID, last.Header.Get(HeaderWebhookID))
+	require.Equal(t, Signature(subscription.Secret, now, payloads[2]), last.Header.Get(HeaderWebhookSignature))
+	var event domain.WebhookEvent
+	require.NoError(t, json.Unmarshal(payloads[2], &event))
+	require.Equal(t, delivery.ID, event.ID)
+}
+
+func TestWorker_NonPublicAddress(t *testing.T) {
+	ctx := context.Background()
+	store := newStoreStub()
+	s, err := NewService(store)
+	require.NoError(t, err)
+
+	var called atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called.Store(true)
+	}))
+	defer srv.Close()
+
+	// the host name resolves to the loopback address only when connecting
+	hookURL := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	_, err = s.CreateSubscription(ctx, "owner", hookURL, domain.WebhookFilter{UnitID: []byte{1}})
+	require.NoError(t, err)
+	require.NoError(t, s.BlockProcessed(ctx, 1, money.PartitionTypeID, 10, []*domain.TxInfo{newTx(t, &types.TransactionOrder{Payload: types.Payload{UnitID: []byte{1}}})}))
+
+	worker, err := NewWorker(store, WorkerConfig{Interval: time.Second, Timeout: time.Second, MaxAttempts: 2, BackoffBase: time.Minute, BackoffMax: time.Hour})
+	require.NoError(t, err)
+	n, err := worker.deliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.False(t, called.Load())
+	for _, d := range store.deliveries {
+		require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+		require.Equal(t, "webhook address is not public", d.LastError)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::1":    true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.1.1":     false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"224.0.0.1":       false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+		"255.255.255.255": false,
+	} {
+		require.Equal(t, public, isPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestDeliveryError(t *testing.T) {
+	require.Equal(t, "webhook responded with status 404 Not Found", deliveryError(fmt.Errorf("wrapped: %w", &statusError{status: "404 Not Found"})))
+	require.Equal(t, "webhook address is not public", deliveryError(fmt.Errorf("dial: %w", errAddressNotAllowed)))
+	require.Equal(t, "failed to post event", deliveryError(errors.New("dial tcp 10.0.0.1:80: connection refused")))
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	w := &Worker{config: WorkerConfig{BackoffBase: time.Second, BackoffMax: 5 * time.Second}}
+	require.Equal(t, time.Second, w.backoff(1))
+	require.Equal(t, 2*time.Second, w.backoff(2))
+	require.Equal(t, 4*time.Second, w.backoff(3))
+	require.Equal(t, 5*time.Second, w.backoff(4))
+	require.Equal(t, 5*time.Second, w.backoff(100))
+}
+
+func TestSignature(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	sig := Signature([]byte("secret"), ts, []byte(`{"ID":"1"}`))
+	require.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, sig)
+	require.NotEqual(t, sig, Signature([]byte("other"), ts, []byte(`{"ID":"1"}`)))
+}
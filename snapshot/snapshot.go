@@ -0,0 +1,222 @@
+/*
+Package snapshot implements the portable explorer database snapshot format.
+
+Snapshot is a gzip compressed JSON-lines stream:
+  - first line is the Header;
+  - followed by any number of Record lines;
+  - last line is the Trailer containing the number of records and the SHA-256
+    checksum of all the record lines (including line terminators).
+
+Record documents are opaque to this package, the store decides how documents
+are encoded (ie MongoDB extended JSON).
+*/
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// Version is the current snapshot format version.
+const Version = 1
+
+// maxLineSize is the maximum size of a single line in the snapshot, documents are limited to 16MB by MongoDB.
+const maxLineSize = 32 * 1024 * 1024
+
+var (
+	ErrChecksumMismatch = errors.New("snapshot checksum mismatch")
+	ErrTruncated        = errors.New("snapshot is truncated")
+)
+
+type (
+	Header struct {
+		Version   int       `json:"version"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	Record struct {
+		Collection string          `json:"collection"`
+		Document   json.RawMessage `json:"document"`
+	}
+
+	Trailer struct {
+		Count    uint64 `json:"count"`
+		Checksum string `json:"checksum"`
+	}
+
+	Writer struct {
+		zw    *gzip.Writer
+		bw    *bufio.Writer
+		hash  hash.Hash
+		count uint64
+	}
+
+	Reader struct {
+		zr      *gzip.Reader
+		scanner *bufio.Scanner
+		hash    hash.Hash
+		header  Header
+		count   uint64
+		done    bool
+	}
+
+	// line is used to tell apart records and the trailer while reading
+	line struct {
+		Record
+		Trailer *Trailer `json:"trailer,omitempty"`
+	}
+
+	trailerLine struct {
+		Trailer *Trailer `json:"trailer"`
+	}
+)
+
+// NewWriter writes snapshot header to "w" and returns writer for the records.
+// Writer must be closed to write the trailer, closing the Writer doesn't close "w".
+func NewWriter(w io.Writer) (*Writer, error) {
+	zw := gzip.NewWriter(w)
+	sw := &Writer{zw: zw, bw: bufio.NewWriter(zw), hash: sha256.New()}
+	if err := sw.writeLine(Header{Version: Version, CreatedAt: time.Now().UTC()}); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot header: %w", err)
+	}
+	return sw, nil
+}
+
+func (w *Writer) WriteRecord(collection string, document json.RawMessage) error {
+	data, err := json.Marshal(Record{Collection: collection, Document: document})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot record: %w", err)
+	}
+	data = append(data, '\n')
+	w.hash.Write(data)
+	if _, err = w.bw.Write(data); err != nil {
+		return fmt.Errorf("failed to write snapshot record: %w", err)
+	}
+	w.count++
+	return nil
+}
+
+// Count returns the number of records written so far.
+func (w *Writer) Count() uint64 {
+	return w.count
+}
+
+func (w *Writer) Close() error {
+	trailer := trailerLine{Trailer: &Trailer{Count: w.count, Checksum: hex.EncodeToString(w.hash.Sum(nil))}}
+	if err := w.writeLine(trailer); err != nil {
+		return fmt.Errorf("failed to write snapshot trailer: %w", err)
+	}
+	if err := w.bw.Flush(); err != nil {
+		return fmt.Errorf("failed to flush snapshot: %w", err)
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) writeLine(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.bw.Write(append(data, '\n'))
+	return err
+}
+
+// NewReader reads and validates the snapshot header from "r".
+func NewReader(r io.Reader) (*Reader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	sr := &Reader{zr: zr, scanner: scanner, hash: sha256.New()}
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+		}
+		return nil, ErrTruncated
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &sr.header); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot header: %w", err)
+	}
+	if sr.header.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", sr.header.Version, Version)
+	}
+	return sr, nil
+}
+
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+/*
+Next returns the next record in the snapshot. When the trailer is reached the
+record count and checksum are verified and io.EOF is returned when they match.
+
+NB! As the checksum is verified only at the end of the stream the records
+returned before io.EOF must be treated as unverified, use Verify to check the
+snapshot before consuming it.
+*/
+func (r *Reader) Next() (*Record, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		return nil, ErrTruncated
+	}
+
+	var l line
+	if err := json.Unmarshal(r.scanner.Bytes(), &l); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot line %d: %w", r.count+2, err)
+	}
+	if l.Trailer != nil {
+		r.done = true
+		if l.Trailer.Count != r.count {
+			return nil, fmt.Errorf("%w: trailer has %d records, read %d", ErrChecksumMismatch, l.Trailer.Count, r.count)
+		}
+		if sum := hex.EncodeToString(r.hash.Sum(nil)); sum != l.Trailer.Checksum {
+			return nil, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, l.Trailer.Checksum, sum)
+		}
+		return nil, io.EOF
+	}
+
+	r.hash.Write(r.scanner.Bytes())
+	r.hash.Write([]byte{'\n'})
+	r.count++
+	return &l.Record, nil
+}
+
+func (r *Reader) Close() error {
+	return r.zr.Close()
+}
+
+// Verify reads the whole snapshot and checks its checksum, returns the verified trailer with the number of records
+// and the checksum of the snapshot.
+func Verify(r io.Reader) (*Trailer, error) {
+	sr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer sr.Close()
+
+	for {
+		if _, err := sr.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return &Trailer{Count: sr.count, Checksum: hex.EncodeToString(sr.hash.Sum(nil))}, nil
+			}
+			return nil, err
+		}
+	}
+}
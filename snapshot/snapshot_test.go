@@ -0,0 +1,109 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_WriteRead(t *testing.T) {
+	buf := writeSnapshot(t, []Record{
+		{Collection: "blocks", Document: json.RawMessage(`{"blocknumber":1}`)},
+		{Collection: "blocks", Document: json.RawMessage(`{"blocknumber":2}`)},
+		{Collection: "metadata", Document: json.RawMessage(`{"partitionid":1}`)},
+	})
+
+	trailer, err := Verify(bytes.NewReader(buf))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, trailer.Count)
+	require.Len(t, trailer.Checksum, 64)
+
+	r, err := NewReader(bytes.NewReader(buf))
+	require.NoError(t, err)
+	require.Equal(t, Version, r.Header().Version)
+
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+	require.Len(t, records, 3)
+	require.Equal(t, "metadata", records[2].Collection)
+	require.JSONEq(t, `{"partitionid":1}`, string(records[2].Document))
+	require.NoError(t, r.Close())
+}
+
+func TestSnapshot_ChecksumMismatch(t *testing.T) {
+	buf := writeSnapshot(t, []Record{
+		{Collection: "blocks", Document: json.RawMessage(`{"blocknumber":1}`)},
+	})
+	tampered := rewrite(t, buf, func(s string) string {
+		return strings.Replace(s, `"blocknumber":1`, `"blocknumber":2`, 1)
+	})
+
+	_, err := Verify(bytes.NewReader(tampered))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestSnapshot_Truncated(t *testing.T) {
+	buf := writeSnapshot(t, []Record{
+		{Collection: "blocks", Document: json.RawMessage(`{"blocknumber":1}`)},
+		{Collection: "blocks", Document: json.RawMessage(`{"blocknumber":2}`)},
+	})
+	truncated := rewrite(t, buf, func(s string) string {
+		lines := strings.SplitAfter(s, "\n")
+		return strings.Join(lines[:2], "")
+	})
+
+	_, err := Verify(bytes.NewReader(truncated))
+	require.ErrorIs(t, err, ErrTruncated)
+}
+
+func TestSnapshot_UnsupportedVersion(t *testing.T) {
+	buf := writeSnapshot(t, nil)
+	other := rewrite(t, buf, func(s string) string {
+		return strings.Replace(s, `"version":1`, `"version":99`, 1)
+	})
+
+	_, err := NewReader(bytes.NewReader(other))
+	require.ErrorContains(t, err, "unsupported snapshot version 99")
+}
+
+func writeSnapshot(t *testing.T, records []Record) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	for _, rec := range records {
+		require.NoError(t, w.WriteRecord(rec.Collection, rec.Document))
+	}
+	require.EqualValues(t, len(records), w.Count())
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// rewrite decompresses the snapshot, modifies it using "f" and compresses it again
+func rewrite(t *testing.T, snapshot []byte, f func(string) string) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(snapshot))
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write([]byte(f(string(data))))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
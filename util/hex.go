@@ -1,15 +1,10 @@
 package util
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
-)
-
-const (
-	PubKeyBytesLength     = 33
-	PubKeyHashBytesLength = 32
+	"strings"
 )
 
 func ToHex(src []byte) []byte {
@@ -53,25 +48,6 @@ func CheckHex(input []byte) ([]byte, error) {
 	return input, nil
 }
 
-// PubKeyHash returns the hash of the given hex-encoded public key
-// If the input is already a hash of the public key, it just returns the hash
-func PubKeyHash(pubKeyHex string) ([]byte, error) {
-	bytes, err := DecodeHex(pubKeyHex)
-	if err != nil {
-		return nil, err
-	}
-	var pubKeyHash []byte
-	if len(bytes) == PubKeyBytesLength {
-		hash := sha256.Sum256(bytes)
-		pubKeyHash = hash[:]
-	} else if len(bytes) == PubKeyHashBytesLength {
-		pubKeyHash = bytes
-	} else {
-		return nil, errors.New("hex string is not public key or it's hash")
-	}
-	return pubKeyHash, nil
-}
-
 // DecodeHex decodes a hex string with optional 0x prefix
 func DecodeHex(input string) ([]byte, error) {
 	if len(input) == 0 {
@@ -83,6 +59,23 @@ func DecodeHex(input string) ([]byte, error) {
 	return hex.DecodeString(input[2:])
 }
 
+// NormalizeHexPrefix returns the lowercase hex digits of the hex string with optional 0x prefix, unlike
+// DecodeHex it accepts the odd number of digits so that the string can be used as a prefix of a hex value.
+func NormalizeHexPrefix(input string) (string, error) {
+	if has0xPrefix(input) {
+		input = input[2:]
+	}
+	if len(input) == 0 {
+		return "", errors.New("empty hex string")
+	}
+	for _, c := range input {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return "", fmt.Errorf("invalid hex character %q", c)
+		}
+	}
+	return strings.ToLower(input), nil
+}
+
 func has0xPrefix(input string) bool {
 	return len(input) >= 2 && input[0] == '0' && (input[1] == 'x' || input[1] == 'X')
 }
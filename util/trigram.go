@@ -0,0 +1,42 @@
+package util
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+/*
+Trigrams returns the sorted unique trigrams of the lowercase words of the text. Words are the runs of letters
+and digits, each word is padded with two spaces in front and one space at the end (as PostgreSQL pg_trgm does)
+so that the short words and the beginnings of the words have trigrams too.
+*/
+func Trigrams(text string) []string {
+	var trigrams []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			trigrams = append(trigrams, string(padded[i:i+3]))
+		}
+	}
+	slices.Sort(trigrams)
+	return slices.Compact(trigrams)
+}
+
+// TrigramSimilarity returns the number of the shared trigrams divided by the number of all the trigrams of the texts,
+// 1 when the texts have the same words and 0 when the texts have nothing in common.
+func TrigramSimilarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, t := range ta {
+		if _, found := slices.BinarySearch(tb, t); found {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}